@@ -0,0 +1,399 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/blackcoderx/falcon/pkg/core"
+	"github.com/blackcoderx/falcon/pkg/core/tools"
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/blackcoderx/falcon/pkg/storage"
+	"github.com/blackcoderx/falcon/pkg/tui"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// Final statuses of a headless agent run, as written to the transcript.
+const (
+	askCompleted      = "completed"
+	askBudgetExceeded = "budget_exceeded"
+	askTimeout        = "timeout"
+	askCancelled      = "cancelled"
+	askError          = "error"
+)
+
+var (
+	askPromptFile     string
+	askEnv            string
+	askMaxSteps       int
+	askMaxTokens      int
+	askTimeoutFlag    time.Duration
+	askApproveWrites  []string
+	askTranscriptPath string
+	askStream         bool
+)
+
+func init() {
+	askCmd.Flags().StringVar(&askPromptFile, "prompt-file", "", "Read prompts from this file (- for stdin); prompts are separated by lines containing only ---")
+	askCmd.Flags().StringVarP(&askEnv, "env", "e", "", "Environment from .falcon/environments/ to activate")
+	askCmd.Flags().IntVar(&askMaxSteps, "max-steps", 30, "Maximum number of agent steps (LLM turns) across all prompts, 0 for no limit")
+	askCmd.Flags().IntVar(&askMaxTokens, "max-tokens", 0, "Maximum estimated prompt and completion tokens across all prompts, 0 for no limit")
+	askCmd.Flags().DurationVar(&askTimeoutFlag, "timeout", 0, "Stop the run after this long, e.g. 15m (0 for no limit)")
+	askCmd.Flags().StringArrayVar(&askApproveWrites, "approve-writes", nil, "Approve write_file changes to paths matching this glob, e.g. '.falcon/reports/**' (repeatable; default: deny all)")
+	askCmd.Flags().StringVar(&askTranscriptPath, "transcript", "", "Transcript file (default: .falcon/transcripts/ask_<timestamp>.json)")
+	askCmd.Flags().BoolVar(&askStream, "stream", false, "Also emit streaming chunks of model output")
+	rootCmd.AddCommand(askCmd)
+}
+
+var askCmd = &cobra.Command{
+	Use:   "ask [prompt]",
+	Short: "Run the agent headlessly and stream its events as NDJSON",
+	Long: `Runs the full agent on one prompt, or on a script of prompts from
+--prompt-file, without the TUI. Every agent event is written to stdout as one
+JSON object per line and the whole run is saved as a transcript.
+
+File writes are denied unless the path matches an --approve-writes glob. The
+run stops when it reaches --max-steps, --max-tokens or --timeout.
+
+Exits with code 0 when every prompt is answered, 2 when the run cannot start
+or the agent fails, and 3 when a budget stops it.`,
+	Example: `  falcon ask "scan staging for auth bypass" -e staging
+  falcon ask --prompt-file nightly.txt --max-steps 60 --max-tokens 400000
+  falcon ask "write a smoke flow for /users" --approve-writes '.falcon/**'`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to load .env file: %v\n", err)
+		}
+
+		prompts, err := loadAskPrompts(args, askPromptFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		session, err := newAskSession(core.FalconFolderName, askEnv)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		if askTimeoutFlag > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, askTimeoutFlag)
+			defer cancel()
+		}
+
+		budget := core.Budget{MaxSteps: askMaxSteps, MaxTokens: askMaxTokens}
+		transcript := session.run(ctx, prompts, budget, json.NewEncoder(os.Stdout))
+
+		path := askTranscriptPath
+		if path == "" {
+			path = filepath.Join(storage.GetTranscriptsDir(session.falconDir), "ask_"+transcript.StartedAt.Format("20060102_150405")+".json")
+		}
+		if err := writeAskTranscript(path, transcript); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+		fmt.Fprintln(os.Stderr, formatAskSummary(transcript, path))
+
+		switch transcript.Status {
+		case askCompleted:
+		case askBudgetExceeded, askTimeout:
+			os.Exit(exitBudget)
+		default:
+			os.Exit(exitError)
+		}
+	},
+}
+
+// askEvent is one line of the NDJSON stream. It mirrors core.AgentEvent and
+// adds the events of the run itself: prompt, confirmation_response and done.
+type askEvent struct {
+	Time     time.Time     `json:"time"`
+	Type     string        `json:"type"`
+	Prompt   int           `json:"prompt,omitempty"`
+	Content  string        `json:"content,omitempty"`
+	ToolArgs string        `json:"tool_args,omitempty"`
+	File     *askFileEvent `json:"file,omitempty"`
+	Status   string        `json:"status,omitempty"`
+	Usage    *core.Usage   `json:"usage,omitempty"`
+}
+
+// askFileEvent describes a write_file confirmation and how it was answered.
+type askFileEvent struct {
+	Path     string `json:"path"`
+	IsNew    bool   `json:"is_new"`
+	Diff     string `json:"diff,omitempty"`
+	Approved bool   `json:"approved"`
+}
+
+// askExchange is one prompt of the script and the agent's answer to it.
+type askExchange struct {
+	Prompt string `json:"prompt"`
+	Answer string `json:"answer,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// askTranscript is the saved record of a headless run.
+type askTranscript struct {
+	StartedAt   time.Time     `json:"started_at"`
+	DurationMs  int64         `json:"duration_ms"`
+	Model       string        `json:"model"`
+	Environment string        `json:"environment,omitempty"`
+	Status      string        `json:"status"`
+	Prompts     int           `json:"prompts"`
+	Budget      core.Budget   `json:"budget"`
+	Usage       core.Usage    `json:"usage"`
+	Exchanges   []askExchange `json:"exchanges"`
+	Events      []askEvent    `json:"events"`
+}
+
+// askSession holds the agent and the services it shares with its tools.
+type askSession struct {
+	falconDir      string
+	env            string
+	agent          *core.Agent
+	confirmManager *shared.ConfirmationManager
+}
+
+// newAskSession builds the agent the same way the TUI does, with every tool
+// registered, and activates the environment if one is given.
+func newAskSession(falconDir, env string) (*askSession, error) {
+	if _, err := os.Stat(falconDir); err != nil {
+		return nil, fmt.Errorf("no %s folder in the current directory; run falcon once to set it up", falconDir)
+	}
+	client := tui.NewLLMClient()
+	if err := client.CheckConnection(); err != nil {
+		return nil, fmt.Errorf("failed to reach the LLM provider: %w", err)
+	}
+
+	agent := core.NewAgent(client)
+	framework := viper.GetString("framework")
+	if framework == "" {
+		framework = core.GetConfigFramework()
+	}
+	agent.SetFramework(framework)
+
+	memStore := core.NewMemoryStore(falconDir)
+	agent.SetMemoryStore(memStore)
+
+	workDir, _ := os.Getwd()
+	confirmManager := shared.NewConfirmationManager()
+	confirmManager.SetTimeout(30 * time.Second)
+	registry := tools.NewRegistry(agent, client, workDir, falconDir, memStore, confirmManager)
+	registry.RegisterAllTools()
+
+	if env != "" {
+		if err := registry.PersistManager.SetEnvironment(env); err != nil {
+			return nil, fmt.Errorf("failed to load environment '%s': %w", env, err)
+		}
+		// The registry's HTTP tool reads the transport settings from PersistManager
+		if _, err := loadEnvironment(registry.VariableStore, falconDir, env); err != nil {
+			return nil, err
+		}
+	}
+
+	return &askSession{falconDir: falconDir, env: env, agent: agent, confirmManager: confirmManager}, nil
+}
+
+// run sends each prompt to the agent in turn, writing events to out as they
+// happen. The budget covers all prompts; the run stops at the first prompt
+// that fails or exhausts it.
+func (s *askSession) run(ctx context.Context, prompts []string, budget core.Budget, out *json.Encoder) *askTranscript {
+	s.agent.SetBudget(budget)
+	transcript := &askTranscript{
+		StartedAt:   time.Now(),
+		Model:       s.agent.LLMClient().GetModel(),
+		Environment: s.env,
+		Status:      askCompleted,
+		Budget:      budget,
+		Prompts:     len(prompts),
+	}
+
+	emit := func(event askEvent) {
+		event.Time = time.Now()
+		if event.Type != "streaming" {
+			transcript.Events = append(transcript.Events, event)
+		}
+		if event.Type != "streaming" || askStream {
+			_ = out.Encode(event)
+		}
+	}
+
+	for i, prompt := range prompts {
+		n := i + 1
+		emit(askEvent{Type: "prompt", Prompt: n, Content: prompt})
+
+		answer, err := s.agent.ProcessMessageWithEvents(ctx, prompt, func(event core.AgentEvent) {
+			ev := askEvent{Type: event.Type, Prompt: n, Content: event.Content, ToolArgs: event.ToolArgs}
+			if event.Type == "confirmation_required" && event.FileConfirmation != nil {
+				fc := event.FileConfirmation
+				approved := writeApproved(fc.FilePath, askApproveWrites)
+				ev.File = &askFileEvent{Path: fc.FilePath, IsNew: fc.IsNewFile, Diff: fc.Diff, Approved: approved}
+				emit(ev)
+				// write_file asks for confirmation right after this event
+				s.confirmManager.PresetResponse(approved)
+				decision := "denied"
+				if approved {
+					decision = "approved"
+				}
+				emit(askEvent{Type: "confirmation_response", Prompt: n, Content: decision + ": " + fc.FilePath})
+				return
+			}
+			emit(ev)
+		})
+
+		exchange := askExchange{Prompt: prompt, Answer: answer}
+		if err != nil {
+			exchange.Error = err.Error()
+			transcript.Status = askStatus(err)
+		}
+		transcript.Exchanges = append(transcript.Exchanges, exchange)
+		if err != nil {
+			break
+		}
+	}
+
+	usage := s.agent.Usage()
+	transcript.Usage = usage
+	transcript.DurationMs = time.Since(transcript.StartedAt).Milliseconds()
+	emit(askEvent{Type: "done", Status: transcript.Status, Usage: &usage})
+	return transcript
+}
+
+// askStatus maps the error that ended a run to its transcript status.
+func askStatus(err error) string {
+	switch {
+	case errors.Is(err, core.ErrBudgetExceeded):
+		return askBudgetExceeded
+	case errors.Is(err, context.DeadlineExceeded):
+		return askTimeout
+	case errors.Is(err, context.Canceled):
+		return askCancelled
+	default:
+		return askError
+	}
+}
+
+// writeApproved reports whether a write to path is allowed by the
+// --approve-writes globs. "**" matches any path, a pattern ending in /**
+// matches everything below that directory, and other patterns use
+// filepath.Match. Relative paths and patterns are resolved against the
+// working directory, so an absolute path matches a relative pattern.
+func writeApproved(path string, patterns []string) bool {
+	path = absSlash(path)
+	for _, pattern := range patterns {
+		if pattern == "**" {
+			return true
+		}
+		pattern = absSlash(pattern)
+		if dir, ok := strings.CutSuffix(pattern, "/**"); ok {
+			if path == dir || strings.HasPrefix(path, dir+"/") {
+				return true
+			}
+			continue
+		}
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// absSlash returns the absolute, cleaned form of path with forward slashes.
+func absSlash(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// loadAskPrompts returns the prompt given as an argument followed by the
+// prompts of the prompt file, which are separated by lines containing only ---.
+func loadAskPrompts(args []string, promptFile string) ([]string, error) {
+	var prompts []string
+	if len(args) > 0 && strings.TrimSpace(args[0]) != "" {
+		prompts = append(prompts, strings.TrimSpace(args[0]))
+	}
+
+	if promptFile != "" {
+		var data []byte
+		var err error
+		if promptFile == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(promptFile)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read prompt file: %w", err)
+		}
+
+		var current []string
+		flush := func() {
+			if prompt := strings.TrimSpace(strings.Join(current, "\n")); prompt != "" {
+				prompts = append(prompts, prompt)
+			}
+			current = nil
+		}
+		for _, line := range strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n") {
+			if strings.TrimSpace(line) == "---" {
+				flush()
+				continue
+			}
+			current = append(current, line)
+		}
+		flush()
+	}
+
+	if len(prompts) == 0 {
+		return nil, fmt.Errorf("no prompt given; pass one as an argument or use --prompt-file")
+	}
+	return prompts, nil
+}
+
+func writeAskTranscript(path string, transcript *askTranscript) error {
+	data, err := json.MarshalIndent(transcript, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode transcript: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create transcript directory: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write transcript: %w", err)
+	}
+	return nil
+}
+
+// formatAskSummary renders the one-line result printed to stderr.
+func formatAskSummary(transcript *askTranscript, path string) string {
+	icon := "✅"
+	if transcript.Status != askCompleted {
+		icon = "❌"
+	}
+	return fmt.Sprintf("%s ask %s: %d/%d prompts, %d steps, ~%d tokens in %v (transcript: %s)",
+		icon, transcript.Status, answeredPrompts(transcript), transcript.Prompts,
+		transcript.Usage.Steps, transcript.Usage.Tokens,
+		time.Duration(transcript.DurationMs)*time.Millisecond, path)
+}
+
+func answeredPrompts(transcript *askTranscript) int {
+	n := 0
+	for _, exchange := range transcript.Exchanges {
+		if exchange.Error == "" {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteApproved(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	abs := func(rel string) string { return filepath.Join(dir, rel) }
+
+	cases := []struct {
+		name     string
+		path     string
+		patterns []string
+		want     bool
+	}{
+		{"no patterns", ".falcon/flows/a.yaml", nil, false},
+		{"dir/** below", ".falcon/flows/smoke.yaml", []string{".falcon/**"}, true},
+		{"dir/** the dir itself", ".falcon", []string{".falcon/**"}, true},
+		{"dir/** sibling prefix", ".falcon-old/a.yaml", []string{".falcon/**"}, false},
+		{"dir/** escaping with ..", ".falcon/../main.go", []string{".falcon/**"}, false},
+		{"dir/** with ./ prefix", "./.falcon/reports/r.md", []string{"./.falcon/reports/**"}, true},
+		{"** matches anything", "/etc/passwd", []string{"**"}, true},
+		{"./** stays in the working directory", "/etc/passwd", []string{"./**"}, false},
+		{"./** inside", "pkg/a.go", []string{"./**"}, true},
+		{"glob in one directory", "report.md", []string{"*.md"}, true},
+		{"glob does not cross directories", "docs/report.md", []string{"*.md"}, false},
+		{"second pattern matches", "docs/report.md", []string{"*.go", "docs/*.md"}, true},
+		{"absolute path, relative pattern", abs(".falcon/flows/a.yaml"), []string{".falcon/**"}, true},
+		{"relative path, absolute pattern", ".falcon/flows/a.yaml", []string{abs(".falcon") + "/**"}, true},
+		{"absolute path and pattern", abs("docs/api.md"), []string{abs("docs/*.md")}, true},
+		{"absolute path outside", "/tmp/elsewhere/a.yaml", []string{".falcon/**"}, false},
+		{"root /**", "/var/log/x", []string{"/**"}, true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := writeApproved(tc.path, tc.patterns); got != tc.want {
+				t.Errorf("writeApproved(%q, %q) = %v, want %v", tc.path, tc.patterns, got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/blackcoderx/falcon/pkg/core"
+	"github.com/blackcoderx/falcon/pkg/core/tools/debugging"
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var (
+	driftFramework  string
+	driftPath       string
+	driftReportName string
+)
+
+func init() {
+	driftCmd.Flags().StringVarP(&driftFramework, "framework", "f", "", "Framework whose routes to extract (default: .falcon/config.yaml, else all supported)")
+	driftCmd.Flags().StringVarP(&driftPath, "path", "p", "", "Source directory to scan (default: current directory)")
+	driftCmd.Flags().StringVar(&driftReportName, "report", "", "Report name (default: spec_drift_<timestamp>)")
+	rootCmd.AddCommand(driftCmd)
+}
+
+var driftCmd = &cobra.Command{
+	Use:   "drift",
+	Short: "Compare the routes in the code with the ingested spec",
+	Long: `Statically extracts the route table from the codebase and diffs it against
+.falcon/spec.yaml: undocumented routes, spec endpoints with no handler, and
+method or path-parameter mismatches. Writes the report to .falcon/reports/ and
+exits with code 1 when drift is found (2 on errors).`,
+	Example: `  falcon drift
+  falcon drift --framework express --path src`,
+	Run: func(cmd *cobra.Command, args []string) {
+		framework := driftFramework
+		if framework == "" {
+			framework = viper.GetString("framework")
+		}
+		if framework == "other" {
+			framework = ""
+		}
+
+		falconDir := core.FalconFolderName
+		workDir, err := os.Getwd()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		tool := debugging.NewSpecDriftTool(workDir, falconDir, shared.NewReportWriter(falconDir))
+		outcome, err := tool.Run(debugging.SpecDriftParams{
+			Framework:  framework,
+			Path:       driftPath,
+			ReportName: driftReportName,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		fmt.Println(outcome.Summary)
+		if outcome.Drift.HasDrift() {
+			os.Exit(exitFailed)
+		}
+	},
+}
@@ -0,0 +1,9 @@
+package main
+
+// Exit codes shared by the non-interactive subcommands (ask, drift, perf,
+// run and test), so CI can tell a failed check from a broken invocation.
+const (
+	exitFailed = 1 // the run completed but did not meet its thresholds
+	exitError  = 2 // the run could not be executed
+	exitBudget = 3 // falcon ask stopped at its step, token or time budget
+)
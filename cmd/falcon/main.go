@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/blackcoderx/falcon/pkg/core"
 	"github.com/blackcoderx/falcon/pkg/core/tools/persistence"
 	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/blackcoderx/falcon/pkg/storage"
 	"github.com/blackcoderx/falcon/pkg/tui"
 	"github.com/charmbracelet/glamour"
 	"github.com/joho/godotenv"
@@ -63,7 +65,7 @@ agent that understands your code and can interact with your APIs naturally.`,
 
 			// CLI Mode: Execute saved request
 			if requestFile != "" {
-				if err := runCLI(requestFile, envName); err != nil {
+				if err := runCLI(requestFile, envName, cmd.Flags().Changed("env")); err != nil {
 					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 					os.Exit(1)
 				}
@@ -133,7 +135,7 @@ func initConfig() {
 	_ = viper.ReadInConfig() // no error if not found
 
 	// Inject active provider values as flat viper keys so all downstream code
-	// (newLLMClient, collectProviderValues) works without modification.
+	// (tui.NewLLMClient, collectProviderValues) works without modification.
 	if gcfg, err := core.LoadGlobalConfig(); err == nil {
 		provID, model, values := core.GetActiveProviderEntry(gcfg)
 		if provID != "" {
@@ -159,41 +161,40 @@ func initConfig() {
 	}
 }
 
-func runCLI(requestName, env string) error {
+// runCLI executes one saved request, with its pre-request and post-response
+// hooks, and prints the response. It returns an error when the request
+// cannot be sent, a post-response check fails or the response status is 400
+// or above, so scripts can rely on the exit code.
+func runCLI(requestName, env string, envRequired bool) error {
 	falconDir := core.FalconFolderName
 
 	// Initialize shared components
-	responseManager := shared.NewResponseManager()
 	varStore := shared.NewVariableStore(falconDir)
 
-	// Initialize tools
-	persistManager := persistence.NewPersistenceManager(falconDir)
-
-	// Set environment if specified (TODO: implement simplified SetEnvironment helper if needed,
-	// or use persistence tool directly. For CLI simplicity, let's load env var store directly)
-	if envName != "" {
-		// In a real CLI runner, we'd need a proper way to load environments.
-		// For now, let's skip the explicit tool call wrapper and use the store if possible,
-		// or just acknowledge this needs the registry to be fully robust.
-		// Simplified:
-		// varStore.LoadEnvironment(envName) // Hypothetical
+	// Load the environment into the variable store. The default environment
+	// is optional; one passed with --env must exist.
+	transport, err := loadEnvironment(varStore, falconDir, env)
+	if err != nil {
+		if envRequired || !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
 	}
 
-	// Load request using unified request tool
-	requestTool := persistence.NewRequestTool(persistManager)
-	loadArgs := fmt.Sprintf(`{"action":"load","name":"%s"}`, requestName)
-
-	reqArgs, err := requestTool.Execute(loadArgs)
+	httpTool := shared.NewHTTPTool(nil, varStore)
+	httpTool.SetCookieSessions(shared.NewCookieSessions(falconDir))
+	httpTool.SetTransport(transport)
+	runner := persistence.NewRequestRunner(falconDir, httpTool, varStore)
+	req, err := runner.Load(requestName)
 	if err != nil {
 		return fmt.Errorf("failed to load request '%s': %w", requestName, err)
 	}
 
 	// Execute request
-	httpTool := shared.NewHTTPTool(responseManager, varStore)
-	resp, err := httpTool.Execute(reqArgs)
+	run, err := runner.Send(req)
 	if err != nil {
 		return fmt.Errorf("request failed: %w", err)
 	}
+	resp := run.Format()
 
 	// Render response with Glamour
 	renderer, err := glamour.NewTermRenderer(
@@ -202,19 +203,45 @@ func runCLI(requestName, env string) error {
 	)
 	if err != nil {
 		fmt.Println(resp) // Fallback to raw output
-		return nil
-	}
-
-	out, err := renderer.Render(resp)
-	if err != nil {
+	} else if out, err := renderer.Render(resp); err != nil {
 		fmt.Println(resp) // Fallback
-		return nil
+	} else {
+		fmt.Print(out)
 	}
 
-	fmt.Print(out)
+	if len(run.Failures) > 0 {
+		return fmt.Errorf("request '%s' failed post-response checks: %s", requestName, strings.Join(run.Failures, "; "))
+	}
+	if run.Response.StatusCode >= 400 {
+		return fmt.Errorf("request '%s' returned HTTP %d", requestName, run.Response.StatusCode)
+	}
 	return nil
 }
 
+// loadEnvironment loads .falcon/environments/<name>.yaml into the variable
+// store's session scope and returns its transport settings for the HTTP
+// tool. An empty name is a no-op.
+func loadEnvironment(varStore *shared.VariableStore, falconDir, name string) (shared.TransportConfig, error) {
+	var transport shared.TransportConfig
+	if name == "" {
+		return transport, nil
+	}
+	envPath := filepath.Join(storage.GetEnvironmentsDir(falconDir), name+".yaml")
+	env, err := storage.LoadEnvironment(envPath)
+	if err != nil {
+		return transport, fmt.Errorf("failed to load environment '%s': %w", name, err)
+	}
+	for key, value := range env {
+		varStore.Set(key, value)
+	}
+	if settings, err := storage.LoadEnvironmentTransport(envPath); err != nil {
+		return transport, fmt.Errorf("failed to load environment '%s': %w", name, err)
+	} else if settings != nil {
+		transport = shared.TransportConfig(*settings)
+	}
+	return transport, nil
+}
+
 func main() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
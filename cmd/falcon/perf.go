@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blackcoderx/falcon/pkg/core"
+	"github.com/blackcoderx/falcon/pkg/core/tools/performance_engine"
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	perfFile        string
+	perfMode        string
+	perfBaseURL     string
+	perfEndpoints   []string
+	perfConcurrency int
+	perfDuration    int
+	perfRPS         int
+	perfMaxVUs      int
+	perfThresholds  []string
+	perfReportName  string
+	perfEnv         string
+)
+
+func init() {
+	perfCmd.Flags().StringVar(&perfFile, "file", "", "Scenario file (JSON or YAML) with run_performance parameters")
+	perfCmd.Flags().StringVarP(&perfMode, "mode", "m", "", "Test mode: load, stress, spike, soak (default: load)")
+	perfCmd.Flags().StringVarP(&perfBaseURL, "base-url", "u", "", "Base URL of the API")
+	perfCmd.Flags().StringArrayVar(&perfEndpoints, "endpoint", nil, "Endpoint to test, e.g. \"GET /api/users\" (repeatable; default: Knowledge Graph)")
+	perfCmd.Flags().IntVarP(&perfConcurrency, "concurrency", "c", 0, "Concurrent virtual users (default: 10)")
+	perfCmd.Flags().IntVarP(&perfDuration, "duration", "d", 0, "Duration in seconds (default: 30)")
+	perfCmd.Flags().IntVar(&perfRPS, "rps", 0, "Target arrival rate (switches to the open model)")
+	perfCmd.Flags().IntVar(&perfMaxVUs, "max-vus", 0, "Open model: max concurrent requests (default: 100)")
+	perfCmd.Flags().StringArrayVarP(&perfThresholds, "threshold", "t", nil, "SLO threshold, e.g. \"p95<300ms\" or \"error_rate<1%\" (repeatable)")
+	perfCmd.Flags().StringVar(&perfReportName, "report", "", "Report name (default: performance_report)")
+	perfCmd.Flags().StringVarP(&perfEnv, "env", "e", "", "Environment from .falcon/environments/ for {{VAR}} substitution")
+	rootCmd.AddCommand(perfCmd)
+}
+
+var perfCmd = &cobra.Command{
+	Use:   "perf",
+	Short: "Run a performance test and fail when thresholds are not met",
+	Long: `Runs a load, stress, spike or soak test without the TUI, writes the report to
+.falcon/reports/ and exits with code 1 when any threshold fails (2 on errors).`,
+	Example: `  falcon perf -u http://localhost:3000 --endpoint "GET /api/users" -c 20 -d 60 -t "p95<300ms" -t "error_rate<1%"
+  falcon perf --file perf/checkout.yaml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to load .env file: %v\n", err)
+		}
+
+		params, err := perfParams(cmd)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		falconDir := core.FalconFolderName
+		varStore := shared.NewVariableStore(falconDir)
+		transport, err := loadEnvironment(varStore, falconDir, perfEnv)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		httpTool := shared.NewHTTPTool(nil, nil)
+		httpTool.SetTransport(transport)
+		tool := performance_engine.NewPerformanceEngineTool(falconDir, httpTool, varStore, shared.NewReportWriter(falconDir))
+		outcome, err := tool.Run(params)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		fmt.Println(outcome.Summary)
+		if outcome.Verdict != nil && !outcome.Verdict.Passed {
+			os.Exit(exitFailed)
+		}
+	},
+}
+
+// perfParams builds the run parameters from the scenario file, then applies
+// any flags that were set explicitly.
+func perfParams(cmd *cobra.Command) (performance_engine.PerformanceParams, error) {
+	var params performance_engine.PerformanceParams
+	if perfFile != "" {
+		loaded, err := loadPerfScenario(perfFile)
+		if err != nil {
+			return params, err
+		}
+		params = loaded
+	}
+
+	flags := cmd.Flags()
+	if flags.Changed("mode") {
+		params.Mode = perfMode
+	}
+	if flags.Changed("base-url") {
+		params.BaseURL = perfBaseURL
+	}
+	if flags.Changed("endpoint") {
+		params.Endpoints = perfEndpoints
+	}
+	if flags.Changed("concurrency") {
+		params.Concurrency = perfConcurrency
+	}
+	if flags.Changed("duration") {
+		params.Duration = perfDuration
+	}
+	if flags.Changed("rps") {
+		params.RPS = perfRPS
+	}
+	if flags.Changed("max-vus") {
+		params.MaxVUs = perfMaxVUs
+	}
+	if flags.Changed("threshold") {
+		params.Thresholds = perfThresholds
+	}
+	if flags.Changed("report") {
+		params.ReportName = perfReportName
+	}
+	return params, nil
+}
+
+// loadPerfScenario reads run_performance parameters from a JSON or YAML
+// file. YAML uses the same keys as the tool's JSON parameters.
+func loadPerfScenario(path string) (performance_engine.PerformanceParams, error) {
+	var params performance_engine.PerformanceParams
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return params, fmt.Errorf("failed to read scenario file: %w", err)
+	}
+
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		var raw map[string]interface{}
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return params, fmt.Errorf("failed to parse scenario file: %w", err)
+		}
+		if data, err = json.Marshal(raw); err != nil {
+			return params, fmt.Errorf("failed to parse scenario file: %w", err)
+		}
+	}
+
+	if err := json.Unmarshal(data, &params); err != nil {
+		return params, fmt.Errorf("failed to parse scenario file: %w", err)
+	}
+	return params, nil
+}
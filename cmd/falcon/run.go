@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/blackcoderx/falcon/pkg/core"
+	"github.com/blackcoderx/falcon/pkg/core/tools/flow_runner"
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+var (
+	runEnv        string
+	runVars       []string
+	runBaseURL    string
+	runReportName string
+)
+
+func init() {
+	runCmd.Flags().StringVarP(&runEnv, "env", "e", "", "Environment from .falcon/environments/ for {{VAR}} substitution")
+	runCmd.Flags().StringArrayVar(&runVars, "var", nil, "Flow variable override, e.g. --var user=alice (repeatable)")
+	runCmd.Flags().StringVarP(&runBaseURL, "base-url", "u", "", "Override the flow's base_url")
+	runCmd.Flags().StringVar(&runReportName, "report", "", "Report name (default: flow_report_<flow>_<timestamp>)")
+	rootCmd.AddCommand(runCmd)
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run <flow>",
+	Short: "Run a flow from .falcon/flows/ and fail when a step fails",
+	Long: `Runs a flow file step by step without the LLM, writes the report to
+.falcon/reports/ and exits with code 1 when any step fails (2 on errors).`,
+	Example: `  falcon run integration_login_create_delete
+  falcon run smoke_all_endpoints -e staging --var user=alice`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to load .env file: %v\n", err)
+		}
+
+		vars, err := parseRunVars(runVars)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		falconDir := core.FalconFolderName
+		varStore := shared.NewVariableStore(falconDir)
+		transport, err := loadEnvironment(varStore, falconDir, runEnv)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		httpTool := shared.NewHTTPTool(nil, nil)
+		httpTool.SetCookieSessions(shared.NewCookieSessions(falconDir))
+		httpTool.SetTransport(transport)
+		tool := flow_runner.NewFlowRunnerTool(falconDir, httpTool, varStore, nil, shared.NewReportWriter(falconDir))
+
+		// Ctrl+C stops the flow and still prints what ran
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+		outcome, err := tool.Run(ctx, flow_runner.RunFlowParams{
+			Flow:       args[0],
+			Variables:  vars,
+			BaseURL:    runBaseURL,
+			ReportName: runReportName,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		fmt.Println(outcome.Summary)
+		if !outcome.Result.Passed {
+			os.Exit(exitFailed)
+		}
+	},
+}
+
+// parseRunVars turns repeated name=value flags into flow variables.
+func parseRunVars(pairs []string) (map[string]interface{}, error) {
+	vars := make(map[string]interface{}, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid --var '%s' (expected name=value)", pair)
+		}
+		vars[strings.TrimSpace(name)] = value
+	}
+	return vars, nil
+}
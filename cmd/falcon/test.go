@@ -0,0 +1,590 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/blackcoderx/falcon/pkg/core"
+	"github.com/blackcoderx/falcon/pkg/core/tools/flow_runner"
+	"github.com/blackcoderx/falcon/pkg/core/tools/functional_test_generator"
+	"github.com/blackcoderx/falcon/pkg/core/tools/persistence"
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/blackcoderx/falcon/pkg/storage"
+	"github.com/joho/godotenv"
+	"github.com/spf13/cobra"
+)
+
+// Test case statuses. "error" means the test could not be executed (invalid
+// file, unreachable URL); "failed" means it ran and an expectation failed.
+const (
+	casePassed  = "passed"
+	caseFailed  = "failed"
+	caseError   = "error"
+	caseSkipped = "skipped"
+)
+
+var (
+	testEnv         string
+	testBaseURL     string
+	testOnly        []string
+	testGenerate    []string
+	testTags        []string
+	testExcludeTags []string
+	testParallel    int
+	testFailFast    bool
+	testJUnit       string
+	testJSON        string
+	testMarkdown    string
+	testReportName  string
+)
+
+func init() {
+	testCmd.Flags().StringVarP(&testEnv, "env", "e", "", "Environment from .falcon/environments/ for {{VAR}} substitution")
+	testCmd.Flags().StringVarP(&testBaseURL, "base-url", "u", "", "Base URL for relative request URLs, flows and generated scenarios (default: {{BASE_URL}})")
+	testCmd.Flags().StringSliceVar(&testOnly, "only", nil, "Sources to run: requests, flows, suites (default: all)")
+	testCmd.Flags().StringSliceVar(&testGenerate, "generate", nil, "Also run scenarios generated from the Knowledge Graph: happy, negative, boundary")
+	testCmd.Flags().StringArrayVarP(&testTags, "tag", "t", nil, "Run only tests with this tag (repeatable)")
+	testCmd.Flags().StringArrayVar(&testExcludeTags, "exclude-tag", nil, "Skip tests with this tag (repeatable)")
+	testCmd.Flags().IntVarP(&testParallel, "parallel", "p", 1, "Number of requests, flows and suites to run at once")
+	testCmd.Flags().BoolVar(&testFailFast, "fail-fast", false, "Stop starting new tests after the first failure")
+	testCmd.Flags().StringVar(&testJUnit, "junit", "", "Write JUnit XML results to this file (- for stdout)")
+	testCmd.Flags().StringVar(&testJSON, "json", "", "Write JSON results to this file (- for stdout)")
+	testCmd.Flags().StringVar(&testMarkdown, "markdown", "", "Write Markdown results to this file (- for stdout)")
+	testCmd.Flags().StringVar(&testReportName, "report", "", "Report name in .falcon/reports/ (default: test_run_<timestamp>)")
+	rootCmd.AddCommand(testCmd)
+}
+
+var testCmd = &cobra.Command{
+	Use:   "test [name...]",
+	Short: "Run saved requests, flows, suites and generated scenarios for CI",
+	Long: `Runs saved requests (.falcon/requests/), flows (.falcon/flows/), suites
+(.falcon/suites/) and optionally scenarios generated from the Knowledge Graph
+without the LLM. Names limit the run to those requests, flows or suites.
+
+Exits with code 0 when every test passes, 1 when any test fails or cannot be
+executed, and 2 when the run itself cannot start.`,
+	Example: `  falcon test -e staging --junit results.xml
+  falcon test login checkout_flow --fail-fast
+  falcon test -t smoke -p 4 --json - > results.json
+  falcon test --only flows --generate happy,negative -u http://localhost:3000`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := godotenv.Load(); err != nil && !os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Warning: Failed to load .env file: %v\n", err)
+		}
+
+		falconDir := core.FalconFolderName
+		varStore := shared.NewVariableStore(falconDir)
+		transport, err := loadEnvironment(varStore, falconDir, testEnv)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		baseURL := testBaseURL
+		if baseURL == "" {
+			baseURL, _ = varStore.Get("BASE_URL")
+		}
+
+		collector := &testCollector{
+			falconDir:   falconDir,
+			baseURL:     baseURL,
+			flowBaseURL: testBaseURL,
+			varStore:    varStore,
+			httpTool:    shared.NewHTTPTool(nil, nil),
+			transport:   transport,
+		}
+		collector.httpTool.SetCookieSessions(shared.NewCookieSessions(falconDir))
+		collector.httpTool.SetTransport(transport)
+		collector.runner = persistence.NewRequestRunner(falconDir, collector.httpTool, varStore)
+		collector.runner.SetBaseURL(baseURL)
+		jobs, err := collector.collect(testOnly, testGenerate)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+		jobs = filterTestJobs(jobs, args, testTags, testExcludeTags)
+		if len(jobs) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: no tests matched (save requests, flows or suites in .falcon/, or use --generate)")
+			os.Exit(exitError)
+		}
+
+		start := time.Now()
+		run := newTestRun(testEnv, start, runTestJobs(jobs, testParallel, testFailFast))
+
+		// Keep stdout clean for machine-readable output written to "-".
+		summaryOut := os.Stdout
+		if testJUnit == "-" || testJSON == "-" || testMarkdown == "-" {
+			summaryOut = os.Stderr
+		}
+
+		summary := formatTestSummary(run)
+		reportPath, err := shared.NewReportWriter(falconDir).Write(testReportName, "test_run", formatTestMarkdown(run))
+		if err != nil {
+			summary += fmt.Sprintf("\n\nWarning: failed to save report: %v", err)
+		} else {
+			summary += fmt.Sprintf("\n\nReport saved to: %s", reportPath)
+		}
+		fmt.Fprintln(summaryOut, summary)
+
+		if err := writeTestOutputs(run, testJUnit, testJSON, testMarkdown); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(exitError)
+		}
+
+		if !run.Passed() {
+			os.Exit(exitFailed)
+		}
+	},
+}
+
+// testCase is one reported test: a saved request, a flow or suite step, or
+// a generated scenario.
+type testCase struct {
+	Suite      string `json:"suite"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// testJob is the unit of scheduling: a single request or scenario, or a
+// whole flow or suite whose steps must run in order.
+type testJob struct {
+	suite string
+	name  string
+	keys  []string // Names the job can be selected by (lowercase)
+	tags  []string
+	run   func() []testCase
+}
+
+// testCollector turns the files in .falcon/ into test jobs.
+type testCollector struct {
+	falconDir   string
+	baseURL     string // For relative request URLs and generated scenarios
+	flowBaseURL string // Overrides each flow's base_url when set
+	varStore    *shared.VariableStore
+	httpTool    *shared.HTTPTool
+	transport   shared.TransportConfig     // From the --env environment
+	runner      *persistence.RequestRunner // Sends saved requests with their hooks
+}
+
+// collect gathers jobs from the selected sources, in a stable order:
+// requests, flows, suites, then generated scenarios.
+func (c *testCollector) collect(only, generate []string) ([]testJob, error) {
+	sources := map[string]bool{"requests": true, "flows": true, "suites": true}
+	if len(only) > 0 {
+		sources = map[string]bool{}
+		for _, s := range only {
+			s = strings.ToLower(strings.TrimSpace(s))
+			if s != "requests" && s != "flows" && s != "suites" {
+				return nil, fmt.Errorf("unknown source '%s' (use: requests, flows, suites)", s)
+			}
+			sources[s] = true
+		}
+	}
+
+	var jobs []testJob
+	if sources["requests"] {
+		requestJobs, err := c.requestJobs()
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, requestJobs...)
+	}
+	if sources["flows"] {
+		flowJobs, err := c.flowJobs()
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, flowJobs...)
+	}
+	if sources["suites"] {
+		suiteJobs, err := c.suiteJobs()
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, suiteJobs...)
+	}
+	if len(generate) > 0 {
+		generatedJobs, err := c.generatedJobs(generate)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, generatedJobs...)
+	}
+	return jobs, nil
+}
+
+func (c *testCollector) requestJobs() ([]testJob, error) {
+	files, err := storage.ListRequests(c.falconDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []testJob
+	for _, file := range files {
+		key := strings.TrimSuffix(file, filepath.Ext(file))
+		path := filepath.Join(storage.GetRequestsDir(c.falconDir), file)
+		req, err := storage.LoadRequest(path)
+		if err != nil {
+			// Report the broken file as an errored test instead of aborting the run.
+			loadErr := err
+			jobs = append(jobs, testJob{suite: "requests", name: key, keys: []string{strings.ToLower(key)}, run: func() []testCase {
+				return []testCase{{Suite: "requests", Name: key, Status: caseError, Message: loadErr.Error()}}
+			}})
+			continue
+		}
+
+		name := req.Name
+		if name == "" {
+			name = key
+		}
+		jobs = append(jobs, testJob{
+			suite: "requests",
+			name:  name,
+			keys:  []string{strings.ToLower(key), strings.ToLower(name)},
+			tags:  req.Tags,
+			run: func() []testCase {
+				return []testCase{c.runSavedRequest(name, req)}
+			},
+		})
+	}
+	return jobs, nil
+}
+
+// runSavedRequest sends a saved request, with its pre-request and
+// post-response hooks, and checks its expectations. Without an expect block
+// or post-response assertions, any status below 400 passes.
+func (c *testCollector) runSavedRequest(name string, req *storage.Request) testCase {
+	tc := testCase{Suite: "requests", Name: name}
+
+	var expected shared.TestExpectation
+	if req.Expect != nil {
+		data, _ := json.Marshal(req.Expect)
+		if err := json.Unmarshal(data, &expected); err != nil {
+			tc.Status, tc.Message = caseError, fmt.Sprintf("invalid expect: %v", err)
+			return tc
+		}
+	}
+
+	start := time.Now()
+	run, err := c.runner.Send(req)
+	tc.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		tc.Status, tc.Message = caseError, err.Error()
+		return tc
+	}
+	resp := run.Response
+	tc.StatusCode = resp.StatusCode
+
+	failures := run.Failures
+	if req.Expect != nil {
+		failures = append(failures, shared.ValidateExpectations(expected, resp, tc.DurationMs)...)
+	} else if (req.Post == nil || req.Post.Assert == nil) && resp.StatusCode >= 400 {
+		failures = append(failures, fmt.Sprintf("HTTP %d", resp.StatusCode))
+	}
+	if len(failures) > 0 {
+		tc.Status, tc.Message = caseFailed, strings.Join(failures, "; ")
+		return tc
+	}
+	tc.Status = casePassed
+	return tc
+}
+
+func (c *testCollector) flowJobs() ([]testJob, error) {
+	names, err := flow_runner.ListFlows(c.falconDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []testJob
+	for _, name := range names {
+		suite := "flow:" + name
+		flow, err := flow_runner.LoadFlow(c.falconDir, name)
+		if err != nil {
+			loadErr := err
+			jobs = append(jobs, testJob{suite: suite, name: name, keys: []string{strings.ToLower(name)}, run: func() []testCase {
+				return []testCase{{Suite: suite, Name: name, Status: caseError, Message: loadErr.Error()}}
+			}})
+			continue
+		}
+		if c.flowBaseURL != "" {
+			flow.BaseURL = c.flowBaseURL
+		}
+
+		jobs = append(jobs, testJob{
+			suite: suite,
+			name:  name,
+			keys:  []string{strings.ToLower(name), strings.ToLower(flow.Name)},
+			tags:  flow.Tags,
+			run: func() []testCase {
+				result := flow_runner.NewRunner(c.falconDir, c.httpTool, c.varStore, nil).Run(context.Background(), flow, nil)
+				cases := make([]testCase, 0, len(result.Steps))
+				for _, step := range result.Steps {
+					label := step.Step
+					if step.Phase != "" {
+						label = step.Phase + ": " + label
+					}
+					tc := testCase{Suite: suite, Name: label, Message: step.Message, StatusCode: step.StatusCode, DurationMs: step.DurationMs}
+					switch step.Status {
+					case flow_runner.StatusPass:
+						tc.Status = casePassed
+					case flow_runner.StatusSkipped:
+						tc.Status = caseSkipped
+					default:
+						tc.Status = caseFailed
+					}
+					cases = append(cases, tc)
+				}
+				return cases
+			},
+		})
+	}
+	return jobs, nil
+}
+
+func (c *testCollector) suiteJobs() ([]testJob, error) {
+	dir := storage.GetSuitesDir(c.falconDir)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list suites: %w", err)
+	}
+
+	var jobs []testJob
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml" && ext != ".json") {
+			continue
+		}
+		key := strings.TrimSuffix(e.Name(), ext)
+		suite := "suite:" + key
+		params, err := shared.LoadSuiteFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			loadErr := err
+			jobs = append(jobs, testJob{suite: suite, name: key, keys: []string{strings.ToLower(key)}, run: func() []testCase {
+				return []testCase{{Suite: suite, Name: key, Status: caseError, Message: loadErr.Error()}}
+			}})
+			continue
+		}
+
+		jobs = append(jobs, testJob{
+			suite: suite,
+			name:  params.Name,
+			keys:  []string{strings.ToLower(key), strings.ToLower(params.Name)},
+			tags:  params.Tags,
+			run: func() []testCase {
+				return c.runSuite(suite, *params)
+			},
+		})
+	}
+	return jobs, nil
+}
+
+// runSuite runs a suite with its own response manager, so suites running in
+// parallel do not read each other's responses.
+func (c *testCollector) runSuite(suite string, params shared.TestSuiteParams) []testCase {
+	responseManager := shared.NewResponseManager()
+	httpTool := shared.NewHTTPTool(responseManager, c.varStore)
+	httpTool.SetTransport(c.transport)
+	tool := shared.NewTestSuiteTool(httpTool, shared.NewAssertTool(responseManager), shared.NewExtractTool(responseManager, c.varStore), responseManager, c.varStore, c.falconDir)
+
+	for i := range params.Tests {
+		req := &params.Tests[i].Request
+		if c.baseURL != "" && !strings.HasPrefix(req.URL, "http://") && !strings.HasPrefix(req.URL, "https://") && !strings.HasPrefix(req.URL, "{{") {
+			req.URL = strings.TrimSuffix(c.baseURL, "/") + "/" + strings.TrimPrefix(req.URL, "/")
+		}
+	}
+
+	result := tool.RunSuite(params)
+	cases := make([]testCase, 0, len(params.Tests))
+	for _, t := range result.Tests {
+		tc := testCase{Suite: suite, Name: t.Name, StatusCode: t.StatusCode, DurationMs: t.Duration.Milliseconds(), Status: casePassed}
+		if !t.Passed {
+			tc.Status, tc.Message = caseFailed, t.Error
+		}
+		cases = append(cases, tc)
+	}
+	// Tests after a failure with on_failure: stop were never run.
+	for _, t := range params.Tests[len(result.Tests):] {
+		cases = append(cases, testCase{Suite: suite, Name: t.Name, Status: caseSkipped, Message: "suite stopped after a failure"})
+	}
+	return cases
+}
+
+func (c *testCollector) generatedJobs(strategies []string) ([]testJob, error) {
+	if c.baseURL == "" {
+		return nil, fmt.Errorf("--generate needs --base-url or BASE_URL in the environment")
+	}
+
+	executor := shared.NewTestExecutor(c.httpTool)
+	var jobs []testJob
+	for _, strategy := range strategies {
+		strategy = strings.ToLower(strings.TrimSpace(strategy))
+		if strategy != "happy" && strategy != "negative" && strategy != "boundary" {
+			return nil, fmt.Errorf("unknown strategy '%s' (use: happy, negative, boundary)", strategy)
+		}
+		scenarios, _, err := functional_test_generator.GenerateScenarios(c.falconDir, c.baseURL, []string{strategy}, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		suite := "generated:" + strategy
+		for _, s := range scenarios {
+			scenario := s
+			jobs = append(jobs, testJob{
+				suite: suite,
+				name:  scenario.Name,
+				keys:  []string{strings.ToLower(scenario.ID), strings.ToLower(scenario.Name)},
+				tags:  []string{"generated", strategy, scenario.Category},
+				run: func() []testCase {
+					res := executor.RunScenario(scenario, "")
+					tc := testCase{Suite: suite, Name: scenario.Name, StatusCode: res.ActualStatus, DurationMs: res.DurationMs, Status: casePassed}
+					if !res.Passed {
+						tc.Status, tc.Message = caseFailed, res.Error
+					}
+					return []testCase{tc}
+				},
+			})
+		}
+	}
+	return jobs, nil
+}
+
+// filterTestJobs keeps jobs matching the given names (any, when empty) that
+// carry one of the wanted tags (any, when empty) and none of the excluded ones.
+func filterTestJobs(jobs []testJob, names, tags, excludeTags []string) []testJob {
+	var filtered []testJob
+	for _, job := range jobs {
+		if len(names) > 0 && !matchesAny(job.keys, names) {
+			continue
+		}
+		if len(tags) > 0 && !matchesAny(job.tags, tags) {
+			continue
+		}
+		if len(excludeTags) > 0 && matchesAny(job.tags, excludeTags) {
+			continue
+		}
+		filtered = append(filtered, job)
+	}
+	return filtered
+}
+
+// matchesAny reports whether any value equals any wanted entry, ignoring case
+// and a .yaml/.yml extension on the wanted entry.
+func matchesAny(values, wanted []string) bool {
+	for _, w := range wanted {
+		w = strings.ToLower(strings.TrimSuffix(strings.TrimSuffix(w, ".yaml"), ".yml"))
+		for _, v := range values {
+			if strings.ToLower(v) == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// runTestJobs runs jobs on up to parallel workers and returns their cases in
+// job order. With failFast, jobs not yet started after a failure are skipped.
+func runTestJobs(jobs []testJob, parallel int, failFast bool) []testCase {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	results := make([][]testCase, len(jobs))
+	var stopped atomic.Bool
+	var wg sync.WaitGroup
+	next := make(chan int)
+
+	for w := 0; w < min(parallel, len(jobs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				job := jobs[i]
+				if failFast && stopped.Load() {
+					results[i] = []testCase{{Suite: job.suite, Name: job.name, Status: caseSkipped, Message: "skipped after a failure (--fail-fast)"}}
+					continue
+				}
+				cases := job.run()
+				for _, tc := range cases {
+					if tc.Status == caseFailed || tc.Status == caseError {
+						stopped.Store(true)
+						break
+					}
+				}
+				results[i] = cases
+			}
+		}()
+	}
+	for i := range jobs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	var cases []testCase
+	for _, r := range results {
+		cases = append(cases, r...)
+	}
+	return cases
+}
+
+// testRun is the result of a falcon test invocation.
+type testRun struct {
+	Environment string     `json:"environment,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	DurationMs  int64      `json:"duration_ms"`
+	Total       int        `json:"total"`
+	PassedCount int        `json:"passed"`
+	Failed      int        `json:"failed"`
+	Errors      int        `json:"errors"`
+	Skipped     int        `json:"skipped"`
+	Cases       []testCase `json:"cases"`
+}
+
+func newTestRun(env string, start time.Time, cases []testCase) *testRun {
+	run := &testRun{Environment: env, StartedAt: start, DurationMs: time.Since(start).Milliseconds(), Total: len(cases), Cases: cases}
+	for _, tc := range cases {
+		switch tc.Status {
+		case casePassed:
+			run.PassedCount++
+		case caseFailed:
+			run.Failed++
+		case caseError:
+			run.Errors++
+		case caseSkipped:
+			run.Skipped++
+		}
+	}
+	return run
+}
+
+// Passed reports whether no test failed or errored. Skipped tests (flow
+// branches, --fail-fast) do not fail the run on their own.
+func (r *testRun) Passed() bool {
+	return r.Failed == 0 && r.Errors == 0
+}
+
+// suites returns the suite names in the order they first appear.
+func (r *testRun) suites() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, tc := range r.Cases {
+		if !seen[tc.Suite] {
+			seen[tc.Suite] = true
+			names = append(names, tc.Suite)
+		}
+	}
+	return names
+}
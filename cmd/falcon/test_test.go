@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata/")
+
+func TestFilterTestJobs(t *testing.T) {
+	jobs := []testJob{
+		{name: "login", keys: []string{"login", "requests/login"}, tags: []string{"smoke", "auth"}},
+		{name: "checkout", keys: []string{"checkout"}, tags: []string{"e2e"}},
+		{name: "orders", keys: []string{"orders"}, tags: []string{"smoke", "slow"}},
+		{name: "untagged", keys: []string{"untagged"}},
+	}
+
+	cases := []struct {
+		name                string
+		names, tags, ignore []string
+		want                []string
+	}{
+		{name: "no filters", want: []string{"login", "checkout", "orders", "untagged"}},
+		{name: "by name", names: []string{"checkout"}, want: []string{"checkout"}},
+		{name: "by file name", names: []string{"Login.yaml", "orders.yml"}, want: []string{"login", "orders"}},
+		{name: "by alternate key", names: []string{"requests/login"}, want: []string{"login"}},
+		{name: "by tag", tags: []string{"SMOKE"}, want: []string{"login", "orders"}},
+		{name: "exclude tag", ignore: []string{"slow"}, want: []string{"login", "checkout", "untagged"}},
+		{name: "tag and exclude", tags: []string{"smoke"}, ignore: []string{"auth"}, want: []string{"orders"}},
+		{name: "name and tag", names: []string{"checkout", "login"}, tags: []string{"e2e"}, want: []string{"checkout"}},
+		{name: "no match", names: []string{"missing"}, want: nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got []string
+			for _, job := range filterTestJobs(jobs, tc.names, tc.tags, tc.ignore) {
+				got = append(got, job.name)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// fakeJob returns a job whose cases all have status, after delay.
+func fakeJob(name, status string, delay time.Duration, running, peak *int32) testJob {
+	return testJob{suite: "requests", name: name, run: func() []testCase {
+		n := atomic.AddInt32(running, 1)
+		defer atomic.AddInt32(running, -1)
+		for p := atomic.LoadInt32(peak); n > p && !atomic.CompareAndSwapInt32(peak, p, n); p = atomic.LoadInt32(peak) {
+		}
+		time.Sleep(delay)
+		return []testCase{{Suite: "requests", Name: name, Status: status}}
+	}}
+}
+
+func caseSummary(cases []testCase) string {
+	parts := make([]string, len(cases))
+	for i, tc := range cases {
+		parts[i] = tc.Name + "=" + tc.Status
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestRunTestJobs(t *testing.T) {
+	t.Run("parallel keeps job order", func(t *testing.T) {
+		var running, peak int32
+		var jobs []testJob
+		for i := 0; i < 6; i++ {
+			// Earlier jobs take longer, so they finish last
+			jobs = append(jobs, fakeJob(fmt.Sprintf("job%d", i), casePassed, time.Duration(6-i)*10*time.Millisecond, &running, &peak))
+		}
+		jobs = append(jobs, testJob{suite: "flows", name: "flow", run: func() []testCase {
+			return []testCase{{Name: "flow step 1", Status: casePassed}, {Name: "flow step 2", Status: caseFailed}}
+		}})
+
+		got := caseSummary(runTestJobs(jobs, 3, false))
+		want := "job0=passed job1=passed job2=passed job3=passed job4=passed job5=passed flow step 1=passed flow step 2=failed"
+		if got != want {
+			t.Errorf("cases:\n got %s\nwant %s", got, want)
+		}
+		if p := atomic.LoadInt32(&peak); p != 3 {
+			t.Errorf("peak parallelism = %d, want 3", p)
+		}
+	})
+
+	t.Run("fail fast skips later jobs", func(t *testing.T) {
+		var running, peak int32
+		slowStarted := make(chan struct{})
+		broken, slow := fakeJob("broken", caseFailed, 0, &running, &peak), fakeJob("slow", casePassed, 20*time.Millisecond, &running, &peak)
+		runBroken, runSlow := broken.run, slow.run
+		// broken fails only once slow is running, so slow still completes
+		broken.run = func() []testCase { <-slowStarted; return runBroken() }
+		slow.run = func() []testCase { close(slowStarted); return runSlow() }
+		jobs := []testJob{
+			broken,
+			slow,
+			fakeJob("later1", casePassed, 0, &running, &peak),
+			fakeJob("later2", caseError, 0, &running, &peak),
+			fakeJob("later3", casePassed, 0, &running, &peak),
+		}
+
+		cases := runTestJobs(jobs, 2, true)
+		if got, want := caseSummary(cases), "broken=failed slow=passed later1=skipped later2=skipped later3=skipped"; got != want {
+			t.Errorf("cases:\n got %s\nwant %s", got, want)
+		}
+		if msg := cases[2].Message; !strings.Contains(msg, "--fail-fast") || cases[2].Suite != "requests" {
+			t.Errorf("skipped case = %+v", cases[2])
+		}
+	})
+
+	t.Run("without fail fast every job runs", func(t *testing.T) {
+		var running, peak int32
+		jobs := []testJob{
+			fakeJob("broken", caseError, 0, &running, &peak),
+			fakeJob("next", casePassed, 0, &running, &peak),
+		}
+		if got := caseSummary(runTestJobs(jobs, 0, false)); got != "broken=error next=passed" {
+			t.Errorf("cases = %s", got)
+		}
+		if p := atomic.LoadInt32(&peak); p != 1 {
+			t.Errorf("parallel 0 ran %d jobs at once, want 1", p)
+		}
+	})
+}
+
+func TestTestRun_Passed(t *testing.T) {
+	cases := []struct {
+		statuses []string
+		passed   bool
+	}{
+		{nil, true},
+		{[]string{casePassed, casePassed}, true},
+		{[]string{casePassed, caseSkipped}, true},
+		{[]string{caseSkipped}, true},
+		{[]string{casePassed, caseFailed}, false},
+		{[]string{caseError, caseSkipped}, false},
+	}
+	for _, tc := range cases {
+		var tcs []testCase
+		for _, s := range tc.statuses {
+			tcs = append(tcs, testCase{Status: s})
+		}
+		run := newTestRun("", time.Now(), tcs)
+		if run.Passed() != tc.passed {
+			t.Errorf("%v: Passed = %v, want %v", tc.statuses, run.Passed(), tc.passed)
+		}
+		if counted := run.PassedCount + run.Failed + run.Errors + run.Skipped; counted != run.Total || run.Total != len(tc.statuses) {
+			t.Errorf("%v: counted %d of total %d", tc.statuses, counted, run.Total)
+		}
+	}
+}
+
+func TestFormatJUnit_Golden(t *testing.T) {
+	run := newTestRun("staging", time.Date(2026, 3, 1, 9, 30, 0, 0, time.UTC), []testCase{
+		{Suite: "requests", Name: "login", Status: casePassed, StatusCode: 200, DurationMs: 120},
+		{Suite: "requests", Name: "get <user> & \"friends\"", Status: caseFailed, Message: "expected status 200, got 404\nbody: {\"error\": \"not found\"}", StatusCode: 404, DurationMs: 45},
+		{Suite: "flow: checkout", Name: "add item", Status: casePassed, DurationMs: 300},
+		{Suite: "flow: checkout", Name: "pay", Status: caseError, Message: "dial tcp: connection refused", DurationMs: 1001},
+		{Suite: "flow: checkout", Name: "confirm", Status: caseSkipped, Message: "previous step failed"},
+	})
+	run.DurationMs = 1466
+
+	got, err := formatJUnit(run)
+	if err != nil {
+		t.Fatalf("formatJUnit: %v", err)
+	}
+	golden := filepath.Join("testdata", "junit.golden.xml")
+	if *updateGolden {
+		if err := os.WriteFile(golden, got, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatalf("missing golden file (run with -update): %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("JUnit XML differs from %s:\n%s", golden, got)
+	}
+}
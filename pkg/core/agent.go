@@ -3,6 +3,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"sync"
 
@@ -13,13 +14,17 @@ import (
 // executes tools, and provides API debugging assistance.
 type Agent struct {
 	llmClient    llm.LLMClient
-	clientMu     sync.RWMutex // Protects access to llmClient
+	clientMu     sync.RWMutex // Protects access to llmClient and textProtocol
 	tools        map[string]Tool
 	toolsMu      sync.RWMutex // Protects access to tools map
 	history      []llm.Message
 	historyMu    sync.RWMutex // Protects access to history slice
 	lastResponse interface{}  // Store last tool response for chaining
 
+	// textProtocol is set once the current client reports that its model
+	// lacks native function calling; the ReAct loop then parses ACTION lines.
+	textProtocol bool
+
 	// History management
 	maxHistory int // maximum number of messages to keep in history (0 = unlimited)
 
@@ -28,6 +33,11 @@ type Agent struct {
 
 	// Persistent memory across sessions
 	memoryStore *MemoryStore
+
+	// Step and token limits for headless runs (zero = unlimited)
+	budget  Budget
+	usage   Usage
+	usageMu sync.Mutex // Protects access to budget and usage
 }
 
 // Default limits for history management.
@@ -66,6 +76,21 @@ func (a *Agent) ExecuteTool(toolName string, args string) (string, error) {
 	return tool.Execute(args)
 }
 
+// ExecuteToolContext executes a tool by name, passing ctx to tools that
+// implement ContextTool so a cancelled context stops them early.
+func (a *Agent) ExecuteToolContext(ctx context.Context, toolName string, args string) (string, error) {
+	a.toolsMu.RLock()
+	tool, ok := a.tools[toolName]
+	a.toolsMu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("tool '%s' not found", toolName)
+	}
+	if ct, ok := tool.(ContextTool); ok {
+		return ct.ExecuteContext(ctx, args)
+	}
+	return tool.Execute(args)
+}
+
 // SetLastResponse stores the last response from a tool for chaining.
 func (a *Agent) SetLastResponse(response interface{}) {
 	a.lastResponse = response
@@ -83,6 +108,23 @@ func (a *Agent) SwapLLMClient(client llm.LLMClient) {
 	a.clientMu.Lock()
 	defer a.clientMu.Unlock()
 	a.llmClient = client
+	a.textProtocol = false
+}
+
+// UsesNativeTools reports whether the ReAct loop sends tool schemas to the
+// model and reads structured tool calls, rather than parsing ACTION lines.
+func (a *Agent) UsesNativeTools() bool {
+	a.clientMu.RLock()
+	defer a.clientMu.RUnlock()
+	return !a.textProtocol
+}
+
+// disableNativeTools switches the agent to the text ACTION protocol for the
+// current client. It is reset when the client is swapped.
+func (a *Agent) disableNativeTools() {
+	a.clientMu.Lock()
+	defer a.clientMu.Unlock()
+	a.textProtocol = true
 }
 
 // SetFramework sets the user's API framework for context-aware assistance.
@@ -139,6 +181,16 @@ func (a *Agent) AppendHistoryPair(assistantMsg, observationMsg llm.Message) {
 	a.truncateHistory()
 }
 
+// AppendHistoryTurn adds a native tool-calling turn (the assistant message
+// carrying tool calls followed by one tool result per call) atomically.
+// This method is thread-safe.
+func (a *Agent) AppendHistoryTurn(msgs ...llm.Message) {
+	a.historyMu.Lock()
+	defer a.historyMu.Unlock()
+	a.history = append(a.history, msgs...)
+	a.truncateHistory()
+}
+
 // truncateHistory removes old messages if history exceeds maxHistory.
 // Keeps the most recent messages. If maxHistory is 0, no truncation occurs.
 // Caller must hold historyMu lock.
@@ -155,5 +207,11 @@ func (a *Agent) truncateHistory() {
 			// Remove from the beginning (oldest messages)
 			a.history = a.history[excess:]
 		}
+
+		// Never start history with tool results whose originating call was
+		// truncated away; providers reject orphaned tool messages.
+		for len(a.history) > 0 && a.history[0].Role == "tool" {
+			a.history = a.history[1:]
+		}
 	}
 }
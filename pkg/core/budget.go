@@ -0,0 +1,83 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/blackcoderx/falcon/pkg/llm"
+)
+
+// ErrBudgetExceeded is returned by the ReAct loop when the next LLM turn
+// would go over the limits set with SetBudget.
+var ErrBudgetExceeded = errors.New("agent budget exceeded")
+
+// Budget caps how much work the agent may do. Zero values mean unlimited.
+type Budget struct {
+	// MaxSteps is the maximum number of LLM turns (think-act-observe cycles).
+	MaxSteps int `json:"max_steps"`
+	// MaxTokens is the maximum number of estimated prompt and completion tokens.
+	MaxTokens int `json:"max_tokens"`
+}
+
+// Usage is the work the agent has done since the budget was last set.
+type Usage struct {
+	Steps  int `json:"steps"`
+	Tokens int `json:"tokens"`
+}
+
+// SetBudget sets the step and token limits and resets the usage counters.
+// Usage accumulates across messages, so one budget can cover a scripted
+// conversation. The TUI never sets a budget.
+func (a *Agent) SetBudget(budget Budget) {
+	a.usageMu.Lock()
+	defer a.usageMu.Unlock()
+	a.budget = budget
+	a.usage = Usage{}
+}
+
+// Usage returns the steps and estimated tokens used since SetBudget.
+func (a *Agent) Usage() Usage {
+	a.usageMu.Lock()
+	defer a.usageMu.Unlock()
+	return a.usage
+}
+
+// chargeTurn counts one LLM turn and its prompt before the request is sent.
+// It returns ErrBudgetExceeded instead of starting a turn over the limits.
+func (a *Agent) chargeTurn(messages []llm.Message) error {
+	a.usageMu.Lock()
+	defer a.usageMu.Unlock()
+
+	if a.budget.MaxSteps > 0 && a.usage.Steps >= a.budget.MaxSteps {
+		return fmt.Errorf("%w: reached the limit of %d steps", ErrBudgetExceeded, a.budget.MaxSteps)
+	}
+	tokens := 0
+	for _, msg := range messages {
+		tokens += estimateTokens(msg.Content)
+		for _, call := range msg.ToolCalls {
+			tokens += estimateTokens(call.Name + call.Arguments)
+		}
+	}
+	if a.budget.MaxTokens > 0 && a.usage.Tokens+tokens > a.budget.MaxTokens {
+		return fmt.Errorf("%w: next turn needs ~%d tokens, %d of %d already used", ErrBudgetExceeded, tokens, a.usage.Tokens, a.budget.MaxTokens)
+	}
+	a.usage.Steps++
+	a.usage.Tokens += tokens
+	return nil
+}
+
+// chargeCompletion adds the estimated tokens of a model response.
+func (a *Agent) chargeCompletion(content string, calls []llm.ToolCall) {
+	a.usageMu.Lock()
+	defer a.usageMu.Unlock()
+	a.usage.Tokens += estimateTokens(content)
+	for _, call := range calls {
+		a.usage.Tokens += estimateTokens(call.Name + call.Arguments)
+	}
+}
+
+// estimateTokens uses the same rough ratio as the prompt builder:
+// 1 token ≈ 4 characters.
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
@@ -20,6 +20,7 @@ type Builder struct {
 	memoryPreview   string
 	tools           map[string]Tool
 	useCompactTools bool // If true, use compact reference instead of full descriptions
+	nativeTools     bool // If true, tools are called via the provider's function-calling API
 }
 
 // NewBuilder creates a new prompt builder with configuration.
@@ -65,6 +66,13 @@ func (b *Builder) UseFullToolDescriptions() *Builder {
 	return b
 }
 
+// WithNativeTools selects the output format for models that call tools through
+// native function calling instead of ACTION lines.
+func (b *Builder) WithNativeTools(native bool) *Builder {
+	b.nativeTools = native
+	return b
+}
+
 // Build constructs the final system prompt.
 // The order is critical - most important sections first.
 func (b *Builder) Build() string {
@@ -99,7 +107,11 @@ func (b *Builder) Build() string {
 	sb.WriteString("\n")
 
 	// 6. Output Format - HOW to respond (always last)
-	sb.WriteString(OutputFormat)
+	if b.nativeTools {
+		sb.WriteString(NativeOutputFormat)
+	} else {
+		sb.WriteString(OutputFormat)
+	}
 
 	return sb.String()
 }
@@ -37,7 +37,7 @@ Final Answer: [Concise response to the user]
 
 ## Rules
 
-1. **One tool per response** — call exactly one tool, then wait for the observation
+1. **One tool per response** — only the first ACTION line runs, so call exactly one tool, then wait for the observation
 2. **Always think first** — your Thought should state your hypothesis before the ACTION
 3. **ACTION on its own line** — no text on the same line after the closing parenthesis
 4. **JSON must use double quotes** — no single quotes, no trailing commas, no comments
@@ -60,7 +60,7 @@ ACTION: http_request({"method": "GET", "url": "{{BASE_URL}}/users"})
 **Good** — always assert after receiving a response:
 ` + "```" + `
 Thought: Got 200. Let me verify the response body has the expected shape.
-ACTION: assert_response({"status_code": 200, "json_path": "$[0].id"})
+ACTION: assert_response({"status_code": 200, "json_path": {"$": {"$type": "array"}, "$[0].id": {"$exists": true}}})
 ` + "```" + `
 
 **Bad** — no thought, just calling:
@@ -109,3 +109,57 @@ When reporting failures:
 Be concise and actionable.
 
 `
+
+// NativeOutputFormat replaces OutputFormat when the model supports native
+// tool calling. Tools are invoked through the provider's function-calling API,
+// so the ACTION line syntax is not needed.
+const NativeOutputFormat = `# OUTPUT FORMAT
+
+## The ReAct Cycle
+
+You operate in a loop: **Think → Act → Observe → Repeat**.
+
+Tools are available to you as native function calls. To use a tool, call it
+through the function-calling interface — do NOT write ` + "`" + `ACTION:` + "`" + ` lines in your text.
+
+Before each tool call, write a short Thought stating your hypothesis:
+
+` + "```" + `
+Thought: [What do I know? What am I testing? What do I expect?]
+` + "```" + `
+
+The tool result is returned to you as the observation. When done, call
+` + "`" + `session_log` + "`" + ` with ` + "`" + `{"action":"end", "summary":"..."}` + "`" + `, then reply with text only:
+
+` + "```" + `
+Final Answer: [Concise response to the user]
+` + "```" + `
+
+## Rules
+
+1. **Independent calls only** — every tool call in a turn runs, in order, before you see any result; when a call needs an earlier result (an extracted token, an ID), make it in the next turn
+2. **Always think first** — state your hypothesis before calling a tool
+3. **Arguments must match the tool schema** — pass a JSON object, never a string
+4. **A reply without a tool call ends the loop** — only do this for the Final Answer
+
+## Final Answer — When and How to Stop
+
+Write ` + "`" + `Final Answer:` + "`" + ` when **at least one** of these is true:
+1. You have a direct, evidence-backed answer to the user's question
+2. You have completed all the steps the user asked for
+3. You have hit a dead end and need the user's input to proceed further
+4. You have called 3 or more tools without getting closer to the answer — stop and report what you found so far
+
+A good Final Answer says what you did, what you found, what it means, and what's next.
+Do not speculate about results you didn't observe.
+
+## Diagnosis Format
+
+When reporting failures:
+- **File**: path/to/file.go:42
+- **Cause**: Missing validation for 'email' field
+- **Fix**: Add email format validator
+
+Be concise and actionable.
+
+`
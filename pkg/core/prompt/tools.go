@@ -34,7 +34,7 @@ func BuildToolsSection(tools map[string]Tool) string {
 		case "http_request", "variable", "auth", "wait", "retry":
 			domains["Core"] = append(domains["Core"], tool)
 
-		case "request", "environment", "falcon_write", "falcon_read", "memory", "session_log":
+		case "request", "environment", "cookies", "falcon_write", "falcon_read", "memory", "session_log":
 			domains["Persistence"] = append(domains["Persistence"], tool)
 
 		case "ingest_spec":
@@ -43,7 +43,7 @@ func BuildToolsSection(tools map[string]Tool) string {
 		case "assert_response", "extract_value", "validate_json_schema":
 			domains["Unit"] = append(domains["Unit"], tool)
 
-		case "orchestrate_integration":
+		case "orchestrate_integration", "run_flow":
 			domains["Integration"] = append(domains["Integration"], tool)
 
 		case "run_smoke":
@@ -61,7 +61,7 @@ func BuildToolsSection(tools map[string]Tool) string {
 		case "scan_security":
 			domains["Security"] = append(domains["Security"], tool)
 
-		case "find_handler", "analyze_endpoint", "analyze_failure", "propose_fix",
+		case "find_handler", "check_spec_drift", "analyze_endpoint", "analyze_failure", "propose_fix",
 			"create_test_file", "read_file", "search_code", "write_file", "list_files":
 			domains["Debugging"] = append(domains["Debugging"], tool)
 
@@ -100,36 +100,40 @@ const CompactToolReference = `# QUICK TOOL REFERENCE
 ## By Intent
 | Intent | Tool | Key Params |
 |--------|------|------------|
-| Make API call | http_request | method, url, headers?, body? |
+| Make API call | http_request | method, url, headers?, query?, body?, body_type? (json\|form\|multipart\|text\|xml\|binary), body_file?, session? (cookie jar), follow_redirects?, max_redirects?, transport? (ca_cert, client_cert, client_key, insecure_skip_verify, proxy, http_version), contract? (check against spec) |
 | Set/get variable | variable | action="set\|get", name, value, scope |
 | Authenticate | auth | action="bearer\|basic\|oauth2\|parse_jwt", token/credentials |
 | Delay | wait | seconds |
 | Retry a tool | retry | tool, args, max_attempts |
-| Save/load/list requests | request | action="save\|load\|list", name?, method?, url? |
-| Manage environments | environment | action="set\|list", name?, variables? |
+| Save/load/run/list requests | request | action="save\|load\|run\|list", name?, method?, url?, tags?, expect?, pre?, post? |
+| Manage environments | environment | action="get\|set\|list", name?, variables?, transport? (TLS, mTLS, proxy, HTTP version for every request) |
+| Manage cookie sessions | cookies | action="list\|show\|save\|load\|clear\|delete", session? |
 | Write to .falcon/ | falcon_write | path, content, format="yaml\|json\|markdown" |
 | Read from .falcon/ | falcon_read | path, format="raw\|yaml\|json" |
 | Session audit | session_log | action="start\|end\|list\|read", summary? |
 | Save/recall API knowledge | memory | action="save\|recall\|forget\|list\|update_knowledge" |
-| Parse OpenAPI/Postman spec | ingest_spec | file_path, format |
-| Assert HTTP response | assert_response | status_code?, body_contains?, json_path? |
+| Parse OpenAPI/Postman spec, or import HAR, Insomnia, Bruno, curl or GraphQL (also seeds .falcon/requests/) | ingest_spec | source, format |
+| Assert HTTP response | assert_response | status_code?, body_contains?, json_path? ({path: value or {$gt,$regex,$type,$len,$exists,...}}) |
 | Extract value from response | extract_value | json_path/header/cookie/regex, save_as |
 | Validate JSON schema | validate_json_schema | schema |
 | Compare two responses | compare_responses | response_a, response_b |
-| Check regression baseline | check_regression | baseline, current |
+| Check regression baseline | check_regression | base_url, baseline_name, version?, ignore_paths?, latency_tolerance? |
+| Capture/manage baselines | check_regression | action="capture\|list\|delete\|promote", baseline_name, version?, endpoints? |
 | Verify idempotency | verify_idempotency | endpoint, method |
 | Generate functional tests | generate_functional_tests | strategy (happy/negative/boundary/all) |
-| Run test scenarios | run_tests | scenarios, base_url, scenario? (optional single) |
+| Run test scenarios | run_tests | scenarios, base_url, scenario? (optional single), contract? |
 | Data-driven test | run_data_driven | endpoint, data_file |
 | Auto full test flow | auto_test | endpoint, base_url |
 | Fix and verify loop | auto_fix | endpoint, base_url, expected_status?, max_attempts? |
-| Smoke test | run_smoke | - |
-| Integration workflow | orchestrate_integration | workflow |
+| Smoke test | run_smoke | base_url, endpoints?, contract? |
+| Integration workflow | orchestrate_integration | workflow, teardown?, variables?, base_url |
+| Run saved flow (no LLM) | run_flow | flow (name in .falcon/flows/), variables?, base_url? |
 | Test suite | test_suite | name, tests |
-| Load/stress test | run_performance | mode (load/stress/spike/soak), duration_seconds, users |
+| Load/stress test | run_performance | mode (load/stress/spike/soak), base_url, concurrency, duration_sec, rps? (open model), stages?, limits?, thresholds? (e.g. "p95<300ms"), requests? (saved/inline, weight), headers?, data? |
 | Webhook capture | webhook_listener | port?, timeout? |
-| Security scan | scan_security | type (owasp/fuzz/auth/all) |
+| Security scan | scan_security | type (owasp/fuzz/auth/all), auth_token? (JWT attacks), jwt_wordlist?, jwt_public_key? |
 | Find handler in code | find_handler | endpoint, method |
+| Diff code routes against the spec | check_spec_drift | framework?, path? |
 | Analyze endpoint code | analyze_endpoint | endpoint |
 | Diagnose test failure | analyze_failure | test_results |
 | Propose code fix | propose_fix | file, vulnerability_description |
@@ -141,14 +145,14 @@ const CompactToolReference = `# QUICK TOOL REFERENCE
 
 ## By Domain
 **Core**: http_request, variable, auth, wait, retry
-**Persistence**: request, environment, falcon_write, falcon_read, memory, session_log
+**Persistence**: request, environment, cookies, falcon_write, falcon_read, memory, session_log
 **Spec**: ingest_spec
 **Unit/Functional Testing**: assert_response, extract_value, validate_json_schema, generate_functional_tests, run_tests, run_data_driven
 **Contract Testing**: compare_responses, check_regression, verify_idempotency
-**Integration/E2E**: orchestrate_integration, auto_test, auto_fix, test_suite
+**Integration/E2E**: orchestrate_integration, run_flow, auto_test, auto_fix, test_suite
 **Smoke**: run_smoke
 **Performance**: run_performance, webhook_listener
 **Security**: scan_security
-**Debugging**: find_handler, analyze_endpoint, analyze_failure, propose_fix, create_test_file, read_file, search_code, write_file, list_files
+**Debugging**: find_handler, check_spec_drift, analyze_endpoint, analyze_failure, propose_fix, create_test_file, read_file, search_code, write_file, list_files
 
 `
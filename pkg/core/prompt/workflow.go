@@ -58,7 +58,7 @@ When the user asks to "test" an API or endpoint, identify the intent first. Neve
 | "Is the API up?" / "Quick health check" | Smoke | run_smoke |
 | "Test happy path, bad inputs, edge cases" | Functional | generate_functional_tests → run_tests |
 | "Test the full user signup journey end-to-end" | E2E | orchestrate_integration (full session scope) |
-| "Does the response match the OpenAPI spec?" | Contract | ingest_spec → validate_json_schema / check_regression |
+| "Does the response match the OpenAPI spec?" | Contract | ingest_spec → http_request / run_tests / run_smoke with contract=true |
 | "How does it handle 100 concurrent users?" | Performance | run_performance |
 | "Check for auth bypass / OWASP vulnerabilities" | Security | scan_security |
 
@@ -135,7 +135,7 @@ After every observation:
 | Working request with headers/body | request(action="save") |
 | Auth token for this session | variable(scope="session") |
 | Reusable config across sessions | variable(scope="global") |
-| Test flow for reuse | falcon_write(path="flows/<type>_<description>.yaml") |
+| Test flow for reuse | falcon_write(path="flows/<type>_<description>.yaml"), then run_flow |
 
 **falcon.md vs memory.json:**
 - **falcon.md** is the API encyclopedia — endpoints, schemas, auth flows, error patterns
@@ -151,7 +151,7 @@ Common sources of confusion — read this before picking a tool:
   - auth(action="bearer", token="...") | auth(action="basic", username, password) | auth(action="oauth2", ...) | auth(action="parse_jwt", token="...")
 
 - **request** replaces save_request, load_request, list_requests:
-  - request(action="save", name, method, url) | request(action="load", name) | request(action="list")
+  - request(action="save", name, method, url, pre?, post?) | request(action="load", name) | request(action="run", name) | request(action="list")
 
 - **environment** replaces set_environment, list_environments:
   - environment(action="set", name, variables?) | environment(action="list")
@@ -211,7 +211,7 @@ memory(action="save")             → writes .falcon/memory.json
 memory(action="recall")           → reads  .falcon/memory.json + falcon.md
 memory(action="update_knowledge") → writes .falcon/falcon.md (validated)
 check_regression                  → reads + writes .falcon/baselines/
-ingest_spec                       → writes .falcon/spec.yaml
+ingest_spec                       → writes .falcon/spec.yaml (+ .falcon/requests/ for HAR/Insomnia/Bruno/curl/GraphQL imports)
 scan_security                     → writes .falcon/reports/security_report_<api>_<ts>.md
 run_performance                   → writes .falcon/reports/performance_report_<api>_<ts>.md
 generate_functional_tests         → writes .falcon/reports/functional_report_<api>_<ts>.md
@@ -232,6 +232,7 @@ run_tests                         → writes .falcon/reports/unit_report_<name>.
 | Check for regressions | check_regression | compare_responses |
 | Set up authentication | auth(action="bearer") or auth(action="oauth2") | variable(scope="session") |
 | Explore codebase | search_code → read_file | find_handler |
+| Spec out of date? | ingest_spec → check_spec_drift | find_handler → read_file |
 | Smoke test all endpoints | ingest_spec → run_smoke | analyze_failure |
 | Integration flow | orchestrate_integration | run_tests(flows/integration_*.yaml) |
 
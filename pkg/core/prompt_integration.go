@@ -28,7 +28,8 @@ func (a *Agent) preparePromptBuilder() *prompt.Builder {
 		WithFramework(a.framework).
 		WithManifestSummary(manifestSummary).
 		WithMemoryPreview(memoryPreview).
-		WithTools(promptTools)
+		WithTools(promptTools).
+		WithNativeTools(a.UsesNativeTools())
 }
 
 // buildSystemPrompt constructs the complete system prompt for the LLM.
@@ -2,6 +2,7 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -23,6 +24,27 @@ func (a *Agent) ProcessMessage(input string) (string, error) {
 		messages := []llm.Message{{Role: "system", Content: systemPrompt}}
 		messages = append(messages, a.history...)
 
+		if err := a.chargeTurn(messages); err != nil {
+			return "", err
+		}
+
+		// Prefer native tool calling; fall back to the text protocol for
+		// models without function calling (the prompt is rebuilt on retry).
+		if a.UsesNativeTools() {
+			answer, done, err := a.runNativeTurn(context.Background(), messages, nil)
+			if errors.Is(err, llm.ErrToolsUnsupported) {
+				a.disableNativeTools()
+				continue
+			}
+			if err != nil {
+				return "", err
+			}
+			if done {
+				return answer, nil
+			}
+			continue
+		}
+
 		// Get LLM response with silent retry (up to 3 attempts, exponential backoff).
 		// Retries on both hard errors AND empty responses.
 		const maxRetries = 3
@@ -43,27 +65,11 @@ func (a *Agent) ProcessMessage(input string) (string, error) {
 		if response == "" {
 			return fmt.Sprintf("I received an empty response from the AI after %d attempts. The model may be overloaded or unavailable.", maxRetries), nil
 		}
+		a.chargeCompletion(response, nil)
 
-		// Parse response for thoughts and tool calls
-		_, toolName, toolArgs, finalAnswer := a.parseResponse(response)
-
-		if finalAnswer != "" && toolName == "" {
-			a.AppendHistory(llm.Message{Role: "assistant", Content: response})
-			return finalAnswer, nil
-		}
-
-		if toolName != "" {
-			// Execute tool with common logic
-			observation := a.executeTool(toolName, toolArgs, nil)
-
-			// Add interaction to history
-			a.appendReActTurn(response, observation)
-			continue
+		if answer, done := a.handleTextResponse(response, nil); done {
+			return answer, nil
 		}
-
-		// If we get here, we have a final answer (possibly via default in parseResponse)
-		a.AppendHistory(llm.Message{Role: "assistant", Content: response})
-		return finalAnswer, nil
 	}
 }
 
@@ -93,6 +99,28 @@ func (a *Agent) ProcessMessageWithEvents(ctx context.Context, input string, call
 		messages := []llm.Message{{Role: "system", Content: systemPrompt}}
 		messages = append(messages, a.history...)
 
+		if err := a.chargeTurn(messages); err != nil {
+			callback(AgentEvent{Type: "error", Content: fmt.Sprintf("Stopped: %v", err)})
+			return "", err
+		}
+
+		// Prefer native tool calling; fall back to the text protocol for
+		// models without function calling (the prompt is rebuilt on retry).
+		if a.UsesNativeTools() {
+			answer, done, err := a.runNativeTurn(ctx, messages, callback)
+			if errors.Is(err, llm.ErrToolsUnsupported) {
+				a.disableNativeTools()
+				continue
+			}
+			if err != nil {
+				return "", err
+			}
+			if done {
+				return answer, nil
+			}
+			continue
+		}
+
 		// Get LLM response with streaming
 		var response string
 		var streamErr error
@@ -138,35 +166,42 @@ func (a *Agent) ProcessMessageWithEvents(ctx context.Context, input string, call
 			callback(AgentEvent{Type: "error", Content: errorMsg})
 			return "I received an empty response from the AI after retrying.", nil
 		}
+		a.chargeCompletion(response, nil)
 
-		// Parse response for thoughts and tool calls
-		thought, toolName, toolArgs, finalAnswer := a.parseResponse(response)
-
-		// If we got a thought (and it's different from the streamed content), emit it
-		if thought != "" && thought != response {
-			callback(AgentEvent{Type: "thinking", Content: thought})
+		if answer, done := a.handleTextResponse(response, callback); done {
+			return answer, nil
 		}
+	}
+}
 
-		if finalAnswer != "" && toolName == "" {
-			a.AppendHistory(llm.Message{Role: "assistant", Content: response})
-			callback(AgentEvent{Type: "answer", Content: finalAnswer})
-			return finalAnswer, nil
-		}
+// handleTextResponse applies the text ACTION protocol to a model response.
+// If the response contains a tool call, the tool is executed and the turn is
+// recorded (done=false). Otherwise the response is the final answer (done=true).
+// callback may be nil for the blocking loop.
+func (a *Agent) handleTextResponse(response string, callback EventCallback) (answer string, done bool) {
+	// Parse response for thoughts and tool calls
+	thought, toolName, toolArgs, finalAnswer := a.parseResponse(response)
+
+	// If we got a thought (and it's different from the streamed content), emit it
+	if callback != nil && thought != "" && thought != response {
+		callback(AgentEvent{Type: "thinking", Content: thought})
+	}
 
-		if toolName != "" {
-			// Execute tool with events
-			observation := a.executeTool(toolName, toolArgs, callback)
+	if toolName != "" {
+		// Execute tool with common logic
+		observation := a.executeTool(toolName, toolArgs, callback)
 
-			// Add interaction to history
-			a.appendReActTurn(response, observation)
-			continue
-		}
+		// Add interaction to history
+		a.appendReActTurn(response, observation)
+		return "", false
+	}
 
-		// If we get here, we have a final answer
-		a.AppendHistory(llm.Message{Role: "assistant", Content: response})
+	// No tool call: final answer (possibly via default in parseResponse)
+	a.AppendHistory(llm.Message{Role: "assistant", Content: response})
+	if callback != nil {
 		callback(AgentEvent{Type: "answer", Content: finalAnswer})
-		return finalAnswer, nil
 	}
+	return finalAnswer, true
 }
 
 // parseResponse extracts structured components from an LLM response.
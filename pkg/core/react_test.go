@@ -1,6 +1,7 @@
 package core
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/blackcoderx/falcon/pkg/llm"
@@ -340,3 +341,137 @@ func TestUnlimitedHistory(t *testing.T) {
 		t.Errorf("history length = %d, want 200 (unlimited)", len(history))
 	}
 }
+
+// mockLLMClient implements llm.LLMClient with scripted native tool responses.
+type mockLLMClient struct {
+	toolResponses []*llm.ToolChatResponse
+	toolErr       error
+	textResponses []string
+	calls         int
+}
+
+func (m *mockLLMClient) Chat(messages []llm.Message) (string, error) {
+	resp := m.textResponses[0]
+	m.textResponses = m.textResponses[1:]
+	return resp, nil
+}
+
+func (m *mockLLMClient) ChatStream(messages []llm.Message, callback llm.StreamCallback) (string, error) {
+	return m.Chat(messages)
+}
+
+func (m *mockLLMClient) ChatWithTools(messages []llm.Message, tools []llm.ToolDefinition, callback llm.StreamCallback) (*llm.ToolChatResponse, error) {
+	m.calls++
+	if m.toolErr != nil {
+		return nil, m.toolErr
+	}
+	resp := m.toolResponses[0]
+	m.toolResponses = m.toolResponses[1:]
+	return resp, nil
+}
+
+func (m *mockLLMClient) CheckConnection() error { return nil }
+func (m *mockLLMClient) GetModel() string       { return "mock" }
+
+func TestProcessMessage_NativeToolCalls(t *testing.T) {
+	var gotArgs string
+	client := &mockLLMClient{toolResponses: []*llm.ToolChatResponse{
+		{Content: "Thought: fetch users", ToolCalls: []llm.ToolCall{{ID: "call_1", Name: "http_request", Arguments: `{"method":"GET"}`}}},
+		{Content: "Final Answer: done"},
+	}}
+	agent := NewAgent(client)
+	agent.RegisterTool(&mockTool{name: "http_request", executeFunc: func(args string) (string, error) {
+		gotArgs = args
+		return "200 OK", nil
+	}})
+
+	answer, err := agent.ProcessMessage("get users")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if answer != "done" {
+		t.Errorf("answer = %q, want %q", answer, "done")
+	}
+	if gotArgs != `{"method":"GET"}` {
+		t.Errorf("tool args = %q", gotArgs)
+	}
+
+	history := agent.GetHistory()
+	if len(history) != 4 {
+		t.Fatalf("history length = %d, want 4", len(history))
+	}
+	if len(history[1].ToolCalls) != 1 || history[2].Role != "tool" || history[2].ToolCallID != "call_1" {
+		t.Errorf("native turn not recorded: %+v", history[1:3])
+	}
+}
+
+func TestProcessMessage_FallsBackToTextProtocol(t *testing.T) {
+	client := &mockLLMClient{
+		toolErr:       llm.ErrToolsUnsupported,
+		textResponses: []string{`ACTION: read_file({"path": "main.go"})`, "Final Answer: read it"},
+	}
+	agent := NewAgent(client)
+	agent.RegisterTool(&mockTool{name: "read_file"})
+
+	answer, err := agent.ProcessMessage("read main.go")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if answer != "read it" {
+		t.Errorf("answer = %q, want %q", answer, "read it")
+	}
+	if agent.UsesNativeTools() {
+		t.Error("expected agent to switch to the text protocol")
+	}
+	if client.calls != 1 {
+		t.Errorf("ChatWithTools called %d times, want 1", client.calls)
+	}
+}
+
+func TestFlattenToolMessages(t *testing.T) {
+	flat := llm.FlattenToolMessages([]llm.Message{
+		{Role: "assistant", Content: "Thought: x", ToolCalls: []llm.ToolCall{{Name: "read_file", Arguments: `{"path":"a"}`}}},
+		{Role: "tool", Content: "contents", ToolName: "read_file"},
+	})
+
+	if flat[0].Content != "Thought: x\nACTION: read_file({\"path\":\"a\"})" {
+		t.Errorf("assistant content = %q", flat[0].Content)
+	}
+	if flat[1].Role != "user" || flat[1].Content != "Observation: contents" {
+		t.Errorf("tool message = %+v", flat[1])
+	}
+}
+
+func TestProcessMessage_StopsAtStepBudget(t *testing.T) {
+	client := &mockLLMClient{toolResponses: []*llm.ToolChatResponse{
+		{Content: "Thought: look", ToolCalls: []llm.ToolCall{{ID: "call_1", Name: "read_file", Arguments: `{}`}}},
+		{Content: "Final Answer: done"},
+	}}
+	agent := NewAgent(client)
+	agent.RegisterTool(&mockTool{name: "read_file"})
+	agent.SetBudget(Budget{MaxSteps: 1})
+
+	_, err := agent.ProcessMessage("read it")
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("err = %v, want ErrBudgetExceeded", err)
+	}
+	if usage := agent.Usage(); usage.Steps != 1 || usage.Tokens == 0 {
+		t.Errorf("usage = %+v, want 1 step and a token estimate", usage)
+	}
+	if client.calls != 1 {
+		t.Errorf("ChatWithTools called %d times, want 1", client.calls)
+	}
+}
+
+func TestProcessMessage_StopsAtTokenBudget(t *testing.T) {
+	client := &mockLLMClient{toolResponses: []*llm.ToolChatResponse{{Content: "Final Answer: done"}}}
+	agent := NewAgent(client)
+	agent.SetBudget(Budget{MaxTokens: 10})
+
+	if _, err := agent.ProcessMessage("hello"); !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("err = %v, want ErrBudgetExceeded (system prompt alone exceeds 10 tokens)", err)
+	}
+	if client.calls != 0 {
+		t.Errorf("expected no LLM call over budget, got %d", client.calls)
+	}
+}
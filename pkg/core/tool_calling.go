@@ -0,0 +1,133 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/blackcoderx/falcon/pkg/llm"
+)
+
+// toolDefinitions builds the native tool declarations sent to the model.
+// Tools are sorted by name so the request is stable across turns.
+func (a *Agent) toolDefinitions() []llm.ToolDefinition {
+	a.toolsMu.RLock()
+	defer a.toolsMu.RUnlock()
+
+	defs := make([]llm.ToolDefinition, 0, len(a.tools))
+	for _, tool := range a.tools {
+		defs = append(defs, llm.ToolDefinition{
+			Name:        tool.Name(),
+			Description: tool.Description(),
+			Parameters:  toolSchema(tool),
+		})
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// toolSchema returns the JSON Schema for a tool's arguments. Tools without an
+// explicit schema get an open object whose description carries the
+// Parameters() hint, which is the same text the ACTION protocol relies on.
+func toolSchema(tool Tool) map[string]interface{} {
+	if st, ok := tool.(SchemaTool); ok {
+		if schema := st.InputSchema(); schema != nil {
+			return schema
+		}
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"description":          "Arguments: " + tool.Parameters(),
+		"additionalProperties": true,
+	}
+}
+
+// runNativeTurn performs one think-act-observe step using native tool calling.
+// It returns done=true with the final answer when the model replies without a
+// tool call. llm.ErrToolsUnsupported is passed through so the caller can switch
+// to the text protocol. callback may be nil for the blocking loop.
+func (a *Agent) runNativeTurn(ctx context.Context, messages []llm.Message, callback EventCallback) (answer string, done bool, err error) {
+	var streamCallback llm.StreamCallback
+	if callback != nil {
+		streamCallback = func(chunk string) {
+			callback(AgentEvent{Type: "streaming", Content: chunk})
+		}
+	}
+
+	defs := a.toolDefinitions()
+
+	// Same retry policy as the text protocol: up to 3 attempts with
+	// exponential backoff, retrying on errors and on empty responses.
+	const maxRetries = 3
+	var resp *llm.ToolChatResponse
+	for attempt := 1; attempt <= maxRetries; attempt++ {
+		resp, err = a.LLMClient().ChatWithTools(messages, defs, streamCallback)
+		if errors.Is(err, llm.ErrToolsUnsupported) {
+			return "", false, err
+		}
+		if err == nil && (resp.Content != "" || len(resp.ToolCalls) > 0) {
+			break
+		}
+		if attempt < maxRetries {
+			retryDelay := time.Duration(1<<uint(attempt-1)) * 2 * time.Second
+			if callback != nil {
+				reason := "received empty response from AI (model may have crashed or timed out)"
+				if err != nil {
+					reason = fmt.Sprintf("LLM call failed: %v", err)
+				}
+				callback(AgentEvent{Type: "retrying", Content: fmt.Sprintf("%s (attempt %d/%d). Retrying in %s...", reason, attempt, maxRetries, retryDelay)})
+			}
+			select {
+			case <-ctx.Done():
+				return "", false, ctx.Err()
+			case <-time.After(retryDelay):
+			}
+			if callback != nil {
+				callback(AgentEvent{Type: "thinking", Content: fmt.Sprintf("reconnecting (attempt %d/%d)...", attempt+1, maxRetries)})
+			}
+		}
+	}
+	if err != nil {
+		if callback != nil {
+			callback(AgentEvent{Type: "error", Content: fmt.Sprintf("Connection Error: Could not talk to the AI provider after %d attempts.\nDetails: %v\n\nTip: Check if Ollama is running (try 'ollama serve') or check your API key.", maxRetries, err)})
+		}
+		return "", false, fmt.Errorf("agent chat error: %w", err)
+	}
+	if resp.Content == "" && len(resp.ToolCalls) == 0 {
+		if callback != nil {
+			callback(AgentEvent{Type: "error", Content: fmt.Sprintf("Received an empty response from the AI after %d attempts. The model may be overloaded or unavailable.", maxRetries)})
+		}
+		return fmt.Sprintf("I received an empty response from the AI after %d attempts. The model may be overloaded or unavailable.", maxRetries), true, nil
+	}
+
+	a.chargeCompletion(resp.Content, resp.ToolCalls)
+
+	// No structured call: the model either answered or (occasionally) wrote a
+	// text ACTION line anyway, which the text protocol still understands.
+	if len(resp.ToolCalls) == 0 {
+		answer, done = a.handleTextResponse(resp.Content, callback)
+		return answer, done, nil
+	}
+
+	if callback != nil {
+		if thought := extractThought(resp.Content); thought != "" {
+			callback(AgentEvent{Type: "thinking", Content: thought})
+		}
+	}
+
+	turn := []llm.Message{{Role: "assistant", Content: resp.Content, ToolCalls: resp.ToolCalls}}
+	for _, call := range resp.ToolCalls {
+		observation := a.executeTool(call.Name, call.Arguments, callback)
+		turn = append(turn, llm.Message{
+			Role:       "tool",
+			Content:    observation,
+			ToolCallID: call.ID,
+			ToolName:   call.Name,
+		})
+	}
+	a.AppendHistoryTurn(turn...)
+
+	return "", false, nil
+}
@@ -34,6 +34,7 @@ type RunTestsParams struct {
 	Concurrency int                   `json:"concurrency,omitempty"`
 	TimeoutMs   int                   `json:"timeout_ms,omitempty"`
 	ReportName  string                `json:"report_name,omitempty"` // e.g. "test_report_users_api"
+	Contract    bool                  `json:"contract,omitempty"`    // validate responses against .falcon/spec.yaml
 }
 
 func (t *RunTestsTool) Name() string {
@@ -51,7 +52,8 @@ func (t *RunTestsTool) Parameters() string {
   "category": "security",
   "categories": ["security", "validation"],
   "concurrency": 5,
-  "timeout_ms": 30000
+  "timeout_ms": 30000,
+  "contract": true
 }`
 }
 
@@ -95,7 +97,13 @@ func (t *RunTestsTool) Execute(args string) (string, error) {
 		concurrency = 5
 	}
 
-	results := t.testExecutor.RunScenarios(scenariosToRun, params.BaseURL, concurrency)
+	var results []shared.TestResult
+	if params.Contract {
+		checker := shared.NewContractChecker(t.falconDir)
+		results = t.testExecutor.RunScenariosWithContract(scenariosToRun, params.BaseURL, concurrency, checker)
+	} else {
+		results = t.testExecutor.RunScenarios(scenariosToRun, params.BaseURL, concurrency)
+	}
 
 	// Summarize
 	passed := 0
@@ -118,6 +126,9 @@ func (t *RunTestsTool) Execute(args string) (string, error) {
 				fmt.Fprintf(&sb, "  Status: Expected %d, Got %d\n", res.ExpectedStatus, res.ActualStatus)
 			}
 		}
+		if res.Contract != nil && res.Passed && len(res.Contract.Issues) > 0 {
+			fmt.Fprintf(&sb, "  Contract warnings: %d (see report)\n", len(res.Contract.Issues))
+		}
 	}
 
 	fmt.Fprintf(&sb, "\nSummary: %d Passed, %d Failed\n", passed, failed)
@@ -157,6 +168,20 @@ func formatTestReport(results []shared.TestResult, passed, failed int) string {
 		if res.Error != "" {
 			fmt.Fprintf(&sb, "- **Error:** %s\n", res.Error)
 		}
+		if res.Contract != nil {
+			if len(res.Contract.Issues) == 0 {
+				fmt.Fprintf(&sb, "- **Contract:** matches %s\n", res.Contract.Endpoint)
+			} else {
+				fmt.Fprintf(&sb, "- **Contract:**\n")
+				for _, issue := range res.Contract.Issues {
+					level := "violation"
+					if issue.Warning {
+						level = "warning"
+					}
+					fmt.Fprintf(&sb, "  - %s (%s)\n", issue, level)
+				}
+			}
+		}
 		fmt.Fprintf(&sb, "\n")
 	}
 
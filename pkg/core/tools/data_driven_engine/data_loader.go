@@ -1,12 +1,45 @@
 package data_driven_engine
 
 import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
 	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
 )
 
 // DataLoader loads data from files or generates fake data.
+//
+// Supported file formats (selected by extension):
+//   - .csv / .tsv: first row is the header; columns may carry a type hint
+//     ("age:int", "price:float", "active:bool", "tags:json", "name:string");
+//     other suffixes stay part of the name ("user:id")
+//   - .json: an array of objects (or {"rows": [...]})
+//   - .jsonl / .ndjson: one object per line
+//
+// Relative paths are resolved against the project root first, then
+// against .falcon/data/.
 type DataLoader struct {
-	Source string
+	Source    string
+	FalconDir string
+
+	// Delimiter overrides the CSV field separator (default ',' or '\t' for .tsv).
+	Delimiter string
+	// Filter keeps only rows whose column equals the given value, or any of
+	// the values when a list is given. Values are compared as strings.
+	Filter map[string]interface{}
+	// Sample picks N random rows after filtering (0 = all rows).
+	Sample int
+	// Seed makes sampling reproducible (0 = time-based).
+	Seed int64
 }
 
 // Load retrieves rows of data as maps.
@@ -15,9 +48,326 @@ func (l *DataLoader) Load(variables []string, maxRows int) ([]map[string]interfa
 		return l.generateFakeData(variables, maxRows), nil
 	}
 
-	// For simulation, if file doesn't exist, return error
-	// In real tool, we would parse CSV or JSON.
-	return nil, fmt.Errorf("data source file not found: %s", l.Source)
+	path, err := l.resolvePath()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv", ".tsv":
+		rows, err = l.loadCSV(path)
+	case ".json":
+		rows, err = loadJSON(path)
+	case ".jsonl", ".ndjson":
+		rows, err = loadJSONL(path)
+	default:
+		return nil, fmt.Errorf("unsupported data source format '%s' (use .csv, .tsv, .json, .jsonl)", filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkVariables(rows, variables); err != nil {
+		return nil, err
+	}
+
+	rows = l.filterRows(rows)
+	rows = l.sampleRows(rows)
+	if maxRows > 0 && len(rows) > maxRows {
+		rows = rows[:maxRows]
+	}
+
+	return rows, nil
+}
+
+// resolvePath locates the data file within the project or .falcon/data/.
+func (l *DataLoader) resolvePath() (string, error) {
+	projectRoot := filepath.Dir(l.FalconDir)
+	candidates := []string{l.Source}
+	if !filepath.IsAbs(l.Source) {
+		candidates = append(candidates, filepath.Join(l.FalconDir, "data", l.Source))
+	}
+
+	for _, candidate := range candidates {
+		absPath, err := shared.ValidatePathWithinWorkDir(candidate, projectRoot)
+		if err != nil {
+			return "", fmt.Errorf("data source '%s': %w", l.Source, err)
+		}
+		if info, err := os.Stat(absPath); err == nil && !info.IsDir() {
+			return absPath, nil
+		}
+	}
+
+	return "", fmt.Errorf("data source file not found: %s (looked in project and %s)", l.Source, filepath.Join(l.FalconDir, "data"))
+}
+
+// loadCSV reads a CSV file with a header row into typed rows.
+func (l *DataLoader) loadCSV(path string) ([]map[string]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open data source: %w", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+	switch {
+	case l.Delimiter != "":
+		reader.Comma = []rune(l.Delimiter)[0]
+	case strings.EqualFold(filepath.Ext(path), ".tsv"):
+		reader.Comma = '\t'
+	}
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("data source '%s' is empty", l.Source)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	names := make([]string, len(header))
+	types := make([]string, len(header))
+	for i, col := range header {
+		names[i], types[i] = splitColumnType(strings.TrimSpace(strings.TrimPrefix(col, "\ufeff")))
+	}
+
+	var rows []map[string]interface{}
+	line := 1
+	for {
+		record, err := reader.Read()
+		line++
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV row %d: %w", line, err)
+		}
+
+		row := make(map[string]interface{}, len(names))
+		for i, name := range names {
+			raw := ""
+			if i < len(record) {
+				raw = record[i]
+			}
+			val, err := convertCell(raw, types[i])
+			if err != nil {
+				return nil, fmt.Errorf("CSV row %d, column '%s': %w", line, name, err)
+			}
+			row[name] = val
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// columnTypes are the type hints a CSV header may carry as a ":type" suffix.
+var columnTypes = map[string]bool{
+	"string": true, "str": true,
+	"int": true, "integer": true,
+	"float": true, "number": true,
+	"bool": true, "boolean": true,
+	"json": true,
+}
+
+// splitColumnType splits "age:int" into its name and type hint. A header
+// whose suffix is not a known type, such as "user:id" or "time:utc", is a
+// plain column name.
+func splitColumnType(col string) (string, string) {
+	idx := strings.LastIndex(col, ":")
+	if idx <= 0 {
+		return col, ""
+	}
+	typ := strings.ToLower(strings.TrimSpace(col[idx+1:]))
+	if !columnTypes[typ] {
+		return col, ""
+	}
+	return strings.TrimSpace(col[:idx]), typ
+}
+
+// convertCell converts a CSV cell according to its column type hint.
+// Untyped cells are inferred conservatively: numbers only when they
+// round-trip exactly (so "007" stays a string), and true/false as booleans.
+func convertCell(raw, typ string) (interface{}, error) {
+	switch typ {
+	case "string", "str":
+		return raw, nil
+	case "int", "integer":
+		if raw == "" {
+			return nil, nil
+		}
+		return strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+	case "float", "number":
+		if raw == "" {
+			return nil, nil
+		}
+		return strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	case "bool", "boolean":
+		if raw == "" {
+			return nil, nil
+		}
+		return strconv.ParseBool(strings.TrimSpace(raw))
+	case "json":
+		if raw == "" {
+			return nil, nil
+		}
+		var v interface{}
+		if err := json.Unmarshal([]byte(raw), &v); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		return v, nil
+	case "":
+		if i, err := strconv.ParseInt(raw, 10, 64); err == nil && strconv.FormatInt(i, 10) == raw {
+			return i, nil
+		}
+		if f, err := strconv.ParseFloat(raw, 64); err == nil && strconv.FormatFloat(f, 'f', -1, 64) == raw {
+			return f, nil
+		}
+		if raw == "true" || raw == "false" {
+			return raw == "true", nil
+		}
+		return raw, nil
+	default:
+		return nil, fmt.Errorf("unknown column type '%s'", typ)
+	}
+}
+
+// loadJSON reads a JSON array of objects, or an object with a "rows" array.
+func loadJSON(path string) ([]map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data source: %w", err)
+	}
+
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(data, &rows); err == nil {
+		return rows, nil
+	}
+
+	var wrapped struct {
+		Rows []map[string]interface{} `json:"rows"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil || wrapped.Rows == nil {
+		return nil, fmt.Errorf("JSON data source must be an array of objects or {\"rows\": [...]}")
+	}
+	return wrapped.Rows, nil
+}
+
+// loadJSONL reads one JSON object per line, skipping blank lines.
+func loadJSONL(path string) ([]map[string]interface{}, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open data source: %w", err)
+	}
+	defer f.Close()
+
+	var rows []map[string]interface{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var row map[string]interface{}
+		if err := json.Unmarshal([]byte(text), &row); err != nil {
+			return nil, fmt.Errorf("JSONL line %d: %w", line, err)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read JSONL: %w", err)
+	}
+
+	return rows, nil
+}
+
+// checkVariables ensures every requested variable exists in at least one row.
+func checkVariables(rows []map[string]interface{}, variables []string) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	for _, v := range variables {
+		found := false
+		for _, row := range rows {
+			if _, ok := row[v]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("variable '%s' not found in data source columns", v)
+		}
+	}
+	return nil
+}
+
+// filterRows keeps rows that match every Filter entry.
+func (l *DataLoader) filterRows(rows []map[string]interface{}) []map[string]interface{} {
+	if len(l.Filter) == 0 {
+		return rows
+	}
+
+	var kept []map[string]interface{}
+	for _, row := range rows {
+		if matchesFilter(row, l.Filter) {
+			kept = append(kept, row)
+		}
+	}
+	return kept
+}
+
+func matchesFilter(row map[string]interface{}, filter map[string]interface{}) bool {
+	for col, want := range filter {
+		got := fmt.Sprint(row[col])
+		if options, ok := want.([]interface{}); ok {
+			matched := false
+			for _, opt := range options {
+				if got == fmt.Sprint(opt) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		} else if got != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
+
+// sampleRows returns Sample random rows, preserving their original order.
+func (l *DataLoader) sampleRows(rows []map[string]interface{}) []map[string]interface{} {
+	if l.Sample <= 0 || l.Sample >= len(rows) {
+		return rows
+	}
+
+	seed := l.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+
+	picked := rng.Perm(len(rows))[:l.Sample]
+	selected := make([]bool, len(rows))
+	for _, idx := range picked {
+		selected[idx] = true
+	}
+
+	sampled := make([]map[string]interface{}, 0, l.Sample)
+	for i, row := range rows {
+		if selected[i] {
+			sampled = append(sampled, row)
+		}
+	}
+	return sampled
 }
 
 func (l *DataLoader) generateFakeData(variables []string, count int) []map[string]interface{} {
@@ -0,0 +1,123 @@
+package data_driven_engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+)
+
+func writeDataFile(t *testing.T, falconDir, name, content string) {
+	t.Helper()
+	dataDir := filepath.Join(falconDir, "data")
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dataDir, name), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDataLoader_CSVTypedColumns(t *testing.T) {
+	falconDir := filepath.Join(t.TempDir(), ".falcon")
+	writeDataFile(t, falconDir, "users.csv", "name,age:int,zip,role,expected_status\n\"Doe, Jane\",31,007,admin,201\nBob,40,12345,viewer,403\n")
+
+	loader := &DataLoader{Source: "users.csv", FalconDir: falconDir}
+	rows, err := loader.Load([]string{"name", "age"}, 0)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(rows) != 2 {
+		t.Fatalf("rows = %d, want 2", len(rows))
+	}
+	if rows[0]["name"] != "Doe, Jane" {
+		t.Errorf("quoted name = %v", rows[0]["name"])
+	}
+	if rows[0]["age"] != int64(31) {
+		t.Errorf("age = %#v, want int64(31)", rows[0]["age"])
+	}
+	if rows[0]["zip"] != "007" {
+		t.Errorf("zip = %#v, want string \"007\"", rows[0]["zip"])
+	}
+}
+
+func TestDataLoader_FilterAndJSONL(t *testing.T) {
+	falconDir := filepath.Join(t.TempDir(), ".falcon")
+	writeDataFile(t, falconDir, "users.jsonl", "{\"name\":\"a\",\"role\":\"admin\"}\n\n{\"name\":\"b\",\"role\":\"viewer\"}\n{\"name\":\"c\",\"role\":\"editor\"}\n")
+
+	loader := &DataLoader{
+		Source:    "users.jsonl",
+		FalconDir: falconDir,
+		Filter:    map[string]interface{}{"role": []interface{}{"admin", "editor"}},
+	}
+	rows, err := loader.Load([]string{"name"}, 0)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if len(rows) != 2 || rows[0]["name"] != "a" || rows[1]["name"] != "c" {
+		t.Errorf("filtered rows = %v", rows)
+	}
+}
+
+func TestDataLoader_MissingVariable(t *testing.T) {
+	falconDir := filepath.Join(t.TempDir(), ".falcon")
+	writeDataFile(t, falconDir, "users.json", `[{"name":"a"}]`)
+
+	loader := &DataLoader{Source: "users.json", FalconDir: falconDir}
+	if _, err := loader.Load([]string{"email"}, 0); err == nil {
+		t.Error("expected error for missing variable")
+	}
+}
+
+func TestTemplateEngine_PopulateTypedAndExpectations(t *testing.T) {
+	engine := &TemplateEngine{}
+	template := shared.TestScenario{
+		URL:      "/users/{{id}}",
+		Body:     map[string]interface{}{"age": "{{age}}", "greeting": "hi {{name}}"},
+		Expected: shared.TestExpectation{StatusCode: 200},
+	}
+
+	populated := engine.Populate(template, map[string]interface{}{
+		"id": int64(7), "age": int64(31), "name": "Jane", "expected_status": int64(201),
+	})
+
+	if populated.URL != "/users/7" {
+		t.Errorf("URL = %q", populated.URL)
+	}
+	body := populated.Body.(map[string]interface{})
+	if body["age"] != int64(31) {
+		t.Errorf("age = %#v, want typed int", body["age"])
+	}
+	if body["greeting"] != "hi Jane" {
+		t.Errorf("greeting = %q", body["greeting"])
+	}
+	if populated.Expected.StatusCode != 201 {
+		t.Errorf("expected status = %d, want 201", populated.Expected.StatusCode)
+	}
+}
+
+func TestDataLoader_CSVColumnsWithColons(t *testing.T) {
+	falconDir := filepath.Join(t.TempDir(), ".falcon")
+	writeDataFile(t, falconDir, "users.csv", "user:id,created:utc,score:FLOAT,a:b:int\nu-1,2024-01-01,1.5,7\n")
+
+	loader := &DataLoader{Source: "users.csv", FalconDir: falconDir}
+	rows, err := loader.Load(nil, 0)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	want := map[string]interface{}{
+		"user:id":     "u-1",
+		"created:utc": "2024-01-01",
+		"score":       1.5,
+		"a:b":         int64(7),
+	}
+	for name, val := range want {
+		if rows[0][name] != val {
+			t.Errorf("%s = %#v, want %#v", name, rows[0][name], val)
+		}
+	}
+	if len(rows[0]) != len(want) {
+		t.Errorf("columns = %v", rows[0])
+	}
+}
@@ -2,50 +2,104 @@ package data_driven_engine
 
 import (
 	"encoding/json"
+	"strconv"
 	"strings"
 
 	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
 )
 
+// Reserved data columns that set per-row expectations instead of template values.
+const (
+	ColExpectedStatus       = "expected_status"        // exact status code, e.g. 201
+	ColExpectedBodyContains = "expected_body_contains" // substring(s), "|" separated
+	ColExpectedMaxMs        = "expected_max_ms"        // max response time in ms
+)
+
 // TemplateEngine replaces placeholders in test scenarios with actual data.
 type TemplateEngine struct{}
 
 // Populate replaces {{var}} placeholders in the scenario with values from the data row.
+// A body value that is exactly "{{var}}" takes the row value with its type
+// (numbers stay numbers); placeholders embedded in longer strings are
+// substituted as text. Reserved expected_* columns override the scenario's
+// expectations for that row.
 func (e *TemplateEngine) Populate(template shared.TestScenario, data map[string]interface{}) shared.TestScenario {
 	// Deep copy via JSON (simplest for demonstration)
 	var scenario shared.TestScenario
 	bytes, _ := json.Marshal(template)
 	json.Unmarshal(bytes, &scenario)
 
-	// Replace in URL
-	for k, v := range data {
-		placeholder := "{{" + k + "}}"
-		valStr := ""
-		if s, ok := v.(string); ok {
-			valStr = s
-		} else {
-			valStr = strings.Trim(string(bytes), "\"") // simplified
-		}
-
-		scenario.URL = strings.ReplaceAll(scenario.URL, placeholder, valStr)
+	// Replace in URL and headers
+	scenario.URL = substituteText(scenario.URL, data)
+	for k, v := range scenario.Headers {
+		scenario.Headers[k] = substituteText(v, data)
 	}
 
-	// Replace in Body (if map)
+	// Replace in Body
 	if scenario.Body != nil {
-		bodyBytes, _ := json.Marshal(scenario.Body)
-		bodyStr := string(bodyBytes)
-		for k, v := range data {
-			placeholder := "{{" + k + "}}"
-			valBytes, _ := json.Marshal(v)
-			valStr := string(valBytes)
-			// Avoid double quotes if value is already a string
-			if strings.HasPrefix(valStr, "\"") && strings.HasSuffix(valStr, "\"") {
-				valStr = valStr[1 : len(valStr)-1]
+		scenario.Body = substituteValue(scenario.Body, data)
+	}
+
+	applyRowExpectations(&scenario.Expected, data)
+
+	return scenario
+}
+
+// substituteValue walks a decoded JSON value and replaces placeholders.
+func substituteValue(v interface{}, data map[string]interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, child := range val {
+			val[k] = substituteValue(child, data)
+		}
+		return val
+	case []interface{}:
+		for i, child := range val {
+			val[i] = substituteValue(child, data)
+		}
+		return val
+	case string:
+		trimmed := strings.TrimSpace(val)
+		if strings.HasPrefix(trimmed, "{{") && strings.HasSuffix(trimmed, "}}") && strings.Count(trimmed, "{{") == 1 {
+			if rowVal, ok := data[strings.TrimSpace(trimmed[2:len(trimmed)-2])]; ok {
+				return rowVal
 			}
-			bodyStr = strings.ReplaceAll(bodyStr, placeholder, valStr)
 		}
-		json.Unmarshal([]byte(bodyStr), &scenario.Body)
+		return substituteText(val, data)
+	default:
+		return v
 	}
+}
 
-	return scenario
+// substituteText replaces every {{key}} in s with the row value rendered as text.
+func substituteText(s string, data map[string]interface{}) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	for k, v := range data {
+		s = strings.ReplaceAll(s, "{{"+k+"}}", shared.Stringify(v))
+	}
+	return s
+}
+
+// applyRowExpectations overrides expectations from reserved expected_* columns.
+func applyRowExpectations(expected *shared.TestExpectation, data map[string]interface{}) {
+	if v, ok := data[ColExpectedStatus]; ok {
+		if code, err := strconv.Atoi(shared.Stringify(v)); err == nil && code > 0 {
+			expected.StatusCode = code
+			expected.StatusCodeRange = nil
+		}
+	}
+	if v, ok := data[ColExpectedBodyContains]; ok {
+		for _, needle := range strings.Split(shared.Stringify(v), "|") {
+			if needle = strings.TrimSpace(needle); needle != "" {
+				expected.BodyContains = append(expected.BodyContains, needle)
+			}
+		}
+	}
+	if v, ok := data[ColExpectedMaxMs]; ok {
+		if ms, err := strconv.Atoi(shared.Stringify(v)); err == nil && ms > 0 {
+			expected.MaxDurationMs = ms
+		}
+	}
 }
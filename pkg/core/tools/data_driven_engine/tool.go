@@ -29,11 +29,15 @@ func NewDataDrivenEngineTool(falconDir string, httpTool *shared.HTTPTool, testEx
 
 // DataDrivenParams defines parameters for data-driven testing.
 type DataDrivenParams struct {
-	Scenario   shared.TestScenario `json:"scenario"`              // Base scenario template
-	DataSource string              `json:"data_source"`           // Path to CSV/JSON file or 'fake'
-	Variables  []string            `json:"variables"`             // Variable names to map
-	MaxRows    int                 `json:"max_rows,omitempty"`    // Limit number of rows to process
-	ReportName string              `json:"report_name,omitempty"` // e.g. "data_driven_report_users"
+	Scenario   shared.TestScenario    `json:"scenario"`              // Base scenario template
+	DataSource string                 `json:"data_source"`           // Path to CSV/JSON/JSONL file or 'fake'
+	Variables  []string               `json:"variables"`             // Variable names to map
+	MaxRows    int                    `json:"max_rows,omitempty"`    // Limit number of rows to process
+	Filter     map[string]interface{} `json:"filter,omitempty"`      // Keep rows where column == value (or any of a list)
+	Sample     int                    `json:"sample,omitempty"`      // Randomly pick N rows after filtering
+	Seed       int64                  `json:"seed,omitempty"`        // Seed for reproducible sampling
+	Delimiter  string                 `json:"delimiter,omitempty"`   // CSV field separator override
+	ReportName string                 `json:"report_name,omitempty"` // e.g. "data_driven_report_users"
 }
 
 // DataDrivenResult represents the outcome of the data-driven test run.
@@ -50,7 +54,7 @@ func (t *DataDrivenEngineTool) Name() string {
 }
 
 func (t *DataDrivenEngineTool) Description() string {
-	return "Execute test scenarios driven by external data sources (CSV/JSON) or automated data generators, mapping variables to request templates"
+	return "Execute test scenarios driven by external data sources (CSV/JSON/JSONL in the project or .falcon/data/) or automated data generators, mapping variables to request templates. Columns expected_status, expected_body_contains and expected_max_ms set per-row expectations"
 }
 
 func (t *DataDrivenEngineTool) Parameters() string {
@@ -60,8 +64,12 @@ func (t *DataDrivenEngineTool) Parameters() string {
     "url": "http://localhost:3000/api/users",
     "body": {"name": "{{name}}", "email": "{{email}}"}
   },
-  "data_source": "./data/users.csv",
-  "variables": ["name", "email"]
+  "data_source": "users.csv (project path or .falcon/data/; .csv, .tsv, .json, .jsonl) or 'fake'",
+  "variables": ["name", "email"],
+  "filter": {"role": ["admin", "editor"]},
+  "sample": 20,
+  "seed": 42,
+  "max_rows": 100
 }`
 }
 
@@ -72,11 +80,21 @@ func (t *DataDrivenEngineTool) Execute(args string) (string, error) {
 	}
 
 	// 1. Load data
-	loader := &DataLoader{Source: params.DataSource}
+	loader := &DataLoader{
+		Source:    params.DataSource,
+		FalconDir: t.falconDir,
+		Delimiter: params.Delimiter,
+		Filter:    params.Filter,
+		Sample:    params.Sample,
+		Seed:      params.Seed,
+	}
 	rows, err := loader.Load(params.Variables, params.MaxRows)
 	if err != nil {
 		return "", fmt.Errorf("failed to load data: %w", err)
 	}
+	if len(rows) == 0 {
+		return "", fmt.Errorf("data source '%s' produced no rows (check filter/sample)", params.DataSource)
+	}
 
 	// 2. Process rows using TestExecutor
 	tempEngine := &TemplateEngine{}
@@ -135,7 +153,11 @@ func formatDataDrivenReport(result DataDrivenResult) string {
 			}
 			fmt.Fprintf(&sb, "### [%s] %s — %s\n\n", res.ScenarioID, res.ScenarioName, status)
 			fmt.Fprintf(&sb, "- **Duration:** %dms\n", res.DurationMs)
-			fmt.Fprintf(&sb, "- **Status Code:** %d\n", res.ActualStatus)
+			fmt.Fprintf(&sb, "- **Status Code:** %d", res.ActualStatus)
+			if res.ExpectedStatus != 0 {
+				fmt.Fprintf(&sb, " (expected %d)", res.ExpectedStatus)
+			}
+			fmt.Fprintf(&sb, "\n")
 			if res.Error != "" {
 				fmt.Fprintf(&sb, "- **Error:** %s\n", res.Error)
 			}
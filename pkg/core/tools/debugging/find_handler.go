@@ -3,6 +3,9 @@ package debugging
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strings"
 )
 
@@ -60,18 +63,36 @@ func (t *FindHandlerTool) Execute(args string) (string, error) {
 	if err := json.Unmarshal([]byte(args), &params); err != nil {
 		return "", fmt.Errorf("failed to parse parameters: %w", err)
 	}
+	if method, path, ok := strings.Cut(strings.TrimSpace(params.Endpoint), " "); ok {
+		if params.Method == "" {
+			params.Method = method
+		}
+		if params.Path == "" {
+			params.Path = strings.TrimSpace(path)
+		}
+	}
+	if params.Path == "" {
+		return "", fmt.Errorf("path is required (or endpoint as \"METHOD /path\")")
+	}
+
+	// 1. Look the endpoint up in the route table, with group and mount
+	// prefixes resolved
+	if info, ok := t.findRoute(params); ok {
+		out, _ := json.MarshalIndent(info, "", "  ")
+		return string(out), nil
+	}
 
-	// 1. Identify search patterns based on framework
+	// 2. Identify search patterns based on framework
 	patterns := t.getSearchPatterns(params)
 	if len(patterns) == 0 {
 		// Generic fallback: just search the path
-		patterns = []string{params.Path}
+		patterns = []string{regexp.QuoteMeta(params.Path)}
 	}
 
 	var results []string
 	for _, pattern := range patterns {
-		searchArgs := fmt.Sprintf(`{"pattern": "%s"}`, pattern)
-		res, err := t.searchTool.Execute(searchArgs)
+		searchArgs, _ := json.Marshal(map[string]string{"pattern": pattern})
+		res, err := t.searchTool.Execute(string(searchArgs))
 		if err == nil && !strings.Contains(res, "No matches found") {
 			results = append(results, res)
 		}
@@ -85,30 +106,146 @@ func (t *FindHandlerTool) Execute(args string) (string, error) {
 	return strings.Join(results, "\n---\n"), nil
 }
 
+// findRoute matches the endpoint against the extracted routes. A route
+// declared for the method wins over an ANY route; the other matches are
+// listed as related files.
+func (t *FindHandlerTool) findRoute(params FindHandlerParams) (HandlerInfo, bool) {
+	routes, err := ExtractRoutes(t.workDir, params.Framework)
+	if err != nil {
+		return HandlerInfo{}, false
+	}
+	method := strings.ToUpper(params.Method)
+	path, _, _ := strings.Cut(params.Path, "?")
+	path = NormalizeRoutePath(path)
+
+	var matches []Route
+	for _, r := range routes {
+		if !routeMatches(r.Path, path) {
+			continue
+		}
+		switch {
+		case method == "" || r.Method == method:
+			matches = append([]Route{r}, matches...)
+		case r.Method == "ANY":
+			matches = append(matches, r)
+		}
+	}
+	if len(matches) == 0 {
+		return HandlerInfo{}, false
+	}
+
+	best := matches[0]
+	info := HandlerInfo{
+		File:     best.File,
+		Line:     best.Line,
+		Content:  t.snippet(best.File, best.Line),
+		Analysis: fmt.Sprintf("%s %s is declared at %s:%d.", best.Method, best.Path, best.File, best.Line),
+	}
+	for _, r := range matches[1:] {
+		info.RelatedFiles = append(info.RelatedFiles, fmt.Sprintf("%s:%d (%s %s)", r.File, r.Line, r.Method, r.Path))
+	}
+	if len(info.RelatedFiles) > 0 {
+		info.Analysis += " Other declarations match too; see related_files."
+	}
+	return info, true
+}
+
+// routeMatches reports whether a request path, concrete (/users/42) or a
+// template (/users/{id}), is served by a route path.
+func routeMatches(route, path string) bool {
+	rs, ps := strings.Split(route, "/"), strings.Split(path, "/")
+	if len(rs) != len(ps) {
+		return false
+	}
+	for i := range rs {
+		if rs[i] != ps[i] && !isPathParam(rs[i]) && !isPathParam(ps[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// snippet returns the lines around a route declaration, numbered, so the
+// handler it names is usually in view.
+func (t *FindHandlerTool) snippet(file string, line int) string {
+	data, err := os.ReadFile(filepath.Join(t.workDir, file))
+	if err != nil {
+		return ""
+	}
+	lines := strings.Split(string(data), "\n")
+	start, end := max(line-3, 0), min(line+10, len(lines))
+	var sb strings.Builder
+	for i := start; i < end; i++ {
+		fmt.Fprintf(&sb, "%4d | %s\n", i+1, lines[i])
+	}
+	return sb.String()
+}
+
+// getSearchPatterns returns search_code regular expressions for the literal
+// route declaration. They are the fallback when the route table has no
+// match, e.g. for paths built at runtime.
 func (t *FindHandlerTool) getSearchPatterns(params FindHandlerParams) []string {
 	method := strings.ToUpper(params.Method)
-	path := params.Path
+	lower := strings.ToLower(method)
+	title := ""
+	if method != "" {
+		title = method[:1] + lower[1:]
+	}
+	path := regexp.QuoteMeta(params.Path)
+	quoted := `['"]` + path + `['"]`
 
 	switch strings.ToLower(params.Framework) {
-	case "gin":
+	case "gin", "echo":
 		return []string{
-			fmt.Sprintf(`%s("%s"`, method, path),
-			fmt.Sprintf(`%s( "%s"`, method, path),
+			fmt.Sprintf(`%s\(\s*"%s"`, method, path),
 		}
-	case "echo":
+	case "chi", "fiber":
 		return []string{
-			fmt.Sprintf(`%s("%s"`, method, path),
+			fmt.Sprintf(`\.%s\(\s*"%s"`, title, path),
+			fmt.Sprintf(`\.Route\(\s*"%s"`, path),
 		}
 	case "fastapi":
 		return []string{
-			fmt.Sprintf(`@app.%s("%s"`, strings.ToLower(method), path),
-			fmt.Sprintf(`@router.%s("%s"`, strings.ToLower(method), path),
+			fmt.Sprintf(`@\w+\.%s\(\s*%s`, lower, quoted),
+		}
+	case "flask":
+		return []string{
+			fmt.Sprintf(`@\w+\.(route|%s)\(\s*%s`, lower, quoted),
+		}
+	case "django":
+		return []string{
+			fmt.Sprintf(`(path|re_path)\(\s*r?['"]\^?%s`, regexp.QuoteMeta(strings.TrimPrefix(params.Path, "/"))),
+		}
+	case "express", "hono":
+		return []string{
+			fmt.Sprintf(`\.%s\(\s*%s`, lower, quoted),
+			fmt.Sprintf(`\.route\(\s*%s`, quoted),
+		}
+	case "nestjs":
+		return []string{
+			fmt.Sprintf(`@%s\(\s*['"]?%s`, title, regexp.QuoteMeta(strings.TrimPrefix(params.Path, "/"))),
+			fmt.Sprintf(`@Controller\(\s*['"]/?%s`, regexp.QuoteMeta(strings.TrimPrefix(params.Path, "/"))),
+		}
+	case "spring":
+		return []string{
+			fmt.Sprintf(`@(%sMapping|RequestMapping)\(.*"%s"`, title, path),
+		}
+	case "laravel":
+		return []string{
+			fmt.Sprintf(`Route::(%s|match|any)\(.*%s`, lower, quoted),
+		}
+	case "rails":
+		return []string{
+			fmt.Sprintf(`%s\s+['"]/?%s`, lower, regexp.QuoteMeta(strings.TrimPrefix(params.Path, "/"))),
+		}
+	case "actix":
+		return []string{
+			fmt.Sprintf(`#\[%s\(\s*"%s"`, lower, path),
+			fmt.Sprintf(`\.route\(\s*"%s"`, path),
 		}
-	case "express":
+	case "axum":
 		return []string{
-			fmt.Sprintf(`\.%s(['"]%s['"]`, strings.ToLower(method), path),
-			fmt.Sprintf(`app\.%s(['"]%s['"]`, strings.ToLower(method), path),
-			fmt.Sprintf(`router\.%s(['"]%s['"]`, strings.ToLower(method), path),
+			fmt.Sprintf(`\.route\(\s*"%s"`, path),
 		}
 	default:
 		return nil
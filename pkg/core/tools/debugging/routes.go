@@ -0,0 +1,564 @@
+package debugging
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Route is an HTTP route declared in the source code.
+type Route struct {
+	Method string `json:"method"` // upper case; "ANY" matches every method
+	Path   string `json:"path"`   // full path, parameters written as {name}
+	File   string `json:"file"`   // relative to the scanned directory
+	Line   int    `json:"line"`
+}
+
+// routeExtractor finds the route declarations in one source file and
+// records them, with any mounts, in the table.
+type routeExtractor struct {
+	extensions []string
+	extract    func(f *routeFile, t *routeTable)
+}
+
+// routeExtractors holds one extractor per language; frameworkExtractors maps
+// each framework to the extractor that understands it.
+var routeExtractors = map[string]routeExtractor{
+	"go":      {extensions: []string{".go"}, extract: extractGoRoutes},
+	"python":  {extensions: []string{".py"}, extract: extractPythonRoutes},
+	"js":      {extensions: jsExtensions, extract: extractJSRoutes},
+	"nestjs":  {extensions: []string{".ts", ".js"}, extract: extractNestRoutes},
+	"spring":  {extensions: []string{".java", ".kt", ".properties", ".yml", ".yaml"}, extract: extractSpringRoutes},
+	"laravel": {extensions: []string{".php"}, extract: extractLaravelRoutes},
+	"rails":   {extensions: []string{".rb"}, extract: extractRailsRoutes},
+	"rust":    {extensions: []string{".rs"}, extract: extractRustRoutes},
+}
+
+var frameworkExtractors = map[string]string{
+	"gin": "go", "echo": "go", "chi": "go", "fiber": "go",
+	"fastapi": "python", "flask": "python", "django": "python",
+	"express": "js", "hono": "js", "nestjs": "nestjs",
+	"spring": "spring", "laravel": "laravel", "rails": "rails",
+	"actix": "rust", "axum": "rust",
+}
+
+// routeSkipDirs are never scanned: dependencies, virtualenvs and build output.
+var routeSkipDirs = map[string]bool{
+	"node_modules": true, "vendor": true, "venv": true, "__pycache__": true,
+	"site-packages": true, "dist": true, "build": true, "target": true,
+}
+
+// maxRouteFileSize skips generated or minified files.
+const maxRouteFileSize = 1 << 20
+
+var routeParamName = regexp.MustCompile(`^[:*]?\{?([A-Za-z_][\w-]*)`)
+
+// RouteFrameworks lists the frameworks ExtractRoutes has an extractor for.
+func RouteFrameworks() []string {
+	names := make([]string, 0, len(frameworkExtractors))
+	for name := range frameworkExtractors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExtractRoutes statically reads the route table of the codebase under root.
+// Prefixes from router groups, mounted routers, blueprints, controller
+// classes and the like are resolved, so each path is the one the API serves.
+// An empty or unknown framework runs every extractor on its file types.
+// Test files are skipped; routes are sorted by path, then method.
+func ExtractRoutes(root, framework string) ([]Route, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read source directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", root)
+	}
+
+	extractors := routeExtractorsFor(framework)
+	table := &routeTable{defaults: make(map[string]string)}
+
+	err = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info.IsDir() {
+			name := info.Name()
+			if path != root && (strings.HasPrefix(name, ".") || routeSkipDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Size() > maxRouteFileSize || isTestFile(info.Name()) {
+			return nil
+		}
+
+		ext := strings.ToLower(filepath.Ext(path))
+		var content string
+		for _, extractor := range extractors {
+			if !containsString(extractor.extensions, ext) {
+				continue
+			}
+			if content == "" {
+				data, err := os.ReadFile(path)
+				if err != nil {
+					return nil
+				}
+				content = string(data)
+			}
+			rel, _ := filepath.Rel(root, path)
+			extractor.extract(newRouteFile(filepath.ToSlash(rel), content), table)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan source directory: %w", err)
+	}
+
+	routes := table.resolve()
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		if routes[i].Method != routes[j].Method {
+			return routes[i].Method < routes[j].Method
+		}
+		if routes[i].File != routes[j].File {
+			return routes[i].File < routes[j].File
+		}
+		return routes[i].Line < routes[j].Line
+	})
+	return routes, nil
+}
+
+func routeExtractorsFor(framework string) []routeExtractor {
+	if name, ok := frameworkExtractors[strings.ToLower(framework)]; ok {
+		return []routeExtractor{routeExtractors[name]}
+	}
+	names := make([]string, 0, len(routeExtractors))
+	for name := range routeExtractors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	extractors := make([]routeExtractor, len(names))
+	for i, name := range names {
+		extractors[i] = routeExtractors[name]
+	}
+	return extractors
+}
+
+// routeFile is a source file being scanned. Extractors match patterns on
+// content and brackets on masked, the content with comments and string
+// literals blanked out.
+type routeFile struct {
+	rel        string
+	content    string
+	masked     string
+	lineStarts []int
+	scopes     []string // scopes of every route in the file
+	regions    []routeRegion
+}
+
+// routeRegion is a span of a file whose routes share a path prefix (a chi
+// Route block, a Spring controller class) or can be mounted under a scope
+// (a Go function, an Axum router variable).
+type routeRegion struct {
+	start, end int
+	prefix     string
+	scope      string
+}
+
+func newRouteFile(rel, content string) *routeFile {
+	f := &routeFile{rel: rel, content: content, masked: content, lineStarts: []int{0}}
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			f.lineStarts = append(f.lineStarts, i+1)
+		}
+	}
+	return f
+}
+
+// line returns the 1-based line of an offset.
+func (f *routeFile) line(offset int) int {
+	return sort.SearchInts(f.lineStarts, offset+1)
+}
+
+// inCode reports whether an offset is outside comments and string literals.
+func (f *routeFile) inCode(offset int) bool {
+	return offset < len(f.masked) && f.masked[offset] == f.content[offset]
+}
+
+func (f *routeFile) addRegion(start, end int, prefix, scope string) {
+	f.regions = append(f.regions, routeRegion{start: start, end: end, prefix: prefix, scope: scope})
+}
+
+// prefixAt joins the prefixes of the regions around an offset, outermost
+// first.
+func (f *routeFile) prefixAt(offset int) string {
+	regions := make([]routeRegion, 0, len(f.regions))
+	for _, r := range f.regions {
+		if r.prefix != "" && r.start <= offset && offset < r.end {
+			regions = append(regions, r)
+		}
+	}
+	sort.SliceStable(regions, func(i, j int) bool { return regions[i].start < regions[j].start })
+	var sb strings.Builder
+	for _, r := range regions {
+		sb.WriteString("/" + r.prefix)
+	}
+	return sb.String()
+}
+
+// scopesAt returns the file's scopes and those of the regions around an
+// offset.
+func (f *routeFile) scopesAt(offset int) []string {
+	scopes := append([]string(nil), f.scopes...)
+	for _, r := range f.regions {
+		if r.scope != "" && r.start <= offset && offset < r.end {
+			scopes = append(scopes, r.scope)
+		}
+	}
+	return scopes
+}
+
+// routeMount attaches the routes of a scope under a prefix: an Express
+// app.use('/v1', router), a Flask register_blueprint, a Django include().
+type routeMount struct {
+	target  string
+	prefix  string
+	inherit bool     // no prefix given; the target's default applies (Flask url_prefix)
+	scopes  []string // scopes the mount itself is declared in
+}
+
+type scopedRoute struct {
+	Route
+	scopes []string
+}
+
+// routeTable collects the routes and mounts of a codebase; resolve joins
+// them into full paths.
+type routeTable struct {
+	routes   []scopedRoute
+	mounts   []routeMount
+	defaults map[string]string // scope -> prefix used unless a mount gives one
+}
+
+// add records a route declared at offset, below the regions around it and
+// in the given extra scopes.
+func (t *routeTable) add(f *routeFile, offset int, method, path string, scopes ...string) {
+	t.routes = append(t.routes, scopedRoute{
+		Route: Route{
+			Method: strings.ToUpper(method),
+			Path:   f.prefixAt(offset) + "/" + path,
+			File:   f.rel,
+			Line:   f.line(offset),
+		},
+		scopes: append(f.scopesAt(offset), scopes...),
+	})
+}
+
+// mount records that the target scope is served below prefix, from a
+// mount declared at offset.
+func (t *routeTable) mount(f *routeFile, offset int, target, prefix string, scopes ...string) {
+	t.mounts = append(t.mounts, routeMount{
+		target: target,
+		prefix: f.prefixAt(offset) + "/" + prefix,
+		scopes: append(f.scopesAt(offset), scopes...),
+	})
+}
+
+// resolve expands every route into the full paths it is served at. A route
+// in a mounted scope appears once per mount; mounts of mounts are followed,
+// and cycles are cut.
+func (t *routeTable) resolve() []Route {
+	byTarget := make(map[string][]routeMount)
+	for _, m := range t.mounts {
+		byTarget[m.target] = append(byTarget[m.target], m)
+	}
+
+	var prefixes func(scopes []string, visiting map[string]bool) []string
+	prefixes = func(scopes []string, visiting map[string]bool) []string {
+		var mounted, defaults []string
+		for _, scope := range scopes {
+			mounts := byTarget[scope]
+			if len(mounts) == 0 {
+				if prefix, ok := t.defaults[scope]; ok {
+					defaults = append(defaults, "/"+prefix)
+				}
+				continue
+			}
+			if visiting[scope] {
+				continue
+			}
+			visiting[scope] = true
+			for _, m := range mounts {
+				prefix := m.prefix
+				if m.inherit {
+					prefix += "/" + t.defaults[scope]
+				}
+				for _, parent := range prefixes(m.scopes, visiting) {
+					mounted = append(mounted, parent+prefix)
+				}
+			}
+			delete(visiting, scope)
+		}
+		switch {
+		case len(mounted) > 0:
+			return mounted
+		case len(defaults) > 0:
+			return defaults
+		}
+		return []string{""}
+	}
+
+	var routes []Route
+	seen := make(map[string]bool)
+	for _, r := range t.routes {
+		for _, prefix := range prefixes(r.scopes, make(map[string]bool)) {
+			route := r.Route
+			route.Path = NormalizeRoutePath(prefix + route.Path)
+			key := fmt.Sprintf("%s %s %s:%d", route.Method, route.Path, route.File, route.Line)
+			if !seen[key] {
+				seen[key] = true
+				routes = append(routes, route)
+			}
+		}
+	}
+	return routes
+}
+
+// NormalizeRoutePath rewrites framework path syntax to the {name} form specs
+// use: ":id", "{id:int}", "*filepath", "<int:id>" and "{id?}" all become
+// "{id}", and a bare "*" becomes "{wildcard}". Trailing and duplicate slashes
+// are dropped.
+func NormalizeRoutePath(path string) string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" {
+			continue
+		}
+		switch {
+		case segment == "*":
+			segment = "{wildcard}"
+		case strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") ||
+			strings.HasPrefix(segment, "{") || strings.HasPrefix(segment, "<"):
+			name := strings.TrimPrefix(segment, "<")
+			if i := strings.LastIndex(name, ":"); strings.HasPrefix(segment, "<") && i >= 0 {
+				name = name[i+1:] // <int:id>
+			}
+			if m := routeParamName.FindStringSubmatch(name); m != nil {
+				segment = "{" + m[1] + "}"
+			}
+		}
+		segments = append(segments, segment)
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+// codeStyle describes the comment and string syntax maskCode strips.
+type codeStyle struct {
+	lineComments  []string
+	blockComments bool // /* ... */
+	backticks     bool // `raw` strings spanning lines
+	tripleQuotes  bool // Python """...""" and '''...'''
+	charQuotes    bool // ' only starts a short char literal ('a', '\n'); Rust lifetimes are left alone
+}
+
+var (
+	goStyle   = codeStyle{lineComments: []string{"//"}, blockComments: true, backticks: true, charQuotes: true}
+	jsStyle   = codeStyle{lineComments: []string{"//"}, blockComments: true, backticks: true}
+	jvmStyle  = codeStyle{lineComments: []string{"//"}, blockComments: true, charQuotes: true}
+	rustStyle = jvmStyle
+	phpStyle  = codeStyle{lineComments: []string{"//", "#"}, blockComments: true}
+	pyStyle   = codeStyle{lineComments: []string{"#"}, tripleQuotes: true}
+	rubyStyle = codeStyle{lineComments: []string{"#"}}
+)
+
+// mask blanks out comments and the contents of string literals, keeping
+// offsets and newlines, so brackets can be matched and matches inside
+// comments told apart.
+func (f *routeFile) mask(style codeStyle) {
+	b := []byte(f.content)
+	blank := func(from, to int) {
+		for i := from; i < to && i < len(b); i++ {
+			if b[i] != '\n' {
+				b[i] = ' '
+			}
+		}
+	}
+	hasAt := func(i int, s string) bool { return strings.HasPrefix(f.content[i:], s) }
+
+	for i := 0; i < len(b); i++ {
+		c := f.content[i]
+		if style.blockComments && hasAt(i, "/*") {
+			end := strings.Index(f.content[i+2:], "*/")
+			if end < 0 {
+				end = len(b)
+			} else {
+				end += i + 4
+			}
+			blank(i, end)
+			i = end - 1
+			continue
+		}
+		if lineComment := func() bool {
+			for _, prefix := range style.lineComments {
+				if hasAt(i, prefix) {
+					return true
+				}
+			}
+			return false
+		}(); lineComment {
+			end := strings.IndexByte(f.content[i:], '\n')
+			if end < 0 {
+				end = len(b) - i
+			}
+			blank(i, i+end)
+			i += end - 1
+			continue
+		}
+		if style.tripleQuotes && (hasAt(i, `"""`) || hasAt(i, "'''")) {
+			quote := f.content[i : i+3]
+			end := strings.Index(f.content[i+3:], quote)
+			if end < 0 {
+				end = len(b) - i - 3
+			}
+			blank(i+3, i+3+end)
+			i += end + 5
+			continue
+		}
+		if c == '\'' && style.charQuotes {
+			// 'a' or '\n'; anything else is a lifetime or a generic label
+			if end := strings.IndexByte(f.content[i+1:min(len(b), i+12)], '\''); end >= 0 &&
+				(end == 1 || f.content[i+1] == '\\') {
+				blank(i+1, i+1+end)
+				i += end + 1
+			}
+			continue
+		}
+		if c == '"' || c == '\'' || (c == '`' && style.backticks) {
+			j := i + 1
+			for ; j < len(b) && f.content[j] != c; j++ {
+				if f.content[j] == '\\' && c != '`' {
+					j++
+				} else if f.content[j] == '\n' && c != '`' {
+					break
+				}
+			}
+			blank(i+1, j)
+			i = j
+		}
+	}
+	f.masked = string(b)
+}
+
+// blockEnd returns the offset of the bracket closing the one at open, or
+// the end of the file.
+func (f *routeFile) blockEnd(open int) int {
+	depth := 0
+	for i := open; i < len(f.masked); i++ {
+		switch f.masked[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(f.masked)
+}
+
+// nextBlock returns the span of the first bracket of the given kind at or
+// after offset, or ok=false when there is none.
+func (f *routeFile) nextBlock(offset int, open byte) (start, end int, ok bool) {
+	i := strings.IndexByte(f.masked[offset:], open)
+	if i < 0 {
+		return 0, 0, false
+	}
+	return offset + i, f.blockEnd(offset + i), true
+}
+
+// statementEnd returns where the expression containing offset ends: at the
+// bracket closing its enclosing group, or a ";" outside brackets.
+func (f *routeFile) statementEnd(offset int) int {
+	depth := 0
+	for i := offset; i < len(f.masked); i++ {
+		switch f.masked[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		case ';':
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return len(f.masked)
+}
+
+// args splits the text between the brackets at open and its closing bracket
+// on top-level commas.
+func (f *routeFile) args(open int) []string {
+	end := f.blockEnd(open)
+	var args []string
+	depth, start := 0, open+1
+	for i := open + 1; i < end && i < len(f.masked); i++ {
+		switch f.masked[i] {
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, strings.TrimSpace(f.content[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	if last := strings.TrimSpace(f.content[start:min(end, len(f.content))]); last != "" {
+		args = append(args, last)
+	}
+	return args
+}
+
+var (
+	quotedString = regexp.MustCompile("[\"'`]([^\"'`]*)[\"'`]")
+	keywordArg   = regexp.MustCompile(`^(\w+)\s*=\s*(.*)$`) // name=value arguments (Python, Kotlin/Java annotations)
+)
+
+// unquote returns the first string literal in s.
+func unquote(s string) (string, bool) {
+	m := quotedString.FindStringSubmatch(s)
+	if m == nil {
+		return "", false
+	}
+	return m[1], true
+}
+
+func isTestFile(name string) bool {
+	lower := strings.ToLower(name)
+	return strings.HasSuffix(lower, "_test.go") || strings.HasPrefix(lower, "test_") ||
+		strings.HasSuffix(lower, "_test.py") || strings.HasSuffix(lower, "_spec.rb") ||
+		strings.HasSuffix(lower, "test.java") || strings.HasSuffix(lower, "test.kt") ||
+		strings.HasSuffix(lower, "test.php") || strings.Contains(lower, ".test.") || strings.Contains(lower, ".spec.")
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package debugging
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	goFuncDecl   = regexp.MustCompile(`(?m)^func\s+(?:\([^)]*\)\s*)?(\w+)\s*(?:\[[^\]]*\]\s*)?\(`)
+	goGroup      = regexp.MustCompile(`\b(\w+)\s*:?=\s*(\w+)\.Group\(\s*"([^"]*)"`)
+	goRouteBlock = regexp.MustCompile(`\b\w+\.Route\(\s*"([^"]*)"\s*,\s*func`)
+	goRouteCall  = regexp.MustCompile(`\b(\w+)\.(GET|POST|PUT|PATCH|DELETE|HEAD|OPTIONS|CONNECT|TRACE|Get|Post|Put|Patch|Delete|Head|Options|Connect|Trace|Any|All)\(\s*"([^"]*)"`)
+	goMethodCall = regexp.MustCompile(`\b(\w+)\.(?:Handle|Add|Method|MethodFunc)\(\s*"([A-Z]+)"\s*,\s*"([^"]*)"`)
+	goMatchCall  = regexp.MustCompile(`\b(\w+)\.Match\(\s*\[\]string\{([^}]*)\}\s*,\s*"([^"]*)"`)
+	goHandle     = regexp.MustCompile(`\b(\w+)\.(?:Handle|HandleFunc)\(\s*"((?:[A-Z]+\s+)?/[^"]*)"`)
+	goMount      = regexp.MustCompile(`\b(\w+)\.Mount\(\s*"([^"]*)"\s*,\s*(?:\w+\.)*(\w+)(\()?`)
+	goGroupCall  = regexp.MustCompile(`\b(?:\w+\.)?(\w+)\(\s*(\w+)\s*\)`)
+)
+
+// goMatch is a pattern match in a Go file, processed in source order.
+type goMatch struct {
+	kind int
+	loc  []int
+}
+
+const (
+	goKindGroup = iota
+	goKindRoute
+	goKindMethod
+	goKindMatch
+	goKindHandle
+	goKindMount
+	goKindCall
+)
+
+// extractGoRoutes reads gin, echo, chi, fiber and net/http routes:
+//
+//	api := r.Group("/api")              // gin, echo, fiber groups
+//	api.GET("/users/:id", h)            // r.Get, app.All, e.Any, ...
+//	r.Handle("GET", "/x", h)            // gin Handle, echo/fiber Add, chi Method
+//	r.Route("/users", func(r chi.Router) { r.Get("/{id}", h) })
+//	r.Mount("/admin", adminRouter())    // chi and fiber mounts
+//	mux.HandleFunc("GET /items/{id}", h)
+//
+// Routes declared inside a function are in its scope, so a call such as
+// registerUserRoutes(api) serves them below the group's prefix.
+func extractGoRoutes(f *routeFile, t *routeTable) {
+	f.mask(goStyle)
+
+	var funcStarts []int
+	for _, m := range goFuncDecl.FindAllStringSubmatchIndex(f.content, -1) {
+		name := f.content[m[2]:m[3]]
+		funcStarts = append(funcStarts, m[0])
+		if _, end, ok := f.nextBlock(m[1]-1, '('); ok {
+			if start, bodyEnd, ok := f.nextBlock(end, '{'); ok {
+				f.addRegion(start, bodyEnd, "", "gofn:"+name)
+			}
+		}
+	}
+	for _, m := range goRouteBlock.FindAllStringSubmatchIndex(f.content, -1) {
+		if start, end, ok := f.nextBlock(m[1], '{'); ok && f.inCode(m[0]) {
+			f.addRegion(start, end, f.content[m[2]:m[3]], "")
+		}
+	}
+
+	var matches []goMatch
+	for kind, re := range []*regexp.Regexp{goGroup, goRouteCall, goMethodCall, goMatchCall, goHandle, goMount, goGroupCall} {
+		for _, loc := range re.FindAllStringSubmatchIndex(f.content, -1) {
+			if f.inCode(loc[0]) {
+				matches = append(matches, goMatch{kind: kind, loc: loc})
+			}
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].loc[0] < matches[j].loc[0] })
+
+	// prefixes of group variables, in source order; they are local to
+	// the function declaring them
+	groups := make(map[string]string)
+	group := func(name string) string { return groups[name] }
+	varScope := func(name string) string { return "govar:" + f.rel + ":" + name }
+	sub := func(loc []int, n int) string {
+		if loc[2*n] < 0 {
+			return ""
+		}
+		return f.content[loc[2*n]:loc[2*n+1]]
+	}
+
+	for _, m := range matches {
+		offset := m.loc[0]
+		for len(funcStarts) > 0 && funcStarts[0] <= offset {
+			groups = make(map[string]string)
+			funcStarts = funcStarts[1:]
+		}
+		switch m.kind {
+		case goKindGroup:
+			groups[sub(m.loc, 1)] = group(sub(m.loc, 2)) + "/" + sub(m.loc, 3)
+		case goKindRoute:
+			receiver, method, path := sub(m.loc, 1), sub(m.loc, 2), sub(m.loc, 3)
+			if path != "" && !strings.HasPrefix(path, "/") {
+				continue
+			}
+			if method == "Any" || method == "All" {
+				method = "ANY"
+			}
+			t.add(f, offset, method, group(receiver)+"/"+path, varScope(receiver))
+		case goKindMethod:
+			receiver := sub(m.loc, 1)
+			t.add(f, offset, sub(m.loc, 2), group(receiver)+"/"+sub(m.loc, 3), varScope(receiver))
+		case goKindMatch:
+			receiver := sub(m.loc, 1)
+			for _, method := range strings.Split(sub(m.loc, 2), ",") {
+				if method, ok := unquote(method); ok {
+					t.add(f, offset, method, group(receiver)+"/"+sub(m.loc, 3), varScope(receiver))
+				}
+			}
+		case goKindHandle:
+			// chi Handle("/x", h) serves every method; Go 1.22 patterns
+			// may start with one: "GET /items/{id}"
+			receiver, pattern := sub(m.loc, 1), sub(m.loc, 2)
+			method, path, ok := strings.Cut(pattern, " ")
+			if !ok {
+				method, path = "ANY", pattern
+			}
+			t.add(f, offset, method, group(receiver)+"/"+strings.TrimSpace(path), varScope(receiver))
+		case goKindMount:
+			receiver, prefix, target := sub(m.loc, 1), sub(m.loc, 2), sub(m.loc, 3)
+			scope := varScope(target)
+			if sub(m.loc, 4) == "(" {
+				scope = "gofn:" + target
+			}
+			t.mount(f, offset, scope, group(receiver)+"/"+prefix)
+		case goKindCall:
+			fn, arg := sub(m.loc, 1), sub(m.loc, 2)
+			if prefix, ok := groups[arg]; ok && fn != "Group" {
+				t.mount(f, offset, "gofn:"+fn, prefix)
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package debugging
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	laravelRoute    = regexp.MustCompile(`Route::(get|post|put|patch|delete|options|any)\(\s*['"]([^'"]*)['"]`)
+	laravelMatch    = regexp.MustCompile(`Route::match\(\s*\[([^\]]*)\]\s*,\s*['"]([^'"]*)['"]`)
+	laravelResource = regexp.MustCompile(`Route::(resource|apiResource)\(\s*['"]([^'"]*)['"]`)
+	laravelGroup    = regexp.MustCompile(`Route::[^;]*?\bgroup\(`)
+	laravelPrefix   = regexp.MustCompile(`(?:\bprefix\(\s*|['"]prefix['"]\s*=>\s*)['"]([^'"]*)['"]`)
+	laravelOnly     = regexp.MustCompile(`(?:\b|['"])(only|except)['"]?\s*(?:\(|=>)\s*\[([^\]]*)\]`)
+)
+
+// laravelActions are the routes of a resource controller, in the order
+// `php artisan route:list` shows them; apiResource drops create and edit.
+var laravelActions = []struct {
+	name, method, suffix string
+	member, form         bool
+}{
+	{"index", "GET", "", false, false},
+	{"create", "GET", "/create", false, true},
+	{"store", "POST", "", false, false},
+	{"show", "GET", "", true, false},
+	{"edit", "GET", "/edit", true, true},
+	{"update", "PUT", "", true, false},
+	{"update", "PATCH", "", true, false},
+	{"destroy", "DELETE", "", true, false},
+}
+
+// extractLaravelRoutes reads Laravel route files:
+//
+//	Route::get('/users/{id}', [UserController::class, 'show']);
+//	Route::match(['get', 'post'], '/search', ...);
+//	Route::prefix('admin')->group(function () { ... });
+//	Route::group(['prefix' => 'v1'], function () { ... });
+//	Route::apiResource('photos.comments', CommentController::class)->only(['index', 'show']);
+//
+// Routes in routes/api.php are served below /api, as the framework's
+// RouteServiceProvider mounts them.
+func extractLaravelRoutes(f *routeFile, t *routeTable) {
+	f.mask(phpStyle)
+
+	if strings.HasSuffix("/"+f.rel, "/routes/api.php") {
+		f.addRegion(0, len(f.content), "api", "")
+	}
+	for _, m := range laravelGroup.FindAllStringIndex(f.content, -1) {
+		if !f.inCode(m[0]) {
+			continue
+		}
+		open := m[1] - 1
+		// the prefix is in the chain or the group's attribute array, not
+		// in the closure, which may hold groups of its own
+		attributes := f.content[m[0]:open]
+		if args := f.args(open); len(args) > 0 && strings.HasPrefix(args[0], "[") {
+			attributes += args[0]
+		}
+		prefix := ""
+		if p := laravelPrefix.FindStringSubmatch(attributes); p != nil {
+			prefix = p[1]
+		}
+		f.addRegion(open, f.blockEnd(open), prefix, "")
+	}
+
+	for _, m := range laravelRoute.FindAllStringSubmatchIndex(f.content, -1) {
+		if f.inCode(m[0]) {
+			t.add(f, m[0], f.content[m[2]:m[3]], f.content[m[4]:m[5]])
+		}
+	}
+	for _, m := range laravelMatch.FindAllStringSubmatchIndex(f.content, -1) {
+		if !f.inCode(m[0]) {
+			continue
+		}
+		for _, method := range quotedString.FindAllStringSubmatch(f.content[m[2]:m[3]], -1) {
+			t.add(f, m[0], method[1], f.content[m[4]:m[5]])
+		}
+	}
+	for _, m := range laravelResource.FindAllStringSubmatchIndex(f.content, -1) {
+		if !f.inCode(m[0]) {
+			continue
+		}
+		kind, name := f.content[m[2]:m[3]], f.content[m[4]:m[5]]
+		base, param := laravelResourcePath(name)
+		only, except := laravelActionFilter(f.content[m[0]:f.statementEnd(m[0])])
+		for _, action := range laravelActions {
+			switch {
+			case action.form && kind == "apiResource",
+				only != nil && !containsString(only, action.name),
+				containsString(except, action.name):
+				continue
+			}
+			p := base
+			if action.member {
+				p += "/{" + param + "}"
+			}
+			t.add(f, m[0], action.method, p+action.suffix)
+		}
+	}
+}
+
+// laravelResourcePath returns the path of a resource and the name of its
+// member parameter. Dotted names nest: "photos.comments" is served at
+// photos/{photo}/comments/{comment}.
+func laravelResourcePath(name string) (string, string) {
+	parts := strings.Split(name, ".")
+	var segments []string
+	for _, part := range parts[:len(parts)-1] {
+		segments = append(segments, part, "{"+singular(part)+"}")
+	}
+	last := parts[len(parts)-1]
+	return strings.Join(append(segments, last), "/"), singular(last)
+}
+
+// laravelActionFilter reads ->only([...]) and ->except([...]), or the
+// 'only' and 'except' options, from a resource declaration.
+func laravelActionFilter(declaration string) (only, except []string) {
+	for _, m := range laravelOnly.FindAllStringSubmatch(declaration, -1) {
+		var actions []string
+		for _, action := range quotedString.FindAllStringSubmatch(m[2], -1) {
+			actions = append(actions, action[1])
+		}
+		if m[1] == "only" {
+			only = append(only, actions...)
+		} else {
+			except = append(except, actions...)
+		}
+	}
+	return only, except
+}
+
+// singular turns a resource name into its parameter name, the way Laravel
+// and Rails do for regular English plurals.
+func singular(name string) string {
+	name = strings.ReplaceAll(name, "-", "_")
+	switch {
+	case strings.HasSuffix(name, "ies"):
+		return strings.TrimSuffix(name, "ies") + "y"
+	case strings.HasSuffix(name, "sses"), strings.HasSuffix(name, "xes"),
+		strings.HasSuffix(name, "ches"), strings.HasSuffix(name, "shes"):
+		return strings.TrimSuffix(name, "es")
+	case strings.HasSuffix(name, "s") && !strings.HasSuffix(name, "ss"):
+		return strings.TrimSuffix(name, "s")
+	}
+	return name
+}
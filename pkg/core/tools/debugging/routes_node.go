@@ -0,0 +1,219 @@
+package debugging
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+var jsExtensions = []string{".js", ".ts", ".mjs", ".cjs"}
+
+var (
+	jsRequire     = regexp.MustCompile(`\b(?:const|let|var)\s+(\w+)\s*=\s*require\(\s*['"]([^'"]+)['"]\s*\)`)
+	jsImport      = regexp.MustCompile(`\bimport\s+(?:(\w+)\s*,?\s*)?(?:\*\s+as\s+(\w+)|\{([^}]*)\})?\s*from\s+['"]([^'"]+)['"]`)
+	jsBasePath    = regexp.MustCompile(`\b(\w+)\s*=\s*(?:new\s+Hono(?:<[^>]*>)?\([^)]*\)|(\w+))\s*\.basePath\(\s*['"\x60]([^'"\x60]*)['"\x60]`)
+	jsRouteCall   = regexp.MustCompile(`\b(\w+)\.(get|post|put|patch|delete|head|options|all)\(\s*['"\x60]([^'"\x60]*)['"\x60]`)
+	jsOnCall      = regexp.MustCompile(`\b(\w+)\.on\(\s*(\[[^\]]*\]|['"][A-Za-z]+['"])\s*,\s*['"\x60](/[^'"\x60]*)['"\x60]`)
+	jsRouteChain  = regexp.MustCompile(`\b(\w+)\.route\(\s*['"\x60](/[^'"\x60]*)['"\x60]\s*\)`)
+	jsChainMethod = regexp.MustCompile(`^\s*\.\s*(get|post|put|patch|delete|head|options|all)\s*\(`)
+	jsMount       = regexp.MustCompile(`\b(\w+)\.(use|route)\(\s*['"\x60](/[^'"\x60]*)['"\x60]\s*,`)
+	jsRequireArg  = regexp.MustCompile(`^require\(\s*['"]([^'"]+)['"]\s*\)`)
+)
+
+// jsClients are HTTP client receivers whose get/post calls send requests
+// rather than declare routes.
+var jsClients = map[string]bool{
+	"axios": true, "http": true, "https": true, "client": true, "httpClient": true,
+	"request": true, "agent": true, "supertest": true, "superagent": true, "got": true, "ky": true,
+}
+
+// extractJSRoutes reads Express and Hono routes:
+//
+//	router.get('/users/:id', handler)       // app.post, api.all, ...
+//	router.route('/books').get(list).post(create)
+//	app.use('/api/v1', usersRouter)         // Express mounts, also require('./users')
+//	const api = new Hono().basePath('/api') // Hono
+//	app.route('/books', books)              // Hono mounts
+//
+// Every file is a scope named after its module path, so a router imported
+// from another file is served below the prefix it is mounted at.
+func extractJSRoutes(f *routeFile, t *routeTable) {
+	f.mask(jsStyle)
+
+	module := strings.TrimSuffix(f.rel, path.Ext(f.rel))
+	f.scopes = []string{"js:" + module}
+	if path.Base(module) == "index" {
+		f.scopes = append(f.scopes, "js:"+path.Dir(module))
+	}
+	dir := path.Dir(f.rel)
+	resolveModule := func(spec string) (string, bool) {
+		if !strings.HasPrefix(spec, ".") {
+			return "", false
+		}
+		resolved := path.Join(dir, spec)
+		return "js:" + strings.TrimSuffix(resolved, path.Ext(resolved)), true
+	}
+
+	imports := make(map[string]string) // local name -> module scope
+	for _, m := range jsRequire.FindAllStringSubmatch(f.content, -1) {
+		if scope, ok := resolveModule(m[2]); ok {
+			imports[m[1]] = scope
+		}
+	}
+	for _, m := range jsImport.FindAllStringSubmatch(f.content, -1) {
+		scope, ok := resolveModule(m[4])
+		if !ok {
+			continue
+		}
+		names := []string{m[1], m[2]}
+		for _, name := range strings.Split(m[3], ",") {
+			if fields := strings.Fields(name); len(fields) > 0 {
+				names = append(names, fields[len(fields)-1]) // { router as users }
+			}
+		}
+		for _, name := range names {
+			if name != "" {
+				imports[name] = scope
+			}
+		}
+	}
+
+	prefixes := make(map[string]string) // Hono basePath
+	for _, m := range jsBasePath.FindAllStringSubmatch(f.content, -1) {
+		prefixes[m[1]] = prefixes[m[2]] + "/" + m[3]
+	}
+	varScope := func(name string) string { return "jsvar:" + f.rel + ":" + name }
+	targetScope := func(expr string) (string, bool) {
+		expr = strings.TrimSpace(expr)
+		if m := jsRequireArg.FindStringSubmatch(expr); m != nil {
+			return resolveModule(m[1])
+		}
+		name, _, _ := strings.Cut(expr, ".")
+		if scope, ok := imports[name]; ok {
+			return scope, true
+		}
+		return varScope(name), isIdentifier(name)
+	}
+
+	for _, m := range jsRouteCall.FindAllStringSubmatchIndex(f.content, -1) {
+		receiver, method, p := f.content[m[2]:m[3]], f.content[m[4]:m[5]], f.content[m[6]:m[7]]
+		if !strings.HasPrefix(p, "/") || !f.inCode(m[0]) || jsClients[receiver] {
+			// app.get('env'), axios.get('/api/users'), ...
+			continue
+		}
+		if method == "all" {
+			method = "ANY"
+		}
+		t.add(f, m[0], method, prefixes[receiver]+p, varScope(receiver))
+	}
+	for _, m := range jsOnCall.FindAllStringSubmatchIndex(f.content, -1) {
+		receiver, methods, p := f.content[m[2]:m[3]], f.content[m[4]:m[5]], f.content[m[6]:m[7]]
+		for _, method := range quotedString.FindAllStringSubmatch(methods, -1) {
+			t.add(f, m[0], method[1], prefixes[receiver]+p, varScope(receiver))
+		}
+	}
+	for _, m := range jsRouteChain.FindAllStringSubmatchIndex(f.content, -1) {
+		if !f.inCode(m[0]) {
+			continue
+		}
+		receiver, p := f.content[m[2]:m[3]], f.content[m[4]:m[5]]
+		// follow the chain: .get(...).post(...)
+		for i := m[1]; i < len(f.masked); {
+			c := jsChainMethod.FindStringSubmatchIndex(f.masked[i:])
+			if c == nil {
+				break
+			}
+			method := f.masked[i+c[2] : i+c[3]]
+			if method == "all" {
+				method = "ANY"
+			}
+			t.add(f, m[0], method, prefixes[receiver]+p, varScope(receiver))
+			i = f.blockEnd(i+c[1]-1) + 1
+		}
+	}
+
+	for _, m := range jsMount.FindAllStringSubmatchIndex(f.content, -1) {
+		if !f.inCode(m[0]) {
+			continue
+		}
+		receiver, p := f.content[m[2]:m[3]], f.content[m[6]:m[7]]
+		open := m[5]
+		args := f.args(open)
+		if len(args) < 2 {
+			continue
+		}
+		// app.use('/api', auth, limiter, router): the router comes last
+		if target, ok := targetScope(args[len(args)-1]); ok {
+			t.mount(f, m[0], target, prefixes[receiver]+p, varScope(receiver))
+		}
+	}
+}
+
+var (
+	nestController   = regexp.MustCompile(`@Controller\(`)
+	nestRoute        = regexp.MustCompile(`@(Get|Post|Put|Patch|Delete|Head|Options|All)\(`)
+	nestGlobalPrefix = regexp.MustCompile(`\.setGlobalPrefix\(\s*['"\x60]([^'"\x60]*)['"\x60]`)
+	nestPathOption   = regexp.MustCompile(`\bpath\s*:\s*['"\x60]([^'"\x60]*)['"\x60]`)
+)
+
+// extractNestRoutes reads NestJS controllers: the @Controller('users')
+// prefix applies to the @Get(':id'), @Post() ... handlers of its class, and
+// app.setGlobalPrefix('api') to every controller.
+func extractNestRoutes(f *routeFile, t *routeTable) {
+	f.mask(jsStyle)
+
+	for _, m := range nestController.FindAllStringIndex(f.content, -1) {
+		if !f.inCode(m[0]) {
+			continue
+		}
+		args := f.args(m[1] - 1)
+		prefix := ""
+		if len(args) > 0 {
+			if p, ok := unquote(args[0]); ok && !strings.HasPrefix(args[0], "{") {
+				prefix = p
+			} else if opt := nestPathOption.FindStringSubmatch(args[0]); opt != nil {
+				prefix = opt[1] // @Controller({ path: 'users', version: '1' })
+			}
+		}
+		class := strings.Index(f.masked[m[1]:], "class ")
+		if class < 0 {
+			continue
+		}
+		if start, end, ok := f.nextBlock(m[1]+class, '{'); ok {
+			f.addRegion(start, end, prefix, "nest:controller")
+		}
+	}
+
+	for _, m := range nestRoute.FindAllStringSubmatchIndex(f.content, -1) {
+		if !f.inCode(m[0]) || !containsString(f.scopesAt(m[0]), "nest:controller") {
+			continue
+		}
+		method := f.content[m[2]:m[3]]
+		if method == "All" {
+			method = "ANY"
+		}
+		p := ""
+		if args := f.args(m[1] - 1); len(args) > 0 {
+			p, _ = unquote(args[0])
+		}
+		t.add(f, m[0], method, p, "nest:app")
+	}
+
+	for _, m := range nestGlobalPrefix.FindAllStringSubmatchIndex(f.content, -1) {
+		if f.inCode(m[0]) {
+			t.mount(f, m[0], "nest:app", f.content[m[2]:m[3]])
+		}
+	}
+}
+
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, c := range s {
+		if c != '_' && c != '$' && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') && (i == 0 || c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
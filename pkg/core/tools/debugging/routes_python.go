@@ -0,0 +1,290 @@
+package debugging
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	pyFromImport  = regexp.MustCompile(`(?m)^\s*from\s+([.\w]+)\s+import\s+(\([^)]*\)|[^\n]+)`)
+	pyImport      = regexp.MustCompile(`(?m)^\s*import\s+([\w.]+)(?:\s+as\s+(\w+))?`)
+	pyAPIRouter   = regexp.MustCompile(`\b(\w+)\s*=\s*(?:\w+\.)?APIRouter\(`)
+	pyBlueprint   = regexp.MustCompile(`\b(\w+)\s*=\s*(?:\w+\.)?Blueprint\(`)
+	pyDecorator   = regexp.MustCompile(`@(\w+)\.(get|post|put|patch|delete|head|options|trace|route|api_route)\(`)
+	pyURLRule     = regexp.MustCompile(`\b(\w+)\.add_url_rule\(`)
+	pyResource    = regexp.MustCompile(`\b\w+\.add_resource\(\s*\w+\s*,`)
+	pyInclude     = regexp.MustCompile(`\b(\w+)\.(include_router|register_blueprint)\(`)
+	pyDRFRouter   = regexp.MustCompile(`\b(\w+)\s*=\s*(?:\w+\.)*(?:Default|Simple)Router\(`)
+	pyDRFRegister = regexp.MustCompile(`\b(\w+)\.register\(\s*r?["']([^"']*)["']`)
+	pyURLPattern  = regexp.MustCompile(`\b(path|re_path|url)\(\s*r?["']([^"']*)["']\s*,\s*`)
+	pyMethods     = regexp.MustCompile(`methods\s*=\s*[\[(]([^\])]*)[\])]`)
+	pyRegexGroup  = regexp.MustCompile(`\(\?P<(\w+)>[^)]*\)`)
+)
+
+// extractPythonRoutes reads FastAPI, Flask and Django routes:
+//
+//	router = APIRouter(prefix="/users")          // FastAPI
+//	@router.get("/{user_id}")
+//	app.include_router(users.router, prefix="/api/v1")
+//	bp = Blueprint("users", __name__, url_prefix="/users")   // Flask
+//	@bp.route("/<int:id>", methods=["GET", "PUT"])
+//	app.register_blueprint(bp, url_prefix="/api")
+//	path("api/", include("users.urls"))          // Django urls.py
+//	router.register(r"orders", OrderViewSet)     // Django REST framework
+//
+// Routers, blueprints and url modules are scoped by module path, so a mount
+// in one file finds the routes of another through its imports.
+func extractPythonRoutes(f *routeFile, t *routeTable) {
+	f.mask(pyStyle)
+
+	module := strings.TrimSuffix(f.rel, ".py")
+	module = strings.TrimSuffix(module, "/__init__")
+	module = strings.ReplaceAll(module, "/", ".")
+	f.scopes = pyScopes("pymod:", module, "")
+	imports := pyImports(f.content, module)
+
+	// resolve turns an expression naming a router ("router", "users.router",
+	// "api_router") into its scope
+	varScope := func(name string) string { return "py:" + module + ":" + name }
+	resolve := func(expr string) string {
+		expr = strings.TrimSpace(expr)
+		i := strings.LastIndex(expr, ".")
+		if i < 0 {
+			target, ok := imports[expr]
+			if !ok {
+				return varScope(expr)
+			}
+			// an imported name: from .users import router as users_router
+			j := strings.LastIndex(target, ".")
+			return "py:" + target[:max(j, 0)] + ":" + target[j+1:]
+		}
+		// an attribute of a module: users.router, api.v1.users.router
+		mod := expr[:i]
+		if target, ok := imports[mod]; ok {
+			mod = target
+		} else if head, rest, _ := strings.Cut(mod, "."); imports[head] != "" {
+			mod = imports[head] + "." + rest
+		}
+		return "py:" + mod + ":" + expr[i+1:]
+	}
+	scopesOf := func(name string) []string {
+		return pyScopes("py:", module, ":"+name)
+	}
+
+	prefixes := make(map[string]string) // APIRouter prefixes, local to the router's routes
+	for _, m := range pyAPIRouter.FindAllStringSubmatchIndex(f.content, -1) {
+		if prefix, ok := pyKeywordArg(f.args(m[1]-1), "prefix"); ok {
+			prefixes[f.content[m[2]:m[3]]] = prefix
+		}
+	}
+	for _, m := range pyBlueprint.FindAllStringSubmatchIndex(f.content, -1) {
+		if prefix, ok := pyKeywordArg(f.args(m[1]-1), "url_prefix"); ok {
+			for _, scope := range scopesOf(f.content[m[2]:m[3]]) {
+				t.defaults[scope] = prefix
+			}
+		}
+	}
+
+	for _, m := range pyDecorator.FindAllStringSubmatchIndex(f.content, -1) {
+		if !f.inCode(m[0]) {
+			continue
+		}
+		receiver, kind := f.content[m[2]:m[3]], f.content[m[4]:m[5]]
+		args := f.args(m[1] - 1)
+		p, ok := pyPath(args)
+		if !ok {
+			continue
+		}
+		methods := []string{kind}
+		if kind == "route" || kind == "api_route" {
+			methods = pyMethodList(f.content[m[1]:f.blockEnd(m[1]-1)])
+		}
+		for _, method := range methods {
+			t.add(f, m[0], method, prefixes[receiver]+"/"+p, scopesOf(receiver)...)
+		}
+	}
+	for _, m := range pyURLRule.FindAllStringSubmatchIndex(f.content, -1) {
+		receiver := f.content[m[2]:m[3]]
+		if p, ok := pyPath(f.args(m[1] - 1)); ok && f.inCode(m[0]) {
+			for _, method := range pyMethodList(f.content[m[1]:f.blockEnd(m[1]-1)]) {
+				t.add(f, m[0], method, p, scopesOf(receiver)...)
+			}
+		}
+	}
+	for _, m := range pyResource.FindAllStringIndex(f.content, -1) {
+		// Flask-RESTful: the resource class decides the methods
+		open := strings.IndexByte(f.content[m[0]:], '(') + m[0]
+		for _, arg := range f.args(open)[1:] {
+			if p, ok := unquote(arg); ok && strings.HasPrefix(p, "/") && f.inCode(m[0]) {
+				t.add(f, m[0], "ANY", p)
+			}
+		}
+	}
+
+	for _, m := range pyInclude.FindAllStringSubmatchIndex(f.content, -1) {
+		args := f.args(m[1] - 1)
+		if len(args) == 0 || !f.inCode(m[0]) {
+			continue
+		}
+		receiver, kind := f.content[m[2]:m[3]], f.content[m[4]:m[5]]
+		keyword := "prefix"
+		if kind == "register_blueprint" {
+			keyword = "url_prefix"
+		}
+		prefix, explicit := pyKeywordArg(args, keyword)
+		t.mount(f, m[0], resolve(args[0]), prefixes[receiver]+"/"+prefix, scopesOf(receiver)...)
+		if kind == "register_blueprint" && !explicit {
+			// Flask: the blueprint's own url_prefix applies
+			t.mounts[len(t.mounts)-1].inherit = true
+		}
+	}
+
+	if !strings.Contains(f.content, "urlpatterns") {
+		return
+	}
+
+	// Django REST framework routers
+	for _, m := range pyDRFRegister.FindAllStringSubmatchIndex(f.content, -1) {
+		receiver, prefix := f.content[m[2]:m[3]], f.content[m[4]:m[5]]
+		if !f.inCode(m[0]) || !pyDRFRouter.MatchString(f.content[:m[0]]) {
+			continue
+		}
+		for _, method := range []string{"GET", "POST"} {
+			t.add(f, m[0], method, prefix, scopesOf(receiver)...)
+		}
+		for _, method := range []string{"GET", "PUT", "PATCH", "DELETE"} {
+			t.add(f, m[0], method, prefix+"/{pk}", scopesOf(receiver)...)
+		}
+	}
+
+	// Django urlpatterns
+	for _, m := range pyURLPattern.FindAllStringSubmatchIndex(f.content, -1) {
+		if !f.inCode(m[0]) {
+			continue
+		}
+		kind, p := f.content[m[2]:m[3]], f.content[m[4]:m[5]]
+		if kind != "path" {
+			p = djangoRegexPath(p)
+		}
+		view := f.content[m[1]:]
+		if !strings.HasPrefix(view, "include(") {
+			t.add(f, m[0], "ANY", p)
+			continue
+		}
+		args := f.args(m[1] + len("include"))
+		if len(args) == 0 {
+			continue
+		}
+		target := strings.Trim(strings.TrimSpace(args[0]), "()")
+		if name, ok := unquote(target); ok {
+			// include("users.urls") or include(("users.urls", "users"))
+			t.mount(f, m[0], "pymod:"+name, p)
+		} else if router, ok := strings.CutSuffix(target, ".urls"); ok {
+			// include(router.urls)
+			t.mount(f, m[0], resolve(router), p)
+		}
+	}
+}
+
+// pyScopes returns a scope per dotted suffix of a module ("app.api.users",
+// "api.users", "users"), so relative and absolute imports both find it.
+func pyScopes(kind, module, suffix string) []string {
+	var scopes []string
+	parts := strings.Split(module, ".")
+	for i := range parts {
+		scopes = append(scopes, kind+strings.Join(parts[i:], ".")+suffix)
+	}
+	return scopes
+}
+
+// pyImports maps each imported name to the dotted path it refers to, with
+// relative imports resolved against the importing module.
+func pyImports(content, module string) map[string]string {
+	imports := make(map[string]string)
+	pkg := module[:max(strings.LastIndex(module, "."), 0)]
+	for _, m := range pyFromImport.FindAllStringSubmatch(content, -1) {
+		from := m[1]
+		if strings.HasPrefix(from, ".") {
+			base := pkg
+			for _, c := range from[1:] {
+				if c != '.' {
+					break
+				}
+				base = base[:max(strings.LastIndex(base, "."), 0)]
+			}
+			from = strings.Trim(base+"."+strings.TrimLeft(from, "."), ".")
+		}
+		for _, name := range strings.Split(strings.Trim(m[2], "()"), ",") {
+			fields := strings.Fields(name)
+			if len(fields) == 0 || fields[0] == "*" {
+				continue
+			}
+			alias := fields[0]
+			if len(fields) == 3 && fields[1] == "as" {
+				alias = fields[2]
+			}
+			imports[alias] = strings.Trim(from+"."+fields[0], ".")
+		}
+	}
+	for _, m := range pyImport.FindAllStringSubmatch(content, -1) {
+		alias := m[2]
+		if alias == "" {
+			alias = m[1]
+		}
+		imports[alias] = m[1]
+	}
+	return imports
+}
+
+// pyPath returns the path argument of a route call: the first positional
+// string, or rule=/path=.
+func pyPath(args []string) (string, bool) {
+	if len(args) == 0 {
+		return "", false
+	}
+	if p, ok := pyKeywordArg(args, "path"); ok {
+		return p, true
+	}
+	if p, ok := pyKeywordArg(args, "rule"); ok {
+		return p, true
+	}
+	if keywordArg.MatchString(args[0]) {
+		return "", false
+	}
+	return unquote(args[0])
+}
+
+// pyKeywordArg returns the string value of a keyword argument.
+func pyKeywordArg(args []string, name string) (string, bool) {
+	for _, arg := range args {
+		if m := keywordArg.FindStringSubmatch(arg); m != nil && m[1] == name {
+			return unquote(m[2])
+		}
+	}
+	return "", false
+}
+
+// pyMethodList reads methods=[...] from route arguments; GET without it.
+func pyMethodList(args string) []string {
+	m := pyMethods.FindStringSubmatch(args)
+	if m == nil {
+		return []string{"GET"}
+	}
+	var methods []string
+	for _, item := range strings.Split(m[1], ",") {
+		if method, ok := unquote(item); ok {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// djangoRegexPath turns a re_path pattern into a path: anchors go and
+// named groups become {name}.
+func djangoRegexPath(pattern string) string {
+	pattern = strings.TrimPrefix(pattern, "^")
+	pattern = strings.TrimSuffix(pattern, "$")
+	pattern = pyRegexGroup.ReplaceAllString(pattern, "{$1}")
+	return path.Clean("/" + strings.ReplaceAll(pattern, `\.`, "."))
+}
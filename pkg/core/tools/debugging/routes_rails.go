@@ -0,0 +1,251 @@
+package debugging
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	railsStatement = regexp.MustCompile(`^(\w+)\b\s*\(?\s*(.*?)\)?\s*(?:\bdo\b(?:\s*\|[^|]*\|)?)?$`)
+	railsBlock     = regexp.MustCompile(`\bdo\b(?:\s*\|[^|]*\|)?\s*$`)
+	railsOption    = regexp.MustCompile(`^(?::(\w+)\s*=>|(\w+):)\s*(.*)$`)
+	railsSymbol    = regexp.MustCompile(`^:(\w+)$`)
+	railsOptional  = regexp.MustCompile(`\([^()]*\)`)
+	railsWord      = regexp.MustCompile(`\w+`)
+)
+
+// railsResourceActions are the routes resources declares; new and edit only
+// render forms and are left out.
+var railsResourceActions = []struct {
+	name, method string
+	member       bool
+}{
+	{"index", "GET", false},
+	{"create", "POST", false},
+	{"show", "GET", true},
+	{"update", "PUT", true},
+	{"update", "PATCH", true},
+	{"destroy", "DELETE", true},
+}
+
+// railsScope is an open do ... end block of a routes file.
+type railsScope struct {
+	kind  string // namespace, scope, resources, resource, member, collection or "" for other blocks
+	path  string
+	param string // member parameter of resources
+}
+
+// extractRailsRoutes reads config/routes.rb and the files it draws from
+// config/routes/:
+//
+//	namespace :api do
+//	  scope "v1" do
+//	    resources :photos, only: [:index, :show] do
+//	      resources :comments                # /api/v1/photos/{photo_id}/comments
+//	      get :preview, on: :member          # /api/v1/photos/{id}/preview
+//	    end
+//	  end
+//	end
+//	match "search", to: "search#index", via: [:get, :post]
+func extractRailsRoutes(f *routeFile, t *routeTable) {
+	if !strings.HasSuffix("/"+f.rel, "/config/routes.rb") && !strings.Contains("/"+f.rel, "/config/routes/") {
+		return
+	}
+	f.mask(rubyStyle)
+
+	var stack []railsScope
+	for i, start := range f.lineStarts {
+		end := len(f.content)
+		if i+1 < len(f.lineStarts) {
+			end = f.lineStarts[i+1] - 1
+		}
+		code := rubyCode(f.content[start:end], f.masked[start:end])
+		trimmed := strings.TrimSpace(code)
+		if trimmed == "" {
+			continue
+		}
+		offset := start + strings.Index(code, trimmed)
+		opensBlock := railsBlock.MatchString(f.masked[start:end])
+		if trimmed == "end" || strings.HasPrefix(trimmed, "end ") || strings.HasPrefix(trimmed, "end.") {
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			continue
+		}
+
+		m := railsStatement.FindStringSubmatch(trimmed)
+		if m == nil {
+			if opensBlock {
+				stack = append(stack, railsScope{})
+			}
+			continue
+		}
+		keyword := m[1]
+		positional, options := railsArgs(m[2])
+		prefix := func(on string) string { return railsPrefix(stack, on) }
+		block := railsScope{}
+
+		switch keyword {
+		case "namespace":
+			if len(positional) > 0 {
+				block = railsScope{kind: keyword, path: positional[0]}
+				if p, ok := options["path"]; ok {
+					block.path = railsString(p)
+				}
+			}
+		case "scope":
+			block.kind = keyword
+			if len(positional) > 0 {
+				block.path = positional[0]
+			} else if p, ok := options["path"]; ok {
+				block.path = railsString(p)
+			}
+			block.path = railsOptional.ReplaceAllString(block.path, "") // scope "(:locale)"
+		case "resources", "resource":
+			only, onlyGiven := railsList(options["only"])
+			except, _ := railsList(options["except"])
+			for _, name := range positional {
+				base := name
+				if p, ok := options["path"]; ok {
+					base = railsString(p)
+				}
+				param := "id"
+				if p, ok := options["param"]; ok {
+					param = railsString(p)
+				}
+				for _, action := range railsResourceActions {
+					if (onlyGiven && !containsString(only, action.name)) || containsString(except, action.name) ||
+						(keyword == "resource" && action.name == "index") {
+						continue
+					}
+					p := base
+					if action.member && keyword == "resources" {
+						p += "/{" + param + "}"
+					}
+					t.add(f, offset, action.method, prefix("")+"/"+p)
+				}
+				block = railsScope{kind: keyword, path: base, param: param}
+				if param == "id" {
+					block.param = singular(name) + "_id"
+				} else {
+					block.param = singular(name) + "_" + param
+				}
+			}
+		case "member", "collection":
+			block.kind = keyword
+		case "get", "post", "put", "patch", "delete", "match":
+			if len(positional) == 0 {
+				break
+			}
+			p := railsOptional.ReplaceAllString(positional[0], "")
+			methods := []string{keyword}
+			if keyword == "match" {
+				methods, _ = railsList(options["via"])
+				if len(methods) == 0 || containsString(methods, "all") {
+					methods = []string{"ANY"}
+				}
+			}
+			on := railsString(options["on"])
+			for _, method := range methods {
+				t.add(f, offset, method, prefix(on)+"/"+p)
+			}
+		case "root":
+			t.add(f, offset, "GET", prefix("")+"/")
+		}
+
+		if opensBlock {
+			stack = append(stack, block)
+		}
+	}
+}
+
+// railsPrefix joins the paths of the open blocks. A route directly inside
+// resources is nested below its member, {photo_id}; inside member (or with
+// on: :member) below {id}; inside collection below the resources itself.
+func railsPrefix(stack []railsScope, on string) string {
+	var sb strings.Builder
+	for i, s := range stack {
+		if s.path != "" {
+			sb.WriteString("/" + s.path)
+		}
+		if s.kind != "resources" {
+			continue
+		}
+		mode := on
+		if i+1 < len(stack) {
+			mode = stack[i+1].kind
+		}
+		switch mode {
+		case "member":
+			sb.WriteString("/{id}")
+		case "collection":
+		default:
+			sb.WriteString("/{" + s.param + "}")
+		}
+	}
+	return sb.String()
+}
+
+// railsArgs splits the arguments of a routes statement into positional
+// names (symbols or strings) and options (key: value, :key => value).
+func railsArgs(args string) ([]string, map[string]string) {
+	var positional []string
+	options := make(map[string]string)
+	depth, start := 0, 0
+	var parts []string
+	for i, c := range args {
+		switch c {
+		case '[', '(', '{':
+			depth++
+		case ']', ')', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, args[start:i])
+				start = i + 1
+			}
+		}
+	}
+	parts = append(parts, args[start:])
+
+	for _, part := range parts {
+		part = strings.TrimSpace(part)
+		if m := railsOption.FindStringSubmatch(part); m != nil {
+			options[m[1]+m[2]] = strings.TrimSpace(m[3])
+		} else if m := railsSymbol.FindStringSubmatch(part); m != nil {
+			positional = append(positional, m[1])
+		} else if s, ok := unquote(part); ok {
+			positional = append(positional, s) // also get "search" => "search#index"
+		}
+	}
+	return positional, options
+}
+
+// railsString returns the value of a symbol or string option.
+func railsString(value string) string {
+	if m := railsSymbol.FindStringSubmatch(value); m != nil {
+		return m[1]
+	}
+	s, _ := unquote(value)
+	return s
+}
+
+// railsList reads a list option: [:index, :show], %i[index show] or :all.
+func railsList(value string) ([]string, bool) {
+	if value == "" {
+		return nil, false
+	}
+	value = strings.TrimPrefix(strings.TrimPrefix(value, "%i"), "%w")
+	return railsWord.FindAllString(value, -1), true
+}
+
+// rubyCode drops a trailing comment from a line; "#{...}" inside strings is
+// kept.
+func rubyCode(content, masked string) string {
+	for i := 0; i < len(content); i++ {
+		if content[i] == '#' && masked[i] == ' ' && strings.TrimSpace(masked[i:]) == "" {
+			return content[:i]
+		}
+	}
+	return content
+}
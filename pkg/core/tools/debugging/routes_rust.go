@@ -0,0 +1,163 @@
+package debugging
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	rustFn        = regexp.MustCompile(`\bfn\s+(\w+)\s*(?:<[^>]*>)?\s*\(`)
+	rustAttribute = regexp.MustCompile(`#\[(get|post|put|patch|delete|head|options|trace|connect)\(\s*"([^"]*)"`)
+	rustRouteAttr = regexp.MustCompile(`#\[route\(\s*"([^"]*)"([^\]]*)\]`)
+	rustAttrVerb  = regexp.MustCompile(`method\s*=\s*"(\w+)"`)
+	rustRoute     = regexp.MustCompile(`\.route\(\s*"([^"]*)"\s*,`)
+	rustResource  = regexp.MustCompile(`web::resource\(\s*"([^"]*)"`)
+	rustScope     = regexp.MustCompile(`web::scope\(\s*"([^"]*)"`)
+	rustService   = regexp.MustCompile(`\.(service|configure)\(\s*(?:\w+::)*(\w+)\s*\)`)
+	rustNest      = regexp.MustCompile(`\.(nest|merge)\(`)
+	rustRouterVar = regexp.MustCompile(`\blet\s+(?:mut\s+)?(\w+)\s*(?::[^=]+)?=\s*(?:\w+::)*Router::(?:new|with_state)\b`)
+	rustVerb      = regexp.MustCompile(`\b(get|post|put|patch|delete|head|options|trace|any)\s*\(`)
+	rustWebVerb   = regexp.MustCompile(`web::(get|post|put|patch|delete|head|trace)\(\)`)
+)
+
+// extractRustRoutes reads Actix Web and Axum routes:
+//
+//	#[get("/users/{id}")] async fn get_user(...)     // Actix macros
+//	web::scope("/api").service(get_user).route("/health", web::get().to(health))
+//	App::new().configure(config)                    // config(cfg) registers more
+//	Router::new().route("/users/:id", get(show).delete(remove))  // Axum
+//	Router::new().nest("/api", api_routes())
+//
+// Functions are scopes, so a handler or a router built in one function is
+// served below the scope or nest that refers to it.
+func extractRustRoutes(f *routeFile, t *routeTable) {
+	f.mask(rustStyle)
+
+	fnScope := func(name string) string { return "rsfn:" + name }
+	varScope := func(name string) string { return "rsvar:" + f.rel + ":" + name }
+
+	for _, m := range rustFn.FindAllStringSubmatchIndex(f.content, -1) {
+		if !f.inCode(m[0]) {
+			continue
+		}
+		if _, end, ok := f.nextBlock(m[1]-1, '('); ok {
+			if start, bodyEnd, ok := f.nextBlock(end, '{'); ok {
+				f.addRegion(start, bodyEnd, "", fnScope(f.content[m[2]:m[3]]))
+			}
+		}
+	}
+	for _, m := range rustScope.FindAllStringSubmatchIndex(f.content, -1) {
+		if f.inCode(m[0]) {
+			f.addRegion(m[0], f.statementEnd(m[0]), f.content[m[2]:m[3]], "")
+		}
+	}
+	for _, m := range rustRouterVar.FindAllStringSubmatchIndex(f.content, -1) {
+		if f.inCode(m[0]) {
+			f.addRegion(m[0], f.statementEnd(m[1]), "", varScope(f.content[m[2]:m[3]]))
+		}
+	}
+	for _, m := range rustNest.FindAllStringSubmatchIndex(f.content, -1) {
+		if !f.inCode(m[0]) {
+			continue
+		}
+		open := m[1] - 1
+		args := f.args(open)
+		var prefix, target string
+		switch {
+		case f.content[m[2]:m[3]] == "merge" && len(args) == 1:
+			target = args[0]
+		case len(args) == 2:
+			prefix, _ = unquote(args[0])
+			target = args[1]
+		default:
+			continue
+		}
+		if strings.Contains(target, "Router::") {
+			// Router::new().route(...) written in place
+			start := open + strings.Index(f.content[open:], target)
+			f.addRegion(start, start+len(target), prefix, "")
+			continue
+		}
+		name, called := strings.CutSuffix(target, "()")
+		name = name[strings.LastIndex(name, ":")+1:]
+		if !isIdentifier(name) {
+			continue
+		}
+		if called {
+			t.mount(f, m[0], fnScope(name), prefix)
+		} else {
+			t.mount(f, m[0], varScope(name), prefix)
+		}
+	}
+
+	for _, m := range rustAttribute.FindAllStringSubmatchIndex(f.content, -1) {
+		if fn := rustFn.FindStringSubmatch(f.content[m[1]:]); fn != nil && f.inCode(m[0]) {
+			t.add(f, m[0], f.content[m[2]:m[3]], f.content[m[4]:m[5]], fnScope(fn[1]))
+		}
+	}
+	for _, m := range rustRouteAttr.FindAllStringSubmatchIndex(f.content, -1) {
+		fn := rustFn.FindStringSubmatch(f.content[m[1]:])
+		if fn == nil || !f.inCode(m[0]) {
+			continue
+		}
+		for _, verb := range rustAttrVerb.FindAllStringSubmatch(f.content[m[4]:m[5]], -1) {
+			t.add(f, m[0], verb[1], f.content[m[2]:m[3]], fnScope(fn[1]))
+		}
+	}
+	for _, m := range rustRoute.FindAllStringSubmatchIndex(f.content, -1) {
+		if !f.inCode(m[0]) {
+			continue
+		}
+		open := m[0] + len(".route")
+		args := f.args(open)
+		if len(args) < 2 {
+			continue
+		}
+		for _, method := range rustMethods(args[1]) {
+			t.add(f, m[0], method, f.content[m[2]:m[3]])
+		}
+	}
+	for _, m := range rustResource.FindAllStringSubmatchIndex(f.content, -1) {
+		if !f.inCode(m[0]) {
+			continue
+		}
+		// web::resource("/x").route(web::get().to(h)).route(web::post()...)
+		methods := rustWebVerb.FindAllStringSubmatch(f.masked[m[1]:f.statementEnd(m[0])], -1)
+		if len(methods) == 0 {
+			t.add(f, m[0], "ANY", f.content[m[2]:m[3]])
+		}
+		for _, method := range methods {
+			t.add(f, m[0], method[1], f.content[m[2]:m[3]])
+		}
+	}
+	for _, m := range rustService.FindAllStringSubmatchIndex(f.content, -1) {
+		if f.inCode(m[0]) {
+			t.mount(f, m[0], fnScope(f.content[m[4]:m[5]]), "")
+		}
+	}
+}
+
+// rustMethods reads the methods of a route's handler argument: Actix
+// web::get().to(h), Axum get(h).post(h2) or any(h). Calls nested inside
+// handler arguments are not methods.
+func rustMethods(handler string) []string {
+	var methods []string
+	for _, m := range rustVerb.FindAllStringSubmatchIndex(handler, -1) {
+		depth := 0
+		for _, c := range handler[:m[0]] {
+			switch c {
+			case '(', '[', '{':
+				depth++
+			case ')', ']', '}':
+				depth--
+			}
+		}
+		if depth == 0 {
+			methods = append(methods, handler[m[2]:m[3]])
+		}
+	}
+	if len(methods) == 0 {
+		return []string{"ANY"}
+	}
+	return methods
+}
@@ -0,0 +1,112 @@
+package debugging
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	springMapping         = regexp.MustCompile(`@(Get|Post|Put|Patch|Delete|Request)Mapping\b`)
+	springRequestMethod   = regexp.MustCompile(`RequestMethod\.([A-Z]+)`)
+	springAnnotation      = regexp.MustCompile(`@[\w.]+(?:\s*\([^)]*\))?`)
+	springClassDecl       = regexp.MustCompile(`^\s*(?:(?:public|protected|private|abstract|final|open|internal|data|static)\s+)*(?:class|interface)\b`)
+	springContextPath     = regexp.MustCompile(`(?m)^\s*(?:server\.servlet\.context-path|spring\.webflux\.base-path)\s*[=:]\s*(\S+)`)
+	springContextPathYAML = regexp.MustCompile(`(?m)^\s*(?:context-path|base-path)\s*:\s*["']?([^\s"'#]+)`)
+)
+
+// extractSpringRoutes reads Spring MVC and WebFlux controllers in Java and
+// Kotlin. A @RequestMapping on the class prefixes the @GetMapping,
+// @PostMapping, ... methods in it; server.servlet.context-path in
+// application.properties or application.yml prefixes every route.
+func extractSpringRoutes(f *routeFile, t *routeTable) {
+	switch path.Ext(f.rel) {
+	case ".properties", ".yml", ".yaml":
+		if !strings.HasPrefix(path.Base(f.rel), "application") {
+			return
+		}
+		re := springContextPath
+		if path.Ext(f.rel) != ".properties" {
+			re = springContextPathYAML
+		}
+		if m := re.FindStringSubmatchIndex(f.content); m != nil {
+			t.mount(f, m[0], "spring:app", f.content[m[2]:m[3]])
+		}
+		return
+	}
+
+	f.mask(jvmStyle)
+	type mapping struct {
+		offset  int
+		methods []string
+		paths   []string
+	}
+	var methods []mapping
+
+	for _, m := range springMapping.FindAllStringSubmatchIndex(f.content, -1) {
+		if !f.inCode(m[0]) {
+			continue
+		}
+		kind := f.content[m[2]:m[3]]
+		end := m[1]
+		var args []string
+		if open := len(f.masked[m[1]:]) - len(strings.TrimLeft(f.masked[m[1]:], " \t")); m[1]+open < len(f.masked) && f.masked[m[1]+open] == '(' {
+			args = f.args(m[1] + open)
+			end = f.blockEnd(m[1]+open) + 1
+		}
+		paths := springPaths(args)
+
+		rest := springAnnotation.ReplaceAllString(f.masked[end:min(end+1000, len(f.masked))], "")
+		if springClassDecl.MatchString(rest) {
+			if start, classEnd, ok := f.nextBlock(end, '{'); ok {
+				f.addRegion(start, classEnd, paths[0], "")
+			}
+			continue
+		}
+
+		verbs := []string{strings.ToUpper(kind)}
+		if kind == "Request" {
+			verbs = nil
+			for _, rm := range springRequestMethod.FindAllStringSubmatch(strings.Join(args, ","), -1) {
+				verbs = append(verbs, rm[1])
+			}
+			if len(verbs) == 0 {
+				verbs = []string{"ANY"}
+			}
+		}
+		methods = append(methods, mapping{offset: m[0], methods: verbs, paths: paths})
+	}
+
+	// class regions are known only once every annotation has been read
+	for _, m := range methods {
+		for _, method := range m.methods {
+			for _, p := range m.paths {
+				t.add(f, m.offset, method, p, "spring:app")
+			}
+		}
+	}
+}
+
+// springPaths reads the paths of a mapping annotation: the positional value
+// or value=/path=, a single string or an array. No paths means "".
+func springPaths(args []string) []string {
+	var paths []string
+	for i, arg := range args {
+		value := arg
+		if m := keywordArg.FindStringSubmatch(arg); m != nil {
+			if m[1] != "value" && m[1] != "path" {
+				continue
+			}
+			value = m[2]
+		} else if i > 0 {
+			continue
+		}
+		for _, s := range quotedString.FindAllStringSubmatch(value, -1) {
+			paths = append(paths, s[1])
+		}
+	}
+	if len(paths) == 0 {
+		return []string{""}
+	}
+	return paths
+}
@@ -0,0 +1,311 @@
+package debugging
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExtractRoutesPrefixes(t *testing.T) {
+	tests := []struct {
+		framework string
+		files     map[string]string
+		want      []string
+	}{
+		{
+			framework: "gin",
+			files: map[string]string{
+				"main.go": `package main
+
+func main() {
+	r := gin.Default()
+	api := r.Group("/api")
+	v1 := api.Group("/v1")
+	v1.GET("/users/:id", getUser)
+	registerOrders(v1)
+}
+
+func registerOrders(g *gin.RouterGroup) {
+	g.POST("/orders", createOrder)
+}
+`,
+			},
+			want: []string{"POST /api/v1/orders", "GET /api/v1/users/{id}"},
+		},
+		{
+			framework: "chi",
+			files: map[string]string{
+				"main.go": `package main
+
+func main() {
+	r := chi.NewRouter()
+	r.Route("/articles", func(r chi.Router) {
+		r.Get("/", list)
+		r.Route("/{articleID}", func(r chi.Router) {
+			r.Put("/", update)
+		})
+	})
+	r.Mount("/admin", adminRouter())
+}
+
+func adminRouter() http.Handler {
+	r := chi.NewRouter()
+	r.Get("/stats", stats)
+	return r
+}
+`,
+			},
+			want: []string{"GET /admin/stats", "GET /articles", "PUT /articles/{articleID}"},
+		},
+		{
+			framework: "express",
+			files: map[string]string{
+				"app.js": `
+const users = require('./routes/users');
+import orders from './routes/orders';
+app.use('/api/users', auth, users);
+app.use('/api/orders', orders);
+`,
+				"routes/users.js": `
+const router = express.Router();
+router.get('/:id', show);
+router.route('/').get(list).post(create);
+module.exports = router;
+`,
+				"routes/orders.ts": `
+const router = Router();
+router.delete('/:orderId', remove);
+axios.get('/not/a/route');
+export default router;
+`,
+			},
+			want: []string{"DELETE /api/orders/{orderId}", "GET /api/users", "POST /api/users", "GET /api/users/{id}"},
+		},
+		{
+			framework: "hono",
+			files: map[string]string{
+				"index.ts": `
+const app = new Hono().basePath('/api');
+const books = new Hono();
+books.get('/:id', (c) => c.json({}));
+app.route('/books', books);
+`,
+			},
+			want: []string{"GET /api/books/{id}"},
+		},
+		{
+			framework: "flask",
+			files: map[string]string{
+				"app/__init__.py": `
+from .users import bp as users_bp
+app.register_blueprint(users_bp, url_prefix="/api/users")
+`,
+				"app/users.py": `
+bp = Blueprint("users", __name__, url_prefix="/users")
+
+@bp.route("/<int:user_id>", methods=["GET", "PUT"])
+def user(user_id): ...
+`,
+			},
+			want: []string{"GET /api/users/{user_id}", "PUT /api/users/{user_id}"},
+		},
+		{
+			framework: "fastapi",
+			files: map[string]string{
+				"main.py": `
+from app.routers import items
+app.include_router(items.router, prefix="/api")
+`,
+				"app/routers/items.py": `
+router = APIRouter(prefix="/items")
+
+@router.get("/{item_id}")
+async def read_item(item_id: int): ...
+`,
+			},
+			want: []string{"GET /api/items/{item_id}"},
+		},
+		{
+			framework: "django",
+			files: map[string]string{
+				"project/urls.py": `
+urlpatterns = [
+    path("api/", include("shop.urls")),
+]
+`,
+				"shop/urls.py": `
+router = DefaultRouter()
+router.register(r"orders", OrderViewSet)
+urlpatterns = [
+    path("products/<int:pk>/", views.product),
+    path("", include(router.urls)),
+]
+`,
+			},
+			want: []string{
+				"GET /api/orders", "POST /api/orders",
+				"DELETE /api/orders/{pk}", "GET /api/orders/{pk}", "PATCH /api/orders/{pk}", "PUT /api/orders/{pk}",
+				"ANY /api/products/{pk}",
+			},
+		},
+		{
+			framework: "nestjs",
+			files: map[string]string{
+				"src/main.ts": `
+const app = await NestFactory.create(AppModule);
+app.setGlobalPrefix('api');
+`,
+				"src/users.controller.ts": `
+@Controller('users')
+export class UsersController {
+  @Get(':id')
+  findOne(@Param('id') id: string) {}
+
+  @Post()
+  create(@Body() dto: CreateUserDto) {}
+}
+`,
+			},
+			want: []string{"POST /api/users", "GET /api/users/{id}"},
+		},
+		{
+			framework: "spring",
+			files: map[string]string{
+				"src/main/resources/application.properties": "server.servlet.context-path=/app\n",
+				"src/main/java/UserController.java": `
+@RestController
+@RequestMapping("/api/users")
+public class UserController {
+    @GetMapping("/{id}")
+    public User get(@PathVariable Long id) { return null; }
+
+    @RequestMapping(value = "/search", method = {RequestMethod.GET, RequestMethod.POST})
+    public List<User> search() { return null; }
+}
+`,
+			},
+			want: []string{"GET /app/api/users/search", "POST /app/api/users/search", "GET /app/api/users/{id}"},
+		},
+		{
+			framework: "laravel",
+			files: map[string]string{
+				"routes/api.php": `<?php
+Route::prefix('v1')->group(function () {
+    Route::get('/users/{id}', [UserController::class, 'show']);
+    Route::apiResource('photos', PhotoController::class)->only(['index', 'show']);
+});
+`,
+				"routes/web.php": `<?php
+Route::group(['prefix' => 'admin'], function () {
+    Route::match(['get', 'post'], '/login', [AuthController::class, 'login']);
+});
+`,
+			},
+			want: []string{
+				"GET /admin/login", "POST /admin/login",
+				"GET /api/v1/photos", "GET /api/v1/photos/{photo}", "GET /api/v1/users/{id}",
+			},
+		},
+		{
+			framework: "rails",
+			files: map[string]string{
+				"config/routes.rb": `Rails.application.routes.draw do
+  namespace :api do
+    resources :posts, only: [:index, :show] do
+      resources :comments, only: :create
+      get :preview, on: :member
+    end
+  end
+  match "search", to: "search#index", via: [:get, :post] # site search
+end
+`,
+			},
+			want: []string{
+				"GET /api/posts", "GET /api/posts/{id}", "GET /api/posts/{id}/preview",
+				"POST /api/posts/{post_id}/comments", "GET /search", "POST /search",
+			},
+		},
+		{
+			framework: "actix",
+			files: map[string]string{
+				"src/main.rs": `
+#[get("/users/{id}")]
+async fn get_user(path: web::Path<u32>) -> impl Responder { "" }
+
+fn config(cfg: &mut web::ServiceConfig) {
+    cfg.service(web::scope("/api").service(get_user).route("/health", web::get().to(health)));
+}
+
+fn main() {
+    App::new().configure(config);
+}
+`,
+			},
+			want: []string{"GET /api/health", "GET /api/users/{id}"},
+		},
+		{
+			framework: "axum",
+			files: map[string]string{
+				"src/main.rs": `
+fn user_routes() -> Router {
+    Router::new().route("/users/:id", get(show).delete(remove))
+}
+
+async fn main() {
+    let app = Router::new()
+        .nest("/api", user_routes())
+        .route("/", get(|| async { "ok" }));
+}
+`,
+			},
+			want: []string{"GET /", "DELETE /api/users/{id}", "GET /api/users/{id}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.framework, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tt.files {
+				writeSource(t, root, name, content)
+			}
+			routes, err := ExtractRoutes(root, tt.framework)
+			if err != nil {
+				t.Fatalf("ExtractRoutes failed: %v", err)
+			}
+			if got := routeKeys(routes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("routes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindHandlerUsesRouteTable(t *testing.T) {
+	root := t.TempDir()
+	writeSource(t, root, "main.go", `package main
+
+func main() {
+	api := r.Group("/api")
+	api.GET("/users/:id", getUser)
+	api.Any("/users/:id", fallback)
+}
+`)
+
+	out, err := NewFindHandlerTool(root).Execute(`{"endpoint": "GET /api/users/42", "framework": "gin"}`)
+	if err != nil {
+		t.Fatalf("Execute failed: %v", err)
+	}
+	var info HandlerInfo
+	if err := json.Unmarshal([]byte(out), &info); err != nil {
+		t.Fatalf("output is not a HandlerInfo: %v\n%s", err, out)
+	}
+	if info.File != "main.go" || info.Line != 5 {
+		t.Errorf("handler = %s:%d, want main.go:5", info.File, info.Line)
+	}
+	if !strings.Contains(info.Content, "getUser") {
+		t.Errorf("content does not show the handler:\n%s", info.Content)
+	}
+	if len(info.RelatedFiles) != 1 {
+		t.Errorf("related files = %v, want the ANY route", info.RelatedFiles)
+	}
+}
@@ -0,0 +1,427 @@
+package debugging
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/blackcoderx/falcon/pkg/core/tools/spec_ingester"
+)
+
+// SpecDriftTool compares the route table of the codebase with the endpoints
+// ingested into .falcon/spec.yaml.
+type SpecDriftTool struct {
+	workDir      string
+	falconDir    string
+	reportWriter *shared.ReportWriter
+}
+
+// NewSpecDriftTool creates a new check_spec_drift tool.
+func NewSpecDriftTool(workDir, falconDir string, reportWriter *shared.ReportWriter) *SpecDriftTool {
+	return &SpecDriftTool{
+		workDir:      workDir,
+		falconDir:    falconDir,
+		reportWriter: reportWriter,
+	}
+}
+
+// SpecDriftParams defines input for check_spec_drift.
+type SpecDriftParams struct {
+	Framework  string `json:"framework,omitempty"`   // default: every supported framework
+	Path       string `json:"path,omitempty"`        // source directory, relative to the project (default: project root)
+	ReportName string `json:"report_name,omitempty"` // e.g. "spec_drift_users_api"
+}
+
+// SpecEndpoint is an endpoint declared in the spec.
+type SpecEndpoint struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+}
+
+// MethodMismatch is a path served by the code and declared in the spec with
+// different methods.
+type MethodMismatch struct {
+	Path        string   `json:"path"`
+	SpecMethods []string `json:"spec_methods"`
+	CodeMethods []string `json:"code_methods"`
+	Routes      []Route  `json:"routes"`
+}
+
+// ParamMismatch is a route that matches a spec endpoint except for its path
+// parameters: different names, or a parameter on one side and a literal
+// segment on the other.
+type ParamMismatch struct {
+	Spec  SpecEndpoint `json:"spec"`
+	Route Route        `json:"route"`
+}
+
+// SpecDrift is the difference between the code and the spec.
+type SpecDrift struct {
+	Routes           int              `json:"routes"`    // routes found in the code
+	Endpoints        int              `json:"endpoints"` // endpoints in the spec
+	Matched          int              `json:"matched"`
+	Undocumented     []Route          `json:"undocumented"`  // in the code, not in the spec
+	Unimplemented    []SpecEndpoint   `json:"unimplemented"` // in the spec, no handler found
+	MethodMismatches []MethodMismatch `json:"method_mismatches"`
+	ParamMismatches  []ParamMismatch  `json:"param_mismatches"`
+}
+
+// HasDrift reports whether the code and the spec disagree.
+func (d *SpecDrift) HasDrift() bool {
+	return len(d.Undocumented) > 0 || len(d.Unimplemented) > 0 ||
+		len(d.MethodMismatches) > 0 || len(d.ParamMismatches) > 0
+}
+
+// SpecDriftOutcome is the result of a drift check.
+type SpecDriftOutcome struct {
+	Drift      *SpecDrift
+	Summary    string
+	ReportPath string
+}
+
+func (t *SpecDriftTool) Name() string {
+	return "check_spec_drift"
+}
+
+func (t *SpecDriftTool) Description() string {
+	return "Statically extract the route table from the codebase and diff it against the ingested spec (.falcon/spec.yaml): undocumented routes, spec endpoints with no handler, and method/path-parameter mismatches. Run ingest_spec first."
+}
+
+func (t *SpecDriftTool) Parameters() string {
+	return `{
+  "framework": "gin|echo|chi|fiber|fastapi|flask|django|express|hono|nestjs|spring|laravel|rails|actix|axum (default: all)",
+  "path": "internal/api",
+  "report_name": "spec_drift_<api>"
+}`
+}
+
+func (t *SpecDriftTool) Execute(args string) (string, error) {
+	var params SpecDriftParams
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	outcome, err := t.Run(params)
+	if err != nil {
+		return "", err
+	}
+	return outcome.Summary, nil
+}
+
+// Run extracts the routes, diffs them against the spec and writes the
+// report. Drift is not an error; the CLI turns it into a non-zero exit code.
+func (t *SpecDriftTool) Run(params SpecDriftParams) (*SpecDriftOutcome, error) {
+	graph, err := spec_ingester.NewGraphBuilder(t.falconDir).LoadGraph()
+	if err != nil {
+		return nil, err
+	}
+	if graph == nil || len(graph.Endpoints) == 0 {
+		return nil, fmt.Errorf("no endpoints in Knowledge Graph - run ingest_spec first")
+	}
+
+	root := t.workDir
+	if params.Path != "" {
+		root = filepath.Join(t.workDir, params.Path)
+		if filepath.IsAbs(params.Path) {
+			root = params.Path
+		}
+	}
+	routes, err := ExtractRoutes(root, params.Framework)
+	if err != nil {
+		return nil, err
+	}
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("no routes found in %s (frameworks with route extraction: %s)", root, strings.Join(RouteFrameworks(), ", "))
+	}
+
+	drift := DiffSpec(routes, graph)
+	outcome := &SpecDriftOutcome{Drift: drift, Summary: formatDriftSummary(drift)}
+
+	reportPath, err := t.reportWriter.Write(params.ReportName, "spec_drift", formatDriftReport(drift, params.Framework))
+	if err != nil {
+		outcome.Summary += fmt.Sprintf("\n\nWarning: failed to save report: %v", err)
+		return outcome, nil
+	}
+	outcome.ReportPath = reportPath
+	outcome.Summary += fmt.Sprintf("\n\nReport saved to: %s", reportPath)
+	return outcome, nil
+}
+
+// DiffSpec compares code routes with the endpoints of the graph. Spec paths
+// are prefixed with the base path of the spec's server URL ("/v1") unless
+// they already start with it. Routes and endpoints are paired in order:
+// exact matches, then paths served with other methods, then paths that only
+// differ in their parameters; whatever is left is undocumented or
+// unimplemented.
+func DiffSpec(routes []Route, graph *shared.APIKnowledgeGraph) *SpecDrift {
+	endpoints := specEndpoints(graph)
+	drift := &SpecDrift{Routes: len(routes), Endpoints: len(endpoints)}
+
+	usedRoutes := make([]bool, len(routes))
+	routesByShape := make(map[string][]int)
+	for i, route := range routes {
+		shape := pathShape(route.Path)
+		routesByShape[shape] = append(routesByShape[shape], i)
+	}
+
+	// 1. same path shape and method
+	var unmatched []SpecEndpoint
+	for _, ep := range endpoints {
+		found := -1
+		for _, i := range routesByShape[pathShape(ep.Path)] {
+			if routes[i].Method == ep.Method || routes[i].Method == "ANY" {
+				if found < 0 {
+					found = i
+				}
+				usedRoutes[i] = true
+			}
+		}
+		if found < 0 {
+			unmatched = append(unmatched, ep)
+			continue
+		}
+		drift.Matched++
+		if !sameParams(ep.Path, routes[found].Path) {
+			drift.ParamMismatches = append(drift.ParamMismatches, ParamMismatch{Spec: ep, Route: routes[found]})
+		}
+	}
+
+	// 2. same path shape, different methods
+	var remaining []SpecEndpoint
+	mismatches := make(map[string]*MethodMismatch)
+	var shapes []string
+	for _, ep := range unmatched {
+		shape := pathShape(ep.Path)
+		mismatch := mismatches[shape]
+		if mismatch == nil {
+			mismatch = &MethodMismatch{Path: ep.Path}
+			for _, i := range routesByShape[shape] {
+				if !usedRoutes[i] {
+					usedRoutes[i] = true
+					mismatch.Routes = append(mismatch.Routes, routes[i])
+					mismatch.CodeMethods = appendUnique(mismatch.CodeMethods, routes[i].Method)
+				}
+			}
+			if len(mismatch.Routes) == 0 {
+				remaining = append(remaining, ep)
+				continue
+			}
+			mismatches[shape] = mismatch
+			shapes = append(shapes, shape)
+		}
+		mismatch.SpecMethods = appendUnique(mismatch.SpecMethods, ep.Method)
+	}
+	for _, shape := range shapes {
+		drift.MethodMismatches = append(drift.MethodMismatches, *mismatches[shape])
+	}
+
+	// 3. same method, paths that differ only where a parameter is involved
+	for _, ep := range remaining {
+		found := -1
+		for i, route := range routes {
+			if !usedRoutes[i] && (route.Method == ep.Method || route.Method == "ANY") && paramCompatible(ep.Path, route.Path) {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			drift.Unimplemented = append(drift.Unimplemented, ep)
+			continue
+		}
+		usedRoutes[found] = true
+		drift.ParamMismatches = append(drift.ParamMismatches, ParamMismatch{Spec: ep, Route: routes[found]})
+	}
+
+	for i, route := range routes {
+		if !usedRoutes[i] {
+			drift.Undocumented = append(drift.Undocumented, route)
+		}
+	}
+	return drift
+}
+
+// specEndpoints lists the graph's endpoints, sorted and with the spec's
+// base path applied. GraphQL operations ("POST /graphql#user") collapse into
+// their single HTTP endpoint.
+func specEndpoints(graph *shared.APIKnowledgeGraph) []SpecEndpoint {
+	basePath := ""
+	if u, err := url.Parse(graph.BaseURL); err == nil && graph.BaseURL != "" {
+		basePath = strings.TrimRight(u.Path, "/")
+	}
+
+	seen := make(map[string]bool)
+	var endpoints []SpecEndpoint
+	for key := range graph.Endpoints {
+		method, path, ok := strings.Cut(key, " ")
+		if !ok {
+			continue
+		}
+		path, _, _ = strings.Cut(path, "#")
+		path = NormalizeRoutePath(path)
+		if basePath != "" && path != basePath && !strings.HasPrefix(path, basePath+"/") {
+			path = NormalizeRoutePath(basePath + path)
+		}
+		ep := SpecEndpoint{Method: strings.ToUpper(method), Path: path}
+		if id := ep.Method + " " + ep.Path; !seen[id] {
+			seen[id] = true
+			endpoints = append(endpoints, ep)
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+	return endpoints
+}
+
+// pathShape replaces every parameter with "{}", so "/users/{id}" and
+// "/users/{userId}" compare equal.
+func pathShape(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if isPathParam(segment) {
+			segments[i] = "{}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// sameParams reports whether two paths of the same shape name their
+// parameters alike.
+func sameParams(a, b string) bool {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := range as {
+		if isPathParam(as[i]) && as[i] != bs[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// paramCompatible reports whether two paths have the same segments except
+// where at least one of them has a parameter.
+func paramCompatible(a, b string) bool {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	if len(as) != len(bs) {
+		return false
+	}
+	for i := range as {
+		if as[i] != bs[i] && !isPathParam(as[i]) && !isPathParam(bs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func isPathParam(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+func appendUnique(list []string, s string) []string {
+	if containsString(list, s) {
+		return list
+	}
+	return append(list, s)
+}
+
+func formatDriftSummary(d *SpecDrift) string {
+	var sb strings.Builder
+	if d.HasDrift() {
+		sb.WriteString("⚠️ Spec drift detected\n")
+	} else {
+		sb.WriteString("✅ Code and spec agree\n")
+	}
+	sb.WriteString(fmt.Sprintf("Routes in code: %d, endpoints in spec: %d, matched: %d\n", d.Routes, d.Endpoints, d.Matched))
+
+	if len(d.Undocumented) > 0 {
+		sb.WriteString(fmt.Sprintf("\nUndocumented routes (%d):\n", len(d.Undocumented)))
+		for _, r := range d.Undocumented {
+			sb.WriteString(fmt.Sprintf("  ✗ %s %s (%s:%d)\n", r.Method, r.Path, r.File, r.Line))
+		}
+	}
+	if len(d.Unimplemented) > 0 {
+		sb.WriteString(fmt.Sprintf("\nSpec endpoints with no handler (%d):\n", len(d.Unimplemented)))
+		for _, ep := range d.Unimplemented {
+			sb.WriteString(fmt.Sprintf("  ✗ %s %s\n", ep.Method, ep.Path))
+		}
+	}
+	if len(d.MethodMismatches) > 0 {
+		sb.WriteString(fmt.Sprintf("\nMethod mismatches (%d):\n", len(d.MethodMismatches)))
+		for _, m := range d.MethodMismatches {
+			sb.WriteString(fmt.Sprintf("  ✗ %s: spec %s, code %s\n", m.Path, strings.Join(m.SpecMethods, ", "), strings.Join(m.CodeMethods, ", ")))
+		}
+	}
+	if len(d.ParamMismatches) > 0 {
+		sb.WriteString(fmt.Sprintf("\nPath parameter mismatches (%d):\n", len(d.ParamMismatches)))
+		for _, m := range d.ParamMismatches {
+			sb.WriteString(fmt.Sprintf("  ✗ %s: spec %s, code %s (%s:%d)\n", m.Spec.Method, m.Spec.Path, m.Route.Path, m.Route.File, m.Route.Line))
+		}
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+func formatDriftReport(d *SpecDrift, framework string) string {
+	if framework == "" {
+		framework = "all supported"
+	}
+	var sb strings.Builder
+	sb.WriteString("# Spec Drift Report\n\n")
+	sb.WriteString(fmt.Sprintf("**Date:** %s\n", time.Now().Format("2006-01-02 15:04:05")))
+	sb.WriteString(fmt.Sprintf("**Framework:** %s\n", framework))
+	status := "PASS - code and spec agree"
+	if d.HasDrift() {
+		status = "FAIL - drift detected"
+	}
+	sb.WriteString(fmt.Sprintf("**Status:** %s\n\n", status))
+
+	sb.WriteString("## Summary\n\n")
+	sb.WriteString("| Metric | Count |\n|--------|-------|\n")
+	sb.WriteString(fmt.Sprintf("| Routes in code | %d |\n", d.Routes))
+	sb.WriteString(fmt.Sprintf("| Endpoints in spec | %d |\n", d.Endpoints))
+	sb.WriteString(fmt.Sprintf("| Matched | %d |\n", d.Matched))
+	sb.WriteString(fmt.Sprintf("| Undocumented routes | %d |\n", len(d.Undocumented)))
+	sb.WriteString(fmt.Sprintf("| Spec endpoints with no handler | %d |\n", len(d.Unimplemented)))
+	sb.WriteString(fmt.Sprintf("| Method mismatches | %d |\n", len(d.MethodMismatches)))
+	sb.WriteString(fmt.Sprintf("| Path parameter mismatches | %d |\n", len(d.ParamMismatches)))
+
+	if len(d.Undocumented) > 0 {
+		sb.WriteString("\n## Undocumented Routes\n\nServed by the code but missing from the spec.\n\n")
+		sb.WriteString("| Method | Path | Location |\n|--------|------|----------|\n")
+		for _, r := range d.Undocumented {
+			sb.WriteString(fmt.Sprintf("| %s | `%s` | `%s:%d` |\n", r.Method, r.Path, r.File, r.Line))
+		}
+	}
+	if len(d.Unimplemented) > 0 {
+		sb.WriteString("\n## Spec Endpoints With No Handler\n\nDeclared in the spec but no route was found in the code.\n\n")
+		sb.WriteString("| Method | Path |\n|--------|------|\n")
+		for _, ep := range d.Unimplemented {
+			sb.WriteString(fmt.Sprintf("| %s | `%s` |\n", ep.Method, ep.Path))
+		}
+	}
+	if len(d.MethodMismatches) > 0 {
+		sb.WriteString("\n## Method Mismatches\n\n")
+		sb.WriteString("| Path | Spec | Code | Location |\n|------|------|------|----------|\n")
+		for _, m := range d.MethodMismatches {
+			var locations []string
+			for _, r := range m.Routes {
+				locations = append(locations, fmt.Sprintf("`%s:%d`", r.File, r.Line))
+			}
+			sb.WriteString(fmt.Sprintf("| `%s` | %s | %s | %s |\n", m.Path, strings.Join(m.SpecMethods, ", "), strings.Join(m.CodeMethods, ", "), strings.Join(locations, ", ")))
+		}
+	}
+	if len(d.ParamMismatches) > 0 {
+		sb.WriteString("\n## Path Parameter Mismatches\n\n")
+		sb.WriteString("| Method | Spec Path | Code Path | Location |\n|--------|-----------|-----------|----------|\n")
+		for _, m := range d.ParamMismatches {
+			sb.WriteString(fmt.Sprintf("| %s | `%s` | `%s` | `%s:%d` |\n", m.Spec.Method, m.Spec.Path, m.Route.Path, m.Route.File, m.Route.Line))
+		}
+	}
+	return sb.String()
+}
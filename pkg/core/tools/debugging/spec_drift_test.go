@@ -0,0 +1,154 @@
+package debugging
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+)
+
+func writeSource(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func routeKeys(routes []Route) []string {
+	var keys []string
+	for _, r := range routes {
+		keys = append(keys, r.Method+" "+r.Path)
+	}
+	return keys
+}
+
+func TestExtractRoutes(t *testing.T) {
+	root := t.TempDir()
+	writeSource(t, root, "main.go", `package main
+
+func routes(r *gin.Engine) {
+	r.GET("/users", listUsers)
+	r.POST("/users", createUser)
+	r.GET("/users/:id", getUser)
+	r.Handle("DELETE", "/users/:id", deleteUser)
+	r.Any("/files/*filepath", serveFiles)
+}
+`)
+	writeSource(t, root, "main_test.go", `r.GET("/only-in-tests", h)`)
+	writeSource(t, root, "app/api.py", `
+@app.get("/items/{item_id:int}")
+def read_item(item_id: int): ...
+
+@router.post(path="/items")
+def create_item(): ...
+`)
+	writeSource(t, root, "server.js", `
+app.set('port', 3000);
+app.get('env');
+router.put('/orders/:orderId?', update);
+`)
+	writeSource(t, root, "node_modules/lib/index.js", `app.get('/vendored', h)`)
+
+	routes, err := ExtractRoutes(root, "")
+	if err != nil {
+		t.Fatalf("ExtractRoutes failed: %v", err)
+	}
+	want := []string{
+		"ANY /files/{filepath}",
+		"POST /items",
+		"GET /items/{item_id}",
+		"PUT /orders/{orderId}",
+		"GET /users",
+		"POST /users",
+		"DELETE /users/{id}",
+		"GET /users/{id}",
+	}
+	if got := routeKeys(routes); !reflect.DeepEqual(got, want) {
+		t.Errorf("routes = %v, want %v", got, want)
+	}
+	if routes[0].File != "main.go" || routes[0].Line != 8 {
+		t.Errorf("location = %s:%d, want main.go:8", routes[0].File, routes[0].Line)
+	}
+
+	routes, err = ExtractRoutes(root, "fastapi")
+	if err != nil {
+		t.Fatalf("ExtractRoutes failed: %v", err)
+	}
+	if len(routes) != 2 {
+		t.Errorf("fastapi routes = %v, want the 2 Python routes", routeKeys(routes))
+	}
+}
+
+func TestNormalizeRoutePath(t *testing.T) {
+	tests := map[string]string{
+		"/users/:id":          "/users/{id}",
+		"/users/{id:int}/":    "/users/{id}",
+		"/static/*filepath":   "/static/{filepath}",
+		"/posts/<int:postId>": "/posts/{postId}",
+		"//a/*":               "/a/{wildcard}",
+		"/":                   "/",
+	}
+	for in, want := range tests {
+		if got := NormalizeRoutePath(in); got != want {
+			t.Errorf("NormalizeRoutePath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDiffSpec(t *testing.T) {
+	routes := []Route{
+		{Method: "GET", Path: "/v1/users", File: "main.go", Line: 1},
+		{Method: "GET", Path: "/v1/users/{id}", File: "main.go", Line: 2},
+		{Method: "POST", Path: "/v1/orders", File: "main.go", Line: 3},
+		{Method: "GET", Path: "/v1/products/{sku}", File: "main.go", Line: 4},
+		{Method: "GET", Path: "/v1/health", File: "main.go", Line: 5},
+	}
+	graph := &shared.APIKnowledgeGraph{
+		BaseURL: "https://api.example.com/v1",
+		Endpoints: map[string]shared.EndpointAnalysis{
+			"GET /users":           {},
+			"GET /users/{userId}":  {},
+			"PUT /orders":          {},
+			"GET /products/latest": {},
+			"DELETE /users/{id}/x": {},
+			"POST /graphql#user":   {},
+			"POST /graphql#orders": {},
+			"GET /v1/users":        {}, // already prefixed
+		},
+	}
+
+	drift := DiffSpec(routes, graph)
+	if drift.Matched != 2 {
+		t.Errorf("matched = %d, want 2", drift.Matched)
+	}
+	if got := routeKeys(drift.Undocumented); !reflect.DeepEqual(got, []string{"GET /v1/health"}) {
+		t.Errorf("undocumented = %v", got)
+	}
+	wantMissing := []SpecEndpoint{{"POST", "/v1/graphql"}, {"DELETE", "/v1/users/{id}/x"}}
+	if !reflect.DeepEqual(drift.Unimplemented, wantMissing) {
+		t.Errorf("unimplemented = %v, want %v", drift.Unimplemented, wantMissing)
+	}
+	if len(drift.MethodMismatches) != 1 || drift.MethodMismatches[0].Path != "/v1/orders" ||
+		!reflect.DeepEqual(drift.MethodMismatches[0].SpecMethods, []string{"PUT"}) ||
+		!reflect.DeepEqual(drift.MethodMismatches[0].CodeMethods, []string{"POST"}) {
+		t.Errorf("method mismatches = %+v", drift.MethodMismatches)
+	}
+	if len(drift.ParamMismatches) != 2 {
+		t.Fatalf("param mismatches = %+v, want 2", drift.ParamMismatches)
+	}
+	if m := drift.ParamMismatches[0]; m.Spec.Path != "/v1/users/{userId}" || m.Route.Path != "/v1/users/{id}" {
+		t.Errorf("param name mismatch = %+v", m)
+	}
+	if m := drift.ParamMismatches[1]; m.Spec.Path != "/v1/products/latest" || m.Route.Path != "/v1/products/{sku}" {
+		t.Errorf("param/literal mismatch = %+v", m)
+	}
+	if !drift.HasDrift() {
+		t.Error("HasDrift() = false, want true")
+	}
+}
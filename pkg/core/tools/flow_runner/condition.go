@@ -0,0 +1,290 @@
+package flow_runner
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+)
+
+// conditionOps are the comparison operators, longest first so ">=" wins
+// over ">". Word operators need surrounding spaces.
+var conditionOps = []string{" not_contains ", " contains ", " matches ", "==", "!=", ">=", "<=", ">", "<"}
+
+// condition is a parsed expression: "||" of "&&" groups of clauses.
+// Parentheses are not supported; "&&" binds tighter than "||".
+type condition struct {
+	expr string
+	any  [][]clause
+}
+
+// clause is a comparison ("left op right") or, without op, a truthiness test.
+type clause struct {
+	negate bool
+	left   string
+	op     string
+	right  string
+}
+
+// operandResolver turns an operand into a value. ok is false when the
+// operand refers to something that does not exist (an unset variable, a
+// missing header, no response yet).
+type operandResolver func(operand string) (value interface{}, ok bool)
+
+// parseCondition parses expressions such as:
+//
+//	{{role}} == admin
+//	status >= 200 && status < 300
+//	$.items contains "sku-1" || !{{skip}}
+//	header:Content-Type matches ^application/json
+func parseCondition(expr string) (condition, error) {
+	c := condition{expr: expr}
+	for _, group := range splitOutsideQuotes(expr, "||") {
+		var clauses []clause
+		for _, part := range splitOutsideQuotes(group, "&&") {
+			cl, err := parseClause(part)
+			if err != nil {
+				return c, fmt.Errorf("invalid condition '%s': %w", expr, err)
+			}
+			clauses = append(clauses, cl)
+		}
+		c.any = append(c.any, clauses)
+	}
+	return c, nil
+}
+
+func parseClause(s string) (clause, error) {
+	s = strings.TrimSpace(s)
+	var cl clause
+	if s == "" {
+		return cl, fmt.Errorf("empty expression")
+	}
+
+	for {
+		switch {
+		case strings.HasPrefix(s, "!") && !strings.HasPrefix(s, "!="):
+			cl.negate = !cl.negate
+			s = strings.TrimSpace(s[1:])
+			continue
+		case strings.HasPrefix(s, "not "):
+			cl.negate = !cl.negate
+			s = strings.TrimSpace(s[len("not "):])
+			continue
+		}
+		break
+	}
+
+	pos, op := findOperator(s)
+	if pos < 0 {
+		if s == "" {
+			return cl, fmt.Errorf("missing operand")
+		}
+		cl.left = s
+		return cl, nil
+	}
+
+	cl.left = strings.TrimSpace(s[:pos])
+	cl.op = strings.TrimSpace(op)
+	cl.right = strings.TrimSpace(s[pos+len(op):])
+	if cl.left == "" || cl.right == "" {
+		return cl, fmt.Errorf("'%s' needs an operand on both sides", cl.op)
+	}
+	if cl.op == "matches" {
+		if _, err := regexp.Compile(unquote(cl.right)); err != nil {
+			return cl, fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	return cl, nil
+}
+
+// findOperator returns the position of the first operator outside quotes,
+// {{placeholders}}, brackets and parentheses, so JSONPath filters such as
+// $.items[?(@.price > 10)] stay in one operand.
+func findOperator(s string) (int, string) {
+	return scanTopLevel(s, 0, func(rest string) string {
+		for _, op := range conditionOps {
+			if strings.HasPrefix(rest, op) {
+				return op
+			}
+		}
+		return ""
+	})
+}
+
+// splitOutsideQuotes splits s on sep, ignoring separators inside quotes,
+// {{placeholders}}, brackets and parentheses.
+func splitOutsideQuotes(s, sep string) []string {
+	var parts []string
+	start := 0
+	for {
+		pos, _ := scanTopLevel(s, start, func(rest string) string {
+			if strings.HasPrefix(rest, sep) {
+				return sep
+			}
+			return ""
+		})
+		if pos < 0 {
+			return append(parts, s[start:])
+		}
+		parts = append(parts, s[start:pos])
+		start = pos + len(sep)
+	}
+}
+
+// scanTopLevel walks s from start and returns the first position outside
+// quotes, {{placeholders}}, brackets and parentheses where match returns a
+// token, along with that token.
+func scanTopLevel(s string, start int, match func(rest string) string) (int, string) {
+	var quote byte
+	placeholders, nesting := 0, 0
+	for i := start; i < len(s); i++ {
+		ch := s[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+			continue
+		case ch == '"' || ch == '\'':
+			quote = ch
+			continue
+		case strings.HasPrefix(s[i:], "{{"):
+			placeholders++
+			i++
+			continue
+		case strings.HasPrefix(s[i:], "}}") && placeholders > 0:
+			placeholders--
+			i++
+			continue
+		case placeholders > 0:
+			continue
+		case ch == '[' || ch == '(':
+			nesting++
+			continue
+		case (ch == ']' || ch == ')') && nesting > 0:
+			nesting--
+			continue
+		case nesting > 0:
+			continue
+		}
+		if token := match(s[i:]); token != "" {
+			return i, token
+		}
+	}
+	return -1, ""
+}
+
+// eval evaluates the condition with the given operand resolver.
+func (c condition) eval(resolve operandResolver) (bool, error) {
+	for _, group := range c.any {
+		all := true
+		for _, cl := range group {
+			ok, err := cl.eval(resolve)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (cl clause) eval(resolve operandResolver) (bool, error) {
+	left, leftOK := resolve(cl.left)
+	if cl.op == "" {
+		return (leftOK && shared.Truthy(operandValue(left))) != cl.negate, nil
+	}
+	right, rightOK := resolve(cl.right)
+
+	// As in JSONPath filters, a comparison with an operand that does not
+	// exist is false rather than decided by comparing placeholder text.
+	if !leftOK || !rightOK {
+		return cl.negate, nil
+	}
+
+	var result bool
+	switch cl.op {
+	case "==":
+		result = valuesEqual(left, right)
+	case "!=":
+		result = !valuesEqual(left, right)
+	case ">", ">=", "<", "<=":
+		cmp, ok := shared.CompareValues(operandValue(left), operandValue(right))
+		switch {
+		case !ok:
+			result = false
+		case cl.op == ">":
+			result = cmp > 0
+		case cl.op == ">=":
+			result = cmp >= 0
+		case cl.op == "<":
+			result = cmp < 0
+		default:
+			result = cmp <= 0
+		}
+	case "contains", "not_contains":
+		result = shared.ContainsValue(left, right)
+		if cl.op == "not_contains" {
+			result = !result
+		}
+	case "matches":
+		re, err := regexp.Compile(shared.Stringify(right))
+		if err != nil {
+			return false, fmt.Errorf("invalid pattern '%v': %w", right, err)
+		}
+		result = re.MatchString(shared.Stringify(left))
+	}
+	return result != cl.negate, nil
+}
+
+// valuesEqual compares two operands with the JSONPath equality rules after
+// reading text values as literals.
+func valuesEqual(a, b interface{}) bool {
+	return shared.LooseEqual(operandValue(a), operandValue(b))
+}
+
+// operandValue reads a text value as a literal, so variables, which are
+// stored as text, compare as the numbers and booleans they hold.
+func operandValue(v interface{}) interface{} {
+	if s, ok := v.(string); ok {
+		return literalValue(strings.TrimSpace(s))
+	}
+	return v
+}
+
+// literalValue reads an unquoted literal: numbers, true/false and null are
+// typed, anything else is a string.
+func literalValue(s string) interface{} {
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null", "nil":
+		return nil
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil {
+		return n
+	}
+	return s
+}
+
+// unquote strips matching single or double quotes.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+func isQuoted(s string) bool {
+	return unquote(s) != s
+}
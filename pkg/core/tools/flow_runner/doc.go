@@ -0,0 +1,2 @@
+// Package flow_runner executes .falcon/flows/ files deterministically, without the LLM.
+package flow_runner
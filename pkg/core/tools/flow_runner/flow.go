@@ -0,0 +1,361 @@
+package flow_runner
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/blackcoderx/falcon/pkg/storage"
+	"gopkg.in/yaml.v3"
+)
+
+// Flow is a multi-step API scenario stored in .falcon/flows/<name>.yaml.
+type Flow struct {
+	Name              string                 `json:"name,omitempty"`
+	Description       string                 `json:"description,omitempty"`
+	Tags              []string               `json:"tags,omitempty"`                // Labels for filtering (falcon test --tag)
+	BaseURL           string                 `json:"base_url,omitempty"`            // Prefix for relative request URLs (supports {{VAR}})
+	Variables         map[string]interface{} `json:"variables,omitempty"`           // Initial {{var}} values
+	Headers           map[string]string      `json:"headers,omitempty"`             // Sent with every request
+	Session           string                 `json:"session,omitempty"`             // Cookie session shared with http_request (default: cookies private to the run)
+	ContinueOnFailure bool                   `json:"continue_on_failure,omitempty"` // Keep going after a failed step (default: stop)
+	Steps             []Step                 `json:"steps"`
+	Teardown          []Step                 `json:"teardown,omitempty"` // Always run, even after a failure
+}
+
+// Step is one entry of a flow. A step either performs one action (request,
+// set, assert or wait) or groups nested steps, optionally as a loop. Any
+// step can be made conditional with "if".
+type Step struct {
+	Name string `json:"name,omitempty"`
+	If   string `json:"if,omitempty"` // Condition, e.g. "{{role}} == admin"
+
+	// Actions
+	Request *RequestSpec           `json:"request,omitempty"`
+	Set     map[string]interface{} `json:"set,omitempty"`
+	Assert  *AssertSpec            `json:"assert,omitempty"`
+	Wait    *Duration              `json:"wait,omitempty"` // "500ms", "2s", or seconds
+
+	// Request options
+	Expect  *shared.TestExpectation `json:"expect,omitempty"`  // Default: any status < 400
+	Extract map[string]string       `json:"extract,omitempty"` // var -> $.path, header:Name, cookie:Name, status or body
+	Retry   *RetrySpec              `json:"retry,omitempty"`
+
+	// Blocks and loops
+	Steps         []Step      `json:"steps,omitempty"`
+	Else          []Step      `json:"else,omitempty"`           // Run when "if" is false
+	Repeat        int         `json:"repeat,omitempty"`         // Run steps N times ({{iteration}} = 1..N)
+	ForEach       interface{} `json:"for_each,omitempty"`       // List, or "{{var}}" holding a list
+	As            string      `json:"as,omitempty"`             // Loop variable name (default: item / iteration)
+	While         string      `json:"while,omitempty"`          // Repeat steps while the condition holds
+	MaxIterations int         `json:"max_iterations,omitempty"` // Safety cap for while loops (default: 100)
+}
+
+// RequestSpec describes an HTTP request: a saved request from
+// .falcon/requests/, an inline request, or both (inline fields override).
+// In YAML it can also be written as a "METHOD /path" string.
+type RequestSpec struct {
+	Saved   string            `json:"saved,omitempty"`
+	Method  string            `json:"method,omitempty"`
+	URL     string            `json:"url,omitempty"` // Absolute URL or a path relative to base_url
+	Headers map[string]string `json:"headers,omitempty"`
+	Query   map[string]string `json:"query,omitempty"`
+	Body    interface{}       `json:"body,omitempty"`
+	// BodyType and BodyFile are as in http_request: json, form, multipart
+	// ("@path" fields upload files), text, xml or binary, and a file to send.
+	BodyType string `json:"body_type,omitempty"`
+	BodyFile string `json:"body_file,omitempty"`
+	Timeout  int    `json:"timeout,omitempty"` // Seconds
+
+	FollowRedirects *bool `json:"follow_redirects,omitempty"` // Default true
+	MaxRedirects    int   `json:"max_redirects,omitempty"`    // Default 10
+	// Transport overrides the environment's TLS, proxy and HTTP version settings.
+	Transport *shared.TransportConfig `json:"transport,omitempty"`
+}
+
+// UnmarshalJSON accepts either an object or a "METHOD /path" string.
+func (r *RequestSpec) UnmarshalJSON(data []byte) error {
+	var shorthand string
+	if err := json.Unmarshal(data, &shorthand); err == nil {
+		parts := strings.Fields(shorthand)
+		switch len(parts) {
+		case 1:
+			r.Method, r.URL = "GET", parts[0]
+		case 2:
+			r.Method, r.URL = strings.ToUpper(parts[0]), parts[1]
+		default:
+			return fmt.Errorf("invalid request '%s' (expected \"METHOD /path\")", shorthand)
+		}
+		return nil
+	}
+
+	type plain RequestSpec
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	*r = RequestSpec(p)
+	return nil
+}
+
+// AssertSpec checks the last response and/or flow variables.
+type AssertSpec struct {
+	shared.TestExpectation
+	Equals map[string]interface{} `json:"equals,omitempty"` // var -> expected value
+	That   []string               `json:"that,omitempty"`   // Conditions that must all hold
+}
+
+// RetrySpec resends a request until its expectation holds.
+type RetrySpec struct {
+	Attempts   int `json:"attempts"`              // Total attempts, including the first
+	IntervalMs int `json:"interval_ms,omitempty"` // Delay between attempts (default: 1000)
+}
+
+// Duration is a wait time written as "500ms"/"2s" or a number of seconds.
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string or a number of seconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err == nil {
+		*d = Duration(seconds * float64(time.Second))
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid wait: %s", string(data))
+	}
+	parsed, err := time.ParseDuration(strings.TrimSpace(s))
+	if err != nil {
+		return fmt.Errorf("invalid wait '%s' (use e.g. \"500ms\" or \"2s\")", s)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// ParseFlow decodes a flow from YAML (or JSON) and validates its structure.
+func ParseFlow(data []byte) (*Flow, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse flow: %w", err)
+	}
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse flow: %w", err)
+	}
+
+	var flow Flow
+	if err := json.Unmarshal(encoded, &flow); err != nil {
+		return nil, fmt.Errorf("failed to parse flow: %w", err)
+	}
+	if err := flow.Validate(); err != nil {
+		return nil, err
+	}
+	return &flow, nil
+}
+
+// LoadFlow reads a flow by name ("login", "login.yaml") from .falcon/flows/,
+// or from a path to a flow file.
+func LoadFlow(falconDir, name string) (*Flow, error) {
+	path, err := ResolveFlowPath(falconDir, name)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read flow '%s': %w", name, err)
+	}
+
+	flow, err := ParseFlow(data)
+	if err != nil {
+		return nil, fmt.Errorf("flow '%s': %w", name, err)
+	}
+	if flow.Name == "" {
+		flow.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return flow, nil
+}
+
+// ResolveFlowPath finds the file for a flow name inside .falcon/flows/, then
+// falls back to treating the name as a path to a file inside the project.
+func ResolveFlowPath(falconDir, name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("flow name is required")
+	}
+
+	flowsDir := storage.GetFlowsDir(falconDir)
+	candidates := []string{name}
+	if ext := filepath.Ext(name); ext != ".yaml" && ext != ".yml" {
+		candidates = []string{name + ".yaml", name + ".yml"}
+	}
+	for _, candidate := range candidates {
+		path, err := shared.ValidatePathWithinWorkDir(candidate, flowsDir)
+		if err != nil {
+			break
+		}
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	if info, err := os.Stat(name); err == nil && !info.IsDir() {
+		return shared.ValidatePathWithinWorkDir(name, filepath.Dir(falconDir))
+	}
+	return "", fmt.Errorf("flow '%s' not found in %s", name, flowsDir)
+}
+
+// ListFlows returns the flow names in .falcon/flows/, sorted.
+func ListFlows(falconDir string) ([]string, error) {
+	entries, err := os.ReadDir(storage.GetFlowsDir(falconDir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to list flows: %w", err)
+	}
+
+	var names []string
+	for _, e := range entries {
+		ext := filepath.Ext(e.Name())
+		if !e.IsDir() && (ext == ".yaml" || ext == ".yml") {
+			names = append(names, strings.TrimSuffix(e.Name(), ext))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Validate checks the flow structure before anything is sent.
+func (f *Flow) Validate() error {
+	if len(f.Steps) == 0 {
+		return fmt.Errorf("flow has no steps")
+	}
+	if err := validateSteps(f.Steps, "steps"); err != nil {
+		return err
+	}
+	return validateSteps(f.Teardown, "teardown")
+}
+
+func validateSteps(steps []Step, path string) error {
+	for i, step := range steps {
+		label := fmt.Sprintf("%s[%d]", path, i+1)
+		if step.Name != "" {
+			label += " (" + step.Name + ")"
+		}
+		if err := step.validate(); err != nil {
+			return fmt.Errorf("%s: %w", label, err)
+		}
+		if err := validateSteps(step.Steps, label); err != nil {
+			return err
+		}
+		if err := validateSteps(step.Else, label+".else"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s Step) validate() error {
+	actions := 0
+	for _, set := range []bool{s.Request != nil, s.Set != nil, s.Assert != nil, s.Wait != nil} {
+		if set {
+			actions++
+		}
+	}
+	loops := 0
+	for _, set := range []bool{s.Repeat > 0, s.ForEach != nil, s.While != ""} {
+		if set {
+			loops++
+		}
+	}
+
+	switch {
+	case actions > 1:
+		return fmt.Errorf("a step can only have one of request, set, assert or wait")
+	case actions == 1 && len(s.Steps) > 0:
+		return fmt.Errorf("a step cannot have both an action and nested steps")
+	case actions == 0 && len(s.Steps) == 0:
+		return fmt.Errorf("step needs an action (request, set, assert, wait) or nested steps")
+	case loops > 1:
+		return fmt.Errorf("a step can only have one of repeat, for_each or while")
+	case loops == 1 && len(s.Steps) == 0:
+		return fmt.Errorf("loops need nested steps")
+	case len(s.Else) > 0 && s.If == "":
+		return fmt.Errorf("'else' requires 'if'")
+	case s.Request == nil && (s.Expect != nil || s.Extract != nil || s.Retry != nil):
+		return fmt.Errorf("expect, extract and retry only apply to request steps")
+	case s.Request != nil && s.Request.Saved == "" && s.Request.URL == "":
+		return fmt.Errorf("request needs 'url' or 'saved'")
+	}
+
+	for _, expr := range []string{s.If, s.While} {
+		if expr == "" {
+			continue
+		}
+		if _, err := parseCondition(expr); err != nil {
+			return err
+		}
+	}
+	if s.Assert != nil {
+		for _, expr := range s.Assert.That {
+			if _, err := parseCondition(expr); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// label names a step for results.
+func (s Step) label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	switch {
+	case s.Request != nil:
+		if s.Request.Saved != "" && s.Request.URL == "" {
+			return s.Request.Saved
+		}
+		method := s.Request.Method
+		if method == "" {
+			method = "GET"
+		}
+		return strings.ToUpper(method) + " " + s.Request.URL
+	case s.Set != nil:
+		keys := make([]string, 0, len(s.Set))
+		for k := range s.Set {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return "set " + strings.Join(keys, ", ")
+	case s.Assert != nil:
+		return "assert"
+	case s.Wait != nil:
+		return "wait " + time.Duration(*s.Wait).String()
+	case s.Repeat > 0:
+		return fmt.Sprintf("repeat %d", s.Repeat)
+	case s.ForEach != nil:
+		if ref, ok := s.ForEach.(string); ok {
+			return "for each " + plainExpr(ref)
+		}
+		return "for each"
+	case s.While != "":
+		return "while " + plainExpr(s.While)
+	case s.If != "":
+		return "if " + plainExpr(s.If)
+	}
+	return "steps"
+}
+
+// plainExpr drops the braces around {{var}} so labels read naturally in
+// summaries and reports.
+func plainExpr(expr string) string {
+	return shared.PlaceholderPattern.ReplaceAllString(expr, "$1")
+}
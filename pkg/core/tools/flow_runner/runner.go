@@ -0,0 +1,648 @@
+package flow_runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/integration_orchestrator"
+	"github.com/blackcoderx/falcon/pkg/core/tools/persistence"
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/blackcoderx/falcon/pkg/storage"
+)
+
+// Step statuses.
+const (
+	StatusPass    = "pass"
+	StatusFail    = "fail"
+	StatusSkipped = "skipped"
+)
+
+// Loop limits.
+const (
+	defaultMaxIterations = 100
+	defaultRetryInterval = time.Second
+)
+
+// StepResult is the outcome of one executed (or skipped) action.
+type StepResult struct {
+	Step       string `json:"step"` // Path through blocks and loops, e.g. "orders [2] › create"
+	Status     string `json:"status"`
+	Message    string `json:"message,omitempty"`
+	Phase      string `json:"phase,omitempty"`   // "teardown" for cleanup steps
+	Request    string `json:"request,omitempty"` // "METHOD URL" for request steps
+	StatusCode int    `json:"status_code,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// FlowResult is the outcome of a flow run.
+type FlowResult struct {
+	Flow      string                 `json:"flow"`
+	Passed    bool                   `json:"passed"`
+	Cancelled bool                   `json:"cancelled,omitempty"` // Stopped before every step ran
+	Steps     []StepResult           `json:"steps"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Skipped   int                    `json:"skipped"`
+	Duration  time.Duration          `json:"duration"`
+	Variables map[string]interface{} `json:"variables"` // Final flow state
+}
+
+// Runner executes flows step by step without the LLM. Given the same API
+// responses, a flow always takes the same path.
+type Runner struct {
+	falconDir   string
+	httpTool    *shared.HTTPTool
+	varStore    *shared.VariableStore
+	environment map[string]string
+}
+
+// NewRunner creates a flow runner. environment holds the active
+// environment's values; it and varStore may be nil.
+func NewRunner(falconDir string, httpTool *shared.HTTPTool, varStore *shared.VariableStore, environment map[string]string) *Runner {
+	return &Runner{falconDir: falconDir, httpTool: httpTool, varStore: varStore, environment: environment}
+}
+
+// execution holds the state of one run.
+type execution struct {
+	ctx    context.Context
+	runner *Runner
+	flow   *Flow
+	env    *integration_orchestrator.Environment
+	jar    *shared.CookieJar // cookies of this run, unless the flow names a session
+	result *FlowResult
+	halted bool
+	phase  string
+}
+
+// Run executes the flow's steps, then its teardown. vars override the
+// flow's own variables. Placeholders that are not flow variables fall back
+// to the active environment, the variable store and {{env:NAME}}. When ctx
+// is cancelled the current request or wait is aborted and the remaining
+// steps, teardown included, are skipped.
+func (r *Runner) Run(ctx context.Context, flow *Flow, vars map[string]interface{}) *FlowResult {
+	start := time.Now()
+	x := &execution{
+		ctx:    ctx,
+		runner: r,
+		flow:   flow,
+		env:    integration_orchestrator.NewEnvironment(""),
+		result: &FlowResult{Flow: flow.Name},
+	}
+	if flow.Session == "" {
+		x.jar = shared.NewCookieJar()
+	}
+
+	for _, k := range sortedKeys(flow.Variables) {
+		x.env.Set(k, x.resolve(flow.Variables[k]))
+	}
+	for k, v := range vars {
+		x.env.Set(k, v)
+	}
+	x.env.BaseURL = x.text(flow.BaseURL)
+
+	x.runSteps(flow.Steps, "")
+
+	// Teardown is best-effort: a failed cleanup step does not stop the rest
+	x.halted = false
+	x.phase = "teardown"
+	x.runSteps(flow.Teardown, "")
+
+	x.result.Cancelled = ctx.Err() != nil
+	x.result.Passed = x.result.Failed == 0 && !x.result.Cancelled
+	x.result.Duration = time.Since(start)
+	x.result.Variables = x.env.State
+	return x.result
+}
+
+func (x *execution) record(res StepResult) {
+	res.Phase = x.phase
+	x.result.Steps = append(x.result.Steps, res)
+	switch res.Status {
+	case StatusPass:
+		x.result.Succeeded++
+	case StatusFail:
+		x.result.Failed++
+		if !x.flow.ContinueOnFailure && x.phase != "teardown" {
+			x.halted = true
+		}
+	default:
+		x.result.Skipped++
+	}
+}
+
+func (x *execution) fail(path string, err error) {
+	x.record(StepResult{Step: path, Status: StatusFail, Message: err.Error()})
+}
+
+func joinPath(prefix, label string) string {
+	if prefix == "" {
+		return label
+	}
+	return prefix + " › " + label
+}
+
+// stopped reports whether no further steps should run.
+func (x *execution) stopped() bool {
+	return x.halted || x.ctx.Err() != nil
+}
+
+func (x *execution) runSteps(steps []Step, prefix string) {
+	for _, step := range steps {
+		path := joinPath(prefix, step.label())
+		if x.ctx.Err() != nil {
+			x.record(StepResult{Step: path, Status: StatusSkipped, Message: "flow cancelled"})
+			continue
+		}
+		if x.halted {
+			x.record(StepResult{Step: path, Status: StatusSkipped, Message: "previous step failed"})
+			continue
+		}
+		x.runStep(step, path)
+	}
+}
+
+func (x *execution) runStep(step Step, path string) {
+	if step.If != "" {
+		ok, err := x.evalCondition(step.If)
+		if err != nil {
+			x.fail(path, err)
+			return
+		}
+		if !ok {
+			if len(step.Else) > 0 {
+				x.runSteps(step.Else, joinPath(path, "else"))
+				return
+			}
+			x.record(StepResult{Step: path, Status: StatusSkipped, Message: "condition not met: " + plainExpr(step.If)})
+			return
+		}
+	}
+
+	switch {
+	case step.Repeat > 0:
+		x.runRepeat(step, path)
+	case step.ForEach != nil:
+		x.runForEach(step, path)
+	case step.While != "":
+		x.runWhile(step, path)
+	case len(step.Steps) > 0:
+		x.runSteps(step.Steps, path)
+	default:
+		x.runAction(step, path)
+	}
+}
+
+func (x *execution) runRepeat(step Step, path string) {
+	name := step.As
+	if name == "" {
+		name = "iteration"
+	}
+	for i := 1; i <= step.Repeat && !x.stopped(); i++ {
+		x.env.Set(name, i)
+		x.runSteps(step.Steps, fmt.Sprintf("%s [%d/%d]", path, i, step.Repeat))
+	}
+}
+
+func (x *execution) runForEach(step Step, path string) {
+	items, err := x.loopItems(step.ForEach)
+	if err != nil {
+		x.fail(path, err)
+		return
+	}
+	name := step.As
+	if name == "" {
+		name = "item"
+	}
+	for i, item := range items {
+		if x.stopped() {
+			break
+		}
+		x.env.Set(name, item)
+		x.env.Set("index", i)
+		x.runSteps(step.Steps, fmt.Sprintf("%s [%d]", path, i+1))
+	}
+}
+
+func (x *execution) runWhile(step Step, path string) {
+	limit := step.MaxIterations
+	if limit <= 0 {
+		limit = defaultMaxIterations
+	}
+	name := step.As
+	if name == "" {
+		name = "iteration"
+	}
+	for i := 1; !x.stopped(); i++ {
+		ok, err := x.evalCondition(step.While)
+		if err != nil {
+			x.fail(path, err)
+			return
+		}
+		if !ok {
+			return
+		}
+		if i > limit {
+			x.fail(path, fmt.Errorf("while loop still running after %d iterations (raise max_iterations)", limit))
+			return
+		}
+		x.env.Set(name, i)
+		x.runSteps(step.Steps, fmt.Sprintf("%s [%d]", path, i))
+	}
+}
+
+// loopItems resolves a for_each value: an inline list, or a "{{var}}" /
+// "$.path" reference to a list.
+func (x *execution) loopItems(v interface{}) ([]interface{}, error) {
+	if s, ok := v.(string); ok {
+		val, found := x.operand(s)
+		if !found {
+			return nil, fmt.Errorf("for_each: '%s' is not set", s)
+		}
+		v = val
+	} else {
+		v = x.resolve(v)
+	}
+
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("for_each needs a list, got %T", v)
+	}
+	return items, nil
+}
+
+func (x *execution) runAction(step Step, path string) {
+	start := time.Now()
+	res := StepResult{Step: path, Status: StatusPass}
+
+	var err error
+	switch {
+	case step.Request != nil:
+		err = x.runRequest(step, &res)
+	case step.Set != nil:
+		for _, k := range sortedKeys(step.Set) {
+			x.env.Set(k, x.resolve(step.Set[k]))
+		}
+	case step.Assert != nil:
+		err = x.runAssert(step.Assert)
+	case step.Wait != nil:
+		err = sleepContext(x.ctx, time.Duration(*step.Wait))
+	}
+
+	res.DurationMs = time.Since(start).Milliseconds()
+	switch {
+	case err != nil && x.ctx.Err() != nil:
+		res.Status = StatusSkipped
+		res.Message = "flow cancelled"
+	case err != nil:
+		res.Status = StatusFail
+		res.Message = err.Error()
+	}
+	x.record(res)
+}
+
+func (x *execution) runRequest(step Step, res *StepResult) error {
+	req, err := x.buildRequest(step.Request)
+	if err != nil {
+		return err
+	}
+	res.Request = req.Method + " " + req.URL
+
+	expect, err := x.resolveExpectation(step.Expect)
+	if err != nil {
+		return err
+	}
+
+	attempts, interval := 1, defaultRetryInterval
+	if step.Retry != nil {
+		attempts = max(step.Retry.Attempts, 1)
+		if step.Retry.IntervalMs > 0 {
+			interval = time.Duration(step.Retry.IntervalMs) * time.Millisecond
+		}
+	}
+
+	var resp *shared.HTTPResponse
+	for attempt := 1; ; attempt++ {
+		resp, err = x.runner.httpTool.RunContext(x.ctx, req)
+		if err == nil {
+			x.env.RecordResponse("", resp)
+			res.StatusCode = resp.StatusCode
+			err = integration_orchestrator.CheckResponse(expect, resp)
+		}
+		if err == nil {
+			break
+		}
+		if attempt >= attempts {
+			if attempts > 1 {
+				return fmt.Errorf("failed after %d attempts: %w", attempts, err)
+			}
+			return err
+		}
+		if err := sleepContext(x.ctx, interval); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(step.Extract) {
+		val, err := integration_orchestrator.ExtractValue(resp, step.Extract[name])
+		if err != nil {
+			return fmt.Errorf("extract '%s': %w", name, err)
+		}
+		x.env.Set(name, val)
+	}
+	return nil
+}
+
+// buildRequest merges the flow headers, the saved request and the inline
+// request, then substitutes placeholders.
+func (x *execution) buildRequest(spec *RequestSpec) (shared.HTTPRequest, error) {
+	method, target, body := spec.Method, spec.URL, spec.Body
+	bodyType, bodyFile := spec.BodyType, spec.BodyFile
+	followRedirects, maxRedirects := spec.FollowRedirects, spec.MaxRedirects
+	transport := spec.Transport
+	session, jar := x.flow.Session, x.jar
+	headers := make(map[string]string)
+	for k, v := range x.flow.Headers {
+		headers[k] = v
+	}
+	query := make(map[string]string)
+
+	if spec.Saved != "" {
+		saved, err := persistence.LoadWithoutHooks(x.runner.falconDir, spec.Saved)
+		if err != nil {
+			return shared.HTTPRequest{}, err
+		}
+		// A flow without its own session sends the request in the one it names
+		switch {
+		case x.flow.Session != "" || saved.Session == "":
+		case saved.Session == shared.NoSession:
+			jar = nil
+		default:
+			session, jar = saved.Session, nil
+		}
+		if method == "" {
+			method = saved.Method
+		}
+		if target == "" {
+			target = saved.URL
+		}
+		if body == nil && bodyFile == "" {
+			body, bodyFile = saved.Body, saved.BodyFile
+			if bodyType == "" {
+				bodyType = saved.BodyType
+			}
+		}
+		for k, v := range saved.Headers {
+			headers[k] = v
+		}
+		for k, v := range saved.Query {
+			query[k] = v
+		}
+		if followRedirects == nil {
+			followRedirects = saved.FollowRedirects
+		}
+		if maxRedirects == 0 {
+			maxRedirects = saved.MaxRedirects
+		}
+		if saved.Transport != nil {
+			merged := shared.TransportConfig(*saved.Transport).Merge(transport)
+			transport = &merged
+		}
+	}
+	for k, v := range spec.Headers {
+		headers[k] = v
+	}
+	for k, v := range spec.Query {
+		query[k] = v
+	}
+	if method == "" {
+		method = "GET"
+	}
+
+	for k, v := range headers {
+		headers[k] = x.text(v)
+	}
+	target = x.text(target)
+	if !strings.HasPrefix(target, "http://") && !strings.HasPrefix(target, "https://") {
+		if x.env.BaseURL == "" {
+			return shared.HTTPRequest{}, fmt.Errorf("'%s' is not an absolute URL (set base_url)", target)
+		}
+		target = x.env.ResolveURL(target)
+	}
+
+	if len(query) > 0 {
+		u, err := url.Parse(target)
+		if err != nil {
+			return shared.HTTPRequest{}, fmt.Errorf("invalid URL '%s': %w", target, err)
+		}
+		q := u.Query()
+		for k, v := range query {
+			q.Set(k, x.text(v))
+		}
+		u.RawQuery = q.Encode()
+		target = u.String()
+	}
+
+	return shared.HTTPRequest{
+		Method:   strings.ToUpper(method),
+		URL:      target,
+		Headers:  headers,
+		Body:     x.resolve(body),
+		BodyType: bodyType,
+		BodyFile: x.text(bodyFile),
+		Timeout:  spec.Timeout,
+		Session:  session,
+		Jar:      jar,
+
+		FollowRedirects: followRedirects,
+		MaxRedirects:    maxRedirects,
+		Transport:       transport.Expand(x.text),
+	}, nil
+}
+
+func (x *execution) runAssert(a *AssertSpec) error {
+	var failures []string
+	for _, name := range sortedKeys(a.Equals) {
+		expected := a.Equals[name]
+		actual, ok := x.lookup(name)
+		if !ok {
+			failures = append(failures, fmt.Sprintf("variable '%s' is not set", name))
+		} else if !valuesEqual(actual, x.resolve(expected)) {
+			failures = append(failures, fmt.Sprintf("variable '%s': expected %v, got %v", name, expected, actual))
+		}
+	}
+
+	for _, expr := range a.That {
+		ok, err := x.evalCondition(expr)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			failures = append(failures, "condition failed: "+plainExpr(expr))
+		}
+	}
+
+	e := a.TestExpectation
+	if e.StatusCode != 0 || e.StatusCodeRange != nil || len(e.BodyContains) > 0 || len(e.BodyNotContains) > 0 ||
+		len(e.HeaderContains) > 0 || e.MaxDurationMs > 0 || len(e.JSONPath) > 0 {
+		if x.env.Last == nil {
+			return fmt.Errorf("no HTTP response to assert on - run a request step first")
+		}
+		expect, err := x.resolveExpectation(&e)
+		if err != nil {
+			return err
+		}
+		failures = append(failures, shared.ValidateExpectations(*expect, x.env.Last, x.env.Last.Duration.Milliseconds())...)
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// resolveExpectation substitutes placeholders in expected values, so checks
+// like {"json_path": {"$.id": "{{user_id}}"}} compare against flow state.
+func (x *execution) resolveExpectation(e *shared.TestExpectation) (*shared.TestExpectation, error) {
+	if e == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	var raw interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	data, err = json.Marshal(x.resolve(raw))
+	if err != nil {
+		return nil, err
+	}
+	var resolved shared.TestExpectation
+	if err := json.Unmarshal(data, &resolved); err != nil {
+		return nil, fmt.Errorf("invalid expectation: %w", err)
+	}
+	return &resolved, nil
+}
+
+func (x *execution) evalCondition(expr string) (bool, error) {
+	c, err := parseCondition(expr)
+	if err != nil {
+		return false, err
+	}
+	return c.eval(x.operand)
+}
+
+// operand resolves a condition operand: a quoted string, a {{var}}, a
+// response reference (status, body, header:Name, $.path into the last
+// response body), or a literal.
+func (x *execution) operand(s string) (interface{}, bool) {
+	s = strings.TrimSpace(s)
+	if isQuoted(s) {
+		return x.text(unquote(s)), true
+	}
+	if name, ok := shared.PlaceholderName(s); ok {
+		return x.lookup(name)
+	}
+	if s == "status" || s == "body" || strings.HasPrefix(s, "$") || strings.HasPrefix(strings.ToLower(s), "header:") {
+		if x.env.Last == nil {
+			return nil, false
+		}
+		v, err := integration_orchestrator.ExtractValue(x.env.Last, s)
+		if err != nil {
+			return nil, false
+		}
+		return v, true
+	}
+	return literalValue(x.text(s)), true
+}
+
+// lookup finds a variable in the flow state, then the active environment,
+// the variable store and the process environment ("env:NAME").
+func (x *execution) lookup(name string) (interface{}, bool) {
+	if v, ok := x.env.State[name]; ok {
+		return v, true
+	}
+	if v, ok := x.runner.environment[name]; ok {
+		return v, true
+	}
+	if strings.HasPrefix(name, "env:") {
+		placeholder := "{{" + name + "}}"
+		if v := storage.SubstituteVariables(placeholder, nil); v != placeholder {
+			return v, true
+		}
+		return nil, false
+	}
+	if x.runner.varStore != nil {
+		if v, ok := x.runner.varStore.Get(name); ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// text substitutes placeholders in a string.
+func (x *execution) text(s string) string {
+	s = storage.SubstituteVariables(x.env.InterpolateString(s), x.runner.environment)
+	if x.runner.varStore != nil {
+		s = x.runner.varStore.Substitute(s)
+	}
+	return s
+}
+
+// resolve substitutes placeholders in a decoded YAML/JSON value. A string
+// that is exactly one flow variable keeps the variable's type.
+func (x *execution) resolve(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			out[x.text(k)] = x.resolve(child)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, child := range val {
+			out[i] = x.resolve(child)
+		}
+		return out
+	case string:
+		if name, ok := shared.PlaceholderName(val); ok {
+			if stateVal, ok := x.env.State[name]; ok {
+				return stateVal
+			}
+		}
+		return x.text(val)
+	default:
+		return val
+	}
+}
+
+// sleepContext waits for d, returning early with the context's error when
+// it is cancelled.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sortedKeys returns map keys in order, so steps that touch several
+// variables behave the same on every run.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
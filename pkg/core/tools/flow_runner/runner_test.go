@@ -0,0 +1,381 @@
+package flow_runner
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/blackcoderx/falcon/pkg/storage"
+)
+
+func newTestServer(t *testing.T) (*httptest.Server, *int32) {
+	t.Helper()
+	var deleted int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"token": "abc", "role": "admin"})
+	})
+	mux.HandleFunc("/items", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"items": []interface{}{
+			map[string]interface{}{"id": 1}, map[string]interface{}{"id": 2},
+		}})
+	})
+	mux.HandleFunc("/items/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			atomic.AddInt32(&deleted, 1)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &deleted
+}
+
+func runTestFlow(t *testing.T, yamlFlow string, baseURL string) *FlowResult {
+	t.Helper()
+	return runTestFlowIn(t, t.TempDir(), yamlFlow, baseURL)
+}
+
+func runTestFlowIn(t *testing.T, dir, yamlFlow string, baseURL string) *FlowResult {
+	t.Helper()
+	flow, err := ParseFlow([]byte(yamlFlow))
+	if err != nil {
+		t.Fatalf("ParseFlow: %v", err)
+	}
+	flow.BaseURL = baseURL
+	return NewRunner(dir, shared.NewHTTPTool(nil, nil), shared.NewVariableStore(dir), nil).Run(context.Background(), flow, nil)
+}
+
+func TestRun_ExtractConditionsAndLoops(t *testing.T) {
+	server, deleted := newTestServer(t)
+
+	result := runTestFlow(t, `
+name: items
+steps:
+  - request: POST /login
+    extract:
+      token: $.token
+      role: $.role
+  - if: "{{role}} == admin"
+    steps:
+      - request:
+          method: GET
+          url: /items
+          headers: {Authorization: "Bearer {{token}}"}
+        expect: {status_code: 200}
+    else:
+      - request: GET /forbidden
+  - for_each: $.items
+    as: item
+    steps:
+      - request: GET /items/{{item.id}}
+  - assert:
+      equals: {token: abc}
+      that: ["status == 200", "$.ok == true"]
+teardown:
+  - request: DELETE /items/1
+    expect: {status_code: 204}
+`, server.URL)
+
+	if !result.Passed {
+		t.Fatalf("expected flow to pass:\n%s", FormatSummary(result))
+	}
+	if result.Failed != 0 || result.Succeeded != 6 {
+		t.Errorf("expected 6 succeeded and 0 failed, got %d/%d", result.Succeeded, result.Failed)
+	}
+	if atomic.LoadInt32(deleted) != 1 {
+		t.Errorf("expected teardown to run once, ran %d times", *deleted)
+	}
+}
+
+func TestRun_FailureHaltsButRunsTeardown(t *testing.T) {
+	server, deleted := newTestServer(t)
+
+	result := runTestFlow(t, `
+name: unauthorized
+steps:
+  - request: GET /items
+  - request: GET /items/1
+teardown:
+  - request: DELETE /items/1
+`, server.URL)
+
+	if result.Passed {
+		t.Fatal("expected flow to fail on 401")
+	}
+	if result.Failed != 1 || result.Skipped != 1 {
+		t.Errorf("expected 1 failed and 1 skipped, got %d/%d", result.Failed, result.Skipped)
+	}
+	if atomic.LoadInt32(deleted) != 1 {
+		t.Error("expected teardown to run after a failure")
+	}
+	if !strings.Contains(FormatSummary(result), "❌ Flow failed") {
+		t.Errorf("summary should report the failure:\n%s", FormatSummary(result))
+	}
+}
+
+func TestRun_TeardownIsBestEffort(t *testing.T) {
+	server, deleted := newTestServer(t)
+
+	result := runTestFlow(t, `
+name: cleanup
+steps:
+  - request: POST /login
+teardown:
+  - request: GET /items
+    expect: {status_code: 200}
+  - request: DELETE /items/1
+`, server.URL)
+
+	if result.Passed || result.Failed != 1 || result.Skipped != 0 {
+		t.Errorf("expected 1 failed and 0 skipped, got %d/%d", result.Failed, result.Skipped)
+	}
+	if atomic.LoadInt32(deleted) != 1 {
+		t.Error("expected the second teardown step to run after the first failed")
+	}
+	for _, step := range result.Steps[1:] {
+		if step.Phase != "teardown" {
+			t.Errorf("step %q: phase %q", step.Step, step.Phase)
+		}
+	}
+}
+
+func TestRun_CancelStopsWaitsRetriesAndRemainingSteps(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if r.URL.Path == "/unavailable" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	cases := map[string]string{
+		"wait": `
+name: wait
+steps:
+  - request: GET /a
+  - wait: 30s
+  - request: GET /b
+teardown:
+  - request: DELETE /a
+`,
+		"retry": `
+name: retry
+steps:
+  - request: GET /unavailable
+    expect: {status_code: 200}
+    retry: {attempts: 100, interval_ms: 1000}
+  - request: GET /b
+teardown:
+  - request: DELETE /a
+`,
+	}
+	for name, src := range cases {
+		t.Run(name, func(t *testing.T) {
+			atomic.StoreInt32(&hits, 0)
+			flow, err := ParseFlow([]byte(src))
+			if err != nil {
+				t.Fatalf("ParseFlow: %v", err)
+			}
+			flow.BaseURL = server.URL
+
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			start := time.Now()
+			dir := t.TempDir()
+			result := NewRunner(dir, shared.NewHTTPTool(nil, nil), nil, nil).Run(ctx, flow, nil)
+
+			if elapsed := time.Since(start); elapsed > 5*time.Second {
+				t.Fatalf("run took %v after cancellation", elapsed)
+			}
+			if !result.Cancelled || result.Passed {
+				t.Errorf("cancelled=%v passed=%v", result.Cancelled, result.Passed)
+			}
+			if n := atomic.LoadInt32(&hits); n > 1 {
+				t.Errorf("%d requests sent, want only the first before cancelling", n)
+			}
+			last := result.Steps[len(result.Steps)-1]
+			if last.Phase != "teardown" || last.Status != StatusSkipped || last.Message != "flow cancelled" {
+				t.Errorf("teardown step: %+v", last)
+			}
+			if !strings.Contains(FormatSummary(result), "Flow cancelled") {
+				t.Errorf("summary:\n%s", FormatSummary(result))
+			}
+		})
+	}
+}
+
+func TestRun_SavedRequestWithOverrides(t *testing.T) {
+	server, _ := newTestServer(t)
+	dir := t.TempDir()
+	saved := storage.Request{Name: "list items", Method: "GET", URL: "/items", Headers: map[string]string{"Authorization": "Bearer wrong"}}
+	if err := storage.SaveRequest(saved, storage.GetRequestsDir(dir)+"/list-items.yaml"); err != nil {
+		t.Fatalf("SaveRequest: %v", err)
+	}
+
+	result := runTestFlowIn(t, dir, `
+name: saved
+variables:
+  token: abc
+steps:
+  - request:
+      saved: list items
+      headers: {Authorization: "Bearer {{token}}"}
+    expect: {status_code: 200}
+`, server.URL)
+
+	if !result.Passed {
+		t.Fatalf("expected saved request with header override to pass:\n%s", FormatSummary(result))
+	}
+}
+
+func TestRun_SavedRequestWithHooksIsRefused(t *testing.T) {
+	server, _ := newTestServer(t)
+	dir := t.TempDir()
+	saved := storage.Request{Name: "login", Method: "POST", URL: "/login", Post: &storage.PostSteps{Extract: map[string]string{"token": "$.token"}}}
+	if err := storage.SaveRequest(saved, storage.GetRequestsDir(dir)+"/login.yaml"); err != nil {
+		t.Fatalf("SaveRequest: %v", err)
+	}
+
+	result := runTestFlowIn(t, dir, `
+name: saved
+steps:
+  - request: {saved: login}
+`, server.URL)
+
+	if result.Passed || len(result.Steps) != 1 || !strings.Contains(result.Steps[0].Message, "pre/post steps") {
+		t.Errorf("expected the saved request with hooks to be refused, got %+v", result.Steps)
+	}
+}
+func TestParseFlow_RejectsInvalidSteps(t *testing.T) {
+	cases := map[string]string{
+		"two actions":   "steps:\n  - request: GET /a\n    wait: 1s\n",
+		"else w/o if":   "steps:\n  - else:\n      - wait: 1s\n",
+		"loop w/o body": "steps:\n  - repeat: 3\n",
+		"bad condition": "steps:\n  - if: \"== 1\"\n    steps:\n      - wait: 1s\n",
+		"expect on set": "steps:\n  - set: {a: 1}\n    expect: {status_code: 200}\n",
+	}
+	for name, src := range cases {
+		if _, err := ParseFlow([]byte(src)); err == nil {
+			t.Errorf("%s: expected validation error", name)
+		}
+	}
+}
+
+func TestCondition_Eval(t *testing.T) {
+	values := map[string]interface{}{
+		"count": 3.0,
+		"role":  "admin",
+		"tags":  []interface{}{"a", "beta"},
+		"limit": "10", // variables from the environment are text
+	}
+	body := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{"id": 1.0, "price": 5.0},
+			map[string]interface{}{"id": 2.0, "price": 20.0},
+		},
+	}
+	resolve := func(s string) (interface{}, bool) {
+		if strings.HasPrefix(s, "$") {
+			v, err := shared.EvalJSONPath(body, s)
+			return v, err == nil
+		}
+		if isQuoted(s) {
+			return unquote(s), true
+		}
+		if v, ok := values[strings.Trim(s, "{}")]; ok {
+			return v, true
+		}
+		if strings.HasPrefix(s, "{{") {
+			return nil, false
+		}
+		return literalValue(s), true
+	}
+
+	cases := map[string]bool{
+		"{{count}} >= 3":                                           true,
+		"{{count}} > 10":                                           false,
+		`{{role}} == "admin" && {{count}} < 5`:                     true,
+		"{{role}} == guest || {{count}} == 3":                      true,
+		"{{tags}} contains beta":                                   true,
+		"{{tags}} not_contains beta":                               false,
+		"{{role}} matches ^ad":                                     true,
+		"!{{missing}}":                                             true,
+		"not {{role}}":                                             false,
+		"$.items[?(@.price > 10)].id contains 2":                   true,
+		"$.items[?(@.price > 10)].id contains 1":                   false,
+		"$.items[?(@.price > 1 && @.price < 10)].id contains 1":    true,
+		"$.items[?(@.price > 10)] && {{count}} == 3":               true,
+		"$.items[?(@.price > 100)].id contains 2 || {{count}} > 1": true,
+		"{{limit}} > 9":                                            true,
+		"{{limit}} == 10":                                          true,
+		"{{missing}} > 5":                                          false,
+		"{{missing}} < 5":                                          false,
+		"{{missing}} != 5":                                         false,
+		"{{missing}} == null":                                      false,
+		"not {{missing}} > 5":                                      true,
+		"{{role}} > 5":                                             false,
+		"{{missing}} matches ^$":                                   false,
+		"{{tags}} contains {{x}}":                                  false,
+	}
+	for expr, want := range cases {
+		c, err := parseCondition(expr)
+		if err != nil {
+			t.Fatalf("parseCondition(%q): %v", expr, err)
+		}
+		got, err := c.eval(resolve)
+		if err != nil {
+			t.Fatalf("eval(%q): %v", expr, err)
+		}
+		if got != want {
+			t.Errorf("eval(%q) = %v, want %v", expr, got, want)
+		}
+	}
+}
+
+func TestParseCondition_FilterPaths(t *testing.T) {
+	cases := []struct {
+		expr    string
+		clauses [][]clause
+	}{
+		{
+			expr:    "$.items[?(@.price > 10)].id contains 2",
+			clauses: [][]clause{{{left: "$.items[?(@.price > 10)].id", op: "contains", right: "2"}}},
+		},
+		{
+			expr:    "$.items[?(@.price > 10 && @.stock >= 1)] && status == 200",
+			clauses: [][]clause{{{left: "$.items[?(@.price > 10 && @.stock >= 1)]"}, {left: "status", op: "==", right: "200"}}},
+		},
+		{
+			expr:    `$.users[?(@.role == "a||b")].name == bob || !{{skip}}`,
+			clauses: [][]clause{{{left: `$.users[?(@.role == "a||b")].name`, op: "==", right: "bob"}}, {{negate: true, left: "{{skip}}"}}},
+		},
+		{
+			expr:    "$['a>b'] != 1",
+			clauses: [][]clause{{{left: "$['a>b']", op: "!=", right: "1"}}},
+		},
+	}
+	for _, tc := range cases {
+		c, err := parseCondition(tc.expr)
+		if err != nil {
+			t.Fatalf("parseCondition(%q): %v", tc.expr, err)
+		}
+		if !reflect.DeepEqual(c.any, tc.clauses) {
+			t.Errorf("parseCondition(%q) = %+v, want %+v", tc.expr, c.any, tc.clauses)
+		}
+	}
+}
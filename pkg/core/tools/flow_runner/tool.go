@@ -0,0 +1,227 @@
+package flow_runner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/persistence"
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+)
+
+// FlowRunnerTool runs flow files from .falcon/flows/ deterministically.
+type FlowRunnerTool struct {
+	falconDir      string
+	httpTool       *shared.HTTPTool
+	varStore       *shared.VariableStore
+	persistManager *persistence.PersistenceManager
+	reportWriter   *shared.ReportWriter
+}
+
+// NewFlowRunnerTool creates a new flow runner tool. persistManager supplies
+// the active environment and may be nil.
+func NewFlowRunnerTool(falconDir string, httpTool *shared.HTTPTool, varStore *shared.VariableStore, persistManager *persistence.PersistenceManager, reportWriter *shared.ReportWriter) *FlowRunnerTool {
+	return &FlowRunnerTool{
+		falconDir:      falconDir,
+		httpTool:       httpTool,
+		varStore:       varStore,
+		persistManager: persistManager,
+		reportWriter:   reportWriter,
+	}
+}
+
+// RunFlowParams defines the parameters for running a flow.
+type RunFlowParams struct {
+	Flow       string                 `json:"flow"`                  // Name in .falcon/flows/ (with or without .yaml)
+	Variables  map[string]interface{} `json:"variables,omitempty"`   // Override the flow's variables
+	BaseURL    string                 `json:"base_url,omitempty"`    // Override the flow's base_url
+	ReportName string                 `json:"report_name,omitempty"` // Default: flow_report_<flow>_<timestamp>
+}
+
+// FlowOutcome is the result of a flow run with its rendered summary.
+type FlowOutcome struct {
+	Result     *FlowResult
+	Summary    string
+	ReportPath string
+}
+
+// Name returns the tool name.
+func (t *FlowRunnerTool) Name() string {
+	return "run_flow"
+}
+
+// Description returns the tool description.
+func (t *FlowRunnerTool) Description() string {
+	return "Run a saved flow from .falcon/flows/ step by step (requests, extraction, assertions, loops, conditionals) without LLM interpretation, and report pass/fail per step"
+}
+
+// Parameters returns the tool parameter description.
+func (t *FlowRunnerTool) Parameters() string {
+	return `{
+  "flow": "integration_login_create_delete",
+  "variables": {"user": "alice"},
+  "base_url": "http://localhost:3000",
+  "report_name": "flow_report_login"
+}`
+}
+
+// Execute runs a flow and returns its summary.
+func (t *FlowRunnerTool) Execute(args string) (string, error) {
+	return t.ExecuteContext(context.Background(), args)
+}
+
+// ExecuteContext runs a flow that stops early when ctx is cancelled.
+func (t *FlowRunnerTool) ExecuteContext(ctx context.Context, args string) (string, error) {
+	var params RunFlowParams
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+
+	outcome, err := t.Run(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	return outcome.Summary, nil
+}
+
+// Run loads and executes a flow, then writes its report. A failed flow is
+// not an error; callers decide what it means (the CLI exits with code 1).
+// Cancelling ctx stops the run; the partial result is still reported.
+func (t *FlowRunnerTool) Run(ctx context.Context, params RunFlowParams) (*FlowOutcome, error) {
+	if params.Flow == "" {
+		names, _ := ListFlows(t.falconDir)
+		if len(names) == 0 {
+			return nil, fmt.Errorf("flow is required (no flows in .falcon/flows/ yet)")
+		}
+		return nil, fmt.Errorf("flow is required (available: %s)", strings.Join(names, ", "))
+	}
+
+	flow, err := LoadFlow(t.falconDir, params.Flow)
+	if err != nil {
+		return nil, err
+	}
+	if params.BaseURL != "" {
+		flow.BaseURL = params.BaseURL
+	}
+
+	var environment map[string]string
+	if t.persistManager != nil {
+		environment = t.persistManager.GetEnvironment()
+	}
+
+	start := time.Now()
+	result := NewRunner(t.falconDir, t.httpTool, t.varStore, environment).Run(ctx, flow, params.Variables)
+
+	outcome := &FlowOutcome{Result: result, Summary: FormatSummary(result)}
+	if t.reportWriter == nil {
+		return outcome, nil
+	}
+
+	reportPath, err := t.reportWriter.Write(params.ReportName, "flow_report_"+flow.Name, formatFlowReport(flow, result, start))
+	if err != nil {
+		outcome.Summary += fmt.Sprintf("\n\nWarning: failed to save report: %v", err)
+		return outcome, nil
+	}
+	outcome.ReportPath = reportPath
+	outcome.Summary += fmt.Sprintf("\n\nReport saved to: %s", reportPath)
+	return outcome, nil
+}
+
+// FormatSummary renders a flow result for the agent, the TUI and the CLI.
+func FormatSummary(r *FlowResult) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "🔁 Flow: %s\n\n", r.Flow)
+
+	for _, step := range r.Steps {
+		icon := "✓"
+		switch step.Status {
+		case StatusFail:
+			icon = "❌"
+		case StatusSkipped:
+			icon = "⏭️"
+		}
+		label := step.Step
+		if step.Phase != "" {
+			label = step.Phase + ": " + label
+		}
+		fmt.Fprintf(&sb, "  %s %s", icon, label)
+		if step.StatusCode != 0 {
+			fmt.Fprintf(&sb, " (%d, %dms)", step.StatusCode, step.DurationMs)
+		}
+		sb.WriteString("\n")
+		if step.Message != "" && step.Status == StatusFail {
+			fmt.Fprintf(&sb, "      %s\n", step.Message)
+		}
+	}
+
+	sb.WriteString("\n")
+	switch {
+	case r.Cancelled:
+		fmt.Fprintf(&sb, "⏹️ Flow cancelled: %d failed, %d succeeded", r.Failed, r.Succeeded)
+	case r.Passed:
+		fmt.Fprintf(&sb, "✅ Flow passed: %d steps succeeded", r.Succeeded)
+	default:
+		fmt.Fprintf(&sb, "❌ Flow failed: %d failed, %d succeeded", r.Failed, r.Succeeded)
+	}
+	if r.Skipped > 0 {
+		fmt.Fprintf(&sb, ", %d skipped", r.Skipped)
+	}
+	fmt.Fprintf(&sb, " in %v", r.Duration.Round(time.Millisecond))
+	return sb.String()
+}
+
+// formatFlowReport builds the Markdown content for a flow report.
+func formatFlowReport(flow *Flow, r *FlowResult, start time.Time) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Flow Report: %s\n\n", flow.Name)
+	if flow.Description != "" {
+		fmt.Fprintf(&sb, "%s\n\n", flow.Description)
+	}
+	fmt.Fprintf(&sb, "**Date:** %s\n\n", start.Format(time.RFC1123))
+	switch {
+	case r.Cancelled:
+		fmt.Fprintf(&sb, "**Result:** ⏹️ CANCELLED\n\n")
+	case r.Passed:
+		fmt.Fprintf(&sb, "**Result:** ✅ PASS\n\n")
+	default:
+		fmt.Fprintf(&sb, "**Result:** ❌ FAIL (%d failed)\n\n", r.Failed)
+	}
+
+	fmt.Fprintf(&sb, "## Summary\n\n")
+	fmt.Fprintf(&sb, "| Metric | Value |\n|--------|-------|\n")
+	fmt.Fprintf(&sb, "| Succeeded | %d |\n", r.Succeeded)
+	fmt.Fprintf(&sb, "| Failed | %d |\n", r.Failed)
+	fmt.Fprintf(&sb, "| Skipped | %d |\n", r.Skipped)
+	fmt.Fprintf(&sb, "| Duration | %v |\n\n", r.Duration.Round(time.Millisecond))
+
+	fmt.Fprintf(&sb, "## Steps\n\n")
+	fmt.Fprintf(&sb, "| # | Step | Request | Status Code | Duration | Result |\n")
+	fmt.Fprintf(&sb, "|---|------|---------|-------------|----------|--------|\n")
+	for i, step := range r.Steps {
+		label := step.Step
+		if step.Phase != "" {
+			label = step.Phase + ": " + label
+		}
+		code := ""
+		if step.StatusCode != 0 {
+			code = fmt.Sprintf("%d", step.StatusCode)
+		}
+		result := "✅ Pass"
+		switch step.Status {
+		case StatusFail:
+			result = "❌ " + step.Message
+		case StatusSkipped:
+			result = "⏭️ Skipped (" + step.Message + ")"
+		}
+		fmt.Fprintf(&sb, "| %d | %s | %s | %s | %dms | %s |\n",
+			i+1, escapeCell(label), escapeCell(step.Request), code, step.DurationMs, escapeCell(result))
+	}
+	return sb.String()
+}
+
+// escapeCell keeps a value inside one Markdown table cell.
+func escapeCell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", "\\|"), "\n", " ")
+}
@@ -0,0 +1,473 @@
+package functional_test_generator
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+)
+
+// maxSampleDepth stops sample generation for deeply nested or recursive schemas.
+const maxSampleDepth = 6
+
+// requestBodySchema returns the JSON request body schema of an endpoint and
+// the spec's example payload for it, if any.
+func requestBodySchema(analysis shared.EndpointAnalysis) (map[string]interface{}, interface{}) {
+	keys := make([]string, 0, len(analysis.RequestSchemas))
+	for contentType := range analysis.RequestSchemas {
+		if strings.Contains(contentType, "json") {
+			keys = append(keys, contentType)
+		}
+	}
+	// application/json first, then other JSON media types in a stable order
+	sort.Slice(keys, func(i, j int) bool {
+		if (keys[i] == "application/json") != (keys[j] == "application/json") {
+			return keys[i] == "application/json"
+		}
+		return keys[i] < keys[j]
+	})
+	for _, contentType := range keys {
+		schema, _ := analysis.RequestSchemas[contentType].(map[string]interface{})
+		if schema != nil || analysis.RequestExamples[contentType] != nil {
+			return schema, analysis.RequestExamples[contentType]
+		}
+	}
+	return nil, nil
+}
+
+// mergeAllOf folds allOf branches into one schema: properties and required
+// lists are combined and the first branch that sets a keyword wins.
+func mergeAllOf(schema map[string]interface{}) map[string]interface{} {
+	branches, ok := schema["allOf"].([]interface{})
+	if !ok {
+		return schema
+	}
+	merged := make(map[string]interface{})
+	props := make(map[string]interface{})
+	var required []interface{}
+
+	parts := []map[string]interface{}{schema}
+	for _, branch := range branches {
+		if b, ok := branch.(map[string]interface{}); ok {
+			parts = append(parts, mergeAllOf(b))
+		}
+	}
+	for _, part := range parts {
+		for k, v := range part {
+			switch k {
+			case "allOf":
+			case "properties":
+				if p, ok := v.(map[string]interface{}); ok {
+					for name, sub := range p {
+						if _, exists := props[name]; !exists {
+							props[name] = sub
+						}
+					}
+				}
+			case "required":
+				if r, ok := v.([]interface{}); ok {
+					required = append(required, r...)
+				}
+			default:
+				if _, exists := merged[k]; !exists {
+					merged[k] = v
+				}
+			}
+		}
+	}
+	if len(props) > 0 {
+		merged["properties"] = props
+		if _, ok := merged["type"]; !ok {
+			merged["type"] = "object"
+		}
+	}
+	if len(required) > 0 {
+		merged["required"] = required
+	}
+	return merged
+}
+
+// resolveVariant picks the schema a value is generated from: allOf branches
+// are merged and the first oneOf/anyOf branch is used.
+func resolveVariant(schema map[string]interface{}) map[string]interface{} {
+	schema = mergeAllOf(schema)
+	for _, key := range []string{"oneOf", "anyOf"} {
+		if branches, ok := schema[key].([]interface{}); ok && len(branches) > 0 {
+			if first, ok := branches[0].(map[string]interface{}); ok {
+				merged := make(map[string]interface{}, len(schema))
+				for k, v := range schema {
+					if k != key {
+						merged[k] = v
+					}
+				}
+				for k, v := range resolveVariant(first) {
+					merged[k] = v
+				}
+				return merged
+			}
+		}
+	}
+	return schema
+}
+
+// schemaTypeOf returns the first non-null type of a schema, inferring it
+// from properties or items when the type is omitted.
+func schemaTypeOf(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+	return ""
+}
+
+// objectProperties returns the properties of an object schema in sorted
+// order, along with the set of required names.
+func objectProperties(schema map[string]interface{}) ([]string, map[string]map[string]interface{}, map[string]bool) {
+	props := make(map[string]map[string]interface{})
+	if p, ok := schema["properties"].(map[string]interface{}); ok {
+		for name, sub := range p {
+			subSchema, _ := sub.(map[string]interface{})
+			if subSchema == nil {
+				subSchema = map[string]interface{}{}
+			}
+			props[name] = subSchema
+		}
+	}
+	required := make(map[string]bool)
+	if r, ok := schema["required"].([]interface{}); ok {
+		for _, name := range r {
+			if n, ok := name.(string); ok {
+				required[n] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, props, required
+}
+
+// sampleFromSchema builds a realistic valid value for a schema. Spec examples,
+// defaults, consts and enums are used as-is; otherwise the value honours the
+// type, format, numeric and length limits, and field name.
+func sampleFromSchema(schema map[string]interface{}, name string, depth int) interface{} {
+	if schema == nil {
+		return nil
+	}
+	schema = resolveVariant(schema)
+
+	if example, ok := schema["example"]; ok {
+		return example
+	}
+	if examples, ok := schema["examples"].([]interface{}); ok && len(examples) > 0 {
+		return examples[0]
+	}
+	if def, ok := schema["default"]; ok {
+		return def
+	}
+	if c, ok := schema["const"]; ok {
+		return c
+	}
+	if enum, ok := schema["enum"].([]interface{}); ok && len(enum) > 0 {
+		for _, v := range enum {
+			if v != nil {
+				return v
+			}
+		}
+		return enum[0]
+	}
+
+	switch schemaTypeOf(schema) {
+	case "object":
+		body := make(map[string]interface{})
+		if depth >= maxSampleDepth {
+			return body
+		}
+		names, props, required := objectProperties(schema)
+		for _, prop := range names {
+			sub := props[prop]
+			if readOnly, _ := sub["readOnly"].(bool); readOnly {
+				continue // set by the server, not accepted in requests
+			}
+			// below the top level, keep payloads small: required fields only
+			if depth > 0 && !required[prop] && len(required) > 0 {
+				continue
+			}
+			body[prop] = sampleFromSchema(sub, prop, depth+1)
+		}
+		return body
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		count := 1
+		if n, ok := schemaNumber(schema, "minItems"); ok && int(n) > count {
+			count = int(n)
+		}
+		arr := make([]interface{}, 0, count)
+		if items == nil || depth >= maxSampleDepth {
+			return arr
+		}
+		for i := 0; i < count; i++ {
+			arr = append(arr, sampleFromSchema(items, name, depth+1))
+		}
+		return arr
+	case "integer":
+		return math.Round(sampleNumber(schema, 1))
+	case "number":
+		return sampleNumber(schema, 1.5)
+	case "boolean":
+		return true
+	case "null":
+		return nil
+	default:
+		return sampleString(schema, name)
+	}
+}
+
+// sampleNumber returns preferred when it fits the schema's limits, and the
+// nearest allowed value otherwise.
+func sampleNumber(schema map[string]interface{}, preferred float64) float64 {
+	low, hasLow := lowerBound(schema)
+	high, hasHigh := upperBound(schema)
+	value := preferred
+	if hasLow && value < low {
+		value = low
+	}
+	if hasHigh && value > high {
+		value = high
+	}
+	if schemaTypeOf(schema) == "integer" {
+		if hasLow && math.Ceil(low) > value {
+			value = math.Ceil(low)
+		}
+		value = math.Round(value)
+	}
+	return value
+}
+
+// lowerBound returns the smallest valid value of a numeric schema.
+func lowerBound(schema map[string]interface{}) (float64, bool) {
+	step := boundStep(schema)
+	if n, ok := schemaNumber(schema, "exclusiveMinimum"); ok {
+		return n + step, true
+	}
+	n, ok := schemaNumber(schema, "minimum")
+	if ok {
+		if ex, _ := schema["exclusiveMinimum"].(bool); ex {
+			n += step
+		}
+	}
+	return n, ok
+}
+
+// upperBound returns the largest valid value of a numeric schema.
+func upperBound(schema map[string]interface{}) (float64, bool) {
+	step := boundStep(schema)
+	if n, ok := schemaNumber(schema, "exclusiveMaximum"); ok {
+		return n - step, true
+	}
+	n, ok := schemaNumber(schema, "maximum")
+	if ok {
+		if ex, _ := schema["exclusiveMaximum"].(bool); ex {
+			n -= step
+		}
+	}
+	return n, ok
+}
+
+// boundStep is the distance from an exclusive limit to the nearest valid value.
+func boundStep(schema map[string]interface{}) float64 {
+	if schemaTypeOf(schema) == "integer" {
+		return 1
+	}
+	return 0.01
+}
+
+// sampleString returns a string matching the schema's format, or a value
+// suggested by the field name, padded or cut to the length limits.
+func sampleString(schema map[string]interface{}, name string) string {
+	format, _ := schema["format"].(string)
+	value := ""
+	switch format {
+	case "email":
+		value = "user@example.com"
+	case "uuid":
+		value = "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "date-time":
+		value = "2024-01-15T10:30:00Z"
+	case "date":
+		value = "2024-01-15"
+	case "time":
+		value = "10:30:00"
+	case "uri", "url":
+		value = "https://example.com"
+	case "hostname":
+		value = "example.com"
+	case "ipv4":
+		value = "192.0.2.1"
+	case "ipv6":
+		value = "2001:db8::1"
+	case "password":
+		value = "S3cure!Passw0rd"
+	case "byte":
+		value = "dGVzdA=="
+	case "phone":
+		value = "+1234567890"
+	default:
+		value = (&HappyPathStrategy{}).generateValidValue("string", name).(string)
+	}
+
+	if n, ok := schemaNumber(schema, "minLength"); ok && len(value) < int(n) {
+		value += strings.Repeat("x", int(n)-len(value))
+	}
+	if n, ok := schemaNumber(schema, "maxLength"); ok && len(value) > int(n) {
+		value = value[:int(n)]
+	}
+	return value
+}
+
+func schemaNumber(schema map[string]interface{}, key string) (float64, bool) {
+	switch n := schema[key].(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// validBody builds a valid request body for an endpoint from its spec
+// example or request schema. It returns nil when the spec has neither.
+func validBody(analysis shared.EndpointAnalysis) map[string]interface{} {
+	schema, example := requestBodySchema(analysis)
+	if body, ok := example.(map[string]interface{}); ok {
+		return copyBody(body)
+	}
+	if schema == nil {
+		return nil
+	}
+	body, _ := sampleFromSchema(schema, "", 0).(map[string]interface{})
+	return body
+}
+
+func copyBody(body map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(body))
+	for k, v := range body {
+		out[k] = v
+	}
+	return out
+}
+
+// fieldLimit describes a top-level body field with constraints worth probing.
+type fieldLimit struct {
+	name   string
+	schema map[string]interface{}
+}
+
+// constrainedFields lists the top-level body fields that declare enums or
+// numeric, length or item-count limits.
+func constrainedFields(schema map[string]interface{}) []fieldLimit {
+	if schema == nil {
+		return nil
+	}
+	schema = resolveVariant(schema)
+	names, props, _ := objectProperties(schema)
+	var fields []fieldLimit
+	for _, name := range names {
+		sub := resolveVariant(props[name])
+		for _, key := range []string{"enum", "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "minLength", "maxLength", "minItems", "maxItems"} {
+			if _, ok := sub[key]; ok {
+				fields = append(fields, fieldLimit{name: name, schema: sub})
+				break
+			}
+		}
+	}
+	return fields
+}
+
+// atLimitValue returns a value sitting exactly on the field's limits, which
+// the API should accept. ok is false when the field has no such limit.
+func atLimitValue(f fieldLimit) (interface{}, bool) {
+	switch schemaTypeOf(f.schema) {
+	case "integer", "number":
+		if high, ok := upperBound(f.schema); ok {
+			return high, true
+		}
+		if low, ok := lowerBound(f.schema); ok {
+			return low, true
+		}
+	case "string":
+		if n, ok := schemaNumber(f.schema, "maxLength"); ok {
+			return strings.Repeat("x", int(n)), true
+		}
+		if n, ok := schemaNumber(f.schema, "minLength"); ok {
+			return strings.Repeat("x", int(n)), true
+		}
+	case "array":
+		if n, ok := schemaNumber(f.schema, "maxItems"); ok {
+			return repeatItems(f.schema, int(n)), true
+		}
+		if n, ok := schemaNumber(f.schema, "minItems"); ok {
+			return repeatItems(f.schema, int(n)), true
+		}
+	}
+	return nil, false
+}
+
+// beyondLimitValue returns a value just outside the field's limits, which
+// the API should reject. ok is false when the field has no such limit.
+func beyondLimitValue(f fieldLimit) (interface{}, bool) {
+	if _, ok := f.schema["enum"]; ok {
+		return "not_an_allowed_value", true
+	}
+	switch schemaTypeOf(f.schema) {
+	case "integer", "number":
+		step := boundStep(f.schema)
+		if high, ok := upperBound(f.schema); ok {
+			return high + step, true
+		}
+		if low, ok := lowerBound(f.schema); ok {
+			return low - step, true
+		}
+	case "string":
+		if n, ok := schemaNumber(f.schema, "maxLength"); ok {
+			return strings.Repeat("x", int(n)+1), true
+		}
+		if n, ok := schemaNumber(f.schema, "minLength"); ok && n > 0 {
+			return strings.Repeat("x", int(n)-1), true
+		}
+	case "array":
+		if n, ok := schemaNumber(f.schema, "maxItems"); ok {
+			return repeatItems(f.schema, int(n)+1), true
+		}
+		if n, ok := schemaNumber(f.schema, "minItems"); ok && n > 0 {
+			return repeatItems(f.schema, int(n)-1), true
+		}
+	}
+	return nil, false
+}
+
+func repeatItems(schema map[string]interface{}, n int) []interface{} {
+	items, _ := schema["items"].(map[string]interface{})
+	arr := make([]interface{}, n)
+	for i := range arr {
+		arr[i] = sampleFromSchema(items, "", 1)
+	}
+	return arr
+}
@@ -64,8 +64,8 @@ func (s *HappyPathStrategy) Generate(endpointKey string, analysis shared.Endpoin
 	// Build URL
 	url := strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(path, "/")
 
-	// Generate valid body based on parameters
-	body := s.generateValidBody(analysis.Parameters)
+	// Generate valid body from the spec's request schema, or from parameters
+	body := s.generateValidBody(analysis)
 
 	// Determine expected status code
 	expectedStatus := s.getExpectedSuccessStatus(method, analysis.Responses)
@@ -89,8 +89,14 @@ func (s *HappyPathStrategy) Generate(endpointKey string, analysis shared.Endpoin
 	return []shared.TestScenario{scenario}
 }
 
-// generateValidBody creates a valid request body based on parameters.
-func (s *HappyPathStrategy) generateValidBody(params []shared.Parameter) map[string]interface{} {
+// generateValidBody creates a valid request body. The spec's example payload
+// or request body schema is preferred; without one, body parameters are
+// filled in from their types and names.
+func (s *HappyPathStrategy) generateValidBody(analysis shared.EndpointAnalysis) map[string]interface{} {
+	if body := validBody(analysis); body != nil {
+		return body
+	}
+	params := analysis.Parameters
 	if len(params) == 0 {
 		return nil
 	}
@@ -321,6 +327,7 @@ func (s *BoundaryStrategy) Generate(endpointKey string, analysis shared.Endpoint
 	url := strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(path, "/")
 
 	var scenarios []shared.TestScenario
+	schema, _ := requestBodySchema(analysis)
 
 	// Test 1: Empty strings
 	scenarios = append(scenarios, shared.TestScenario{
@@ -332,7 +339,7 @@ func (s *BoundaryStrategy) Generate(endpointKey string, analysis shared.Endpoint
 		Method:      method,
 		URL:         url,
 		Headers:     map[string]string{"Content-Type": "application/json"},
-		Body:        s.generateEmptyStringBody(analysis.Parameters),
+		Body:        s.generateEmptyStringBody(analysis),
 		Expected: shared.TestExpectation{
 			StatusCodeRange: &shared.StatusCodeRange{Min: 400, Max: 499},
 		},
@@ -348,7 +355,7 @@ func (s *BoundaryStrategy) Generate(endpointKey string, analysis shared.Endpoint
 		Method:      method,
 		URL:         url,
 		Headers:     map[string]string{"Content-Type": "application/json"},
-		Body:        s.generateMaxValueBody(analysis.Parameters),
+		Body:        s.generateMaxValueBody(analysis),
 		Expected: shared.TestExpectation{
 			StatusCodeRange: &shared.StatusCodeRange{Min: 200, Max: 499},
 		},
@@ -370,13 +377,102 @@ func (s *BoundaryStrategy) Generate(endpointKey string, analysis shared.Endpoint
 		},
 	})
 
+	// Tests 4-5: Spec limits (enum, minimum/maximum, minLength/maxLength, minItems/maxItems)
+	if limits := constrainedFields(schema); len(limits) > 0 {
+		if body := s.generateLimitBody(analysis, limits, atLimitValue); body != nil {
+			scenarios = append(scenarios, shared.TestScenario{
+				ID:          fmt.Sprintf("boundary_at_limits_%s_%s", method, sanitizePath(path)),
+				Name:        fmt.Sprintf("Boundary: At Spec Limits - %s %s", method, path),
+				Category:    "functional",
+				Severity:    "medium",
+				Description: fmt.Sprintf("Request to %s with every constrained field exactly on its documented limit", endpointKey),
+				Method:      method,
+				URL:         url,
+				Headers:     map[string]string{"Content-Type": "application/json"},
+				Body:        body,
+				Expected: shared.TestExpectation{
+					StatusCodeRange: &shared.StatusCodeRange{Min: 200, Max: 299},
+				},
+			})
+		}
+		for _, limit := range limits {
+			value, ok := beyondLimitValue(limit)
+			if !ok {
+				continue
+			}
+			body := validBody(analysis)
+			if body == nil {
+				body = map[string]interface{}{}
+			}
+			body[limit.name] = value
+			scenarios = append(scenarios, shared.TestScenario{
+				ID:          fmt.Sprintf("boundary_beyond_limit_%s_%s_%s", limit.name, method, sanitizePath(path)),
+				Name:        fmt.Sprintf("Boundary: %s Beyond Spec Limit - %s %s", limit.name, method, path),
+				Category:    "functional",
+				Severity:    "medium",
+				Description: fmt.Sprintf("Request to %s with '%s' just outside its documented limits", endpointKey, limit.name),
+				Method:      method,
+				URL:         url,
+				Headers:     map[string]string{"Content-Type": "application/json"},
+				Body:        body,
+				Expected: shared.TestExpectation{
+					StatusCodeRange: &shared.StatusCodeRange{Min: 400, Max: 499},
+				},
+			})
+		}
+	}
+
 	return scenarios
 }
 
+// generateLimitBody starts from a valid body and sets each constrained field
+// to the value chosen by pick. It returns nil when no field has such a value.
+func (s *BoundaryStrategy) generateLimitBody(analysis shared.EndpointAnalysis, limits []fieldLimit, pick func(fieldLimit) (interface{}, bool)) map[string]interface{} {
+	body := validBody(analysis)
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+	changed := false
+	for _, limit := range limits {
+		if value, ok := pick(limit); ok {
+			body[limit.name] = value
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return body
+}
+
+// bodyFields returns the top-level body fields with their types, from the
+// request schema when the spec has one and from body parameters otherwise.
+func bodyFields(analysis shared.EndpointAnalysis) []shared.Parameter {
+	if schema, _ := requestBodySchema(analysis); schema != nil {
+		names, props, required := objectProperties(resolveVariant(schema))
+		fields := make([]shared.Parameter, 0, len(names))
+		for _, name := range names {
+			sub := resolveVariant(props[name])
+			if readOnly, _ := sub["readOnly"].(bool); readOnly {
+				continue
+			}
+			fields = append(fields, shared.Parameter{Name: name, Type: schemaTypeOf(sub), Required: required[name], Description: "in: body"})
+		}
+		return fields
+	}
+	var fields []shared.Parameter
+	for _, param := range analysis.Parameters {
+		if strings.Contains(param.Description, "in: body") {
+			fields = append(fields, param)
+		}
+	}
+	return fields
+}
+
 // generateEmptyStringBody creates a body with empty strings.
-func (s *BoundaryStrategy) generateEmptyStringBody(params []shared.Parameter) map[string]interface{} {
+func (s *BoundaryStrategy) generateEmptyStringBody(analysis shared.EndpointAnalysis) map[string]interface{} {
 	body := make(map[string]interface{})
-	for _, param := range params {
+	for _, param := range bodyFields(analysis) {
 		if strings.Contains(param.Description, "in: body") {
 			switch param.Type {
 			case "string":
@@ -394,9 +490,9 @@ func (s *BoundaryStrategy) generateEmptyStringBody(params []shared.Parameter) ma
 }
 
 // generateMaxValueBody creates a body with maximum values.
-func (s *BoundaryStrategy) generateMaxValueBody(params []shared.Parameter) map[string]interface{} {
+func (s *BoundaryStrategy) generateMaxValueBody(analysis shared.EndpointAnalysis) map[string]interface{} {
 	body := make(map[string]interface{})
-	for _, param := range params {
+	for _, param := range bodyFields(analysis) {
 		if strings.Contains(param.Description, "in: body") {
 			switch param.Type {
 			case "string":
@@ -0,0 +1,80 @@
+package functional_test_generator
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+)
+
+func petAnalysis(t *testing.T) shared.EndpointAnalysis {
+	t.Helper()
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(`{
+	  "allOf": [
+	    {"type": "object", "required": ["name"], "properties": {"name": {"type": "string", "minLength": 2, "maxLength": 5}}},
+	    {"type": "object", "required": ["kind", "owner"], "properties": {
+	      "id": {"type": "integer", "readOnly": true},
+	      "kind": {"type": "string", "enum": ["dog", "cat"]},
+	      "age": {"type": "integer", "minimum": 1, "exclusiveMaximum": 30},
+	      "email": {"type": "string", "format": "email"},
+	      "owner": {"oneOf": [{"type": "object", "required": ["id"], "properties": {"id": {"type": "string", "format": "uuid"}, "nick": {"type": "string"}}}, {"type": "string"}]},
+	      "tags": {"type": "array", "minItems": 2, "items": {"type": "string", "example": "good"}}
+	    }}
+	  ]
+	}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	return shared.EndpointAnalysis{RequestSchemas: map[string]interface{}{"application/json": schema}}
+}
+
+func TestHappyPath_BodyFromSchema(t *testing.T) {
+	body := (&HappyPathStrategy{}).generateValidBody(petAnalysis(t))
+
+	got, _ := json.Marshal(body)
+	want := `{"age":1,"email":"user@example.com","kind":"dog","name":"Test ","owner":{"id":"3fa85f64-5717-4562-b3fc-2c963f66afa6"},"tags":["good","good"]}`
+	if string(got) != want {
+		t.Errorf("body = %s\nwant   %s", got, want)
+	}
+
+	analysis := petAnalysis(t)
+	analysis.RequestExamples = map[string]interface{}{"application/json": map[string]interface{}{"name": "Rex"}}
+	if body := (&HappyPathStrategy{}).generateValidBody(analysis); body["name"] != "Rex" || len(body) != 1 {
+		t.Errorf("expected the spec example to be used, got %v", body)
+	}
+}
+
+func TestBoundary_SpecLimits(t *testing.T) {
+	scenarios := (&BoundaryStrategy{}).Generate("POST /pets", petAnalysis(t), "http://localhost")
+
+	bodies := make(map[string]map[string]interface{})
+	for _, s := range scenarios {
+		body, _ := s.Body.(map[string]interface{})
+		bodies[s.ID] = body
+	}
+
+	at := bodies["boundary_at_limits_POST_pets"]
+	if at == nil || at["age"] != float64(29) || at["name"] != "xxxxx" || len(at["tags"].([]interface{})) != 2 {
+		t.Errorf("at-limits body = %v, want age 29, a 5-char name and 2 tags", at)
+	}
+
+	beyond := map[string]interface{}{
+		"boundary_beyond_limit_age_POST_pets":  float64(30),
+		"boundary_beyond_limit_kind_POST_pets": "not_an_allowed_value",
+		"boundary_beyond_limit_name_POST_pets": "xxxxxx",
+	}
+	for id, want := range beyond {
+		body, ok := bodies[id]
+		if !ok {
+			t.Errorf("missing scenario %s", id)
+			continue
+		}
+		field := id[len("boundary_beyond_limit_") : len(id)-len("_POST_pets")]
+		if body[field] != want || body["email"] != "user@example.com" {
+			t.Errorf("%s body = %v, want %s=%v on an otherwise valid body", id, body, field, want)
+		}
+	}
+	if tags, ok := bodies["boundary_beyond_limit_tags_POST_pets"]["tags"].([]interface{}); !ok || len(tags) != 1 {
+		t.Errorf("expected a tags array below minItems, got %v", bodies["boundary_beyond_limit_tags_POST_pets"])
+	}
+}
@@ -3,6 +3,7 @@ package functional_test_generator
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
 	"github.com/blackcoderx/falcon/pkg/core/tools/spec_ingester"
@@ -11,17 +12,15 @@ import (
 // FunctionalTestGeneratorTool generates comprehensive functional tests
 // from the API Knowledge Graph using various testing strategies.
 type FunctionalTestGeneratorTool struct {
-	falconDir      string
-	strategyEngine *StrategyEngine
-	generator      *TestGenerator
+	falconDir string
+	generator *TestGenerator
 }
 
 // NewFunctionalTestGeneratorTool creates a new functional test generator tool.
 func NewFunctionalTestGeneratorTool(falconDir string, executor *shared.TestExecutor) *FunctionalTestGeneratorTool {
 	return &FunctionalTestGeneratorTool{
-		falconDir:      falconDir,
-		strategyEngine: NewStrategyEngine(),
-		generator:      NewTestGenerator(executor),
+		falconDir: falconDir,
+		generator: NewTestGenerator(executor),
 	}
 }
 
@@ -81,36 +80,10 @@ func (t *FunctionalTestGeneratorTool) Execute(args string) (string, error) {
 		params.Strategies = []string{"happy", "negative", "boundary"}
 	}
 
-	// 1. Load API Knowledge Graph
-	builder := spec_ingester.NewGraphBuilder(t.falconDir)
-	graph, err := builder.LoadGraph()
+	// 1-3. Load the Knowledge Graph and generate scenarios
+	scenarios, strategyBreakdown, err := GenerateScenarios(t.falconDir, params.BaseURL, params.Strategies, params.Endpoints)
 	if err != nil {
-		return "", fmt.Errorf("failed to load API Knowledge Graph: %w", err)
-	}
-	if graph == nil || len(graph.Endpoints) == 0 {
-		return "", fmt.Errorf("API Knowledge Graph is empty. Run 'ingest_spec' first to index an API specification")
-	}
-
-	// 2. Filter endpoints if specific ones requested
-	endpointsToTest := graph.Endpoints
-	if len(params.Endpoints) > 0 {
-		filtered := make(map[string]shared.EndpointAnalysis)
-		for _, endpoint := range params.Endpoints {
-			if analysis, ok := graph.Endpoints[endpoint]; ok {
-				filtered[endpoint] = analysis
-			}
-		}
-		if len(filtered) == 0 {
-			return "", fmt.Errorf("none of the requested endpoints found in Knowledge Graph")
-		}
-		endpointsToTest = filtered
-	}
-
-	// 3. Generate test scenarios using selected strategies
-	scenarios, strategyBreakdown := t.generateScenarios(endpointsToTest, params.BaseURL, params.Strategies)
-
-	if len(scenarios) == 0 {
-		return "", fmt.Errorf("no test scenarios generated")
+		return "", err
 	}
 
 	// 4. Execute tests if requested
@@ -142,8 +115,48 @@ func (t *FunctionalTestGeneratorTool) Execute(args string) (string, error) {
 	return result.Summary, nil
 }
 
+// GenerateScenarios loads the API Knowledge Graph and generates scenarios for
+// the given endpoints (empty = all) with the given strategies (empty = all).
+// Endpoints are visited in sorted order so repeated runs produce the same list.
+func GenerateScenarios(falconDir, baseURL string, strategies, endpoints []string) ([]shared.TestScenario, map[string]int, error) {
+	if len(strategies) == 0 {
+		strategies = []string{"happy", "negative", "boundary"}
+	}
+
+	builder := spec_ingester.NewGraphBuilder(falconDir)
+	graph, err := builder.LoadGraph()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load API Knowledge Graph: %w", err)
+	}
+	if graph == nil || len(graph.Endpoints) == 0 {
+		return nil, nil, fmt.Errorf("API Knowledge Graph is empty. Run 'ingest_spec' first to index an API specification")
+	}
+
+	// Filter endpoints if specific ones requested
+	endpointsToTest := graph.Endpoints
+	if len(endpoints) > 0 {
+		filtered := make(map[string]shared.EndpointAnalysis)
+		for _, endpoint := range endpoints {
+			if analysis, ok := graph.Endpoints[endpoint]; ok {
+				filtered[endpoint] = analysis
+			}
+		}
+		if len(filtered) == 0 {
+			return nil, nil, fmt.Errorf("none of the requested endpoints found in Knowledge Graph")
+		}
+		endpointsToTest = filtered
+	}
+
+	scenarios, strategyBreakdown := generateScenarios(NewStrategyEngine(), endpointsToTest, baseURL, strategies)
+	if len(scenarios) == 0 {
+		return nil, nil, fmt.Errorf("no test scenarios generated")
+	}
+	return scenarios, strategyBreakdown, nil
+}
+
 // generateScenarios creates test scenarios from endpoints using the specified strategies.
-func (t *FunctionalTestGeneratorTool) generateScenarios(
+func generateScenarios(
+	engine *StrategyEngine,
 	endpoints map[string]shared.EndpointAnalysis,
 	baseURL string,
 	strategies []string,
@@ -151,10 +164,17 @@ func (t *FunctionalTestGeneratorTool) generateScenarios(
 	var allScenarios []shared.TestScenario
 	strategyBreakdown := make(map[string]int)
 
+	keys := make([]string, 0, len(endpoints))
+	for endpointKey := range endpoints {
+		keys = append(keys, endpointKey)
+	}
+	sort.Strings(keys)
+
 	// Generate scenarios for each endpoint using each strategy
-	for endpointKey, analysis := range endpoints {
+	for _, endpointKey := range keys {
+		analysis := endpoints[endpointKey]
 		for _, strategyName := range strategies {
-			scenarios := t.strategyEngine.Generate(endpointKey, analysis, baseURL, strategyName)
+			scenarios := engine.Generate(endpointKey, analysis, baseURL, strategyName)
 			allScenarios = append(allScenarios, scenarios...)
 			strategyBreakdown[strategyName] += len(scenarios)
 		}
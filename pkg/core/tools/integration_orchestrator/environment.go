@@ -2,19 +2,24 @@ package integration_orchestrator
 
 import (
 	"strings"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
 )
 
 // Environment manages the context and state across workflow steps.
 type Environment struct {
-	BaseURL string
-	State   map[string]interface{}
+	BaseURL   string
+	State     map[string]interface{}
+	Responses map[string]*shared.HTTPResponse // Responses keyed by step ID
+	Last      *shared.HTTPResponse            // Most recent HTTP response
 }
 
 // NewEnvironment creates a new environment context.
 func NewEnvironment(baseURL string) *Environment {
 	return &Environment{
-		BaseURL: baseURL,
-		State:   make(map[string]interface{}),
+		BaseURL:   baseURL,
+		State:     make(map[string]interface{}),
+		Responses: make(map[string]*shared.HTTPResponse),
 	}
 }
 
@@ -42,3 +47,53 @@ func (e *Environment) Set(key string, value interface{}) {
 func (e *Environment) Get(key string) interface{} {
 	return e.State[key]
 }
+
+// RecordResponse stores a step's response and makes it the last response.
+func (e *Environment) RecordResponse(stepID string, resp *shared.HTTPResponse) {
+	if stepID != "" {
+		e.Responses[stepID] = resp
+	}
+	e.Last = resp
+}
+
+// InterpolateString replaces {{var}} placeholders with state values.
+// Unknown placeholders are left untouched so failures are visible.
+func (e *Environment) InterpolateString(s string) string {
+	return shared.PlaceholderPattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := shared.PlaceholderPattern.FindStringSubmatch(match)[1]
+		val, ok := e.State[name]
+		if !ok {
+			return match
+		}
+		return shared.Stringify(val)
+	})
+}
+
+// Interpolate walks a decoded JSON value and substitutes placeholders.
+// A string that is exactly one placeholder is replaced by the typed state
+// value, so {"id": "{{user_id}}"} keeps a numeric ID numeric.
+func (e *Environment) Interpolate(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			out[e.InterpolateString(k)] = e.Interpolate(child)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, child := range val {
+			out[i] = e.Interpolate(child)
+		}
+		return out
+	case string:
+		if name, ok := shared.PlaceholderName(val); ok {
+			if stateVal, ok := e.State[name]; ok {
+				return stateVal
+			}
+		}
+		return e.InterpolateString(val)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,54 @@
+package integration_orchestrator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnvironment_Interpolate(t *testing.T) {
+	env := NewEnvironment("http://api.test/")
+	env.Set("id", float64(42))
+	env.Set("name", "alice")
+	env.Set("tags", []interface{}{"a", "b"})
+
+	texts := []struct{ in, want string }{
+		{"/users/{{id}}", "/users/42"},
+		{"{{ name }}-{{id}}", "alice-42"},
+		{"/users/{{missing}}", "/users/{{missing}}"},
+		{"tags={{tags}}", `tags=["a","b"]`},
+		{"no placeholders", "no placeholders"},
+	}
+	for _, tc := range texts {
+		if got := env.InterpolateString(tc.in); got != tc.want {
+			t.Errorf("InterpolateString(%q) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+
+	got := env.Interpolate(map[string]interface{}{
+		"id":       "{{id}}",
+		"label":    "user {{id}}",
+		"{{name}}": []interface{}{"{{tags}}", "{{missing}}", true},
+	})
+	want := map[string]interface{}{
+		"id":    float64(42),
+		"label": "user 42",
+		"alice": []interface{}{[]interface{}{"a", "b"}, "{{missing}}", true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Interpolate = %#v, want %#v", got, want)
+	}
+}
+
+func TestEnvironment_ResolveURL(t *testing.T) {
+	cases := []struct{ base, path, want string }{
+		{"http://api.test/", "/users", "http://api.test/users"},
+		{"http://api.test", "users", "http://api.test/users"},
+		{"http://api.test", "https://other.test/x", "https://other.test/x"},
+		{"", "/users", "users"},
+	}
+	for _, tc := range cases {
+		if got := NewEnvironment(tc.base).ResolveURL(tc.path); got != tc.want {
+			t.Errorf("ResolveURL(%q, %q) = %q, want %q", tc.base, tc.path, got, tc.want)
+		}
+	}
+}
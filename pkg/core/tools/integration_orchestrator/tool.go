@@ -23,17 +23,19 @@ func NewIntegrationOrchestratorTool(falconDir string, httpTool *shared.HTTPTool)
 
 // OrchestrateParams defines the parameters for a workflow orchestration.
 type OrchestrateParams struct {
-	Workflow      []WorkflowStep `json:"workflow"`           // List of steps to execute
-	BaseURL       string         `json:"base_url,omitempty"` // Global base URL
-	StopOnFailure bool           `json:"stop_on_failure"`    // Whether to halt if a step fails
+	Workflow      []WorkflowStep         `json:"workflow"`            // List of steps to execute
+	Teardown      []WorkflowStep         `json:"teardown,omitempty"`  // Cleanup steps that always run
+	BaseURL       string                 `json:"base_url,omitempty"`  // Global base URL
+	Variables     map[string]interface{} `json:"variables,omitempty"` // Initial {{var}} values
+	StopOnFailure bool                   `json:"stop_on_failure"`     // Whether to halt if a step fails
 }
 
 // WorkflowStep represents a single action in the integration test.
 type WorkflowStep struct {
 	ID          string                 `json:"id"`
 	Description string                 `json:"description,omitempty"`
-	Action      string                 `json:"action"`           // http, wait, extract, assert
-	Params      map[string]interface{} `json:"params,omitempty"` // Action-specific parameters
+	Action      string                 `json:"action"`           // http, wait, extract, assert, or "METHOD /path"
+	Params      map[string]interface{} `json:"params,omitempty"` // Action-specific parameters (supports {{var}})
 }
 
 // OrchestrateResult represents the outcome of the entire workflow.
@@ -51,6 +53,8 @@ type StepResult struct {
 	Description string `json:"description"`
 	Status      string `json:"status"` // pass, fail, skipped
 	Message     string `json:"message,omitempty"`
+	Phase       string `json:"phase,omitempty"` // "teardown" for cleanup steps
+	DurationMs  int64  `json:"duration_ms"`
 }
 
 func (t *IntegrationOrchestratorTool) Name() string {
@@ -58,16 +62,24 @@ func (t *IntegrationOrchestratorTool) Name() string {
 }
 
 func (t *IntegrationOrchestratorTool) Description() string {
-	return "Execute a multi-step API integration workflow (e.g., Create -> Login -> Order -> Delete) with state sharing between steps"
+	return "Execute a multi-step API integration workflow (e.g., Create -> Login -> Order -> Delete) with {{var}} state sharing, extract/assert/wait-poll steps, and teardown steps that always run"
 }
 
 func (t *IntegrationOrchestratorTool) Parameters() string {
 	return `{
   "workflow": [
-    {"id": "create", "action": "POST /users", "params": {"name": "Test User"}},
-    {"id": "verify", "action": "GET /users/{{id}}"}
+    {"id": "login", "action": "http", "params": {"method": "POST", "path": "/login", "body": {"user": "{{user}}"}, "extract": {"token": "$.token"}}},
+    {"id": "create", "action": "POST /users", "params": {"headers": {"Authorization": "Bearer {{token}}"}, "body": {"name": "Test User"}, "expect": {"status_code": 201}, "extract": {"id": "$.id"}}},
+    {"id": "ready", "action": "wait", "params": {"request": {"path": "/users/{{id}}"}, "until": {"status_code": 200}, "interval_ms": 500, "timeout_seconds": 10}},
+    {"id": "location", "action": "extract", "params": {"from": "create", "values": {"loc": "header:Location", "code": "status"}}},
+    {"id": "check", "action": "assert", "params": {"from": "ready", "body_contains": ["Test User"], "equals": {"code": 201}}}
   ],
-  "base_url": "http://localhost:3000"
+  "teardown": [
+    {"id": "cleanup", "action": "DELETE /users/{{id}}", "params": {"headers": {"Authorization": "Bearer {{token}}"}}}
+  ],
+  "variables": {"user": "alice"},
+  "base_url": "http://localhost:3000",
+  "stop_on_failure": true
 }`
 }
 
@@ -81,12 +93,17 @@ func (t *IntegrationOrchestratorTool) Execute(args string) (string, error) {
 		return "", fmt.Errorf("workflow must contain at least one step")
 	}
 
+	env := NewEnvironment(params.BaseURL)
+	for k, v := range params.Variables {
+		env.Set(k, v)
+	}
+
 	orchestrator := &WorkflowManager{
 		httpTool: t.httpTool,
-		env:      NewEnvironment(params.BaseURL),
+		env:      env,
 	}
 
-	result := orchestrator.Run(params.Workflow, params.StopOnFailure)
+	result := orchestrator.Run(params.Workflow, params.Teardown, params.StopOnFailure)
 	result.Summary = t.formatSummary(result)
 
 	return result.Summary, nil
@@ -106,8 +123,12 @@ func (t *IntegrationOrchestratorTool) formatSummary(r OrchestrateResult) string
 		case "skipped":
 			icon = "⏭️"
 		}
-		summary += fmt.Sprintf("  %s [%s] %s\n", icon, step.StepID, step.Description)
-		if step.Message != "" && step.Status == "fail" {
+		label := step.StepID
+		if step.Phase != "" {
+			label = step.Phase + ":" + label
+		}
+		summary += fmt.Sprintf("  %s [%s] %s\n", icon, label, step.Description)
+		if step.Message != "" && step.Status != "pass" {
 			summary += fmt.Sprintf("    Status: %s\n", step.Message)
 		}
 	}
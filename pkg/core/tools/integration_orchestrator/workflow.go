@@ -1,27 +1,95 @@
 package integration_orchestrator
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
 )
 
+// Step action types. Any other action of the form "METHOD /path" is an HTTP
+// shorthand (e.g. "POST /users").
+const (
+	ActionHTTP    = "http"
+	ActionWait    = "wait"
+	ActionExtract = "extract"
+	ActionAssert  = "assert"
+)
+
+// Defaults for wait/poll steps.
+const (
+	defaultPollInterval = time.Second
+	defaultPollTimeout  = 30 * time.Second
+)
+
+// errUnresolved marks a request whose URL still has a {{name}} placeholder,
+// e.g. because the step that extracts it failed or never ran.
+var errUnresolved = errors.New("unresolved placeholder")
+
+// httpStepParams configures an http step (or the request of a poll step).
+type httpStepParams struct {
+	Method  string                  `json:"method,omitempty"`
+	URL     string                  `json:"url,omitempty"`
+	Path    string                  `json:"path,omitempty"`
+	Headers map[string]string       `json:"headers,omitempty"`
+	Query   map[string]string       `json:"query,omitempty"`
+	Body    interface{}             `json:"body,omitempty"`
+	Timeout int                     `json:"timeout,omitempty"`
+	Expect  *shared.TestExpectation `json:"expect,omitempty"`  // default: any status < 400
+	Extract map[string]string       `json:"extract,omitempty"` // var -> source (see ExtractValue)
+}
+
+// httpParamKeys are the keys that mark shorthand step params as structured
+// rather than being the request body itself.
+var httpParamKeys = []string{"method", "url", "path", "headers", "query", "body", "timeout", "expect", "extract"}
+
+// extractStepParams configures an extract step.
+type extractStepParams struct {
+	From   string            `json:"from,omitempty"` // step ID; default: last response
+	Values map[string]string `json:"values"`         // var -> source
+}
+
+// assertStepParams configures an assert step. Response expectations are
+// checked with shared.ValidateExpectations; Equals compares state variables.
+type assertStepParams struct {
+	shared.TestExpectation
+	From   string                 `json:"from,omitempty"`   // step ID; default: last response
+	Equals map[string]interface{} `json:"equals,omitempty"` // var -> expected value
+}
+
+// waitStepParams configures a wait step: a fixed delay, or polling a request
+// until its expectation holds.
+type waitStepParams struct {
+	Seconds        float64                 `json:"seconds,omitempty"`
+	Ms             int                     `json:"ms,omitempty"`
+	Request        *httpStepParams         `json:"request,omitempty"`
+	Until          *shared.TestExpectation `json:"until,omitempty"`
+	IntervalMs     int                     `json:"interval_ms,omitempty"`
+	TimeoutSeconds int                     `json:"timeout_seconds,omitempty"`
+}
+
 // WorkflowManager orchestrates the execution of individual steps.
 type WorkflowManager struct {
 	httpTool *shared.HTTPTool
 	env      *Environment
 }
 
-// Run executes the workflow steps in sequence.
-func (m *WorkflowManager) Run(steps []WorkflowStep, stopOnFailure bool) OrchestrateResult {
+// Run executes the workflow steps in sequence, then the teardown steps.
+// Teardown steps always run, even if the workflow halted on a failure, so
+// resources created by earlier steps get cleaned up. A teardown step whose
+// URL needs a variable that was never set is skipped.
+func (m *WorkflowManager) Run(steps []WorkflowStep, teardown []WorkflowStep, stopOnFailure bool) OrchestrateResult {
 	var results OrchestrateResult
-	results.TotalSteps = len(steps)
+	results.TotalSteps = len(steps) + len(teardown)
 
 	halted := false
 	for _, step := range steps {
 		if halted {
-			results.StepResults = append(results.StepResults, StepResult{
+			results.record(StepResult{
 				StepID:      step.ID,
 				Description: step.Description,
 				Status:      "skipped",
@@ -29,87 +97,371 @@ func (m *WorkflowManager) Run(steps []WorkflowStep, stopOnFailure bool) Orchestr
 			continue
 		}
 
-		res := m.executeStep(step)
-		results.StepResults = append(results.StepResults, res)
+		res := m.executeStep(step, "")
+		results.record(res)
 
-		if res.Status == "pass" {
-			results.Completed++
-		} else {
-			results.Failed++
-			if stopOnFailure {
-				halted = true
-			}
+		if res.Status != "pass" && stopOnFailure {
+			halted = true
 		}
 	}
 
+	for _, step := range teardown {
+		results.record(m.executeStep(step, "teardown"))
+	}
+
 	return results
 }
 
-func (m *WorkflowManager) executeStep(step WorkflowStep) StepResult {
-	// 1. Resolve variables in step parameters
-	// (Variable interpolation logic would go here)
+// record appends a step result and updates the counters.
+func (r *OrchestrateResult) record(res StepResult) {
+	r.StepResults = append(r.StepResults, res)
+	switch res.Status {
+	case "pass":
+		r.Completed++
+	case "fail":
+		r.Failed++
+	}
+}
 
+func (m *WorkflowManager) executeStep(step WorkflowStep, phase string) StepResult {
 	description := step.Description
 	if description == "" {
 		description = fmt.Sprintf("Execute %s", step.Action)
 	}
 
+	result := StepResult{StepID: step.ID, Description: description, Status: "pass", Phase: phase}
+	start := time.Now()
+
+	// 1. Resolve {{var}} placeholders in the action and its parameters
+	action := m.env.InterpolateString(strings.TrimSpace(step.Action))
+	params, _ := m.env.Interpolate(step.Params).(map[string]interface{})
+
 	// 2. Dispatch action
-	// Simplified: mainly focusing on HTTP for now as it's the core of integration
-	if strings.Contains(step.Action, "/") {
-		return m.executeHTTPRequest(step)
+	var err error
+	switch strings.ToLower(action) {
+	case ActionHTTP:
+		err = m.executeHTTP(step.ID, params, "", "")
+	case ActionWait:
+		err = m.executeWait(step.ID, params)
+	case ActionExtract:
+		err = m.executeExtract(params)
+	case ActionAssert:
+		err = m.executeAssert(params)
+	default:
+		method, path, ok := parseShorthand(action)
+		if !ok {
+			err = fmt.Errorf("unsupported action '%s' (use http, wait, extract, assert or 'METHOD /path')", step.Action)
+			break
+		}
+		err = m.executeHTTP(step.ID, params, method, path)
 	}
 
-	return StepResult{
-		StepID:      step.ID,
-		Description: description,
-		Status:      "fail",
-		Message:     "Unsupported action type",
+	result.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		result.Status = "fail"
+		if phase == "teardown" && errors.Is(err, errUnresolved) {
+			result.Status = "skipped"
+		}
+		result.Message = err.Error()
 	}
+	return result
 }
 
-func (m *WorkflowManager) executeHTTPRequest(step WorkflowStep) StepResult {
-	parts := strings.SplitN(step.Action, " ", 2)
-	method := "GET"
-	path := step.Action
+// parseShorthand splits "POST /users" into method and path.
+func parseShorthand(action string) (method, path string, ok bool) {
+	if !strings.Contains(action, "/") {
+		return "", "", false
+	}
+	parts := strings.SplitN(action, " ", 2)
 	if len(parts) == 2 {
-		method = parts[0]
-		path = parts[1]
+		return strings.ToUpper(parts[0]), strings.TrimSpace(parts[1]), true
+	}
+	return "GET", action, true
+}
+
+// decodeParams converts a generic params map into a typed struct.
+func decodeParams(params map[string]interface{}, out interface{}) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("invalid step params: %w", err)
+	}
+	return nil
+}
+
+// toHTTPParams reads http step params. For shorthand steps whose params
+// contain none of the structured keys, the params are the request body
+// (e.g. {"action": "POST /users", "params": {"name": "Test"}}).
+func toHTTPParams(params map[string]interface{}, method, path string) (*httpStepParams, error) {
+	p := &httpStepParams{}
+	structured := false
+	for _, key := range httpParamKeys {
+		if _, ok := params[key]; ok {
+			structured = true
+			break
+		}
+	}
+
+	if structured {
+		if err := decodeParams(params, p); err != nil {
+			return nil, err
+		}
+	} else if len(params) > 0 {
+		p.Body = params
+	}
+
+	if method != "" {
+		p.Method = method
+	}
+	if path != "" && p.URL == "" && p.Path == "" {
+		p.Path = path
+	}
+	if p.Method == "" {
+		p.Method = "GET"
+	}
+	return p, nil
+}
+
+// buildRequest turns http step params into a shared.HTTPRequest.
+func (m *WorkflowManager) buildRequest(p *httpStepParams) (shared.HTTPRequest, error) {
+	target := p.URL
+	if target == "" {
+		target = p.Path
+	}
+	if target == "" {
+		return shared.HTTPRequest{}, fmt.Errorf("http step requires 'url' or 'path'")
+	}
+	target = m.env.ResolveURL(target)
+	if placeholder := shared.PlaceholderPattern.FindString(target); placeholder != "" {
+		return shared.HTTPRequest{}, fmt.Errorf("%w %s in URL '%s'", errUnresolved, placeholder, target)
+	}
+
+	if len(p.Query) > 0 {
+		u, err := url.Parse(target)
+		if err != nil {
+			return shared.HTTPRequest{}, fmt.Errorf("invalid URL '%s': %w", target, err)
+		}
+		q := u.Query()
+		for k, v := range p.Query {
+			q.Set(k, v)
+		}
+		u.RawQuery = q.Encode()
+		target = u.String()
+	}
+
+	return shared.HTTPRequest{
+		Method:  strings.ToUpper(p.Method),
+		URL:     target,
+		Headers: p.Headers,
+		Body:    p.Body,
+		Timeout: p.Timeout,
+	}, nil
+}
+
+// CheckResponse applies the step expectation, or the default "< 400" rule.
+func CheckResponse(expect *shared.TestExpectation, resp *shared.HTTPResponse) error {
+	if expect == nil {
+		if resp.StatusCode >= 400 {
+			return fmt.Errorf("HTTP %d", resp.StatusCode)
+		}
+		return nil
 	}
+	if failures := shared.ValidateExpectations(*expect, resp, resp.Duration.Milliseconds()); len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	return nil
+}
 
-	url := m.env.ResolveURL(path)
+func (m *WorkflowManager) executeHTTP(stepID string, params map[string]interface{}, method, path string) error {
+	p, err := toHTTPParams(params, method, path)
+	if err != nil {
+		return err
+	}
 
-	req := shared.HTTPRequest{
-		Method: method,
-		URL:    url,
-		// In a real implementation, we'd pull from step.Params
+	req, err := m.buildRequest(p)
+	if err != nil {
+		return err
 	}
 
 	resp, err := m.httpTool.Run(req)
 	if err != nil {
-		return StepResult{
-			StepID:      step.ID,
-			Description: step.Description,
-			Status:      "fail",
-			Message:     err.Error(),
+		return err
+	}
+	m.env.RecordResponse(stepID, resp)
+
+	if err := CheckResponse(p.Expect, resp); err != nil {
+		return err
+	}
+
+	// 3. Post-execution: write extracted values into the shared state
+	return m.extractInto(resp, p.Extract)
+}
+
+func (m *WorkflowManager) executeExtract(params map[string]interface{}) error {
+	var p extractStepParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+	if len(p.Values) == 0 {
+		return fmt.Errorf("extract step requires 'values' (var -> source)")
+	}
+
+	resp, err := m.sourceResponse(p.From)
+	if err != nil {
+		return err
+	}
+	return m.extractInto(resp, p.Values)
+}
+
+func (m *WorkflowManager) executeAssert(params map[string]interface{}) error {
+	var p assertStepParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+
+	var failures []string
+	for name, expected := range p.Equals {
+		actual, ok := m.env.State[name]
+		if !ok {
+			failures = append(failures, fmt.Sprintf("variable '%s' is not set", name))
+		} else if shared.Stringify(actual) != shared.Stringify(expected) {
+			failures = append(failures, fmt.Sprintf("variable '%s': expected %v, got %v", name, expected, actual))
+		}
+	}
+
+	if hasResponseExpectations(p.TestExpectation) {
+		resp, err := m.sourceResponse(p.From)
+		if err != nil {
+			return err
+		}
+		failures = append(failures, shared.ValidateExpectations(p.TestExpectation, resp, resp.Duration.Milliseconds())...)
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// hasResponseExpectations reports whether any response check is configured.
+func hasResponseExpectations(e shared.TestExpectation) bool {
+	return e.StatusCode != 0 || e.StatusCodeRange != nil || len(e.BodyContains) > 0 ||
+		len(e.BodyNotContains) > 0 || len(e.HeaderContains) > 0 || e.MaxDurationMs > 0 || len(e.JSONPath) > 0
+}
+
+func (m *WorkflowManager) executeWait(stepID string, params map[string]interface{}) error {
+	var p waitStepParams
+	if err := decodeParams(params, &p); err != nil {
+		return err
+	}
+
+	if p.Request == nil {
+		delay := time.Duration(p.Ms)*time.Millisecond + time.Duration(p.Seconds*float64(time.Second))
+		if delay <= 0 {
+			return fmt.Errorf("wait step requires 'seconds', 'ms', or a 'request' to poll")
+		}
+		time.Sleep(delay)
+		return nil
+	}
+
+	interval := defaultPollInterval
+	if p.IntervalMs > 0 {
+		interval = time.Duration(p.IntervalMs) * time.Millisecond
+	}
+	timeout := defaultPollTimeout
+	if p.TimeoutSeconds > 0 {
+		timeout = time.Duration(p.TimeoutSeconds) * time.Second
+	}
+
+	if p.Request.Method == "" {
+		p.Request.Method = "GET"
+	}
+	req, err := m.buildRequest(p.Request)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	attempts := 0
+	var lastErr error
+	for {
+		attempts++
+		resp, err := m.httpTool.Run(req)
+		if err == nil {
+			m.env.RecordResponse(stepID, resp)
+			if lastErr = CheckResponse(p.Until, resp); lastErr == nil {
+				return m.extractInto(resp, p.Request.Extract)
+			}
+		} else {
+			lastErr = err
 		}
+
+		if time.Now().Add(interval).After(deadline) {
+			return fmt.Errorf("condition not met after %d attempts (%s): %v", attempts, timeout, lastErr)
+		}
+		time.Sleep(interval)
 	}
+}
 
-	if resp.StatusCode >= 400 {
-		return StepResult{
-			StepID:      step.ID,
-			Description: step.Description,
-			Status:      "fail",
-			Message:     fmt.Sprintf("HTTP %d", resp.StatusCode),
+// sourceResponse returns the response of the given step, or the last one.
+func (m *WorkflowManager) sourceResponse(stepID string) (*shared.HTTPResponse, error) {
+	if stepID != "" {
+		resp, ok := m.env.Responses[stepID]
+		if !ok {
+			return nil, fmt.Errorf("no response recorded for step '%s'", stepID)
 		}
+		return resp, nil
+	}
+	if m.env.Last == nil {
+		return nil, fmt.Errorf("no HTTP response available - run an http step first")
 	}
+	return m.env.Last, nil
+}
 
-	// 3. Post-execution: extract variables if defined in Params
-	// (Logic for shared state management)
+// extractInto evaluates each source against resp and stores it in the state.
+func (m *WorkflowManager) extractInto(resp *shared.HTTPResponse, values map[string]string) error {
+	for name, source := range values {
+		val, err := ExtractValue(resp, source)
+		if err != nil {
+			return fmt.Errorf("extract '%s': %w", name, err)
+		}
+		m.env.Set(name, val)
+	}
+	return nil
+}
 
-	return StepResult{
-		StepID:      step.ID,
-		Description: step.Description,
-		Status:      "pass",
+// ExtractValue reads a value from a response. Sources:
+//
+//	$.data.id       JSONPath into the body
+//	header:X-Id     response header
+//	cookie:sid      cookie from the response's cookie session
+//	status          status code
+//	body            raw body
+func ExtractValue(resp *shared.HTTPResponse, source string) (interface{}, error) {
+	switch {
+	case strings.HasPrefix(source, "$"):
+		return shared.ExtractJSONPathValue(resp.Body, source)
+	case strings.HasPrefix(strings.ToLower(source), "header:"):
+		name := strings.TrimSpace(source[len("header:"):])
+		for k, v := range resp.Headers {
+			if strings.EqualFold(k, name) {
+				return v, nil
+			}
+		}
+		return nil, fmt.Errorf("header '%s' not found", name)
+	case strings.HasPrefix(strings.ToLower(source), "cookie:"):
+		name := strings.TrimSpace(source[len("cookie:"):])
+		if value, ok := resp.Cookie(name); ok {
+			return value, nil
+		}
+		return nil, fmt.Errorf("cookie '%s' not found", name)
+	case source == "status":
+		return resp.StatusCode, nil
+	case source == "body":
+		return resp.Body, nil
+	default:
+		return nil, fmt.Errorf("unknown source '%s' (use $.path, header:Name, cookie:Name, status or body)", source)
 	}
 }
@@ -0,0 +1,206 @@
+package integration_orchestrator
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+)
+
+// testAPI serves a small users API. GET /users/42 returns 404 until it has
+// been polled twice, so wait steps have something to wait for.
+type testAPI struct {
+	server  *httptest.Server
+	polls   int32
+	deleted int32
+}
+
+func newTestAPI(t *testing.T) *testAPI {
+	t.Helper()
+	api := &testAPI{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"token": "abc"})
+	})
+	mux.HandleFunc("POST /users", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Location", "/users/42")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": 42, "name": "Test User"})
+	})
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "42" || atomic.AddInt32(&api.polls, 1) <= 2 {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": 42, "name": "Test User"})
+	})
+	mux.HandleFunc("DELETE /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&api.deleted, 1)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	api.server = httptest.NewServer(mux)
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+// runWorkflow decodes a JSON workflow like the tool does and runs it.
+func runWorkflow(t *testing.T, baseURL, workflow string) (OrchestrateResult, *Environment) {
+	t.Helper()
+	var params OrchestrateParams
+	if err := json.Unmarshal([]byte(workflow), &params); err != nil {
+		t.Fatalf("invalid workflow: %v", err)
+	}
+	env := NewEnvironment(baseURL)
+	for k, v := range params.Variables {
+		env.Set(k, v)
+	}
+	m := &WorkflowManager{httpTool: shared.NewHTTPTool(nil, nil), env: env}
+	return m.Run(params.Workflow, params.Teardown, params.StopOnFailure), env
+}
+
+func statuses(r OrchestrateResult) string {
+	var parts []string
+	for _, s := range r.StepResults {
+		label := s.StepID
+		if s.Phase != "" {
+			label = s.Phase + ":" + label
+		}
+		parts = append(parts, label+"="+s.Status)
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestWorkflow_FullChain(t *testing.T) {
+	api := newTestAPI(t)
+
+	result, env := runWorkflow(t, api.server.URL, `{
+  "workflow": [
+    {"id": "login", "action": "http", "params": {"method": "POST", "path": "/login", "body": {"user": "{{user}}"}, "extract": {"token": "$.token"}}},
+    {"id": "create", "action": "POST /users", "params": {"headers": {"Authorization": "Bearer {{token}}"}, "body": {"name": "Test User"}, "expect": {"status_code": 201}, "extract": {"id": "$.id"}}},
+    {"id": "ready", "action": "wait", "params": {"request": {"path": "/users/{{id}}", "extract": {"name": "$.name"}}, "until": {"status_code": 200}, "interval_ms": 10, "timeout_seconds": 5}},
+    {"id": "location", "action": "extract", "params": {"from": "create", "values": {"loc": "header:location", "code": "status"}}},
+    {"id": "check", "action": "assert", "params": {"from": "ready", "body_contains": ["Test User"], "equals": {"code": 201, "name": "Test User", "id": 42}}},
+    {"id": "pause", "action": "wait", "params": {"ms": 1}}
+  ],
+  "teardown": [
+    {"id": "cleanup", "action": "DELETE /users/{{id}}"}
+  ],
+  "variables": {"user": "alice"},
+  "stop_on_failure": true
+}`)
+
+	if result.Failed != 0 || result.Completed != 7 {
+		t.Fatalf("expected 7 completed, got %d/%d: %s\n%+v", result.Completed, result.Failed, statuses(result), result.StepResults)
+	}
+	if got := env.Get("loc"); got != "/users/42" {
+		t.Errorf("loc = %v", got)
+	}
+	if polls := atomic.LoadInt32(&api.polls); polls != 3 {
+		t.Errorf("expected 3 polls, got %d", polls)
+	}
+	if atomic.LoadInt32(&api.deleted) != 1 {
+		t.Error("expected teardown to delete the user")
+	}
+}
+
+func TestWorkflow_TeardownAfterFailure(t *testing.T) {
+	api := newTestAPI(t)
+
+	result, _ := runWorkflow(t, api.server.URL, `{
+  "workflow": [
+    {"id": "create", "action": "POST /users", "params": {"name": "Test User"}},
+    {"id": "ready", "action": "GET /users/{{id}}"}
+  ],
+  "teardown": [
+    {"id": "cleanup", "action": "DELETE /users/{{id}}"},
+    {"id": "cleanup-fixture", "action": "DELETE /users/7"}
+  ],
+  "stop_on_failure": true
+}`)
+
+	want := "create=fail ready=skipped teardown:cleanup=skipped teardown:cleanup-fixture=pass"
+	if got := statuses(result); got != want {
+		t.Errorf("statuses = %s, want %s", got, want)
+	}
+	if result.Failed != 1 || result.Completed != 1 {
+		t.Errorf("expected 1 failed and 1 completed, got %d/%d", result.Failed, result.Completed)
+	}
+	if msg := result.StepResults[2].Message; !strings.Contains(msg, "unresolved placeholder {{id}}") {
+		t.Errorf("cleanup message = %q", msg)
+	}
+	if atomic.LoadInt32(&api.deleted) != 1 {
+		t.Errorf("expected only the resolvable teardown request to be sent, got %d", api.deleted)
+	}
+}
+
+func TestWorkflow_StepFailures(t *testing.T) {
+	api := newTestAPI(t)
+
+	cases := []struct {
+		name string
+		step string
+		want string
+	}{
+		{"unresolved placeholder", `{"id": "s", "action": "GET /users/{{missing}}"}`, "unresolved placeholder {{missing}}"},
+		{"unset variable", `{"id": "s", "action": "assert", "params": {"equals": {"token": "abc"}}}`, "variable 'token' is not set"},
+		{"unknown source step", `{"id": "s", "action": "extract", "params": {"from": "nope", "values": {"x": "status"}}}`, "no response recorded for step 'nope'"},
+		{"extract without values", `{"id": "s", "action": "extract", "params": {}}`, "requires 'values'"},
+		{"wait without duration", `{"id": "s", "action": "wait", "params": {}}`, "requires 'seconds', 'ms'"},
+		{"poll timeout", `{"id": "s", "action": "wait", "params": {"request": {"path": "/users/1"}, "until": {"status_code": 200}, "interval_ms": 10, "timeout_seconds": 1}}`, "condition not met after"},
+		{"unknown action", `{"id": "s", "action": "launch"}`, "unsupported action 'launch'"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, _ := runWorkflow(t, api.server.URL, `{"workflow": [`+tc.step+`]}`)
+			if result.Failed != 1 || !strings.Contains(result.StepResults[0].Message, tc.want) {
+				t.Errorf("got %s %q, want a failure containing %q", statuses(result), result.StepResults[0].Message, tc.want)
+			}
+		})
+	}
+}
+
+func TestExtractValue(t *testing.T) {
+	resp := &shared.HTTPResponse{
+		StatusCode: 201,
+		Headers:    map[string]string{"Location": "/users/42"},
+		Body:       `{"data": {"id": 42, "tags": ["a"]}}`,
+	}
+
+	cases := []struct {
+		source string
+		want   interface{}
+		err    string
+	}{
+		{source: "$.data.id", want: float64(42)},
+		{source: "$.data.tags[0]", want: "a"},
+		{source: "header:location", want: "/users/42"},
+		{source: "status", want: 201},
+		{source: "body", want: resp.Body},
+		{source: "header:X-Id", err: "header 'X-Id' not found"},
+		{source: "cookie:sid", err: "cookie 'sid' not found"},
+		{source: "$.data.missing", err: "not found"},
+		{source: "xpath://id", err: "unknown source"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.source, func(t *testing.T) {
+			got, err := ExtractValue(resp, tc.source)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("err = %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil || got != tc.want {
+				t.Errorf("got %#v, %v; want %#v", got, err, tc.want)
+			}
+		})
+	}
+}
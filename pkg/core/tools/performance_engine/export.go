@@ -0,0 +1,227 @@
+package performance_engine
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxReportWindows caps the rows of the Markdown time-series table. Longer
+// runs are downsampled; the JSON and CSV exports always keep every second.
+const maxReportWindows = 60
+
+// exportRun is the JSON export layout. Durations are milliseconds so the
+// file can be charted without conversion.
+type exportRun struct {
+	Mode          string           `json:"mode"`
+	BaseURL       string           `json:"base_url"`
+	StartedAt     time.Time        `json:"started_at"`
+	DurationMs    float64          `json:"duration_ms"`
+	Overall       exportMetrics    `json:"overall"`
+	Stages        []exportStage    `json:"stages"`
+	BreachedStage string           `json:"breached_stage,omitempty"`
+	Verdict       *Verdict         `json:"verdict,omitempty"`
+	Endpoints     []exportEndpoint `json:"endpoints"`
+	StatusCodes   map[string]int   `json:"status_codes"`
+	TimeSeries    []exportWindow   `json:"time_series"`
+}
+
+type exportMetrics struct {
+	Total       int     `json:"total"`
+	Success     int     `json:"success"`
+	Fail        int     `json:"fail"`
+	SuccessRate float64 `json:"success_rate"`
+	RPS         float64 `json:"rps"`
+	TargetRPS   float64 `json:"target_rps,omitempty"`
+	Dropped     int     `json:"dropped,omitempty"`
+	AvgMs       float64 `json:"avg_ms"`
+	MinMs       float64 `json:"min_ms"`
+	P50Ms       float64 `json:"p50_ms"`
+	P95Ms       float64 `json:"p95_ms"`
+	P99Ms       float64 `json:"p99_ms"`
+	MaxMs       float64 `json:"max_ms"`
+	QueueAvgMs  float64 `json:"queue_avg_ms,omitempty"`
+	QueueP95Ms  float64 `json:"queue_p95_ms,omitempty"`
+	QueueMaxMs  float64 `json:"queue_max_ms,omitempty"`
+}
+
+type exportStage struct {
+	Stage    Stage         `json:"stage"`
+	Metrics  exportMetrics `json:"metrics"`
+	Breached bool          `json:"breached"`
+	Reasons  []string      `json:"reasons,omitempty"`
+}
+
+type exportEndpoint struct {
+	Endpoint    string         `json:"endpoint"`
+	Total       int            `json:"total"`
+	Fail        int            `json:"fail"`
+	ErrorRate   float64        `json:"error_rate"`
+	AvgMs       float64        `json:"avg_ms"`
+	P50Ms       float64        `json:"p50_ms"`
+	P95Ms       float64        `json:"p95_ms"`
+	P99Ms       float64        `json:"p99_ms"`
+	MinMs       float64        `json:"min_ms"`
+	MaxMs       float64        `json:"max_ms"`
+	StatusCodes map[string]int `json:"status_codes"`
+}
+
+type exportWindow struct {
+	Second    int     `json:"second"`
+	Requests  int     `json:"requests"`
+	Errors    int     `json:"errors"`
+	RPS       float64 `json:"rps"`
+	ErrorRate float64 `json:"error_rate"`
+	P50Ms     float64 `json:"p50_ms"`
+	P95Ms     float64 `json:"p95_ms"`
+	P99Ms     float64 `json:"p99_ms"`
+}
+
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func toExportMetrics(m ExecutionMetrics) exportMetrics {
+	return exportMetrics{
+		Total: m.Total, Success: m.Success, Fail: m.Fail, SuccessRate: m.SuccessRate,
+		RPS: m.RPS, TargetRPS: m.TargetRPS, Dropped: m.Dropped,
+		AvgMs: ms(m.AvgLatency), MinMs: ms(m.Min), P50Ms: ms(m.P50), P95Ms: ms(m.P95), P99Ms: ms(m.P99), MaxMs: ms(m.Max),
+		QueueAvgMs: ms(m.QueueAvg), QueueP95Ms: ms(m.QueueP95), QueueMaxMs: ms(m.QueueMax),
+	}
+}
+
+func toExportWindow(w TimeWindow) exportWindow {
+	return exportWindow{
+		Second: w.Second, Requests: w.Requests, Errors: w.Errors, RPS: w.RPS, ErrorRate: w.ErrorRate,
+		P50Ms: ms(w.P50), P95Ms: ms(w.P95), P99Ms: ms(w.P99),
+	}
+}
+
+// statusKeys renders status codes as JSON object keys ("error" for 0).
+func statusKeys(counts map[int]int) map[string]int {
+	out := make(map[string]int, len(counts))
+	for code, n := range counts {
+		key := strconv.Itoa(code)
+		if code == 0 {
+			key = "error"
+		}
+		out[key] = n
+	}
+	return out
+}
+
+// exportJSON renders the full run, including the per-second time series.
+func exportJSON(params PerformanceParams, result RunResult, verdict *Verdict, startTime time.Time, duration time.Duration) ([]byte, error) {
+	run := exportRun{
+		Mode:          params.Mode,
+		BaseURL:       params.BaseURL,
+		StartedAt:     startTime,
+		DurationMs:    ms(duration),
+		Overall:       toExportMetrics(result.Overall),
+		BreachedStage: result.BreachedStage,
+		Verdict:       verdict,
+		StatusCodes:   statusKeys(result.Overall.StatusCodes),
+	}
+	for _, sr := range result.Stages {
+		run.Stages = append(run.Stages, exportStage{Stage: sr.Stage, Metrics: toExportMetrics(sr.Metrics), Breached: sr.Breached, Reasons: sr.Reasons})
+	}
+	for _, ep := range result.Overall.Endpoints {
+		run.Endpoints = append(run.Endpoints, exportEndpoint{
+			Endpoint: ep.Endpoint, Total: ep.Total, Fail: ep.Fail, ErrorRate: ep.ErrorRate,
+			AvgMs: ms(ep.AvgLatency), P50Ms: ms(ep.P50), P95Ms: ms(ep.P95), P99Ms: ms(ep.P99), MinMs: ms(ep.Min), MaxMs: ms(ep.Max),
+			StatusCodes: statusKeys(ep.StatusCodes),
+		})
+	}
+	for _, w := range result.Overall.TimeSeries {
+		run.TimeSeries = append(run.TimeSeries, toExportWindow(w))
+	}
+	return json.MarshalIndent(run, "", "  ")
+}
+
+// exportCSV renders the per-second time series as CSV.
+func exportCSV(series []TimeWindow) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"second", "requests", "errors", "rps", "error_rate", "p50_ms", "p95_ms", "p99_ms"})
+	for _, tw := range series {
+		_ = w.Write([]string{
+			strconv.Itoa(tw.Second),
+			strconv.Itoa(tw.Requests),
+			strconv.Itoa(tw.Errors),
+			strconv.FormatFloat(tw.RPS, 'f', 2, 64),
+			strconv.FormatFloat(tw.ErrorRate, 'f', 2, 64),
+			strconv.FormatFloat(ms(tw.P50), 'f', 3, 64),
+			strconv.FormatFloat(ms(tw.P95), 'f', 3, 64),
+			strconv.FormatFloat(ms(tw.P99), 'f', 3, 64),
+		})
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// writeExports writes <report>.json and <report>_timeseries.csv next to the
+// Markdown report and returns their paths.
+func writeExports(reportPath string, params PerformanceParams, result RunResult, verdict *Verdict, startTime time.Time, duration time.Duration) ([]string, error) {
+	base := strings.TrimSuffix(reportPath, ".md")
+
+	jsonData, err := exportJSON(params, result, verdict, startTime, duration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode JSON export: %w", err)
+	}
+	csvData, err := exportCSV(result.Overall.TimeSeries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode CSV export: %w", err)
+	}
+
+	jsonPath, csvPath := base+".json", base+"_timeseries.csv"
+	if err := os.WriteFile(jsonPath, jsonData, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write JSON export: %w", err)
+	}
+	if err := os.WriteFile(csvPath, csvData, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write CSV export: %w", err)
+	}
+	return []string{jsonPath, csvPath}, nil
+}
+
+// downsample merges consecutive windows so the series has at most max rows.
+// Request and error counts are summed; RPS is averaged; percentiles take the
+// worst window, which keeps spikes visible.
+func downsample(series []TimeWindow, max int) ([]TimeWindow, int) {
+	if len(series) <= max {
+		return series, 1
+	}
+	step := (len(series) + max - 1) / max
+
+	var out []TimeWindow
+	for i := 0; i < len(series); i += step {
+		end := i + step
+		if end > len(series) {
+			end = len(series)
+		}
+		merged := TimeWindow{Second: series[i].Second}
+		for _, w := range series[i:end] {
+			merged.Requests += w.Requests
+			merged.Errors += w.Errors
+			if w.P50 > merged.P50 {
+				merged.P50 = w.P50
+			}
+			if w.P95 > merged.P95 {
+				merged.P95 = w.P95
+			}
+			if w.P99 > merged.P99 {
+				merged.P99 = w.P99
+			}
+		}
+		merged.RPS = float64(merged.Requests) / float64(end-i)
+		if merged.Requests > 0 {
+			merged.ErrorRate = float64(merged.Errors) / float64(merged.Requests) * 100
+		}
+		out = append(out, merged)
+	}
+	return out, step
+}
@@ -0,0 +1,152 @@
+package performance_engine
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testRunResult() RunResult {
+	overall := ExecutionMetrics{
+		Total: 10, Success: 9, Fail: 1, SuccessRate: 90, RPS: 5,
+		AvgLatency: 12 * time.Millisecond, Min: 2 * time.Millisecond, Max: 80 * time.Millisecond,
+		P50: 10 * time.Millisecond, P95: 40 * time.Millisecond, P99: 75500 * time.Microsecond,
+		StatusCodes: map[int]int{200: 9, 0: 1},
+		Endpoints: []EndpointMetrics{{
+			Endpoint: "GET /users", Total: 10, Fail: 1, ErrorRate: 10,
+			AvgLatency: 12 * time.Millisecond, Min: 2 * time.Millisecond, Max: 80 * time.Millisecond,
+			P50: 10 * time.Millisecond, P95: 40 * time.Millisecond, P99: 75 * time.Millisecond,
+			StatusCodes: map[int]int{200: 9, 0: 1},
+		}},
+		TimeSeries: []TimeWindow{
+			{Second: 0, Requests: 6, Errors: 1, RPS: 6, ErrorRate: 16.666, P50: 9 * time.Millisecond, P95: 30 * time.Millisecond, P99: 1500 * time.Microsecond},
+			{Second: 1, Requests: 4, RPS: 4, P50: 11 * time.Millisecond},
+		},
+	}
+	stage := StageResult{Stage: Stage{Name: "step 1", DurationSec: 2, Concurrency: 3}, Metrics: overall, Breached: true, Reasons: []string{"error rate 10.00% > 5.00%"}}
+	return RunResult{Overall: overall, Stages: []StageResult{stage}, BreachedStage: "step 1", Stopped: true}
+}
+
+func TestExportJSON(t *testing.T) {
+	started := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	verdict := &Verdict{Passed: false, Results: []ThresholdResult{{Scope: "overall", Expr: "p95<30ms", Actual: "40ms"}}}
+	data, err := exportJSON(PerformanceParams{Mode: "stress", BaseURL: "http://api.test"}, testRunResult(), verdict, started, 2500*time.Millisecond)
+	if err != nil {
+		t.Fatalf("exportJSON: %v", err)
+	}
+
+	var run map[string]interface{}
+	if err := json.Unmarshal(data, &run); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	get := func(path string) interface{} {
+		var v interface{} = run
+		for _, key := range strings.Split(path, ".") {
+			switch node := v.(type) {
+			case map[string]interface{}:
+				v = node[key]
+			case []interface{}:
+				v = node[0]
+				v = v.(map[string]interface{})[key]
+			default:
+				return nil
+			}
+		}
+		return v
+	}
+
+	cases := map[string]interface{}{
+		"mode":                   "stress",
+		"base_url":               "http://api.test",
+		"started_at":             "2026-03-01T12:00:00Z",
+		"duration_ms":            2500.0,
+		"breached_stage":         "step 1",
+		"overall.total":          10.0,
+		"overall.success_rate":   90.0,
+		"overall.min_ms":         2.0,
+		"overall.p99_ms":         75.5,
+		"overall.max_ms":         80.0,
+		"overall.target_rps":     nil, // omitted for closed-model runs
+		"overall.queue_max_ms":   nil,
+		"status_codes.200":       9.0,
+		"status_codes.error":     1.0,
+		"stages.stage.name":      "step 1",
+		"stages.breached":        true,
+		"stages.reasons":         []interface{}{"error rate 10.00% > 5.00%"},
+		"stages.metrics.p95_ms":  40.0,
+		"endpoints.endpoint":     "GET /users",
+		"endpoints.error_rate":   10.0,
+		"endpoints.min_ms":       2.0,
+		"endpoints.status_codes": map[string]interface{}{"200": 9.0, "error": 1.0},
+		"time_series.second":     0.0,
+		"time_series.p99_ms":     1.5,
+		"verdict.passed":         false,
+		"verdict.results.expr":   "p95<30ms",
+	}
+	for path, want := range cases {
+		if got := get(path); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s = %#v, want %#v", path, got, want)
+		}
+	}
+	if series := run["time_series"].([]interface{}); len(series) != 2 {
+		t.Errorf("time_series has %d windows, want 2", len(series))
+	}
+}
+
+func TestExportCSVAndWriteExports(t *testing.T) {
+	result := testRunResult()
+	data, err := exportCSV(result.Overall.TimeSeries)
+	if err != nil {
+		t.Fatalf("exportCSV: %v", err)
+	}
+	want := "second,requests,errors,rps,error_rate,p50_ms,p95_ms,p99_ms\n" +
+		"0,6,1,6.00,16.67,9.000,30.000,1.500\n" +
+		"1,4,0,4.00,0.00,11.000,0.000,0.000\n"
+	if string(data) != want {
+		t.Errorf("CSV:\n%s\nwant:\n%s", data, want)
+	}
+
+	report := filepath.Join(t.TempDir(), "performance_report_api.md")
+	paths, err := writeExports(report, PerformanceParams{Mode: "load"}, result, nil, time.Now(), time.Second)
+	if err != nil {
+		t.Fatalf("writeExports: %v", err)
+	}
+	base := strings.TrimSuffix(report, ".md")
+	if !reflect.DeepEqual(paths, []string{base + ".json", base + "_timeseries.csv"}) {
+		t.Fatalf("paths = %v", paths)
+	}
+	if written, err := os.ReadFile(paths[1]); err != nil || string(written) != want {
+		t.Errorf("CSV file = %q, %v", written, err)
+	}
+	var run exportRun
+	if written, err := os.ReadFile(paths[0]); err != nil || json.Unmarshal(written, &run) != nil || run.Verdict != nil || run.Overall.Total != 10 {
+		t.Errorf("JSON file: %+v, %v", run, err)
+	}
+}
+
+func TestDownsample(t *testing.T) {
+	series := make([]TimeWindow, 130)
+	for i := range series {
+		series[i] = TimeWindow{Second: i, Requests: 10, RPS: 10, P95: time.Duration(i) * time.Millisecond}
+	}
+	series[4].Errors = 3
+
+	out, step := downsample(series, maxReportWindows)
+	if step != 3 || len(out) != 44 {
+		t.Fatalf("step %d, %d rows", step, len(out))
+	}
+	if w := out[1]; w.Second != 3 || w.Requests != 30 || w.Errors != 3 || w.RPS != 10 || w.ErrorRate != 10 || w.P95 != 5*time.Millisecond {
+		t.Errorf("row 1 = %+v", w)
+	}
+	if last := out[43]; last.Second != 129 || last.Requests != 10 || last.RPS != 10 {
+		t.Errorf("last row = %+v", last)
+	}
+
+	if short, step := downsample(series[:60], maxReportWindows); step != 1 || len(short) != 60 {
+		t.Errorf("60 windows: step %d, %d rows", step, len(short))
+	}
+}
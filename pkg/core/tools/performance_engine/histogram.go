@@ -0,0 +1,134 @@
+package performance_engine
+
+import (
+	"math/bits"
+	"sort"
+	"time"
+)
+
+// histogramSubBuckets is the number of linear sub-buckets per power of two.
+// 64 sub-buckets bound the relative error of any recorded value to ~1.6%.
+const histogramSubBuckets = 64
+
+// Histogram is a streaming, HDR-style latency histogram. Values are stored
+// in microseconds in log-linear buckets: exact below 128µs, then 64 linear
+// buckets per power of two. Memory depends on the spread of values, not on
+// how many were recorded, so it stays small during long soak runs.
+type Histogram struct {
+	counts map[int]uint64
+	total  uint64
+	sum    time.Duration
+	min    time.Duration
+	max    time.Duration
+}
+
+// NewHistogram creates an empty histogram.
+func NewHistogram() *Histogram {
+	return &Histogram{counts: make(map[int]uint64)}
+}
+
+// Record adds a value to the histogram.
+func (h *Histogram) Record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	h.counts[bucketIndex(d)]++
+	if h.total == 0 || d < h.min {
+		h.min = d
+	}
+	if d > h.max {
+		h.max = d
+	}
+	h.total++
+	h.sum += d
+}
+
+// Merge adds every value recorded in other.
+func (h *Histogram) Merge(other *Histogram) {
+	if other == nil || other.total == 0 {
+		return
+	}
+	for idx, n := range other.counts {
+		h.counts[idx] += n
+	}
+	if h.total == 0 || other.min < h.min {
+		h.min = other.min
+	}
+	if other.max > h.max {
+		h.max = other.max
+	}
+	h.total += other.total
+	h.sum += other.sum
+}
+
+// Count returns the number of recorded values.
+func (h *Histogram) Count() int { return int(h.total) }
+
+// Min returns the smallest recorded value (exact).
+func (h *Histogram) Min() time.Duration { return h.min }
+
+// Max returns the largest recorded value (exact).
+func (h *Histogram) Max() time.Duration { return h.max }
+
+// Mean returns the average of the recorded values (exact).
+func (h *Histogram) Mean() time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+	return h.sum / time.Duration(h.total)
+}
+
+// Percentile returns the value at quantile q (0..1), accurate to the
+// bucket width and clamped to the exact min and max.
+func (h *Histogram) Percentile(q float64) time.Duration {
+	if h.total == 0 {
+		return 0
+	}
+
+	indices := make([]int, 0, len(h.counts))
+	for idx := range h.counts {
+		indices = append(indices, idx)
+	}
+	sort.Ints(indices)
+
+	rank := uint64(q*float64(h.total-1)) + 1
+	var seen uint64
+	for _, idx := range indices {
+		seen += h.counts[idx]
+		if seen >= rank {
+			v := bucketValue(idx)
+			if v < h.min {
+				return h.min
+			}
+			if v > h.max {
+				return h.max
+			}
+			return v
+		}
+	}
+	return h.max
+}
+
+// bucketIndex maps a duration to its log-linear bucket.
+func bucketIndex(d time.Duration) int {
+	us := uint64(d / time.Microsecond)
+	if us < 2*histogramSubBuckets {
+		return int(us)
+	}
+	shift := bits.Len64(us) - 7 // us>>shift lands in [64, 128)
+	sub := us >> uint(shift)
+	return 2*histogramSubBuckets + (shift-1)*histogramSubBuckets + int(sub) - histogramSubBuckets
+}
+
+// bucketValue returns the midpoint of a bucket.
+func bucketValue(idx int) time.Duration {
+	if idx < 2*histogramSubBuckets {
+		return time.Duration(idx) * time.Microsecond
+	}
+	offset := idx - 2*histogramSubBuckets
+	shift := offset/histogramSubBuckets + 1
+	sub := uint64(offset%histogramSubBuckets + histogramSubBuckets)
+	low := sub << uint(shift)
+	width := uint64(1) << uint(shift)
+	return time.Duration(low+width/2) * time.Microsecond
+}
@@ -0,0 +1,48 @@
+package performance_engine
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestHistogram_Percentiles(t *testing.T) {
+	h := NewHistogram()
+	for i := 1; i <= 10000; i++ {
+		h.Record(time.Duration(i) * 100 * time.Microsecond) // 0.1ms .. 1s
+	}
+
+	if h.Count() != 10000 {
+		t.Fatalf("expected 10000 values, got %d", h.Count())
+	}
+	if h.Min() != 100*time.Microsecond || h.Max() != time.Second {
+		t.Errorf("unexpected min/max: %v / %v", h.Min(), h.Max())
+	}
+
+	cases := map[float64]time.Duration{
+		0.50: 500 * time.Millisecond,
+		0.95: 950 * time.Millisecond,
+		0.99: 990 * time.Millisecond,
+	}
+	for q, want := range cases {
+		got := h.Percentile(q)
+		if relErr := math.Abs(float64(got-want)) / float64(want); relErr > 0.02 {
+			t.Errorf("p%.0f: expected ~%v, got %v (error %.2f%%)", q*100, want, got, relErr*100)
+		}
+	}
+}
+
+func TestHistogram_Merge(t *testing.T) {
+	a, b := NewHistogram(), NewHistogram()
+	a.Record(10 * time.Millisecond)
+	b.Record(30 * time.Millisecond)
+	b.Record(50 * time.Millisecond)
+
+	a.Merge(b)
+	if a.Count() != 3 || a.Min() != 10*time.Millisecond || a.Max() != 50*time.Millisecond {
+		t.Errorf("unexpected merge result: count=%d min=%v max=%v", a.Count(), a.Min(), a.Max())
+	}
+	if a.Mean() != 30*time.Millisecond {
+		t.Errorf("expected mean 30ms, got %v", a.Mean())
+	}
+}
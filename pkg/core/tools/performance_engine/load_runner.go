@@ -1,19 +1,41 @@
 package performance_engine
 
 import (
-	"strings"
 	"sync"
 	"time"
 
 	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
 )
 
+// controlInterval is how often the runner adjusts the number of active
+// virtual users while ramping.
+const controlInterval = 100 * time.Millisecond
+
+// defaultMaxVUs caps concurrent requests in arrival-rate mode.
+const defaultMaxVUs = 100
+
 // LoadTestRunner executes the actual load simulation.
 type LoadTestRunner struct {
 	httpTool *shared.HTTPTool
 	params   PerformanceParams
 }
 
+// RunResult is the outcome of a profile run.
+type RunResult struct {
+	Overall       ExecutionMetrics `json:"overall"`
+	Stages        []StageResult    `json:"stages"`
+	BreachedStage string           `json:"breached_stage,omitempty"` // first stage that breached the limits
+	Stopped       bool             `json:"stopped"`                  // run ended early because of a breach
+}
+
+// StageResult holds the metrics observed during one stage.
+type StageResult struct {
+	Stage    Stage            `json:"stage"`
+	Metrics  ExecutionMetrics `json:"metrics"`
+	Breached bool             `json:"breached"`
+	Reasons  []string         `json:"reasons,omitempty"`
+}
+
 // NewLoadTestRunner creates a new load test runner.
 func NewLoadTestRunner(httpTool *shared.HTTPTool, params PerformanceParams) *LoadTestRunner {
 	if params.Concurrency <= 0 {
@@ -28,70 +50,325 @@ func NewLoadTestRunner(httpTool *shared.HTTPTool, params PerformanceParams) *Loa
 	}
 }
 
-// Run executes the performance test according to the mode.
-func (r *LoadTestRunner) Run(endpoints map[string]shared.EndpointAnalysis) ExecutionMetrics {
-	var metricsCollector MetricsCollector
-	var wg sync.WaitGroup
-
-	stop := make(chan struct{})
-	duration := time.Duration(r.params.Duration) * time.Second
+// Run executes the stages in order and collects metrics per stage and
+// overall. Stress runs (or any run with stop_on_breach) end at the first
+// stage whose metrics breach the limits.
+//
+// Without a target rate the runner uses a closed model: each stage scales
+// the number of virtual users, and every user sends its next request as
+// soon as the previous one completes. With a target rate it uses an open
+// model: requests are scheduled at a constant arrival rate independent of
+// response times and executed by up to max_vus workers, so a slow server
+// shows up as queueing delay and dropped requests instead of silently
+// lowering the load (coordinated omission).
+func (r *LoadTestRunner) Run(sc *Scenario, stages []Stage) RunResult {
+	var result RunResult
 
-	// Launch virtual users (goroutines)
-	for i := 0; i < r.params.Concurrency; i++ {
-		wg.Add(1)
-		go func(workerID int) {
-			defer wg.Done()
+	arrival := IsArrivalRate(stages)
+	var jobs chan scheduledRequest
+	var pool *workerPool
+	if arrival {
+		jobs = make(chan scheduledRequest, maxVUs(r.params))
+		pool = newWorkerPool(func(vu int, stop <-chan struct{}, record func(RequestStat)) {
 			for {
+				select {
+				case <-stop:
+					return
+				case job := <-jobs:
+					dequeued := time.Now()
+					stat := r.executeRequest(sc, job.request, vu)
+					stat.QueueDelay = dequeued.Sub(job.at)
+					record(stat)
+				}
+			}
+		})
+		pool.resize(maxVUs(r.params))
+	} else {
+		pool = newWorkerPool(func(vu int, stop <-chan struct{}, record func(RequestStat)) {
+			// Each VU walks the weighted mix from its own offset.
+			for n := vu; ; n++ {
 				select {
 				case <-stop:
 					return
 				default:
-					// Select an endpoint (simplistically pick the first one for now or rotate)
-					for epKey := range endpoints {
-						metricsCollector.Record(r.executeRequest(epKey))
-
-						// Respect RPS if specified
-						if r.params.RPS > 0 {
-							time.Sleep(time.Second / time.Duration(r.params.RPS))
-						}
-					}
 				}
+				record(r.executeRequest(sc, sc.pick(n), vu))
 			}
-		}(i)
+		})
 	}
 
-	// Wait for duration
-	time.Sleep(duration)
-	close(stop)
-	wg.Wait()
+	limits := stageLimits(r.params)
+	stopOnBreach := r.params.StopOnBreach || r.params.Mode == "stress"
+	runStart := time.Now()
+	overall := NewMetricsCollector(runStart)
+	previous := 0
+	var scheduler *arrivalScheduler
+	if arrival {
+		scheduler = &arrivalScheduler{jobs: jobs, scenario: sc}
+	}
+
+	for _, stage := range stages {
+		stageStart := time.Now()
+		stageCollector := NewMetricsCollector(stageStart)
+		pool.setRecorder(func(stat RequestStat) {
+			overall.Record(stat)
+			stageCollector.Record(stat)
+		})
+
+		var scheduled, dropped int
+		if arrival {
+			scheduled, dropped = scheduler.run(stage, previous, stageStart)
+			previous = stage.RPS
+		} else {
+			r.driveStage(pool, stage, previous, stageStart)
+			previous = stage.Concurrency
+		}
+		elapsed := time.Since(stageStart)
 
-	return metricsCollector.Finalize()
+		metrics := stageCollector.Finalize()
+		metrics.TimeSeries = nil // the overall time series already covers every stage
+		metrics.RPS = rate(metrics.Total, elapsed)
+		if arrival {
+			metrics.TargetRPS = rate(scheduled, elapsed)
+			metrics.Dropped = dropped
+		}
+
+		sr := StageResult{Stage: stage, Metrics: metrics, Reasons: limits.Check(metrics)}
+		sr.Breached = len(sr.Reasons) > 0
+		result.Stages = append(result.Stages, sr)
+
+		if sr.Breached && result.BreachedStage == "" {
+			result.BreachedStage = stage.Name
+			if stopOnBreach {
+				result.Stopped = true
+				break
+			}
+		}
+	}
+
+	elapsed := time.Since(runStart)
+	pool.stopAll()
+
+	result.Overall = overall.Finalize()
+	result.Overall.RPS = rate(result.Overall.Total, elapsed)
+	if arrival {
+		// Requests still queued when the run ends were never sent.
+		unsent := len(jobs)
+		result.Overall.TargetRPS = rate(scheduler.scheduled, elapsed)
+		result.Overall.Dropped = scheduler.dropped + unsent
+	}
+	return result
 }
 
-func (r *LoadTestRunner) executeRequest(epKey string) RequestStat {
-	start := time.Now()
+// maxVUs returns the worker cap for arrival-rate runs.
+func maxVUs(params PerformanceParams) int {
+	if params.MaxVUs > 0 {
+		return params.MaxVUs
+	}
+	return defaultMaxVUs
+}
+
+// peakVUs returns the most virtual users a run starts: max_vus in the open
+// model, else the highest stage concurrency (stress steps go up to 5x).
+func peakVUs(params PerformanceParams) int {
+	stages, err := BuildProfile(params)
+	if err != nil || len(stages) == 0 {
+		return max(1, params.Concurrency)
+	}
+	if IsArrivalRate(stages) {
+		return maxVUs(params)
+	}
+	peak := 1
+	for _, s := range stages {
+		peak = max(peak, s.Concurrency)
+	}
+	return peak
+}
+
+// driveStage keeps the pool at the stage's concurrency (interpolating when
+// ramping) until the stage duration has elapsed.
+func (r *LoadTestRunner) driveStage(pool *workerPool, stage Stage, from int, start time.Time) {
+	deadline := start.Add(stage.Duration())
+	ticker := time.NewTicker(controlInterval)
+	defer ticker.Stop()
+
+	for {
+		now := time.Now()
+		if !now.Before(deadline) {
+			return
+		}
+
+		target := stage.Concurrency
+		if stage.Ramp {
+			progress := float64(now.Sub(start)) / float64(stage.Duration())
+			target = from + int(float64(stage.Concurrency-from)*progress+0.5)
+		}
+		pool.resize(target)
 
-	method, path := "GET", "/"
-	if parts := strings.SplitN(epKey, " ", 2); len(parts) == 2 {
-		method, path = parts[0], parts[1]
+		select {
+		case <-ticker.C:
+		case <-time.After(time.Until(deadline)):
+		}
 	}
+}
+
+// executeRequest sends the idx-th scenario request as virtual user vu.
+func (r *LoadTestRunner) executeRequest(sc *Scenario, idx, vu int) RequestStat {
+	start := time.Now()
 
-	resp, err := r.httpTool.Run(shared.HTTPRequest{
-		Method: method,
-		URL:    r.params.BaseURL + path,
-	})
+	epKey, req := sc.request(idx, vu)
+	resp, err := r.httpTool.Run(req)
 	if err != nil {
-		return RequestStat{Latency: time.Since(start), Success: false}
+		return RequestStat{Endpoint: epKey, Latency: time.Since(start), Success: false, CompletedAt: time.Now()}
 	}
 	return RequestStat{
-		StatusCode: resp.StatusCode,
-		Latency:    resp.Duration,
-		Success:    resp.StatusCode < 500,
+		Endpoint:    epKey,
+		StatusCode:  resp.StatusCode,
+		Latency:     resp.Duration,
+		Success:     resp.StatusCode < 500,
+		CompletedAt: time.Now(),
 	}
 }
 
 type RequestStat struct {
-	StatusCode int
-	Latency    time.Duration
-	Success    bool
+	Endpoint    string    // request label ("METHOD /path" unless named)
+	CompletedAt time.Time // when the response finished (time-series bucket)
+	StatusCode  int
+	Latency     time.Duration // service time: request sent to response read
+	QueueDelay  time.Duration // arrival-rate mode: scheduled time to request start
+	Success     bool
+}
+
+// scheduledRequest is a request due at a fixed arrival time.
+type scheduledRequest struct {
+	request int // scenario request index
+	at      time.Time
+}
+
+// arrivalScheduler issues requests at a target rate regardless of how long
+// responses take. Requests that cannot be queued because every worker is
+// busy and the queue is full are dropped and counted.
+type arrivalScheduler struct {
+	jobs     chan scheduledRequest
+	scenario *Scenario
+	next     int
+
+	scheduled int
+	dropped   int
+}
+
+// run schedules requests for one stage, moving linearly from fromRPS to the
+// stage's RPS when ramping. It returns the stage's scheduled and dropped counts.
+func (s *arrivalScheduler) run(stage Stage, fromRPS int, start time.Time) (scheduled, dropped int) {
+	deadline := start.Add(stage.Duration())
+	at := start
+
+	for at.Before(deadline) {
+		target := float64(stage.RPS)
+		if stage.Ramp {
+			progress := float64(at.Sub(start)) / float64(stage.Duration())
+			target = float64(fromRPS) + float64(stage.RPS-fromRPS)*progress
+		}
+		if target <= 0 {
+			// Nothing to send yet; re-evaluate shortly (ramping up from zero).
+			at = at.Add(controlInterval)
+			time.Sleep(time.Until(at))
+			continue
+		}
+
+		if wait := time.Until(at); wait > 0 {
+			time.Sleep(wait)
+		}
+
+		job := scheduledRequest{request: s.scenario.pick(s.next), at: at}
+		s.next++
+		scheduled++
+		select {
+		case s.jobs <- job:
+		default:
+			dropped++
+		}
+
+		at = at.Add(time.Duration(float64(time.Second) / target))
+	}
+
+	if wait := time.Until(deadline); wait > 0 {
+		time.Sleep(wait)
+	}
+
+	s.scheduled += scheduled
+	s.dropped += dropped
+	return scheduled, dropped
+}
+
+// workerPool runs a variable number of virtual users. Each worker loops
+// until its stop channel is closed; results go to the current recorder.
+// Workers are numbered from 0 so data feeds can give each VU its own row.
+type workerPool struct {
+	work func(vu int, stop <-chan struct{}, record func(RequestStat))
+
+	mu       sync.Mutex
+	stops    []chan struct{}
+	recorder func(RequestStat)
+	wg       sync.WaitGroup
+}
+
+func newWorkerPool(work func(vu int, stop <-chan struct{}, record func(RequestStat))) *workerPool {
+	return &workerPool{work: work}
+}
+
+// setRecorder switches where subsequent results are recorded.
+func (p *workerPool) setRecorder(recorder func(RequestStat)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.recorder = recorder
+}
+
+func (p *workerPool) record(stat RequestStat) {
+	p.mu.Lock()
+	recorder := p.recorder
+	p.mu.Unlock()
+	if recorder != nil {
+		recorder(stat)
+	}
+}
+
+// resize starts or stops workers until n are active.
+func (p *workerPool) resize(n int) {
+	if n < 0 {
+		n = 0
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for len(p.stops) < n {
+		vu := len(p.stops)
+		stop := make(chan struct{})
+		p.stops = append(p.stops, stop)
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+			p.work(vu, stop, p.record)
+		}()
+	}
+	for len(p.stops) > n {
+		last := len(p.stops) - 1
+		close(p.stops[last])
+		p.stops = p.stops[:last]
+	}
+}
+
+// stopAll stops every worker and waits for in-flight requests to finish.
+func (p *workerPool) stopAll() {
+	p.resize(0)
+	p.wg.Wait()
+}
+
+// rate returns events per second over elapsed.
+func rate(count int, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return float64(count) / elapsed.Seconds()
 }
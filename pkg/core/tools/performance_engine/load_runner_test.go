@@ -0,0 +1,149 @@
+package performance_engine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+)
+
+// newLoadServer answers after delay, tracking the most requests in flight at once.
+func newLoadServer(t *testing.T, delay time.Duration, status int) (*httptest.Server, *int32) {
+	t.Helper()
+	var inFlight, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(delay)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &peak
+}
+
+func runLoad(t *testing.T, params PerformanceParams) RunResult {
+	t.Helper()
+	sc, err := BuildScenario(params, nil, "", nil)
+	if err != nil {
+		t.Fatalf("BuildScenario: %v", err)
+	}
+	runner := NewLoadTestRunner(shared.NewHTTPTool(nil, nil), params)
+	stages, err := BuildProfile(runner.params)
+	if err != nil {
+		t.Fatalf("BuildProfile: %v", err)
+	}
+	return runner.Run(sc, stages)
+}
+
+func TestLoadTestRunner_ClosedModel(t *testing.T) {
+	server, peak := newLoadServer(t, 10*time.Millisecond, http.StatusOK)
+
+	result := runLoad(t, PerformanceParams{
+		BaseURL: server.URL,
+		Stages:  []Stage{{Name: "hold", DurationSec: 1, Concurrency: 3}},
+		Requests: []RequestTemplate{
+			{Method: "GET", Path: "/users", Weight: 3},
+			{Method: "POST", Path: "/orders", Weight: 1},
+		},
+	})
+
+	if got := atomic.LoadInt32(peak); got != 3 {
+		t.Errorf("peak concurrency = %d, want 3", got)
+	}
+	overall := result.Overall
+	if overall.Total < 60 || overall.Fail != 0 || overall.StatusCodes[200] != overall.Total {
+		t.Fatalf("total %d, fail %d, status codes %v", overall.Total, overall.Fail, overall.StatusCodes)
+	}
+	if overall.TargetRPS != 0 || overall.Dropped != 0 {
+		t.Errorf("closed model reported target rps %.1f, dropped %d", overall.TargetRPS, overall.Dropped)
+	}
+	// Requests in flight when the stage ends only count toward the overall metrics
+	if len(result.Stages) != 1 || result.Stopped {
+		t.Fatalf("%d stages, stopped %v", len(result.Stages), result.Stopped)
+	}
+	if stage := result.Stages[0].Metrics.Total; stage > overall.Total || stage < overall.Total-3 {
+		t.Errorf("stage total %d, overall %d", stage, overall.Total)
+	}
+
+	counts := map[string]int{}
+	for _, ep := range overall.Endpoints {
+		counts[ep.Endpoint] = ep.Total
+	}
+	if ratio := float64(counts["GET /users"]) / float64(counts["POST /orders"]); ratio < 2.5 || ratio > 3.5 {
+		t.Errorf("weighted mix %v, want about 3:1", counts)
+	}
+}
+
+func TestLoadTestRunner_OpenModel(t *testing.T) {
+	server, _ := newLoadServer(t, 0, http.StatusOK)
+
+	result := runLoad(t, PerformanceParams{
+		BaseURL:  server.URL,
+		MaxVUs:   5,
+		Stages:   []Stage{{Name: "steady", DurationSec: 1, RPS: 40}},
+		Requests: []RequestTemplate{{Method: "GET", Path: "/users"}},
+	})
+
+	overall := result.Overall
+	if overall.Total != 40 || overall.Dropped != 0 {
+		t.Errorf("total %d, dropped %d; want 40 sent and none dropped", overall.Total, overall.Dropped)
+	}
+	if overall.TargetRPS < 35 || overall.TargetRPS > 41 {
+		t.Errorf("target rps = %.1f, want about 40", overall.TargetRPS)
+	}
+}
+
+func TestLoadTestRunner_OpenModelDropsWhenSaturated(t *testing.T) {
+	server, peak := newLoadServer(t, 300*time.Millisecond, http.StatusOK)
+
+	// 20 requests are due in one second, but two workers and a queue of two
+	// can only take a handful of them.
+	result := runLoad(t, PerformanceParams{
+		BaseURL:  server.URL,
+		MaxVUs:   2,
+		Stages:   []Stage{{Name: "steady", DurationSec: 1, RPS: 20}},
+		Requests: []RequestTemplate{{Method: "GET", Path: "/slow"}},
+	})
+
+	overall := result.Overall
+	if got := atomic.LoadInt32(peak); got > 2 {
+		t.Errorf("peak concurrency = %d, want at most max_vus 2", got)
+	}
+	if overall.Dropped == 0 || result.Stages[0].Metrics.Dropped == 0 {
+		t.Fatalf("expected dropped requests, got overall %d, stage %d", overall.Dropped, result.Stages[0].Metrics.Dropped)
+	}
+	if overall.Total+overall.Dropped != 20 {
+		t.Errorf("sent %d + dropped %d, want the 20 scheduled", overall.Total, overall.Dropped)
+	}
+	if overall.QueueMax < 100*time.Millisecond {
+		t.Errorf("queue max = %v, want queueing behind slow responses", overall.QueueMax)
+	}
+}
+
+func TestLoadTestRunner_StressStopsAtBreach(t *testing.T) {
+	server, _ := newLoadServer(t, 0, http.StatusInternalServerError)
+
+	result := runLoad(t, PerformanceParams{
+		BaseURL:     server.URL,
+		Mode:        "stress",
+		Concurrency: 1,
+		Duration:    5,
+		Requests:    []RequestTemplate{{Method: "GET", Path: "/boom"}},
+	})
+
+	if !result.Stopped || result.BreachedStage != "step 1" || len(result.Stages) != 1 {
+		t.Fatalf("stopped %v, breached %q, %d stages", result.Stopped, result.BreachedStage, len(result.Stages))
+	}
+	if reasons := result.Stages[0].Reasons; len(reasons) != 1 || reasons[0] != "error rate 100.00% > 5.00%" {
+		t.Errorf("reasons = %q", reasons)
+	}
+}
@@ -20,20 +20,171 @@ type ExecutionMetrics struct {
 	Min         time.Duration `json:"min"`
 	Max         time.Duration `json:"max"`
 	RPS         float64       `json:"rps"`
+
+	// Arrival-rate (open model) runs only.
+	TargetRPS float64       `json:"target_rps,omitempty"`
+	Dropped   int           `json:"dropped,omitempty"`   // scheduled but never sent (no free worker)
+	QueueAvg  time.Duration `json:"queue_avg,omitempty"` // mean wait between scheduled time and send
+	QueueP95  time.Duration `json:"queue_p95,omitempty"`
+	QueueMax  time.Duration `json:"queue_max,omitempty"`
+
+	// Breakdowns
+	Endpoints   []EndpointMetrics `json:"endpoints,omitempty"`    // sorted by endpoint key
+	StatusCodes map[int]int       `json:"status_codes,omitempty"` // 0 = transport error (no response)
+	TimeSeries  []TimeWindow      `json:"time_series,omitempty"`  // one entry per second of the run
+}
+
+// EndpointMetrics summarizes the requests sent to one endpoint.
+type EndpointMetrics struct {
+	Endpoint    string        `json:"endpoint"`
+	Total       int           `json:"total"`
+	Fail        int           `json:"fail"`
+	ErrorRate   float64       `json:"error_rate"`
+	AvgLatency  time.Duration `json:"avg_latency"`
+	P50         time.Duration `json:"p50"`
+	P95         time.Duration `json:"p95"`
+	P99         time.Duration `json:"p99"`
+	Min         time.Duration `json:"min"`
+	Max         time.Duration `json:"max"`
+	StatusCodes map[int]int   `json:"status_codes"`
+}
+
+// TimeWindow summarizes the requests that completed during one second.
+type TimeWindow struct {
+	Second    int           `json:"second"` // offset from the start of the run
+	Requests  int           `json:"requests"`
+	Errors    int           `json:"errors"`
+	RPS       float64       `json:"rps"`
+	ErrorRate float64       `json:"error_rate"`
+	P50       time.Duration `json:"p50"`
+	P95       time.Duration `json:"p95"`
+	P99       time.Duration `json:"p99"`
 }
 
-// MetricsCollector accumulates request statistics during a test run.
+// windowCompactLag is how many seconds a window stays open for late results
+// before its histogram is summarized and released.
+const windowCompactLag = 5
+
+// MetricsCollector accumulates request statistics during a test run. It
+// streams every result into histograms and per-second windows instead of
+// keeping individual samples, so memory stays flat however long the run is.
 type MetricsCollector struct {
-	mu       sync.Mutex
-	stats    []RequestStat
-	duration time.Duration
+	mu    sync.Mutex
+	start time.Time
+
+	total   int
+	success int
+	latency *Histogram
+	queue   *Histogram
+
+	endpoints map[string]*endpointCollector
+	statuses  map[int]int
+
+	windows   map[int]*windowCollector
+	compacted []TimeWindow
+	latest    int
+}
+
+type endpointCollector struct {
+	total    int
+	fail     int
+	latency  *Histogram
+	statuses map[int]int
+}
+
+type windowCollector struct {
+	requests int
+	errors   int
+	latency  *Histogram
+}
+
+// NewMetricsCollector creates a collector whose time windows are measured
+// from start.
+func NewMetricsCollector(start time.Time) *MetricsCollector {
+	return &MetricsCollector{
+		start:     start,
+		latency:   NewHistogram(),
+		queue:     NewHistogram(),
+		endpoints: make(map[string]*endpointCollector),
+		statuses:  make(map[int]int),
+		windows:   make(map[int]*windowCollector),
+	}
 }
 
 // Record adds a single request statistic to the collector.
 func (c *MetricsCollector) Record(stat RequestStat) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	c.stats = append(c.stats, stat)
+
+	c.total++
+	if stat.Success {
+		c.success++
+	}
+	c.latency.Record(stat.Latency)
+	c.queue.Record(stat.QueueDelay)
+	c.statuses[stat.StatusCode]++
+
+	ep, ok := c.endpoints[stat.Endpoint]
+	if !ok {
+		ep = &endpointCollector{latency: NewHistogram(), statuses: make(map[int]int)}
+		c.endpoints[stat.Endpoint] = ep
+	}
+	ep.total++
+	if !stat.Success {
+		ep.fail++
+	}
+	ep.latency.Record(stat.Latency)
+	ep.statuses[stat.StatusCode]++
+
+	at := stat.CompletedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+	second := int(at.Sub(c.start) / time.Second)
+	if second < 0 {
+		second = 0
+	}
+	w, ok := c.windows[second]
+	if !ok {
+		w = &windowCollector{latency: NewHistogram()}
+		c.windows[second] = w
+	}
+	w.requests++
+	if !stat.Success {
+		w.errors++
+	}
+	w.latency.Record(stat.Latency)
+
+	if second > c.latest {
+		c.latest = second
+		c.compact(second - windowCompactLag)
+	}
+}
+
+// compact summarizes and releases windows older than before.
+func (c *MetricsCollector) compact(before int) {
+	for second, w := range c.windows {
+		if second < before {
+			c.compacted = append(c.compacted, w.summary(second))
+			delete(c.windows, second)
+		}
+	}
+}
+
+func (w *windowCollector) summary(second int) TimeWindow {
+	tw := TimeWindow{
+		Second:   second,
+		Requests: w.requests,
+		Errors:   w.errors,
+		RPS:      float64(w.requests),
+		P50:      w.latency.Percentile(0.50),
+		P95:      w.latency.Percentile(0.95),
+		P99:      w.latency.Percentile(0.99),
+	}
+	if w.requests > 0 {
+		tw.ErrorRate = float64(w.errors) / float64(w.requests) * 100
+	}
+	return tw
 }
 
 // Finalize calculates the final metrics from the collected statistics.
@@ -41,50 +192,84 @@ func (c *MetricsCollector) Finalize() ExecutionMetrics {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if len(c.stats) == 0 {
+	if c.total == 0 {
 		return ExecutionMetrics{}
 	}
 
-	var totalLatency time.Duration
-	var successCount int
-	latencies := make([]int64, 0, len(c.stats))
+	metrics := ExecutionMetrics{
+		Total:       c.total,
+		Success:     c.success,
+		Fail:        c.total - c.success,
+		SuccessRate: float64(c.success) / float64(c.total) * 100,
+		AvgLatency:  c.latency.Mean(),
+		Min:         c.latency.Min(),
+		Max:         c.latency.Max(),
+		P50:         c.latency.Percentile(0.50),
+		P95:         c.latency.Percentile(0.95),
+		P99:         c.latency.Percentile(0.99),
+		StatusCodes: copyCounts(c.statuses),
+	}
 
-	min := c.stats[0].Latency
-	max := c.stats[0].Latency
+	if c.queue.Max() > 0 {
+		metrics.QueueAvg = c.queue.Mean()
+		metrics.QueueP95 = c.queue.Percentile(0.95)
+		metrics.QueueMax = c.queue.Max()
+	}
 
-	for _, s := range c.stats {
-		totalLatency += s.Latency
-		if s.Success {
-			successCount++
-		}
-		latencies = append(latencies, int64(s.Latency))
-		if s.Latency < min {
-			min = s.Latency
-		}
-		if s.Latency > max {
-			max = s.Latency
+	for key, ep := range c.endpoints {
+		em := EndpointMetrics{
+			Endpoint:    key,
+			Total:       ep.total,
+			Fail:        ep.fail,
+			ErrorRate:   float64(ep.fail) / float64(ep.total) * 100,
+			AvgLatency:  ep.latency.Mean(),
+			P50:         ep.latency.Percentile(0.50),
+			P95:         ep.latency.Percentile(0.95),
+			P99:         ep.latency.Percentile(0.99),
+			Min:         ep.latency.Min(),
+			Max:         ep.latency.Max(),
+			StatusCodes: copyCounts(ep.statuses),
 		}
+		metrics.Endpoints = append(metrics.Endpoints, em)
 	}
+	sort.Slice(metrics.Endpoints, func(i, j int) bool { return metrics.Endpoints[i].Endpoint < metrics.Endpoints[j].Endpoint })
 
-	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
-
-	count := len(latencies)
-	metrics := ExecutionMetrics{
-		Total:       count,
-		Success:     successCount,
-		Fail:        count - successCount,
-		SuccessRate: float64(successCount) / float64(count) * 100,
-		AvgLatency:  totalLatency / time.Duration(count),
-		Min:         min,
-		Max:         max,
-		P50:         time.Duration(latencies[int(float64(count-1)*0.50)]),
-		P95:         time.Duration(latencies[int(float64(count-1)*0.95)]),
-		P99:         time.Duration(latencies[int(float64(count-1)*0.99)]),
+	metrics.TimeSeries = append(metrics.TimeSeries, c.compacted...)
+	for second, w := range c.windows {
+		metrics.TimeSeries = append(metrics.TimeSeries, w.summary(second))
 	}
+	sort.Slice(metrics.TimeSeries, func(i, j int) bool { return metrics.TimeSeries[i].Second < metrics.TimeSeries[j].Second })
+	metrics.TimeSeries = fillGaps(metrics.TimeSeries)
 
 	return metrics
 }
 
+// fillGaps inserts empty windows for seconds without completed requests so
+// stalls are visible in the time series.
+func fillGaps(series []TimeWindow) []TimeWindow {
+	if len(series) == 0 {
+		return series
+	}
+	filled := make([]TimeWindow, 0, series[len(series)-1].Second+1)
+	next := 0
+	for _, w := range series {
+		for ; next < w.Second; next++ {
+			filled = append(filled, TimeWindow{Second: next})
+		}
+		filled = append(filled, w)
+		next = w.Second + 1
+	}
+	return filled
+}
+
+func copyCounts(m map[int]int) map[int]int {
+	out := make(map[int]int, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
 // FormatSummary returns a human-readable summary of the performance metrics.
 func (m *ExecutionMetrics) FormatSummary(mode string) string {
 	res := fmt.Sprintf("🚀 Performance Test Complete (Mode: %s)\n\n", mode)
@@ -97,5 +282,38 @@ func (m *ExecutionMetrics) FormatSummary(mode string) string {
 	res += fmt.Sprintf("  p50: %v\n", m.P50)
 	res += fmt.Sprintf("  p95: %v\n", m.P95)
 	res += fmt.Sprintf("  p99: %v\n", m.P99)
+	if m.TargetRPS > 0 {
+		res += fmt.Sprintf("Throughput: %.1f req/s achieved vs %.1f req/s target", m.RPS, m.TargetRPS)
+		if m.Dropped > 0 {
+			res += fmt.Sprintf(" (%d dropped)", m.Dropped)
+		}
+		res += "\n"
+		res += fmt.Sprintf("Queueing:   avg %v, p95 %v, max %v\n", m.QueueAvg, m.QueueP95, m.QueueMax)
+	}
+	if len(m.StatusCodes) > 0 {
+		res += "Status Codes: " + formatStatusCounts(m.StatusCodes) + "\n"
+	}
+	return res
+}
+
+// formatStatusCounts renders status counts as "200×950, 500×3, error×2".
+func formatStatusCounts(counts map[int]int) string {
+	codes := make([]int, 0, len(counts))
+	for code := range counts {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	res := ""
+	for i, code := range codes {
+		if i > 0 {
+			res += ", "
+		}
+		label := fmt.Sprintf("%d", code)
+		if code == 0 {
+			label = "error"
+		}
+		res += fmt.Sprintf("%s×%d", label, counts[code])
+	}
 	return res
 }
@@ -0,0 +1,93 @@
+package performance_engine
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMetricsCollector_WindowsAndCompaction(t *testing.T) {
+	start := time.Now()
+	c := NewMetricsCollector(start)
+	at := func(sec float64) time.Time { return start.Add(time.Duration(sec * float64(time.Second))) }
+	record := func(sec float64, endpoint string, status int, latency time.Duration) {
+		c.Record(RequestStat{Endpoint: endpoint, StatusCode: status, Latency: latency, Success: status > 0 && status < 500, CompletedAt: at(sec)})
+	}
+
+	record(0.1, "GET /users", 200, 10*time.Millisecond)
+	record(0.9, "GET /users", 500, 30*time.Millisecond)
+	record(1.5, "POST /orders", 201, 20*time.Millisecond)
+	record(3.2, "GET /users", 0, 5*time.Millisecond) // transport error
+	record(6.0, "GET /users", 200, 40*time.Millisecond)
+	record(4.5, "POST /orders", 201, 15*time.Millisecond) // late, but second 4 is still open
+
+	// Reaching second 6 compacts windows older than 6-5 = 1
+	if len(c.compacted) != 1 || c.compacted[0].Second != 0 || len(c.windows) != 4 {
+		t.Fatalf("after second 6: compacted %+v, %d open windows", c.compacted, len(c.windows))
+	}
+
+	record(9.0, "GET /users", 200, 50*time.Millisecond)
+	if len(c.compacted) != 3 || len(c.windows) != 3 {
+		t.Fatalf("after second 9: %d compacted, %d open windows", len(c.compacted), len(c.windows))
+	}
+	for _, second := range []int{4, 6, 9} {
+		if _, ok := c.windows[second]; !ok {
+			t.Errorf("window %d should still be open", second)
+		}
+	}
+
+	m := c.Finalize()
+	if m.Total != 7 || m.Success != 5 || m.Fail != 2 {
+		t.Errorf("total %d, success %d, fail %d", m.Total, m.Success, m.Fail)
+	}
+	if m.Min != 5*time.Millisecond || m.Max != 50*time.Millisecond {
+		t.Errorf("min %v, max %v", m.Min, m.Max)
+	}
+	if m.StatusCodes[0] != 1 || m.StatusCodes[200] != 3 || m.StatusCodes[201] != 2 || m.StatusCodes[500] != 1 {
+		t.Errorf("status codes %v", m.StatusCodes)
+	}
+	if m.QueueMax != 0 || m.QueueAvg != 0 {
+		t.Errorf("closed-model run reported queueing: %v", m.QueueMax)
+	}
+
+	if len(m.Endpoints) != 2 || m.Endpoints[0].Endpoint != "GET /users" || m.Endpoints[1].Endpoint != "POST /orders" {
+		t.Fatalf("endpoints %+v", m.Endpoints)
+	}
+	users := m.Endpoints[0]
+	if users.Total != 5 || users.Fail != 2 || users.ErrorRate != 40 || users.Min != 5*time.Millisecond || users.Max != 50*time.Millisecond {
+		t.Errorf("GET /users = %+v", users)
+	}
+
+	// One window per second, gaps filled
+	if len(m.TimeSeries) != 10 {
+		t.Fatalf("time series has %d windows, want 10", len(m.TimeSeries))
+	}
+	want := map[int][2]int{0: {2, 1}, 1: {1, 0}, 2: {0, 0}, 3: {1, 1}, 4: {1, 0}, 5: {0, 0}, 6: {1, 0}, 7: {0, 0}, 8: {0, 0}, 9: {1, 0}}
+	for i, w := range m.TimeSeries {
+		if w.Second != i || w.Requests != want[i][0] || w.Errors != want[i][1] || w.RPS != float64(want[i][0]) {
+			t.Errorf("window %d = %+v, want %d requests, %d errors", i, w, want[i][0], want[i][1])
+		}
+	}
+	if w := m.TimeSeries[0]; w.ErrorRate != 50 || w.P50 < 10*time.Millisecond {
+		t.Errorf("window 0 = %+v", w)
+	}
+}
+
+func TestMetricsCollector_QueueAndEdgeCases(t *testing.T) {
+	if m := NewMetricsCollector(time.Now()).Finalize(); m.Total != 0 || m.TimeSeries != nil {
+		t.Errorf("empty collector = %+v", m)
+	}
+
+	start := time.Now()
+	c := NewMetricsCollector(start)
+	c.Record(RequestStat{Endpoint: "GET /", StatusCode: 200, Success: true, Latency: time.Millisecond, QueueDelay: 20 * time.Millisecond, CompletedAt: start.Add(-time.Second)})
+	c.Record(RequestStat{Endpoint: "GET /", StatusCode: 200, Success: true, Latency: time.Millisecond, QueueDelay: 40 * time.Millisecond})
+
+	m := c.Finalize()
+	if m.QueueMax != 40*time.Millisecond || m.QueueAvg < 25*time.Millisecond || m.QueueAvg > 35*time.Millisecond {
+		t.Errorf("queue avg %v, max %v", m.QueueAvg, m.QueueMax)
+	}
+	// A result completing before the start lands in the first window
+	if len(m.TimeSeries) != 1 || m.TimeSeries[0].Requests != 2 {
+		t.Errorf("time series %+v", m.TimeSeries)
+	}
+}
@@ -0,0 +1,200 @@
+package performance_engine
+
+import (
+	"fmt"
+	"time"
+)
+
+// Stage is one segment of a load profile. Concurrency is the number of
+// virtual users at the end of the stage; with Ramp the runner moves linearly
+// from the previous stage's concurrency instead of jumping to it.
+//
+// In arrival-rate mode (any RPS set) the stage's RPS is the target rate at
+// the end of the stage instead, ramped the same way, and Concurrency is
+// only used to derive default rates.
+type Stage struct {
+	Name        string `json:"name,omitempty"`
+	DurationSec int    `json:"duration_sec"`
+	Concurrency int    `json:"concurrency"`
+	RPS         int    `json:"rps,omitempty"`
+	Ramp        bool   `json:"ramp,omitempty"`
+}
+
+// Duration returns the stage length.
+func (s Stage) Duration() time.Duration {
+	return time.Duration(s.DurationSec) * time.Second
+}
+
+// StageLimits are the breaking-point thresholds evaluated at the end of
+// every stage. A zero value disables that check.
+type StageLimits struct {
+	MaxErrorRate float64 `json:"max_error_rate,omitempty"` // percent of failed requests, e.g. 5
+	MaxP95Ms     int     `json:"max_p95_ms,omitempty"`     // p95 latency in milliseconds
+	MaxP99Ms     int     `json:"max_p99_ms,omitempty"`     // p99 latency in milliseconds
+}
+
+// IsZero reports whether no limit is configured.
+func (l StageLimits) IsZero() bool {
+	return l.MaxErrorRate == 0 && l.MaxP95Ms == 0 && l.MaxP99Ms == 0
+}
+
+// Check returns the reasons a stage's metrics breach the limits.
+func (l StageLimits) Check(m ExecutionMetrics) []string {
+	if m.Total == 0 {
+		return nil
+	}
+
+	var reasons []string
+	errorRate := 100 - m.SuccessRate
+	if l.MaxErrorRate > 0 && errorRate > l.MaxErrorRate {
+		reasons = append(reasons, fmt.Sprintf("error rate %.2f%% > %.2f%%", errorRate, l.MaxErrorRate))
+	}
+	if l.MaxP95Ms > 0 && m.P95 > time.Duration(l.MaxP95Ms)*time.Millisecond {
+		reasons = append(reasons, fmt.Sprintf("p95 %v > %dms", m.P95, l.MaxP95Ms))
+	}
+	if l.MaxP99Ms > 0 && m.P99 > time.Duration(l.MaxP99Ms)*time.Millisecond {
+		reasons = append(reasons, fmt.Sprintf("p99 %v > %dms", m.P99, l.MaxP99Ms))
+	}
+	return reasons
+}
+
+// defaultStressLimits stops a stress run once 5% of requests fail.
+var defaultStressLimits = StageLimits{MaxErrorRate: 5}
+
+// stressSteps is the number of concurrency steps in the default stress profile.
+const stressSteps = 5
+
+// BuildProfile returns the stages for a run. Explicit stages win; otherwise
+// a default profile is derived from the mode, concurrency and duration:
+//
+//   - load:   ramp up (20%), hold (70%), ramp down (10%)
+//   - stress: five equal steps of 1x..5x concurrency, stopping at the first breach
+//   - spike:  low baseline, sudden jump to full concurrency, recovery
+//   - soak:   constant concurrency split into checkpoints of checkpoint_sec
+//     (default: ten checkpoints of at least 10 seconds)
+//
+// When params.RPS is set, each default stage gets a target rate scaled by
+// its share of the concurrency (so the stress steps run at 1x..5x RPS).
+func BuildProfile(params PerformanceParams) ([]Stage, error) {
+	stages, err := buildStages(params)
+	if err != nil || params.RPS <= 0 {
+		return stages, err
+	}
+
+	explicit := false
+	for _, s := range stages {
+		if s.RPS > 0 {
+			explicit = true
+			break
+		}
+	}
+	if explicit {
+		return stages, nil
+	}
+
+	for i := range stages {
+		if len(params.Stages) > 0 {
+			stages[i].RPS = params.RPS
+		} else {
+			stages[i].RPS = int(float64(params.RPS)*float64(stages[i].Concurrency)/float64(params.Concurrency) + 0.5)
+		}
+	}
+	return stages, nil
+}
+
+// IsArrivalRate reports whether the stages use the open (arrival-rate) model.
+func IsArrivalRate(stages []Stage) bool {
+	for _, s := range stages {
+		if s.RPS > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func buildStages(params PerformanceParams) ([]Stage, error) {
+	if len(params.Stages) > 0 {
+		for i, s := range params.Stages {
+			if s.DurationSec <= 0 {
+				return nil, fmt.Errorf("stage %d: duration_sec must be positive", i+1)
+			}
+			if s.Concurrency < 0 || s.RPS < 0 {
+				return nil, fmt.Errorf("stage %d: concurrency and rps must not be negative", i+1)
+			}
+		}
+		return expandCheckpoints(params.Stages, params.CheckpointSec), nil
+	}
+
+	c, d := params.Concurrency, params.Duration
+	switch params.Mode {
+	case "", "load":
+		up, down := max(1, d/5), max(1, d/10)
+		return []Stage{
+			{Name: "ramp-up", DurationSec: up, Concurrency: c, Ramp: true},
+			{Name: "steady", DurationSec: max(1, d-up-down), Concurrency: c},
+			{Name: "ramp-down", DurationSec: down, Concurrency: 0, Ramp: true},
+		}, nil
+
+	case "stress":
+		step := max(1, d/stressSteps)
+		stages := make([]Stage, stressSteps)
+		for i := range stages {
+			stages[i] = Stage{Name: fmt.Sprintf("step %d", i+1), DurationSec: step, Concurrency: c * (i + 1)}
+		}
+		return stages, nil
+
+	case "spike":
+		low := max(1, c/10)
+		baseline, spike := max(1, d*2/5), max(1, d/5)
+		return []Stage{
+			{Name: "baseline", DurationSec: baseline, Concurrency: low},
+			{Name: "spike", DurationSec: spike, Concurrency: c},
+			{Name: "recovery", DurationSec: max(1, d-baseline-spike), Concurrency: low},
+		}, nil
+
+	case "soak":
+		interval := params.CheckpointSec
+		if interval <= 0 {
+			interval = max(10, d/10)
+		}
+		return expandCheckpoints([]Stage{{Name: "soak", DurationSec: d, Concurrency: c}}, interval), nil
+
+	default:
+		return nil, fmt.Errorf("unknown mode '%s' (use load, stress, spike or soak)", params.Mode)
+	}
+}
+
+// expandCheckpoints splits non-ramping stages longer than interval seconds
+// into consecutive checkpoint stages so long runs report metrics
+// periodically. A zero interval leaves the stages unchanged.
+func expandCheckpoints(stages []Stage, interval int) []Stage {
+	var out []Stage
+	for _, s := range stages {
+		if interval <= 0 || s.Ramp || s.DurationSec <= interval {
+			out = append(out, s)
+			continue
+		}
+
+		name := s.Name
+		if name == "" {
+			name = "stage"
+		}
+		for elapsed, n := 0, 1; elapsed < s.DurationSec; elapsed, n = elapsed+interval, n+1 {
+			out = append(out, Stage{
+				Name:        fmt.Sprintf("%s checkpoint %d", name, n),
+				DurationSec: min(interval, s.DurationSec-elapsed),
+				Concurrency: s.Concurrency,
+				RPS:         s.RPS,
+			})
+		}
+	}
+	return out
+}
+
+// stageLimits returns the limits for a run, applying the stress default.
+func stageLimits(params PerformanceParams) StageLimits {
+	if params.Limits.IsZero() && params.Mode == "stress" {
+		return defaultStressLimits
+	}
+	return params.Limits
+}
@@ -0,0 +1,210 @@
+package performance_engine
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// describe renders stages as "name duration@concurrency[/rps][~]" for compact comparison.
+func describe(stages []Stage) string {
+	parts := make([]string, len(stages))
+	for i, s := range stages {
+		parts[i] = fmt.Sprintf("%s %ds@%d", s.Name, s.DurationSec, s.Concurrency)
+		if s.RPS > 0 {
+			parts[i] += fmt.Sprintf("/%d", s.RPS)
+		}
+		if s.Ramp {
+			parts[i] += "~"
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+func TestBuildProfile(t *testing.T) {
+	cases := []struct {
+		name   string
+		params PerformanceParams
+		want   string
+		err    string
+	}{
+		{
+			name:   "load",
+			params: PerformanceParams{Mode: "load", Concurrency: 10, Duration: 60},
+			want:   "ramp-up 12s@10~, steady 42s@10, ramp-down 6s@0~",
+		},
+		{
+			name:   "load defaults to load mode",
+			params: PerformanceParams{Concurrency: 4, Duration: 3},
+			want:   "ramp-up 1s@4~, steady 1s@4, ramp-down 1s@0~",
+		},
+		{
+			name:   "stress",
+			params: PerformanceParams{Mode: "stress", Concurrency: 10, Duration: 50},
+			want:   "step 1 10s@10, step 2 10s@20, step 3 10s@30, step 4 10s@40, step 5 10s@50",
+		},
+		{
+			name:   "stress with rps",
+			params: PerformanceParams{Mode: "stress", Concurrency: 10, Duration: 50, RPS: 20},
+			want:   "step 1 10s@10/20, step 2 10s@20/40, step 3 10s@30/60, step 4 10s@40/80, step 5 10s@50/100",
+		},
+		{
+			name:   "spike",
+			params: PerformanceParams{Mode: "spike", Concurrency: 20, Duration: 50},
+			want:   "baseline 20s@2, spike 10s@20, recovery 20s@2",
+		},
+		{
+			name:   "spike with rps",
+			params: PerformanceParams{Mode: "spike", Concurrency: 20, Duration: 50, RPS: 100},
+			want:   "baseline 20s@2/10, spike 10s@20/100, recovery 20s@2/10",
+		},
+		{
+			name:   "soak default checkpoints",
+			params: PerformanceParams{Mode: "soak", Concurrency: 5, Duration: 300},
+			want: "soak checkpoint 1 30s@5, soak checkpoint 2 30s@5, soak checkpoint 3 30s@5, soak checkpoint 4 30s@5, soak checkpoint 5 30s@5, " +
+				"soak checkpoint 6 30s@5, soak checkpoint 7 30s@5, soak checkpoint 8 30s@5, soak checkpoint 9 30s@5, soak checkpoint 10 30s@5",
+		},
+		{
+			name:   "soak short run uses 10s checkpoints",
+			params: PerformanceParams{Mode: "soak", Concurrency: 5, Duration: 25},
+			want:   "soak checkpoint 1 10s@5, soak checkpoint 2 10s@5, soak checkpoint 3 5s@5",
+		},
+		{
+			name:   "soak checkpoint_sec",
+			params: PerformanceParams{Mode: "soak", Concurrency: 5, Duration: 300, CheckpointSec: 70},
+			want:   "soak checkpoint 1 70s@5, soak checkpoint 2 70s@5, soak checkpoint 3 70s@5, soak checkpoint 4 70s@5, soak checkpoint 5 20s@5",
+		},
+		{
+			name: "explicit stages get the global rps",
+			params: PerformanceParams{Concurrency: 10, Duration: 60, RPS: 30, Stages: []Stage{
+				{Name: "warm", DurationSec: 5, Concurrency: 2, Ramp: true},
+				{Name: "hold", DurationSec: 10, Concurrency: 8},
+			}},
+			want: "warm 5s@2/30~, hold 10s@8/30",
+		},
+		{
+			name: "explicit stage rates win",
+			params: PerformanceParams{Concurrency: 10, Duration: 60, RPS: 30, Stages: []Stage{
+				{Name: "warm", DurationSec: 5, RPS: 10, Ramp: true},
+				{Name: "hold", DurationSec: 10, RPS: 50},
+			}},
+			want: "warm 5s@0/10~, hold 10s@0/50",
+		},
+		{
+			name:   "explicit stages split into checkpoints",
+			params: PerformanceParams{CheckpointSec: 4, Stages: []Stage{{DurationSec: 10, Concurrency: 3}}},
+			want:   "stage checkpoint 1 4s@3, stage checkpoint 2 4s@3, stage checkpoint 3 2s@3",
+		},
+		{
+			name:   "stage without duration",
+			params: PerformanceParams{Stages: []Stage{{DurationSec: 5, Concurrency: 1}, {Concurrency: 1}}},
+			err:    "stage 2: duration_sec must be positive",
+		},
+		{
+			name:   "negative stage rps",
+			params: PerformanceParams{Stages: []Stage{{DurationSec: 5, RPS: -1}}},
+			err:    "must not be negative",
+		},
+		{
+			name:   "unknown mode",
+			params: PerformanceParams{Mode: "burst", Concurrency: 1, Duration: 10},
+			err:    "unknown mode 'burst'",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stages, err := BuildProfile(tc.params)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("err = %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildProfile: %v", err)
+			}
+			if got := describe(stages); got != tc.want {
+				t.Errorf("stages:\n got %s\nwant %s", got, tc.want)
+			}
+			if IsArrivalRate(stages) != (tc.params.RPS > 0) {
+				t.Errorf("IsArrivalRate = %v with rps %d", IsArrivalRate(stages), tc.params.RPS)
+			}
+		})
+	}
+}
+
+func TestBuildProfile_CoversDuration(t *testing.T) {
+	for _, mode := range []string{"load", "stress", "spike", "soak"} {
+		for _, d := range []int{10, 37, 60, 600} {
+			stages, err := buildStages(PerformanceParams{Mode: mode, Concurrency: 10, Duration: d})
+			if err != nil {
+				t.Fatalf("%s %ds: %v", mode, d, err)
+			}
+			total := 0
+			for _, s := range stages {
+				total += s.DurationSec
+			}
+			// stress rounds each step down to a whole second
+			if total != d && !(mode == "stress" && total == d/stressSteps*stressSteps) {
+				t.Errorf("%s %ds: stages last %ds (%s)", mode, d, total, describe(stages))
+			}
+		}
+	}
+}
+
+func TestExpandCheckpoints(t *testing.T) {
+	stages := []Stage{
+		{Name: "up", DurationSec: 30, Concurrency: 10, Ramp: true},
+		{Name: "hold", DurationSec: 25, Concurrency: 10, RPS: 40},
+		{Name: "short", DurationSec: 10, Concurrency: 10},
+	}
+
+	if got := expandCheckpoints(stages, 0); !reflect.DeepEqual(got, stages) {
+		t.Errorf("interval 0 changed the stages: %s", describe(got))
+	}
+	want := "up 30s@10~, hold checkpoint 1 10s@10/40, hold checkpoint 2 10s@10/40, hold checkpoint 3 5s@10/40, short 10s@10"
+	if got := describe(expandCheckpoints(stages, 10)); got != want {
+		t.Errorf("interval 10:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestStageLimits(t *testing.T) {
+	metrics := ExecutionMetrics{Total: 100, SuccessRate: 92, P95: 450 * time.Millisecond, P99: 1200 * time.Millisecond}
+
+	cases := []struct {
+		name    string
+		limits  StageLimits
+		metrics ExecutionMetrics
+		want    []string
+	}{
+		{"no limits", StageLimits{}, metrics, nil},
+		{"error rate", StageLimits{MaxErrorRate: 5}, metrics, []string{"error rate 8.00% > 5.00%"}},
+		{"error rate at the limit", StageLimits{MaxErrorRate: 8}, metrics, nil},
+		{"p95", StageLimits{MaxP95Ms: 300}, metrics, []string{"p95 450ms > 300ms"}},
+		{"p99", StageLimits{MaxP99Ms: 1000}, metrics, []string{"p99 1.2s > 1000ms"}},
+		{"all", StageLimits{MaxErrorRate: 1, MaxP95Ms: 100, MaxP99Ms: 100}, metrics, []string{"error rate 8.00% > 1.00%", "p95 450ms > 100ms", "p99 1.2s > 100ms"}},
+		{"within limits", StageLimits{MaxErrorRate: 10, MaxP95Ms: 500, MaxP99Ms: 2000}, metrics, nil},
+		{"no requests", StageLimits{MaxErrorRate: 1}, ExecutionMetrics{}, nil},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.limits.Check(tc.metrics); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Check = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	// Stress runs stop at 5% errors unless limits are given
+	if got := stageLimits(PerformanceParams{Mode: "stress"}); got != defaultStressLimits {
+		t.Errorf("stress default limits = %+v", got)
+	}
+	custom := StageLimits{MaxP95Ms: 800}
+	if got := stageLimits(PerformanceParams{Mode: "stress", Limits: custom}); got != custom {
+		t.Errorf("stress custom limits = %+v", got)
+	}
+	if got := stageLimits(PerformanceParams{Mode: "load"}); !got.IsZero() {
+		t.Errorf("load limits = %+v, want none", got)
+	}
+}
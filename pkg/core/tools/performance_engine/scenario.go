@@ -0,0 +1,415 @@
+package performance_engine
+
+import (
+	"fmt"
+	"math/rand"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/data_driven_engine"
+	"github.com/blackcoderx/falcon/pkg/core/tools/persistence"
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/blackcoderx/falcon/pkg/storage"
+)
+
+// Data feed modes decide which row a request uses.
+const (
+	FeedPerVU      = "per_vu"     // each virtual user keeps its own row (row = VU index)
+	FeedSequential = "sequential" // every request takes the next row
+	FeedRandom     = "random"     // every request takes a random row
+)
+
+// RequestTemplate describes one request in a performance scenario: a saved
+// request from .falcon/requests/ or an inline template. {{VAR}} placeholders
+// are filled from the data feed row, then from the variable store.
+type RequestTemplate struct {
+	Name    string            `json:"name,omitempty"`  // label in metrics (default "METHOD path")
+	Saved   string            `json:"saved,omitempty"` // saved request name in .falcon/requests/
+	Method  string            `json:"method,omitempty"`
+	Path    string            `json:"path,omitempty"` // relative to base_url, or an absolute URL
+	Headers map[string]string `json:"headers,omitempty"`
+	Query   map[string]string `json:"query,omitempty"`
+	Body    interface{}       `json:"body,omitempty"`
+	Weight  int               `json:"weight,omitempty"` // relative share of traffic (default 1)
+}
+
+// DataFeed supplies per-request values from a data file.
+type DataFeed struct {
+	Source string                 `json:"source"`           // .csv/.tsv/.json/.jsonl in the project or .falcon/data/, or "fake"
+	Mode   string                 `json:"mode,omitempty"`   // per_vu (default), sequential, random
+	Filter map[string]interface{} `json:"filter,omitempty"` // keep rows whose column matches
+}
+
+// Scenario is the resolved request mix for a run.
+type Scenario struct {
+	requests []preparedRequest
+	order    []int // weighted round-robin sequence of request indexes
+
+	rows     []map[string]interface{}
+	feedMode string
+	feedNext uint64
+}
+
+type preparedRequest struct {
+	label   string
+	method  string
+	url     string
+	headers map[string]string
+	body    interface{}
+	weight  int
+	dynamic bool // contains data feed placeholders
+}
+
+// BuildScenario resolves saved requests, inline templates or plain
+// "METHOD /path" endpoints into a runnable scenario. Variable store values
+// are applied once up front; data feed placeholders are filled per request.
+// Placeholders that neither source can fill are reported as an error so a
+// run never measures a wall of 401s and 404s.
+func BuildScenario(params PerformanceParams, endpoints map[string]shared.EndpointAnalysis, falconDir string, varStore *shared.VariableStore) (*Scenario, error) {
+	templates := params.Requests
+	if len(templates) == 0 {
+		keys := make([]string, 0, len(endpoints))
+		for key := range endpoints {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			method, path := splitEndpoint(key)
+			templates = append(templates, RequestTemplate{Name: key, Method: method, Path: path})
+		}
+	}
+	if len(templates) == 0 {
+		return nil, fmt.Errorf("no requests to run")
+	}
+
+	sc := &Scenario{}
+	columns := make(map[string]bool)
+	if params.Data != nil && params.Data.Source != "" {
+		sc.feedMode = params.Data.Mode
+		if sc.feedMode == "" {
+			sc.feedMode = FeedPerVU
+		}
+		if sc.feedMode != FeedPerVU && sc.feedMode != FeedSequential && sc.feedMode != FeedRandom {
+			return nil, fmt.Errorf("invalid data mode '%s' (use per_vu, sequential, random)", sc.feedMode)
+		}
+	}
+
+	var unresolved []string
+	for i, tpl := range templates {
+		req, err := prepareRequest(tpl, params, falconDir, varStore)
+		if err != nil {
+			return nil, fmt.Errorf("request %d: %w", i+1, err)
+		}
+		sc.requests = append(sc.requests, req)
+		for _, name := range req.placeholders() {
+			if !columns[name] {
+				columns[name] = true
+				unresolved = append(unresolved, name)
+			}
+		}
+	}
+
+	if sc.feedMode != "" {
+		loader := &data_driven_engine.DataLoader{Source: params.Data.Source, FalconDir: falconDir, Filter: params.Data.Filter}
+		rows, err := loader.Load(unresolved, feedRowLimit(params, sc.feedMode))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load data feed: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil, fmt.Errorf("data feed '%s' has no rows", params.Data.Source)
+		}
+		sc.rows = rows
+		unresolved = nil
+		for i := range sc.requests {
+			sc.requests[i].dynamic = len(sc.requests[i].placeholders()) > 0
+		}
+	}
+
+	if len(unresolved) > 0 {
+		return nil, fmt.Errorf("unresolved variables: %s (set them with the variable tool, or add a data feed)", strings.Join(unresolved, ", "))
+	}
+
+	for i, req := range sc.requests {
+		if !isAbsoluteURL(req.url) && !req.dynamic {
+			return nil, fmt.Errorf("request '%s' has no absolute URL (set base_url)", sc.requests[i].label)
+		}
+	}
+
+	sc.order = weightedOrder(sc.requests)
+	return sc, nil
+}
+
+// feedRowLimit is how many data feed rows a run can use. Sequential and
+// random feeds cycle through the whole file; a per_vu feed needs one row per
+// virtual user. Fake data is generated one row per virtual user.
+func feedRowLimit(params PerformanceParams, mode string) int {
+	if mode == FeedPerVU || params.Data.Source == "fake" || params.Data.Source == "random" {
+		return peakVUs(params)
+	}
+	return 0
+}
+
+// prepareRequest merges a template with its saved request and the shared
+// headers, then applies the variable store.
+func prepareRequest(tpl RequestTemplate, params PerformanceParams, falconDir string, varStore *shared.VariableStore) (preparedRequest, error) {
+	req := preparedRequest{method: strings.ToUpper(tpl.Method), url: tpl.Path, headers: make(map[string]string), body: tpl.Body, weight: tpl.Weight}
+
+	// Shared headers first, so the saved request and the template can override them.
+	for k, v := range params.Headers {
+		req.headers[k] = v
+	}
+
+	var query map[string]string
+	if tpl.Saved != "" {
+		saved, err := persistence.LoadWithoutHooks(falconDir, tpl.Saved)
+		if err != nil {
+			return req, err
+		}
+		if saved.Session != "" && saved.Session != shared.NoSession {
+			return req, fmt.Errorf("saved request '%s' uses cookie session '%s', but performance scenarios send no cookies", tpl.Saved, saved.Session)
+		}
+		if req.method == "" {
+			req.method = strings.ToUpper(saved.Method)
+		}
+		if req.url == "" {
+			req.url = saved.URL
+		}
+		if req.body == nil {
+			req.body = saved.Body
+		}
+		for k, v := range saved.Headers {
+			req.headers[k] = v
+		}
+		query = saved.Query
+	}
+	if req.method == "" {
+		req.method = "GET"
+	}
+	if req.url == "" {
+		return req, fmt.Errorf("path is required (or a saved request)")
+	}
+	if req.weight <= 0 {
+		req.weight = 1
+	}
+
+	req.label = tpl.Name
+	if req.label == "" {
+		if tpl.Saved != "" {
+			req.label = tpl.Saved
+		} else {
+			req.label = req.method + " " + req.url
+		}
+	}
+
+	for k, v := range tpl.Headers {
+		req.headers[k] = v
+	}
+	if len(tpl.Query) > 0 {
+		merged := make(map[string]string, len(query)+len(tpl.Query))
+		for k, v := range query {
+			merged[k] = v
+		}
+		for k, v := range tpl.Query {
+			merged[k] = v
+		}
+		query = merged
+	}
+
+	// OpenAPI path parameters ({id}) are filled like {{id}}.
+	req.url = shared.PathParamPattern.ReplaceAllString(req.url, "{{$1}}")
+	req.url = appendQuery(req.url, query)
+
+	substitute := func(text string) string {
+		if varStore != nil {
+			text = varStore.Substitute(text)
+		}
+		return storage.SubstituteVariables(text, nil) // {{env:NAME}}
+	}
+	req.url = substitute(req.url)
+	for k, v := range req.headers {
+		req.headers[k] = substitute(v)
+	}
+	req.body = substituteBody(req.body, substitute, nil)
+
+	if !isAbsoluteURL(req.url) && !strings.HasPrefix(req.url, "{{") && params.BaseURL != "" {
+		req.url = strings.TrimRight(params.BaseURL, "/") + "/" + strings.TrimLeft(req.url, "/")
+	}
+	return req, nil
+}
+
+func isAbsoluteURL(u string) bool {
+	return strings.HasPrefix(u, "http://") || strings.HasPrefix(u, "https://")
+}
+
+// appendQuery adds query parameters to a URL in a stable order.
+func appendQuery(rawURL string, query map[string]string) string {
+	if len(query) == 0 {
+		return rawURL
+	}
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		// Placeholders stay readable so they can still be substituted.
+		v := query[k]
+		if !shared.PlaceholderPattern.MatchString(v) {
+			v = url.QueryEscape(v)
+		}
+		parts = append(parts, url.QueryEscape(k)+"="+v)
+	}
+	sep := "?"
+	if strings.Contains(rawURL, "?") {
+		sep = "&"
+	}
+	return rawURL + sep + strings.Join(parts, "&")
+}
+
+// placeholders lists the {{name}} placeholders still left in the request.
+func (p preparedRequest) placeholders() []string {
+	var names []string
+	seen := make(map[string]bool)
+	collect := func(s string) {
+		for _, m := range shared.PlaceholderPattern.FindAllStringSubmatch(s, -1) {
+			if !seen[m[1]] {
+				seen[m[1]] = true
+				names = append(names, m[1])
+			}
+		}
+	}
+	collect(p.url)
+	for _, v := range p.headers {
+		collect(v)
+	}
+	substituteBody(p.body, func(s string) string { collect(s); return s }, nil)
+	return names
+}
+
+// Labels returns the request labels with their weights, in scenario order.
+func (s *Scenario) Labels() []string {
+	labels := make([]string, len(s.requests))
+	for i, req := range s.requests {
+		labels[i] = req.label
+		if req.weight > 1 {
+			labels[i] += " ×" + strconv.Itoa(req.weight)
+		}
+	}
+	return labels
+}
+
+// RowCount returns the number of data feed rows (0 without a feed).
+func (s *Scenario) RowCount() int { return len(s.rows) }
+
+// pick returns the request index for the n-th request of a sequence.
+func (s *Scenario) pick(n int) int {
+	return s.order[n%len(s.order)]
+}
+
+// request renders the idx-th request for virtual user vu.
+func (s *Scenario) request(idx, vu int) (string, shared.HTTPRequest) {
+	req := s.requests[idx]
+	if !req.dynamic {
+		return req.label, shared.HTTPRequest{Method: req.method, URL: req.url, Headers: req.headers, Body: req.body}
+	}
+
+	row := s.row(vu)
+	text := func(v string) string { return fillPlaceholders(v, row) }
+	headers := make(map[string]string, len(req.headers))
+	for k, v := range req.headers {
+		headers[k] = text(v)
+	}
+	return req.label, shared.HTTPRequest{
+		Method:  req.method,
+		URL:     text(req.url),
+		Headers: headers,
+		Body:    substituteBody(req.body, text, row),
+	}
+}
+
+// row selects the data feed row for a request.
+func (s *Scenario) row(vu int) map[string]interface{} {
+	switch s.feedMode {
+	case FeedSequential:
+		n := atomic.AddUint64(&s.feedNext, 1) - 1
+		return s.rows[n%uint64(len(s.rows))]
+	case FeedRandom:
+		return s.rows[rand.Intn(len(s.rows))]
+	default:
+		return s.rows[vu%len(s.rows)]
+	}
+}
+
+// weightedOrder spreads requests over one cycle of total weight using smooth
+// weighted round-robin, so a 3:1 mix interleaves instead of running in blocks.
+func weightedOrder(requests []preparedRequest) []int {
+	total := 0
+	for _, r := range requests {
+		total += r.weight
+	}
+	current := make([]int, len(requests))
+	order := make([]int, 0, total)
+	for len(order) < total {
+		best := 0
+		for i, r := range requests {
+			current[i] += r.weight
+			if current[i] > current[best] {
+				best = i
+			}
+		}
+		current[best] -= total
+		order = append(order, best)
+	}
+	return order
+}
+
+// fillPlaceholders replaces {{column}} with row values rendered as text.
+func fillPlaceholders(s string, row map[string]interface{}) string {
+	if !strings.Contains(s, "{{") {
+		return s
+	}
+	return shared.PlaceholderPattern.ReplaceAllStringFunc(s, func(m string) string {
+		name := shared.PlaceholderPattern.FindStringSubmatch(m)[1]
+		v, ok := row[name]
+		if !ok {
+			return m
+		}
+		return shared.Stringify(v)
+	})
+}
+
+// substituteBody returns a copy of a decoded JSON body with text replaced in
+// every string. When row is set, a string that is exactly one placeholder
+// takes the row value with its type (so {{age}} stays a number).
+func substituteBody(v interface{}, text func(string) string, row map[string]interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, child := range val {
+			out[k] = substituteBody(child, text, row)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, child := range val {
+			out[i] = substituteBody(child, text, row)
+		}
+		return out
+	case string:
+		if row != nil {
+			if name, ok := shared.PlaceholderName(val); ok {
+				if rowVal, ok := row[name]; ok {
+					return rowVal
+				}
+			}
+		}
+		return text(val)
+	default:
+		return v
+	}
+}
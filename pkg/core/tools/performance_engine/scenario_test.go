@@ -0,0 +1,182 @@
+package performance_engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/blackcoderx/falcon/pkg/storage"
+)
+
+// writeFeed writes a CSV with ids 1..n to .falcon/data/users.csv.
+func writeFeed(t *testing.T, n int) string {
+	t.Helper()
+	falconDir := filepath.Join(t.TempDir(), ".falcon")
+	if err := os.MkdirAll(filepath.Join(falconDir, "data"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	sb.WriteString("id\n")
+	for i := 1; i <= n; i++ {
+		fmt.Fprintf(&sb, "%d\n", i)
+	}
+	if err := os.WriteFile(filepath.Join(falconDir, "data", "users.csv"), []byte(sb.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return falconDir
+}
+
+func TestBuildScenario_FeedModes(t *testing.T) {
+	falconDir := writeFeed(t, 250)
+	base := PerformanceParams{
+		BaseURL:     "http://api.test",
+		Concurrency: 10,
+		Duration:    30,
+		Requests:    []RequestTemplate{{Method: "GET", Path: "/users/{{id}}"}},
+	}
+
+	cases := []struct {
+		name     string
+		mode     string
+		tweak    func(*PerformanceParams)
+		wantRows int
+	}{
+		{"sequential uses every row", FeedSequential, nil, 250},
+		{"random uses every row", FeedRandom, nil, 250},
+		{"per_vu one row per VU", FeedPerVU, nil, 10},
+		{"per_vu closed model above max_vus", FeedPerVU, func(p *PerformanceParams) { p.Concurrency = 150 }, 150},
+		{"per_vu stress peak", FeedPerVU, func(p *PerformanceParams) { p.Mode = "stress"; p.Concurrency = 30 }, 150},
+		{"per_vu open model", FeedPerVU, func(p *PerformanceParams) { p.RPS = 50; p.MaxVUs = 40 }, 40},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := base
+			params.Data = &DataFeed{Source: "users.csv", Mode: tc.mode}
+			if tc.tweak != nil {
+				tc.tweak(&params)
+			}
+			sc, err := BuildScenario(params, nil, falconDir, nil)
+			if err != nil {
+				t.Fatalf("BuildScenario: %v", err)
+			}
+			if sc.RowCount() != tc.wantRows {
+				t.Errorf("rows = %d, want %d", sc.RowCount(), tc.wantRows)
+			}
+		})
+	}
+}
+
+func TestScenario_RowSelection(t *testing.T) {
+	falconDir := writeFeed(t, 250)
+	params := PerformanceParams{
+		BaseURL:     "http://api.test",
+		Concurrency: 10,
+		Duration:    30,
+		Requests:    []RequestTemplate{{Method: "GET", Path: "/users/{{id}}"}},
+	}
+
+	params.Data = &DataFeed{Source: "users.csv", Mode: FeedSequential}
+	sc, err := BuildScenario(params, nil, falconDir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var urls []string
+	for i := 0; i < 252; i++ {
+		_, req := sc.request(0, 0)
+		urls = append(urls, req.URL)
+	}
+	if urls[0] != "http://api.test/users/1" || urls[249] != "http://api.test/users/250" || urls[250] != "http://api.test/users/1" {
+		t.Errorf("sequential: %s, %s, %s", urls[0], urls[249], urls[250])
+	}
+
+	params.Data.Mode = FeedPerVU
+	if sc, err = BuildScenario(params, nil, falconDir, nil); err != nil {
+		t.Fatal(err)
+	}
+	for vu := 0; vu < 3; vu++ {
+		_, first := sc.request(0, vu)
+		_, again := sc.request(0, vu)
+		if first.URL != again.URL || first.URL != fmt.Sprintf("http://api.test/users/%d", vu+1) {
+			t.Errorf("per_vu vu %d: %s then %s", vu, first.URL, again.URL)
+		}
+	}
+
+	params.Data.Mode = FeedRandom
+	if sc, err = BuildScenario(params, nil, falconDir, nil); err != nil {
+		t.Fatal(err)
+	}
+	seen := map[string]bool{}
+	for i := 0; i < 2000; i++ {
+		_, req := sc.request(0, 0)
+		seen[req.URL] = true
+	}
+	if len(seen) <= 100 {
+		t.Errorf("random picked only %d distinct rows", len(seen))
+	}
+}
+
+func TestWeightedOrder(t *testing.T) {
+	weights := func(ws ...int) []preparedRequest {
+		reqs := make([]preparedRequest, len(ws))
+		for i, w := range ws {
+			reqs[i].weight = w
+		}
+		return reqs
+	}
+
+	if got := weightedOrder(weights(3, 1)); !reflect.DeepEqual(got, []int{0, 0, 1, 0}) {
+		t.Errorf("3:1 = %v, want interleaved [0 0 1 0]", got)
+	}
+	if got := weightedOrder(weights(1, 1, 1)); !reflect.DeepEqual(got, []int{0, 1, 2}) {
+		t.Errorf("1:1:1 = %v", got)
+	}
+
+	order := weightedOrder(weights(5, 2, 3))
+	counts := make([]int, 3)
+	for _, idx := range order {
+		counts[idx]++
+	}
+	if len(order) != 10 || !reflect.DeepEqual(counts, []int{5, 2, 3}) {
+		t.Errorf("5:2:3 = %v (counts %v)", order, counts)
+	}
+	for i := 2; i < len(order); i++ {
+		if order[i] == order[i-1] && order[i] == order[i-2] {
+			t.Errorf("5:2:3 runs in blocks: %v", order)
+			break
+		}
+	}
+}
+
+func TestBuildScenario_SavedRequests(t *testing.T) {
+	falconDir := filepath.Join(t.TempDir(), ".falcon")
+	save := func(req storage.Request) {
+		path := filepath.Join(storage.GetRequestsDir(falconDir), storage.RequestFileName(req.Name))
+		if err := storage.SaveRequest(req, path); err != nil {
+			t.Fatalf("SaveRequest: %v", err)
+		}
+	}
+	save(storage.Request{Name: "list", Method: "GET", URL: "/users", Headers: map[string]string{"Accept": "application/json"}})
+	save(storage.Request{Name: "signed", Method: "GET", URL: "/users", Pre: []storage.PreStep{{Set: map[string]string{"sig": "x"}}}})
+	save(storage.Request{Name: "cookies", Method: "GET", URL: "/users", Session: "admin"})
+
+	cases := map[string]string{
+		"list":    "",
+		"signed":  "pre/post steps",
+		"cookies": "cookie session 'admin'",
+	}
+	for name, wantErr := range cases {
+		params := PerformanceParams{BaseURL: "http://api.test", Concurrency: 1, Duration: 1, Requests: []RequestTemplate{{Saved: name}}}
+		sc, err := BuildScenario(params, nil, falconDir, nil)
+		switch {
+		case wantErr == "" && err != nil:
+			t.Errorf("%s: %v", name, err)
+		case wantErr == "" && sc.requests[0].headers["Accept"] != "application/json":
+			t.Errorf("%s: saved headers not applied: %v", name, sc.requests[0].headers)
+		case wantErr != "" && (err == nil || !strings.Contains(err.Error(), wantErr)):
+			t.Errorf("%s: err = %v, want %q", name, err, wantErr)
+		}
+	}
+}
@@ -0,0 +1,311 @@
+package performance_engine
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// thresholdPattern parses expressions such as "p95 < 300ms", "error_rate<1%"
+// or "rps >= 100".
+var thresholdPattern = regexp.MustCompile(`^\s*([a-z0-9_]+)\s*(<=|>=|<|>)\s*([0-9]*\.?[0-9]+)\s*(ms|us|µs|s|%)?\s*$`)
+
+// Threshold is a parsed SLO expression.
+type Threshold struct {
+	Expr   string
+	Metric string
+	Op     string
+	Value  float64 // milliseconds for latency metrics, percent for rates, plain otherwise
+}
+
+// ThresholdResult is the outcome of one threshold check.
+type ThresholdResult struct {
+	Scope  string `json:"scope"` // "overall" or the endpoint key
+	Expr   string `json:"expr"`
+	Actual string `json:"actual"`
+	Passed bool   `json:"passed"`
+}
+
+// Verdict is the pass/fail decision for a run.
+type Verdict struct {
+	Passed  bool              `json:"passed"`
+	Results []ThresholdResult `json:"results"`
+}
+
+// Failed returns the checks that did not pass.
+func (v *Verdict) Failed() []ThresholdResult {
+	var failed []ThresholdResult
+	for _, r := range v.Results {
+		if !r.Passed {
+			failed = append(failed, r)
+		}
+	}
+	return failed
+}
+
+// latencyMetrics are compared in milliseconds.
+var latencyMetrics = map[string]bool{
+	"avg": true, "min": true, "max": true, "p50": true, "p95": true, "p99": true,
+	"queue_avg": true, "queue_p95": true, "queue_max": true,
+}
+
+// rateMetrics are compared in percent.
+var rateMetrics = map[string]bool{"error_rate": true, "success_rate": true}
+
+// runOnlyMetrics only exist for the whole run, not per endpoint.
+var runOnlyMetrics = map[string]bool{
+	"rps": true, "dropped": true, "queue_avg": true, "queue_p95": true, "queue_max": true,
+}
+
+// ParseThreshold parses an expression like "p95<300ms". Latency values
+// default to milliseconds; rates accept an optional "%".
+func ParseThreshold(expr string) (Threshold, error) {
+	m := thresholdPattern.FindStringSubmatch(strings.ToLower(expr))
+	if m == nil {
+		return Threshold{}, fmt.Errorf("invalid threshold '%s' (expected e.g. \"p95<300ms\", \"error_rate<1%%\", \"rps>=50\")", expr)
+	}
+
+	metric, op, unit := m[1], m[2], m[4]
+	value, err := strconv.ParseFloat(m[3], 64)
+	if err != nil {
+		return Threshold{}, fmt.Errorf("invalid threshold value in '%s': %w", expr, err)
+	}
+
+	switch {
+	case latencyMetrics[metric]:
+		switch unit {
+		case "", "ms":
+		case "s":
+			value *= 1000
+		case "us", "µs":
+			value /= 1000
+		default:
+			return Threshold{}, fmt.Errorf("threshold '%s': %s needs a time unit (ms, s, us)", expr, metric)
+		}
+	case rateMetrics[metric]:
+		if unit != "" && unit != "%" {
+			return Threshold{}, fmt.Errorf("threshold '%s': %s is a percentage", expr, metric)
+		}
+	case metric == "rps" || metric == "dropped":
+		if unit != "" {
+			return Threshold{}, fmt.Errorf("threshold '%s': %s takes a plain number", expr, metric)
+		}
+	default:
+		return Threshold{}, fmt.Errorf("threshold '%s': unknown metric '%s' (use avg, min, max, p50, p95, p99, error_rate, success_rate, rps, dropped, queue_avg, queue_p95, queue_max)", expr, metric)
+	}
+
+	return Threshold{Expr: strings.TrimSpace(expr), Metric: metric, Op: op, Value: value}, nil
+}
+
+// parseThresholds parses a list of expressions.
+func parseThresholds(exprs []string) ([]Threshold, error) {
+	thresholds := make([]Threshold, 0, len(exprs))
+	for _, expr := range exprs {
+		t, err := ParseThreshold(expr)
+		if err != nil {
+			return nil, err
+		}
+		thresholds = append(thresholds, t)
+	}
+	return thresholds, nil
+}
+
+// ValidateThresholds checks every expression before a run starts, including
+// that endpoint thresholds only use metrics measured per endpoint.
+func ValidateThresholds(params PerformanceParams) error {
+	if _, err := parseThresholds(params.Thresholds); err != nil {
+		return err
+	}
+	for ep, exprs := range params.EndpointThresholds {
+		thresholds, err := parseThresholds(exprs)
+		if err != nil {
+			return fmt.Errorf("endpoint '%s': %w", ep, err)
+		}
+		for _, t := range thresholds {
+			if runOnlyMetrics[t.Metric] {
+				return fmt.Errorf("endpoint '%s': %s is only available for the whole run", ep, t.Metric)
+			}
+		}
+	}
+	return nil
+}
+
+// EvaluateThresholds checks the run against its SLOs. Global thresholds
+// apply to the whole run. Each endpoint in endpoint_thresholds is also
+// checked on its own metrics, using its thresholds plus any global ones for
+// metrics it does not override. Returns nil when no thresholds are set.
+func EvaluateThresholds(params PerformanceParams, metrics ExecutionMetrics) (*Verdict, error) {
+	if len(params.Thresholds) == 0 && len(params.EndpointThresholds) == 0 {
+		return nil, nil
+	}
+
+	global, err := parseThresholds(params.Thresholds)
+	if err != nil {
+		return nil, err
+	}
+
+	verdict := &Verdict{Passed: true}
+	add := func(scope string, t Threshold, actual float64) {
+		passed := compare(actual, t.Op, t.Value)
+		verdict.Results = append(verdict.Results, ThresholdResult{
+			Scope:  scope,
+			Expr:   t.Expr,
+			Actual: formatActual(t.Metric, actual),
+			Passed: passed,
+		})
+		if !passed {
+			verdict.Passed = false
+		}
+	}
+
+	for _, t := range global {
+		add("overall", t, runMetricValue(t.Metric, metrics))
+	}
+
+	endpointKeys := make([]string, 0, len(params.EndpointThresholds))
+	for ep := range params.EndpointThresholds {
+		endpointKeys = append(endpointKeys, ep)
+	}
+	sort.Strings(endpointKeys)
+
+	for _, ep := range endpointKeys {
+		overrides, err := parseThresholds(params.EndpointThresholds[ep])
+		if err != nil {
+			return nil, fmt.Errorf("endpoint '%s': %w", ep, err)
+		}
+
+		em, ok := findEndpointMetrics(metrics.Endpoints, ep)
+		if !ok {
+			verdict.Results = append(verdict.Results, ThresholdResult{Scope: ep, Expr: "requests > 0", Actual: "0", Passed: false})
+			verdict.Passed = false
+			continue
+		}
+
+		overridden := make(map[string]bool)
+		for _, t := range overrides {
+			overridden[t.Metric] = true
+		}
+		checks := append([]Threshold{}, overrides...)
+		for _, t := range global {
+			if !overridden[t.Metric] && !runOnlyMetrics[t.Metric] {
+				checks = append(checks, t)
+			}
+		}
+
+		for _, t := range checks {
+			if runOnlyMetrics[t.Metric] {
+				return nil, fmt.Errorf("endpoint '%s': %s is only available for the whole run", ep, t.Metric)
+			}
+			add(ep, t, endpointMetricValue(t.Metric, em))
+		}
+	}
+
+	return verdict, nil
+}
+
+func findEndpointMetrics(endpoints []EndpointMetrics, key string) (EndpointMetrics, bool) {
+	method, path := splitEndpoint(key)
+	for _, em := range endpoints {
+		m, p := splitEndpoint(em.Endpoint)
+		if m == method && p == path {
+			return em, true
+		}
+	}
+	return EndpointMetrics{}, false
+}
+
+// splitEndpoint normalizes "METHOD /path" (GET when the method is omitted).
+func splitEndpoint(key string) (string, string) {
+	parts := strings.SplitN(strings.TrimSpace(key), " ", 2)
+	if len(parts) == 2 {
+		return strings.ToUpper(parts[0]), strings.TrimSpace(parts[1])
+	}
+	return "GET", parts[0]
+}
+
+func runMetricValue(metric string, m ExecutionMetrics) float64 {
+	switch metric {
+	case "avg":
+		return ms(m.AvgLatency)
+	case "min":
+		return ms(m.Min)
+	case "max":
+		return ms(m.Max)
+	case "p50":
+		return ms(m.P50)
+	case "p95":
+		return ms(m.P95)
+	case "p99":
+		return ms(m.P99)
+	case "queue_avg":
+		return ms(m.QueueAvg)
+	case "queue_p95":
+		return ms(m.QueueP95)
+	case "queue_max":
+		return ms(m.QueueMax)
+	case "error_rate":
+		if m.Total == 0 {
+			return 0
+		}
+		return 100 - m.SuccessRate
+	case "success_rate":
+		return m.SuccessRate
+	case "rps":
+		return m.RPS
+	case "dropped":
+		return float64(m.Dropped)
+	}
+	return 0
+}
+
+func endpointMetricValue(metric string, m EndpointMetrics) float64 {
+	switch metric {
+	case "avg":
+		return ms(m.AvgLatency)
+	case "min":
+		return ms(m.Min)
+	case "max":
+		return ms(m.Max)
+	case "p50":
+		return ms(m.P50)
+	case "p95":
+		return ms(m.P95)
+	case "p99":
+		return ms(m.P99)
+	case "error_rate":
+		return m.ErrorRate
+	case "success_rate":
+		return 100 - m.ErrorRate
+	}
+	return 0
+}
+
+func compare(actual float64, op string, want float64) bool {
+	switch op {
+	case "<":
+		return actual < want
+	case "<=":
+		return actual <= want
+	case ">":
+		return actual > want
+	case ">=":
+		return actual >= want
+	}
+	return false
+}
+
+func formatActual(metric string, v float64) string {
+	switch {
+	case latencyMetrics[metric]:
+		return time.Duration(v * float64(time.Millisecond)).Round(10 * time.Microsecond).String()
+	case rateMetrics[metric]:
+		return fmt.Sprintf("%.2f%%", v)
+	case metric == "rps":
+		return fmt.Sprintf("%.1f", v)
+	default:
+		return fmt.Sprintf("%.0f", v)
+	}
+}
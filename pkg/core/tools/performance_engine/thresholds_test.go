@@ -0,0 +1,215 @@
+package performance_engine
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+)
+
+func TestParseThreshold(t *testing.T) {
+	cases := []struct {
+		expr   string
+		metric string
+		op     string
+		value  float64
+		err    string
+	}{
+		{expr: "p95<300ms", metric: "p95", op: "<", value: 300},
+		{expr: "p99 <= 2s", metric: "p99", op: "<=", value: 2000},
+		{expr: "avg < 1.5s", metric: "avg", op: "<", value: 1500},
+		{expr: "max<500us", metric: "max", op: "<", value: 0.5},
+		{expr: "min > 5", metric: "min", op: ">", value: 5},
+		{expr: "P50 < 100MS", metric: "p50", op: "<", value: 100},
+		{expr: "error_rate<1%", metric: "error_rate", op: "<", value: 1},
+		{expr: "success_rate >= 99.5", metric: "success_rate", op: ">=", value: 99.5},
+		{expr: "rps>=50", metric: "rps", op: ">=", value: 50},
+		{expr: "dropped <= 0", metric: "dropped", op: "<=", value: 0},
+		{expr: "queue_p95 < 20ms", metric: "queue_p95", op: "<", value: 20},
+		{expr: "p95<300%", err: "needs a time unit"},
+		{expr: "error_rate<1ms", err: "is a percentage"},
+		{expr: "rps>=50%", err: "plain number"},
+		{expr: "latency<300ms", err: "unknown metric"},
+		{expr: "p95 == 300ms", err: "invalid threshold"},
+		{expr: "p95 <", err: "invalid threshold"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.expr, func(t *testing.T) {
+			got, err := ParseThreshold(tc.expr)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("err = %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseThreshold: %v", err)
+			}
+			if got.Metric != tc.metric || got.Op != tc.op || got.Value != tc.value {
+				t.Errorf("got %s %s %v, want %s %s %v", got.Metric, got.Op, got.Value, tc.metric, tc.op, tc.value)
+			}
+		})
+	}
+}
+
+func TestEvaluateThresholds(t *testing.T) {
+	metrics := ExecutionMetrics{
+		Total: 200, Success: 196, Fail: 4, SuccessRate: 98,
+		AvgLatency: 80 * time.Millisecond, Min: 5 * time.Millisecond, Max: 900 * time.Millisecond,
+		P50: 60 * time.Millisecond, P95: 250 * time.Millisecond, P99: 700 * time.Millisecond,
+		RPS: 40, Dropped: 3,
+		Endpoints: []EndpointMetrics{
+			{Endpoint: "GET /users", Total: 150, Fail: 0, ErrorRate: 0, AvgLatency: 40 * time.Millisecond,
+				Min: 5 * time.Millisecond, P50: 30 * time.Millisecond, P95: 120 * time.Millisecond, P99: 200 * time.Millisecond, Max: 300 * time.Millisecond},
+			{Endpoint: "POST /upload", Total: 50, Fail: 4, ErrorRate: 8, AvgLatency: 200 * time.Millisecond,
+				Min: 50 * time.Millisecond, P50: 150 * time.Millisecond, P95: 800 * time.Millisecond, P99: 880 * time.Millisecond, Max: 900 * time.Millisecond},
+		},
+	}
+
+	cases := []struct {
+		name      string
+		global    []string
+		endpoints map[string][]string
+		passed    bool
+		results   []string // "scope expr pass|fail"
+		err       string
+	}{
+		{
+			name:    "no thresholds",
+			passed:  true,
+			results: nil,
+		},
+		{
+			name:    "operators on the whole run",
+			global:  []string{"p95<300ms", "p99<=700ms", "min>1ms", "max>=1s", "rps>40", "error_rate<=2%", "success_rate>=98"},
+			passed:  false,
+			results: []string{"overall p95<300ms pass", "overall p99<=700ms pass", "overall min>1ms pass", "overall max>=1s fail", "overall rps>40 fail", "overall error_rate<=2% pass", "overall success_rate>=98 pass"},
+		},
+		{
+			name:    "seconds and run-only metrics",
+			global:  []string{"avg<0.1s", "dropped<=5"},
+			passed:  true,
+			results: []string{"overall avg<0.1s pass", "overall dropped<=5 pass"},
+		},
+		{
+			name:   "endpoint overrides global and inherits the rest",
+			global: []string{"p95<300ms", "error_rate<5%", "rps>=10"},
+			endpoints: map[string][]string{
+				"POST /upload": {"p95<1s"},
+				"/users":       {"min>=5ms"},
+			},
+			passed: false,
+			results: []string{
+				"overall p95<300ms pass", "overall error_rate<5% pass", "overall rps>=10 pass",
+				"/users min>=5ms pass", "/users p95<300ms pass", "/users error_rate<5% pass",
+				"POST /upload p95<1s pass", "POST /upload error_rate<5% fail",
+			},
+		},
+		{
+			name:      "endpoint without requests",
+			endpoints: map[string][]string{"DELETE /users": {"p95<1s"}},
+			passed:    false,
+			results:   []string{"DELETE /users requests > 0 fail"},
+		},
+		{
+			name:      "run-only metric per endpoint",
+			endpoints: map[string][]string{"GET /users": {"rps>10"}},
+			err:       "only available for the whole run",
+		},
+		{
+			name:   "invalid expression",
+			global: []string{"p95<fast"},
+			err:    "invalid threshold",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			params := PerformanceParams{Thresholds: tc.global, EndpointThresholds: tc.endpoints}
+			verdict, err := EvaluateThresholds(params, metrics)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("err = %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("EvaluateThresholds: %v", err)
+			}
+			if tc.results == nil {
+				if verdict != nil {
+					t.Errorf("verdict = %+v, want nil", verdict)
+				}
+				return
+			}
+			var got []string
+			for _, r := range verdict.Results {
+				status := "pass"
+				if !r.Passed {
+					status = "fail"
+				}
+				got = append(got, r.Scope+" "+r.Expr+" "+status)
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.results, "\n") {
+				t.Errorf("results:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tc.results, "\n"))
+			}
+			if verdict.Passed != tc.passed || (len(verdict.Failed()) == 0) != tc.passed {
+				t.Errorf("passed = %v (failed %v), want %v", verdict.Passed, verdict.Failed(), tc.passed)
+			}
+		})
+	}
+}
+
+func TestValidateThresholds(t *testing.T) {
+	cases := []struct {
+		name   string
+		params PerformanceParams
+		err    string
+	}{
+		{"valid", PerformanceParams{Thresholds: []string{"rps>=10", "p95<1s"}, EndpointThresholds: map[string][]string{"GET /users": {"min<5ms", "error_rate<1%"}}}, ""},
+		{"invalid global", PerformanceParams{Thresholds: []string{"p95<fast"}}, "invalid threshold"},
+		{"invalid endpoint", PerformanceParams{EndpointThresholds: map[string][]string{"GET /users": {"latency<1s"}}}, "endpoint 'GET /users': threshold"},
+		{"endpoint rps", PerformanceParams{EndpointThresholds: map[string][]string{"GET /users": {"rps>10"}}}, "endpoint 'GET /users': rps is only available for the whole run"},
+		{"endpoint dropped", PerformanceParams{EndpointThresholds: map[string][]string{"POST /orders": {"dropped<1"}}}, "dropped is only available"},
+		{"endpoint queue", PerformanceParams{EndpointThresholds: map[string][]string{"POST /orders": {"queue_p95<10ms"}}}, "queue_p95 is only available"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateThresholds(tc.params)
+			if tc.err == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("err = %v, want %q", err, tc.err)
+			}
+		})
+	}
+}
+
+func TestRun_RejectsEndpointRunOnlyMetricsBeforeSending(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer server.Close()
+
+	tool := NewPerformanceEngineTool(t.TempDir(), shared.NewHTTPTool(nil, nil), nil, nil)
+	_, err := tool.Run(PerformanceParams{
+		BaseURL:            server.URL,
+		Duration:           1,
+		Requests:           []RequestTemplate{{Method: "GET", Path: "/users"}},
+		EndpointThresholds: map[string][]string{"GET /users": {"rps>10"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "only available for the whole run") {
+		t.Fatalf("err = %v", err)
+	}
+	if n := atomic.LoadInt32(&hits); n != 0 {
+		t.Errorf("sent %d requests before rejecting the thresholds", n)
+	}
+}
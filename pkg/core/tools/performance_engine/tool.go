@@ -3,6 +3,7 @@ package performance_engine
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -14,27 +15,50 @@ import (
 type PerformanceEngineTool struct {
 	falconDir    string
 	httpTool     *shared.HTTPTool
+	varStore     *shared.VariableStore
 	reportWriter *shared.ReportWriter
 }
 
 // NewPerformanceEngineTool creates a new performance engine tool.
-func NewPerformanceEngineTool(falconDir string, httpTool *shared.HTTPTool, reportWriter *shared.ReportWriter) *PerformanceEngineTool {
+func NewPerformanceEngineTool(falconDir string, httpTool *shared.HTTPTool, varStore *shared.VariableStore, reportWriter *shared.ReportWriter) *PerformanceEngineTool {
 	return &PerformanceEngineTool{
 		falconDir:    falconDir,
 		httpTool:     httpTool,
+		varStore:     varStore,
 		reportWriter: reportWriter,
 	}
 }
 
 // PerformanceParams defines parameters for performance testing.
 type PerformanceParams struct {
-	Mode        string   `json:"mode"`                  // load, stress, spike, soak
-	BaseURL     string   `json:"base_url"`              // Base URL of the API
+	Mode        string   `json:"mode"`                   // load, stress, spike, soak
+	BaseURL     string   `json:"base_url"`               // Base URL of the API
 	Endpoints   []string `json:"endpoints,omitempty"`    // Specific endpoints to test
 	Concurrency int      `json:"concurrency,omitempty"`  // Number of concurrent virtual users (default: 10)
 	Duration    int      `json:"duration_sec,omitempty"` // Duration of test in seconds (default: 30)
-	RPS         int      `json:"rps,omitempty"`          // Target requests per second (optional)
+	RPS         int      `json:"rps,omitempty"`          // Target arrival rate; switches to the open model (optional)
+	MaxVUs      int      `json:"max_vus,omitempty"`      // Open model: max concurrent requests (default: 100)
 	ReportName  string   `json:"report_name,omitempty"`  // e.g. "performance_report_dummyjson_products"
+
+	Requests []RequestTemplate `json:"requests,omitempty"` // Weighted mix of saved or inline requests (overrides endpoints)
+	Headers  map[string]string `json:"headers,omitempty"`  // Sent with every request, e.g. {"Authorization": "Bearer {{TOKEN}}"}
+	Data     *DataFeed         `json:"data,omitempty"`     // Per-VU data feed for {{column}} placeholders
+
+	Stages        []Stage     `json:"stages,omitempty"`         // Custom load profile (overrides the mode's default stages)
+	Limits        StageLimits `json:"limits,omitempty"`         // Breaking-point thresholds checked after each stage
+	CheckpointSec int         `json:"checkpoint_sec,omitempty"` // Split long stages into checkpoints (soak default: duration/10)
+	StopOnBreach  bool        `json:"stop_on_breach,omitempty"` // Stop at the first breached stage (always on for stress)
+
+	Thresholds         []string            `json:"thresholds,omitempty"`          // SLOs for the whole run, e.g. "p95<300ms", "error_rate<1%"
+	EndpointThresholds map[string][]string `json:"endpoint_thresholds,omitempty"` // Per-endpoint SLOs; override global thresholds for the same metric
+}
+
+// PerformanceOutcome is the result of a run, including the SLO verdict.
+type PerformanceOutcome struct {
+	Result     RunResult
+	Verdict    *Verdict // nil when no thresholds were configured
+	Summary    string
+	ReportPath string
 }
 
 // Name returns the tool name.
@@ -53,9 +77,19 @@ func (t *PerformanceEngineTool) Parameters() string {
   "mode": "load|stress|spike|soak",
   "base_url": "http://localhost:3000",
   "endpoints": ["GET /api/users"],
+  "requests": [{"saved": "login", "weight": 1}, {"method": "POST", "path": "/api/orders", "body": {"sku": "{{sku}}"}, "weight": 3}],
+  "headers": {"Authorization": "Bearer {{TOKEN}}"},
+  "data": {"source": "users.csv", "mode": "per_vu"},
   "concurrency": 10,
   "duration_sec": 30,
   "rps": 50,
+  "max_vus": 100,
+  "stages": [{"name": "warm-up", "duration_sec": 10, "concurrency": 10, "ramp": true}, {"duration_sec": 30, "concurrency": 50}],
+  "limits": {"max_error_rate": 5, "max_p95_ms": 500},
+  "checkpoint_sec": 60,
+  "stop_on_breach": false,
+  "thresholds": ["p95<300ms", "error_rate<1%"],
+  "endpoint_thresholds": {"POST /api/upload": ["p95<2s"]},
   "report_name": "performance_report_<api>_<resource>"
 }`
 }
@@ -67,8 +101,19 @@ func (t *PerformanceEngineTool) Execute(args string) (string, error) {
 		return "", fmt.Errorf("failed to parse parameters: %w", err)
 	}
 
-	if params.BaseURL == "" {
-		return "", fmt.Errorf("base_url is required")
+	outcome, err := t.Run(params)
+	if err != nil {
+		return "", err
+	}
+	return outcome.Summary, nil
+}
+
+// Run executes a performance test, evaluates its thresholds and writes the
+// report. A failed verdict is not an error; callers decide what it means
+// (the CLI turns it into a non-zero exit code).
+func (t *PerformanceEngineTool) Run(params PerformanceParams) (*PerformanceOutcome, error) {
+	if params.BaseURL == "" && len(params.Requests) == 0 {
+		return nil, fmt.Errorf("base_url is required")
 	}
 
 	// Default mode is load
@@ -76,54 +121,204 @@ func (t *PerformanceEngineTool) Execute(args string) (string, error) {
 		params.Mode = "load"
 	}
 
-	// Get endpoints
-	endpoints, err := t.getEndpoints(params.Endpoints)
-	if err != nil {
-		return "", fmt.Errorf("failed to get endpoints: %w", err)
+	if err := ValidateThresholds(params); err != nil {
+		return nil, err
+	}
+
+	// Get endpoints (saved or inline requests replace them)
+	var endpoints map[string]shared.EndpointAnalysis
+	if len(params.Requests) == 0 {
+		var err error
+		endpoints, err = t.getEndpoints(params.Endpoints)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get endpoints: %w", err)
+		}
 	}
 
 	runner := NewLoadTestRunner(t.httpTool, params)
+	params = runner.params
+
+	scenario, err := BuildScenario(params, endpoints, t.falconDir, t.varStore)
+	if err != nil {
+		return nil, err
+	}
+
+	stages, err := BuildProfile(params)
+	if err != nil {
+		return nil, err
+	}
 
 	startTime := time.Now()
-	metrics := runner.Run(endpoints)
+	result := runner.Run(scenario, stages)
 	duration := time.Since(startTime)
 
-	reportContent := formatPerformanceReport(params, metrics, startTime, duration)
+	verdict, err := EvaluateThresholds(params, result.Overall)
+	if err != nil {
+		return nil, err
+	}
+
+	outcome := &PerformanceOutcome{Result: result, Verdict: verdict}
+	summary := result.Overall.FormatSummary(params.Mode) + strings.TrimRight(formatStageSummary(result), "\n")
+	if verdict != nil {
+		summary += "\n\n" + strings.TrimRight(formatVerdictSummary(verdict), "\n")
+	}
+
+	reportContent := formatPerformanceReport(params, scenario, result, verdict, startTime, duration)
 	reportPath, err := t.reportWriter.Write(params.ReportName, "performance_report", reportContent)
 	if err != nil {
-		return metrics.FormatSummary(params.Mode) + fmt.Sprintf("\n\nWarning: failed to save report: %v", err), nil
+		outcome.Summary = summary + fmt.Sprintf("\n\nWarning: failed to save report: %v", err)
+		return outcome, nil
 	}
+	outcome.ReportPath = reportPath
 
-	return metrics.FormatSummary(params.Mode) + fmt.Sprintf("\n\nReport saved to: %s", reportPath), nil
+	summary += fmt.Sprintf("\n\nReport saved to: %s", reportPath)
+	exports, err := writeExports(reportPath, params, result, verdict, startTime, duration)
+	if err != nil {
+		outcome.Summary = summary + fmt.Sprintf("\nWarning: %v", err)
+		return outcome, nil
+	}
+	outcome.Summary = summary + fmt.Sprintf("\nMetrics exported to: %s", strings.Join(exports, ", "))
+	return outcome, nil
+}
+
+// formatVerdictSummary renders the pass/fail verdict and any failed checks.
+func formatVerdictSummary(v *Verdict) string {
+	var sb strings.Builder
+	failed := v.Failed()
+	if v.Passed {
+		fmt.Fprintf(&sb, "✅ Verdict: PASS (%d/%d thresholds met)\n", len(v.Results), len(v.Results))
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "❌ Verdict: FAIL (%d/%d thresholds failed)\n", len(failed), len(v.Results))
+	for _, r := range failed {
+		fmt.Fprintf(&sb, "  ✗ [%s] %s (actual: %s)\n", r.Scope, r.Expr, r.Actual)
+	}
+	return sb.String()
+}
+
+// formatStageSummary lists per-stage results and the breaking stage, if any.
+func formatStageSummary(result RunResult) string {
+	var sb strings.Builder
+	arrival := resultIsArrivalRate(result)
+	sb.WriteString("Stages:\n")
+	for _, sr := range result.Stages {
+		icon := "✓"
+		if sr.Breached {
+			icon = "✗"
+		}
+		fmt.Fprintf(&sb, "  %s %s (%s, %ds): %d req, %.2f%% success, p95 %v\n",
+			icon, stageLabel(sr.Stage), stageLoad(sr.Stage, arrival), sr.Stage.DurationSec,
+			sr.Metrics.Total, sr.Metrics.SuccessRate, sr.Metrics.P95)
+		if sr.Metrics.Dropped > 0 {
+			fmt.Fprintf(&sb, "      Dropped: %d (no free VU)\n", sr.Metrics.Dropped)
+		}
+		if sr.Breached {
+			fmt.Fprintf(&sb, "      Breached: %s\n", strings.Join(sr.Reasons, "; "))
+		}
+	}
+
+	if result.BreachedStage != "" {
+		fmt.Fprintf(&sb, "\n⚠️ Thresholds breached at stage: %s", result.BreachedStage)
+		if result.Stopped {
+			sb.WriteString(" (run stopped)")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// stageLoad describes a stage's load: a rate in arrival-rate mode, VUs otherwise.
+func stageLoad(s Stage, arrival bool) string {
+	if arrival {
+		return fmt.Sprintf("%d rps", s.RPS)
+	}
+	return fmt.Sprintf("%d VUs", s.Concurrency)
+}
+
+// resultIsArrivalRate reports whether the run used the open model.
+func resultIsArrivalRate(result RunResult) bool {
+	stages := make([]Stage, len(result.Stages))
+	for i, sr := range result.Stages {
+		stages[i] = sr.Stage
+	}
+	return IsArrivalRate(stages)
+}
+
+// stageLabel names a stage for reports.
+func stageLabel(s Stage) string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return fmt.Sprintf("%d VUs", s.Concurrency)
 }
 
 // formatPerformanceReport builds the Markdown content for a performance report.
-func formatPerformanceReport(params PerformanceParams, metrics ExecutionMetrics, startTime time.Time, duration time.Duration) string {
+func formatPerformanceReport(params PerformanceParams, scenario *Scenario, result RunResult, verdict *Verdict, startTime time.Time, duration time.Duration) string {
 	var sb strings.Builder
+	metrics := result.Overall
 
 	fmt.Fprintf(&sb, "# Performance Test Report\n\n")
 	fmt.Fprintf(&sb, "**Date:** %s\n\n", startTime.Format(time.RFC1123))
 	fmt.Fprintf(&sb, "**Target:** %s\n\n", params.BaseURL)
 	fmt.Fprintf(&sb, "**Mode:** %s\n\n", params.Mode)
+	if verdict != nil {
+		if verdict.Passed {
+			fmt.Fprintf(&sb, "**Verdict:** ✅ PASS\n\n")
+		} else {
+			fmt.Fprintf(&sb, "**Verdict:** ❌ FAIL (%d of %d thresholds failed)\n\n", len(verdict.Failed()), len(verdict.Results))
+		}
+	}
 
 	fmt.Fprintf(&sb, "## Configuration\n\n")
 	fmt.Fprintf(&sb, "| Parameter | Value |\n|-----------|-------|\n")
 	fmt.Fprintf(&sb, "| Concurrency | %d virtual users |\n", params.Concurrency)
 	fmt.Fprintf(&sb, "| Duration | %ds |\n", params.Duration)
 	if params.RPS > 0 {
-		fmt.Fprintf(&sb, "| Target RPS | %d |\n", params.RPS)
+		fmt.Fprintf(&sb, "| Target RPS | %d (open model, max %d VUs) |\n", params.RPS, maxVUs(params))
 	}
-	if len(params.Endpoints) > 0 {
-		fmt.Fprintf(&sb, "| Endpoints | %s |\n", strings.Join(params.Endpoints, ", "))
+	fmt.Fprintf(&sb, "| Requests | %s |\n", strings.Join(scenario.Labels(), ", "))
+	if params.Data != nil && params.Data.Source != "" {
+		fmt.Fprintf(&sb, "| Data Feed | %s (%s, %d rows) |\n", params.Data.Source, scenario.feedMode, scenario.RowCount())
+	}
+	if limits := stageLimits(params); !limits.IsZero() {
+		var parts []string
+		if limits.MaxErrorRate > 0 {
+			parts = append(parts, fmt.Sprintf("error rate ≤ %.2f%%", limits.MaxErrorRate))
+		}
+		if limits.MaxP95Ms > 0 {
+			parts = append(parts, fmt.Sprintf("p95 ≤ %dms", limits.MaxP95Ms))
+		}
+		if limits.MaxP99Ms > 0 {
+			parts = append(parts, fmt.Sprintf("p99 ≤ %dms", limits.MaxP99Ms))
+		}
+		fmt.Fprintf(&sb, "| Stage Limits | %s |\n", strings.Join(parts, ", "))
 	}
 	fmt.Fprintf(&sb, "\n")
 
+	if verdict != nil {
+		fmt.Fprintf(&sb, "## Thresholds\n\n")
+		fmt.Fprintf(&sb, "| Scope | Threshold | Actual | Result |\n|-------|-----------|--------|--------|\n")
+		for _, r := range verdict.Results {
+			status := "✅ Pass"
+			if !r.Passed {
+				status = "❌ Fail"
+			}
+			fmt.Fprintf(&sb, "| %s | `%s` | %s | %s |\n", r.Scope, r.Expr, r.Actual, status)
+		}
+		fmt.Fprintf(&sb, "\n")
+	}
+
 	fmt.Fprintf(&sb, "## Results\n\n")
 	fmt.Fprintf(&sb, "| Metric | Value |\n|--------|-------|\n")
 	fmt.Fprintf(&sb, "| Total Requests | %d |\n", metrics.Total)
 	fmt.Fprintf(&sb, "| Successful | %d |\n", metrics.Success)
 	fmt.Fprintf(&sb, "| Failed | %d |\n", metrics.Fail)
 	fmt.Fprintf(&sb, "| Success Rate | %.2f%% |\n", metrics.SuccessRate)
+	fmt.Fprintf(&sb, "| Throughput | %.1f req/s |\n", metrics.RPS)
+	if metrics.TargetRPS > 0 {
+		fmt.Fprintf(&sb, "| Target Throughput | %.1f req/s |\n", metrics.TargetRPS)
+		fmt.Fprintf(&sb, "| Dropped (no free VU) | %d |\n", metrics.Dropped)
+	}
 	fmt.Fprintf(&sb, "| Test Duration | %v |\n\n", duration)
 
 	fmt.Fprintf(&sb, "## Latency\n\n")
@@ -133,7 +328,95 @@ func formatPerformanceReport(params PerformanceParams, metrics ExecutionMetrics,
 	fmt.Fprintf(&sb, "| p50 | %v |\n", metrics.P50)
 	fmt.Fprintf(&sb, "| p95 | %v |\n", metrics.P95)
 	fmt.Fprintf(&sb, "| p99 | %v |\n", metrics.P99)
-	fmt.Fprintf(&sb, "| Max | %v |\n", metrics.Max)
+	fmt.Fprintf(&sb, "| Max | %v |\n\n", metrics.Max)
+
+	if metrics.TargetRPS > 0 {
+		fmt.Fprintf(&sb, "## Queueing Delay\n\n")
+		fmt.Fprintf(&sb, "Time between a request's scheduled arrival and when a virtual user sent it. ")
+		fmt.Fprintf(&sb, "Latency above excludes this wait.\n\n")
+		fmt.Fprintf(&sb, "| Metric | Delay |\n|--------|-------|\n")
+		fmt.Fprintf(&sb, "| Avg | %v |\n", metrics.QueueAvg)
+		fmt.Fprintf(&sb, "| p95 | %v |\n", metrics.QueueP95)
+		fmt.Fprintf(&sb, "| Max | %v |\n\n", metrics.QueueMax)
+	}
+
+	if len(metrics.Endpoints) > 0 {
+		fmt.Fprintf(&sb, "## Endpoints\n\n")
+		fmt.Fprintf(&sb, "| Endpoint | Requests | Error Rate | Avg | p50 | p95 | p99 | Max | Status Codes |\n")
+		fmt.Fprintf(&sb, "|----------|----------|------------|-----|-----|-----|-----|-----|--------------|\n")
+		for _, ep := range metrics.Endpoints {
+			fmt.Fprintf(&sb, "| %s | %d | %.2f%% | %v | %v | %v | %v | %v | %s |\n",
+				ep.Endpoint, ep.Total, ep.ErrorRate, ep.AvgLatency, ep.P50, ep.P95, ep.P99, ep.Max,
+				formatStatusCounts(ep.StatusCodes))
+		}
+		fmt.Fprintf(&sb, "\n")
+	}
+
+	if len(metrics.StatusCodes) > 0 {
+		fmt.Fprintf(&sb, "## Status Codes\n\n")
+		fmt.Fprintf(&sb, "| Status | Count | Share |\n|--------|-------|-------|\n")
+		codes := make([]int, 0, len(metrics.StatusCodes))
+		for code := range metrics.StatusCodes {
+			codes = append(codes, code)
+		}
+		sort.Ints(codes)
+		for _, code := range codes {
+			label := fmt.Sprintf("%d", code)
+			if code == 0 {
+				label = "transport error"
+			}
+			n := metrics.StatusCodes[code]
+			fmt.Fprintf(&sb, "| %s | %d | %.2f%% |\n", label, n, float64(n)/float64(metrics.Total)*100)
+		}
+		fmt.Fprintf(&sb, "\n")
+	}
+
+	if len(metrics.TimeSeries) > 0 {
+		series, step := downsample(metrics.TimeSeries, maxReportWindows)
+		fmt.Fprintf(&sb, "## Time Series\n\n")
+		if step > 1 {
+			fmt.Fprintf(&sb, "Each row covers %d seconds (percentiles show the worst second). The JSON/CSV export has every second.\n\n", step)
+		}
+		fmt.Fprintf(&sb, "| Second | Requests | RPS | Error Rate | p50 | p95 | p99 |\n")
+		fmt.Fprintf(&sb, "|--------|----------|-----|------------|-----|-----|-----|\n")
+		for _, w := range series {
+			fmt.Fprintf(&sb, "| %d | %d | %.1f | %.2f%% | %v | %v | %v |\n",
+				w.Second, w.Requests, w.RPS, w.ErrorRate, w.P50, w.P95, w.P99)
+		}
+		fmt.Fprintf(&sb, "\n")
+	}
+
+	fmt.Fprintf(&sb, "## Stages\n\n")
+	arrival := resultIsArrivalRate(result)
+	fmt.Fprintf(&sb, "| Stage | Load | Duration | Requests | RPS | Success Rate | p95 | p99 | Status |\n")
+	fmt.Fprintf(&sb, "|-------|------|----------|----------|-----|--------------|-----|-----|--------|\n")
+	for _, sr := range result.Stages {
+		status := "✅ OK"
+		if sr.Breached {
+			status = "❌ " + strings.Join(sr.Reasons, "; ")
+		}
+		load := stageLoad(sr.Stage, arrival)
+		if sr.Stage.Ramp {
+			load = "→ " + load
+		}
+		rps := fmt.Sprintf("%.1f", sr.Metrics.RPS)
+		if sr.Metrics.TargetRPS > 0 {
+			rps = fmt.Sprintf("%.1f / %.1f", sr.Metrics.RPS, sr.Metrics.TargetRPS)
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %ds | %d | %s | %.2f%% | %v | %v | %s |\n",
+			stageLabel(sr.Stage), load, sr.Stage.DurationSec, sr.Metrics.Total, rps,
+			sr.Metrics.SuccessRate, sr.Metrics.P95, sr.Metrics.P99, status)
+	}
+	fmt.Fprintf(&sb, "\n")
+	if result.BreachedStage != "" {
+		fmt.Fprintf(&sb, "**Breaking point:** stage `%s`", result.BreachedStage)
+		if result.Stopped {
+			fmt.Fprintf(&sb, " (run stopped early)")
+		}
+		fmt.Fprintf(&sb, "\n")
+	} else {
+		fmt.Fprintf(&sb, "No stage breached the configured limits.\n")
+	}
 
 	return sb.String()
 }
@@ -0,0 +1,136 @@
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+)
+
+// CookiesTool manages the cookie sessions http_request keeps between calls,
+// and saves them to .falcon/sessions/ so a login survives restarts.
+type CookiesTool struct {
+	sessions *shared.CookieSessions
+}
+
+func NewCookiesTool(sessions *shared.CookieSessions) *CookiesTool {
+	return &CookiesTool{sessions: sessions}
+}
+
+type CookiesParams struct {
+	Action  string `json:"action"`            // "list", "show", "save", "load", "clear", "delete"
+	Session string `json:"session,omitempty"` // default: "default"
+}
+
+func (t *CookiesTool) Name() string { return "cookies" }
+
+func (t *CookiesTool) Description() string {
+	return "Manage cookie sessions used by http_request (session param). Actions: list (all sessions), show (cookies in a session, values masked), save (persist to .falcon/sessions/ and keep the file updated), load (restore a saved session), clear (drop its cookies), delete (forget it and remove its file)"
+}
+
+func (t *CookiesTool) Parameters() string {
+	return `{
+  "action":  "list|show|save|load|clear|delete",
+  "session": "default"
+}`
+}
+
+func (t *CookiesTool) Execute(args string) (string, error) {
+	var params CookiesParams
+	if err := json.Unmarshal([]byte(args), &params); err != nil {
+		return "", fmt.Errorf("failed to parse parameters: %w", err)
+	}
+	if params.Session == "" {
+		params.Session = shared.DefaultSession
+	}
+
+	switch params.Action {
+	case "list":
+		return t.list()
+	case "show":
+		jar, err := t.sessions.Jar(params.Session)
+		if err != nil {
+			return "", err
+		}
+		return t.show(params.Session, jar), nil
+	case "save":
+		path, err := t.sessions.Save(params.Session)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("✅ Session '%s' saved to %s; it is kept up to date as responses set cookies", params.Session, path), nil
+	case "load":
+		jar, err := t.sessions.Reload(params.Session)
+		if err != nil {
+			return "", err
+		}
+		return t.show(params.Session, jar), nil
+	case "clear":
+		jar, err := t.sessions.Jar(params.Session)
+		if err != nil {
+			return "", err
+		}
+		jar.Clear()
+		return fmt.Sprintf("✅ Cleared the cookies of session '%s'", params.Session), nil
+	case "delete":
+		if err := t.sessions.Delete(params.Session); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("✅ Deleted session '%s'", params.Session), nil
+	default:
+		return "", fmt.Errorf("unknown action '%s' (use: list, show, save, load, clear, delete)", params.Action)
+	}
+}
+
+func (t *CookiesTool) list() (string, error) {
+	names := t.sessions.Names()
+	if len(names) == 0 {
+		return "No cookie sessions yet. http_request stores cookies in the 'default' session.", nil
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Cookie sessions (%d):\n", len(names)))
+	for _, name := range names {
+		jar, err := t.sessions.Jar(name)
+		if err != nil {
+			sb.WriteString(fmt.Sprintf("  - %s (unreadable: %v)\n", name, err))
+			continue
+		}
+		saved := ""
+		if t.sessions.IsPersistent(name) {
+			saved = ", saved"
+		}
+		sb.WriteString(fmt.Sprintf("  - %s (%d cookies%s)\n", name, len(jar.All()), saved))
+	}
+	return sb.String(), nil
+}
+
+func (t *CookiesTool) show(name string, jar *shared.CookieJar) string {
+	cookies := jar.All()
+	if len(cookies) == 0 {
+		return fmt.Sprintf("Session '%s' has no cookies.", name)
+	}
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Session '%s' (%d cookies):\n", name, len(cookies)))
+	for _, c := range cookies {
+		domain := c.Domain
+		if !c.HostOnly {
+			domain = "." + domain
+		}
+		var flags []string
+		if c.Secure {
+			flags = append(flags, "Secure")
+		}
+		if c.HTTPOnly {
+			flags = append(flags, "HttpOnly")
+		}
+		if c.Expires.IsZero() {
+			flags = append(flags, "session")
+		} else {
+			flags = append(flags, "expires "+c.Expires.Local().Format(time.RFC3339))
+		}
+		sb.WriteString(fmt.Sprintf("  %s=%s  %s%s  %s\n", c.Name, shared.MaskSecret(c.Value), domain, c.Path, strings.Join(flags, ", ")))
+	}
+	return sb.String()
+}
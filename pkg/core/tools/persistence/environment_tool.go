@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
 	"github.com/blackcoderx/falcon/pkg/storage"
 )
 
@@ -20,22 +21,24 @@ func NewEnvironmentTool(manager *PersistenceManager) *EnvironmentTool {
 }
 
 type EnvironmentParams struct {
-	Action    string            `json:"action"`              // "set", "list"
-	Name      string            `json:"name,omitempty"`
-	Variables map[string]string `json:"variables,omitempty"` // optional: define env vars
+	Action    string                  `json:"action"` // "set", "list"
+	Name      string                  `json:"name,omitempty"`
+	Variables map[string]string       `json:"variables,omitempty"` // optional: define env vars
+	Transport *shared.TransportConfig `json:"transport,omitempty"` // optional: TLS, proxy and HTTP version settings
 }
 
 func (t *EnvironmentTool) Name() string { return "environment" }
 
 func (t *EnvironmentTool) Description() string {
-	return "Manage environments in .falcon/environments/. Actions: get (return the currently active environment name), set (activate environment and optionally persist its variables), list (show all available environments and which is active)"
+	return "Manage environments in .falcon/environments/. Actions: get (return the currently active environment name), set (activate environment and optionally persist its variables and transport settings: ca_cert, client_cert, client_key, insecure_skip_verify, proxy, http_version), list (show all available environments and which is active)"
 }
 
 func (t *EnvironmentTool) Parameters() string {
 	return `{
   "action": "get|set|list",
   "name":      "dev|staging|prod|...",
-  "variables": {"BASE_URL": "http://localhost:3000", "API_KEY": "{{API_KEY}}"}
+  "variables": {"BASE_URL": "http://localhost:3000", "API_KEY": "{{API_KEY}}"},
+  "transport": {"ca_cert": "certs/ca.pem", "client_cert": "certs/client.pem", "client_key": "certs/client.key", "proxy": "http://proxy:8080", "http_version": "1.1"}
 }`
 }
 
@@ -63,6 +66,9 @@ func (t *EnvironmentTool) get() (string, error) {
 	if env == "" {
 		return "No environment is currently active.", nil
 	}
+	if transport := t.manager.Transport(); !transport.IsZero() {
+		return fmt.Sprintf("Active environment: %s (transport: %s)", env, transport.Summary()), nil
+	}
 	return fmt.Sprintf("Active environment: %s", env), nil
 }
 
@@ -74,19 +80,27 @@ func (t *EnvironmentTool) set(params EnvironmentParams) (string, error) {
 	result := fmt.Sprintf("Environment set to '%s'", params.Name)
 
 	// If variables are provided, persist them first so SetEnvironment can load them
+	envPath := filepath.Join(storage.GetEnvironmentsDir(t.manager.GetBaseDir()), params.Name+".yaml")
 	if len(params.Variables) > 0 {
-		envPath := filepath.Join(storage.GetEnvironmentsDir(t.manager.GetBaseDir()), params.Name+".yaml")
 		if err := storage.SaveEnvironment(params.Variables, envPath); err != nil {
 			return "", fmt.Errorf("failed to save environment variables: %w", err)
 		}
 		result += fmt.Sprintf(" with %d variables", len(params.Variables))
 	}
+	if params.Transport != nil {
+		if err := storage.SaveEnvironmentTransport((*storage.Transport)(params.Transport), envPath); err != nil {
+			return "", fmt.Errorf("failed to save transport settings: %w", err)
+		}
+	}
 
 	// Activate the environment (loads variables into memory)
 	if err := t.manager.SetEnvironment(params.Name); err != nil {
 		// If the file doesn't exist yet and no variables were given, that's an error
 		return "", err
 	}
+	if transport := t.manager.Transport(); !transport.IsZero() {
+		result += fmt.Sprintf(" (transport: %s)", transport.Summary())
+	}
 
 	return result, nil
 }
@@ -0,0 +1,402 @@
+package persistence
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/integration_orchestrator"
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/blackcoderx/falcon/pkg/storage"
+)
+
+// maxHookDepth caps how deep pre-request steps may chain saved requests.
+const maxHookDepth = 5
+
+// RequestRunner sends saved requests with their pre-request and
+// post-response hooks. Values the hooks produce go into the variable store,
+// so later requests in the session can use them too.
+type RequestRunner struct {
+	falconDir string
+	httpTool  *shared.HTTPTool
+	varStore  *shared.VariableStore
+	env       map[string]string
+	baseURL   string
+}
+
+// NewRequestRunner creates a runner for the saved requests in falconDir.
+func NewRequestRunner(falconDir string, httpTool *shared.HTTPTool, varStore *shared.VariableStore) *RequestRunner {
+	return &RequestRunner{falconDir: falconDir, httpTool: httpTool, varStore: varStore}
+}
+
+// SetEnvironment adds variables substituted after the variable store's, such
+// as the environment selected with the environment tool.
+func (r *RequestRunner) SetEnvironment(env map[string]string) {
+	r.env = env
+}
+
+// SetBaseURL sets the base for relative request URLs. Without it, the
+// BASE_URL variable is used.
+func (r *RequestRunner) SetBaseURL(baseURL string) {
+	r.baseURL = baseURL
+}
+
+// RequestRun is the outcome of sending a saved request.
+type RequestRun struct {
+	Request  shared.HTTPRequest
+	Response *shared.HTTPResponse
+	Log      []string // one line per hook step
+	Failures []string // failed post-response assertions and extractions
+}
+
+// Format renders the hook log, the response and any failed checks.
+func (run *RequestRun) Format() string {
+	var sb strings.Builder
+	if len(run.Log) > 0 {
+		sb.WriteString("Hooks:\n")
+		for _, line := range run.Log {
+			sb.WriteString("  " + line + "\n")
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(run.Response.FormatResponse())
+	if len(run.Failures) > 0 {
+		sb.WriteString("\n\n❌ Post-response checks failed:\n")
+		for _, f := range run.Failures {
+			sb.WriteString("  ✗ " + f + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// Load reads a saved request by name.
+func (r *RequestRunner) Load(name string) (*storage.Request, error) {
+	return LoadSavedRequest(r.falconDir, name)
+}
+
+// LoadSavedRequest reads a saved request by name from falconDir.
+func LoadSavedRequest(falconDir, name string) (*storage.Request, error) {
+	requestsDir := storage.GetRequestsDir(falconDir)
+	path, err := shared.ValidatePathWithinWorkDir(storage.RequestFileName(name), requestsDir)
+	if err != nil {
+		return nil, fmt.Errorf("saved request '%s': %w", name, err)
+	}
+	req, err := storage.LoadRequest(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load saved request '%s': %w", name, err)
+	}
+	return req, nil
+}
+
+// LoadWithoutHooks reads a saved request for callers that send it
+// themselves, such as flows and performance scenarios. Requests with pre or
+// post steps are refused, since only Send runs them.
+func LoadWithoutHooks(falconDir, name string) (*storage.Request, error) {
+	req, err := LoadSavedRequest(falconDir, name)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.Pre) > 0 || req.Post != nil {
+		return nil, fmt.Errorf("saved request '%s' has pre/post steps, which only run when it is sent with the request tool, falcon --request or falcon test", name)
+	}
+	return req, nil
+}
+
+// Send runs the request's pre steps, sends it and runs its post steps. An
+// error means the request could not be sent; failed post-response checks
+// are reported in Failures.
+func (r *RequestRunner) Send(req *storage.Request) (*RequestRun, error) {
+	return r.send(req, []string{req.Name}, shared.DefaultSession)
+}
+
+// send runs a request in the cookie session it names, or else in session,
+// the one of the request that runs it as a pre step.
+func (r *RequestRunner) send(req *storage.Request, chain []string, session string) (*RequestRun, error) {
+	if req.Session != "" {
+		session = req.Session
+	}
+	run := &RequestRun{}
+	for i, step := range req.Pre {
+		label := preStepLabel(step, i)
+		msg, err := r.runPreStep(req, step, chain, session)
+		if err != nil {
+			return run, fmt.Errorf("pre-request step '%s': %w", label, err)
+		}
+		run.Log = append(run.Log, fmt.Sprintf("pre  %s: %s", label, msg))
+	}
+
+	httpReq, err := r.Build(req)
+	if err != nil {
+		return run, err
+	}
+	httpReq.Session = session
+	if session == shared.NoSession {
+		httpReq.Session = ""
+	}
+	run.Request = httpReq
+
+	resp, err := r.httpTool.Run(httpReq)
+	if err != nil {
+		return run, err
+	}
+	run.Response = resp
+
+	if req.Post != nil {
+		for _, name := range sortedKeys(req.Post.Extract) {
+			val, err := integration_orchestrator.ExtractValue(resp, req.Post.Extract[name])
+			if err != nil {
+				run.Failures = append(run.Failures, fmt.Sprintf("extract '%s': %v", name, err))
+				continue
+			}
+			r.varStore.Set(name, shared.Stringify(val))
+			run.Log = append(run.Log, fmt.Sprintf("post extract: {{%s}} = %s", name, shared.MaskSecret(shared.Stringify(val))))
+		}
+		if req.Post.Assert != nil {
+			var expected shared.TestExpectation
+			data, _ := json.Marshal(req.Post.Assert)
+			if err := json.Unmarshal([]byte(r.substitute(string(data))), &expected); err != nil {
+				return run, fmt.Errorf("invalid post assert: %w", err)
+			}
+			failures := shared.ValidateExpectations(expected, resp, resp.Duration.Milliseconds())
+			run.Failures = append(run.Failures, failures...)
+			if len(failures) == 0 {
+				run.Log = append(run.Log, "post assert: passed")
+			}
+		}
+	}
+	return run, nil
+}
+
+// runPreStep performs one pre-request step and describes what it did.
+func (r *RequestRunner) runPreStep(req *storage.Request, step storage.PreStep, chain []string, session string) (string, error) {
+	switch {
+	case step.Request != "":
+		if len(chain) >= maxHookDepth {
+			return "", fmt.Errorf("saved requests chained more than %d deep", maxHookDepth)
+		}
+		for _, name := range chain {
+			if strings.EqualFold(name, step.Request) {
+				return "", fmt.Errorf("request cycle: %s -> %s", strings.Join(chain, " -> "), step.Request)
+			}
+		}
+		dep, err := r.Load(step.Request)
+		if err != nil {
+			return "", err
+		}
+		run, err := r.send(dep, append(chain, step.Request), session)
+		if err != nil {
+			return "", err
+		}
+		if len(run.Failures) > 0 {
+			return "", fmt.Errorf("%s", strings.Join(run.Failures, "; "))
+		}
+		if dep.Post == nil || dep.Post.Assert == nil {
+			if err := integration_orchestrator.CheckResponse(nil, run.Response); err != nil {
+				return "", err
+			}
+		}
+		var saved []string
+		for _, name := range sortedKeys(step.Extract) {
+			val, err := integration_orchestrator.ExtractValue(run.Response, step.Extract[name])
+			if err != nil {
+				return "", fmt.Errorf("extract '%s': %w", name, err)
+			}
+			r.varStore.Set(name, shared.Stringify(val))
+			saved = append(saved, "{{"+name+"}}")
+		}
+		msg := fmt.Sprintf("%s %s -> HTTP %d", run.Request.Method, run.Request.URL, run.Response.StatusCode)
+		if len(saved) > 0 {
+			msg += ", saved " + strings.Join(saved, ", ")
+		}
+		return msg, nil
+
+	case step.Token != nil:
+		return r.fetchToken(*step.Token)
+
+	case step.Sign != nil:
+		return r.sign(req, *step.Sign)
+
+	case step.Set != nil:
+		var saved []string
+		for _, name := range sortedKeys(step.Set) {
+			r.varStore.Set(name, r.substitute(step.Set[name]))
+			saved = append(saved, "{{"+name+"}}")
+		}
+		return "set " + strings.Join(saved, ", "), nil
+	}
+	return "", fmt.Errorf("step needs one of request, token, sign or set")
+}
+
+// fetchToken runs an OAuth2 flow through the auth_oauth2 tool, which stores
+// the token and its Bearer header.
+func (r *RequestRunner) fetchToken(step storage.TokenStep) (string, error) {
+	if step.Flow == "" {
+		step.Flow = "client_credentials"
+	}
+	if step.SaveAs == "" {
+		step.SaveAs = "access_token"
+	}
+	args, _ := json.Marshal(shared.OAuth2Params{
+		Flow:         step.Flow,
+		TokenURL:     step.TokenURL,
+		ClientID:     step.ClientID,
+		ClientSecret: step.ClientSecret,
+		Username:     step.Username,
+		Password:     step.Password,
+		Scopes:       step.Scopes,
+		SaveTokenAs:  step.SaveAs,
+	})
+	if _, err := shared.NewOAuth2Tool(r.varStore).Execute(r.substitute(string(args))); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s token saved as {{%s}} and {{%s_header}}", step.Flow, step.SaveAs, step.SaveAs), nil
+}
+
+// sign computes an HMAC signature over the payload, with the request as it
+// would be sent at this point available as {{request.*}}.
+func (r *RequestRunner) sign(req *storage.Request, step storage.SignStep) (string, error) {
+	if step.SaveAs == "" {
+		return "", fmt.Errorf("sign needs save_as")
+	}
+	var newHash func() hash.Hash
+	switch strings.ToLower(step.Algorithm) {
+	case "", "hmac-sha256":
+		newHash = sha256.New
+	case "hmac-sha1":
+		newHash = sha1.New
+	case "hmac-sha512":
+		newHash = sha512.New
+	default:
+		return "", fmt.Errorf("unknown algorithm '%s' (use hmac-sha256, hmac-sha1 or hmac-sha512)", step.Algorithm)
+	}
+
+	r.varStore.Set("timestamp", strconv.FormatInt(time.Now().Unix(), 10))
+	payload := step.Payload
+	if strings.Contains(payload, "{{request.") {
+		httpReq, err := r.Build(req)
+		if err != nil {
+			return "", err
+		}
+		target, err := httpReq.FullURL()
+		if err != nil {
+			return "", err
+		}
+		// A multipart body gets a new boundary when sent, so it cannot be signed
+		var body []byte
+		if httpReq.BodyKind() != shared.BodyTypeMultipart {
+			if body, _, err = httpReq.EncodeBody(); err != nil {
+				return "", err
+			}
+		}
+		path := target
+		if u, err := url.Parse(target); err == nil {
+			path = u.RequestURI()
+		}
+		payload = strings.NewReplacer(
+			"{{request.method}}", httpReq.Method,
+			"{{request.url}}", target,
+			"{{request.path}}", path,
+			"{{request.body}}", string(body),
+		).Replace(payload)
+	}
+
+	mac := hmac.New(newHash, []byte(r.substitute(step.Secret)))
+	mac.Write([]byte(r.substitute(payload)))
+	sum := mac.Sum(nil)
+
+	var signature string
+	switch strings.ToLower(step.Encoding) {
+	case "", "hex":
+		signature = hex.EncodeToString(sum)
+	case "base64":
+		signature = base64.StdEncoding.EncodeToString(sum)
+	default:
+		return "", fmt.Errorf("unknown encoding '%s' (use hex or base64)", step.Encoding)
+	}
+	r.varStore.Set(step.SaveAs, signature)
+	return fmt.Sprintf("signature saved as {{%s}}", step.SaveAs), nil
+}
+
+// Build substitutes variables and resolves a relative URL against the base
+// URL.
+func (r *RequestRunner) Build(req *storage.Request) (shared.HTTPRequest, error) {
+	var httpReq shared.HTTPRequest
+
+	reqJSON, err := json.Marshal(shared.HTTPRequest{
+		Method:   req.Method,
+		URL:      req.URL,
+		Headers:  req.Headers,
+		Query:    req.Query,
+		Body:     req.Body,
+		BodyType: req.BodyType,
+		BodyFile: req.BodyFile,
+
+		FollowRedirects: req.FollowRedirects,
+		MaxRedirects:    req.MaxRedirects,
+		Transport:       (*shared.TransportConfig)(req.Transport),
+	})
+	if err != nil {
+		return httpReq, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	if err := json.Unmarshal([]byte(r.substitute(string(reqJSON))), &httpReq); err != nil {
+		return httpReq, fmt.Errorf("failed to substitute variables: %w", err)
+	}
+
+	if !strings.HasPrefix(httpReq.URL, "http://") && !strings.HasPrefix(httpReq.URL, "https://") {
+		baseURL := r.baseURL
+		if baseURL == "" {
+			baseURL = r.substitute("{{BASE_URL}}")
+		}
+		if strings.Contains(baseURL, "{{") {
+			return httpReq, fmt.Errorf("relative URL '%s' needs a base URL (set BASE_URL)", httpReq.URL)
+		}
+		httpReq.URL = strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(httpReq.URL, "/")
+	}
+	if strings.Contains(httpReq.URL, "{{") {
+		return httpReq, fmt.Errorf("unresolved variable in URL '%s'", httpReq.URL)
+	}
+	return httpReq, nil
+}
+
+// substitute replaces {{VAR}} with session and global variables, then the
+// environment and {{env:VAR}} references.
+func (r *RequestRunner) substitute(text string) string {
+	return storage.SubstituteVariables(r.varStore.Substitute(text), r.env)
+}
+
+// preStepLabel names a pre-request step in logs and errors.
+func preStepLabel(step storage.PreStep, i int) string {
+	switch {
+	case step.Name != "":
+		return step.Name
+	case step.Request != "":
+		return step.Request
+	case step.Token != nil:
+		return "token"
+	case step.Sign != nil:
+		return "sign"
+	case step.Set != nil:
+		return "set"
+	}
+	return fmt.Sprintf("#%d", i+1)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,148 @@
+package persistence
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/blackcoderx/falcon/pkg/storage"
+)
+
+func saveTestRequest(t *testing.T, dir string, req storage.Request) {
+	t.Helper()
+	path := filepath.Join(storage.GetRequestsDir(dir), storage.RequestFileName(req.Name))
+	if err := storage.SaveRequest(req, path); err != nil {
+		t.Fatalf("SaveRequest: %v", err)
+	}
+}
+
+func TestRequestRunner_PreAndPostHooks(t *testing.T) {
+	var gotSignature, gotTimestamp string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "abc"})
+	})
+	mux.HandleFunc("/orders", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		gotSignature, gotTimestamp = r.Header.Get("X-Signature"), r.Header.Get("X-Timestamp")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": 42})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	dir := t.TempDir()
+	saveTestRequest(t, dir, storage.Request{Name: "login", Method: "POST", URL: "/login"})
+	saveTestRequest(t, dir, storage.Request{
+		Name:   "create-order",
+		Method: "POST",
+		URL:    "/orders",
+		Headers: map[string]string{
+			"Authorization": "Bearer {{token}}",
+			"X-Signature":   "{{signature}}",
+			"X-Timestamp":   "{{timestamp}}",
+		},
+		Body: map[string]interface{}{"item": "book"},
+		Pre: []storage.PreStep{
+			{Request: "login", Extract: map[string]string{"token": "$.access_token"}},
+			{Sign: &storage.SignStep{Secret: "s3cret", Payload: "{{timestamp}}{{request.method}}{{request.path}}", SaveAs: "signature"}},
+		},
+		Post: &storage.PostSteps{
+			Extract: map[string]string{"order_id": "$.id"},
+			Assert:  map[string]interface{}{"status_code": 201},
+		},
+	})
+
+	varStore := shared.NewVariableStore(dir)
+	runner := NewRequestRunner(dir, shared.NewHTTPTool(nil, nil), varStore)
+	runner.SetBaseURL(server.URL)
+	req, err := runner.Load("create-order")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	run, err := runner.Send(req)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if run.Response.StatusCode != http.StatusCreated || len(run.Failures) > 0 {
+		t.Fatalf("status = %d, failures = %v", run.Response.StatusCode, run.Failures)
+	}
+	if id, _ := varStore.Get("order_id"); id != "42" {
+		t.Errorf("order_id = %q, want 42", id)
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(gotTimestamp + "POST/orders"))
+	if want := hex.EncodeToString(mac.Sum(nil)); gotSignature != want {
+		t.Errorf("signature = %q, want %q", gotSignature, want)
+	}
+	if out := run.Format(); !strings.Contains(out, "saved {{token}}") || !strings.Contains(out, "post assert: passed") {
+		t.Errorf("hook log missing steps:\n%s", out)
+	}
+}
+
+func TestRequestRunner_PostAssertFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	runner := NewRequestRunner(dir, shared.NewHTTPTool(nil, nil), shared.NewVariableStore(dir))
+	run, err := runner.Send(&storage.Request{
+		Name:   "health",
+		Method: "GET",
+		URL:    server.URL,
+		Post:   &storage.PostSteps{Assert: map[string]interface{}{"status_code": 204}},
+	})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(run.Failures) != 1 {
+		t.Errorf("failures = %v, want the status code mismatch", run.Failures)
+	}
+}
+
+func TestRequestRunner_Cycle(t *testing.T) {
+	dir := t.TempDir()
+	saveTestRequest(t, dir, storage.Request{Name: "a", Method: "GET", URL: "http://localhost/a", Pre: []storage.PreStep{{Request: "b"}}})
+	saveTestRequest(t, dir, storage.Request{Name: "b", Method: "GET", URL: "http://localhost/b", Pre: []storage.PreStep{{Request: "a"}}})
+
+	runner := NewRequestRunner(dir, shared.NewHTTPTool(nil, nil), shared.NewVariableStore(dir))
+	req, err := runner.Load("a")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if _, err := runner.Send(req); err == nil || !strings.Contains(err.Error(), "request cycle: a -> b -> a") {
+		t.Errorf("err = %v, want a request cycle", err)
+	}
+}
+
+func TestLoadWithoutHooks(t *testing.T) {
+	dir := t.TempDir()
+	saveTestRequest(t, dir, storage.Request{Name: "plain", Method: "GET", URL: "/a"})
+	saveTestRequest(t, dir, storage.Request{Name: "pre", Method: "GET", URL: "/a", Pre: []storage.PreStep{{Set: map[string]string{"x": "1"}}}})
+	saveTestRequest(t, dir, storage.Request{Name: "post", Method: "GET", URL: "/a", Post: &storage.PostSteps{Extract: map[string]string{"id": "$.id"}}})
+
+	if _, err := LoadWithoutHooks(dir, "plain"); err != nil {
+		t.Errorf("plain: %v", err)
+	}
+	for _, name := range []string{"pre", "post"} {
+		if _, err := LoadWithoutHooks(dir, name); err == nil || !strings.Contains(err.Error(), "pre/post steps") {
+			t.Errorf("%s: err = %v, want the hooks to be refused", name, err)
+		}
+	}
+	if _, err := LoadWithoutHooks(dir, "missing"); err == nil {
+		t.Error("missing: expected an error")
+	}
+}
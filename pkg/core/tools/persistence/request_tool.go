@@ -12,38 +12,67 @@ import (
 )
 
 // RequestTool is the unified request management tool that replaces save_request,
-// load_request, and list_requests.
+// load_request, and list_requests. It also runs saved requests with their
+// pre-request and post-response hooks.
 type RequestTool struct {
-	manager *PersistenceManager
+	manager         *PersistenceManager
+	runner          *RequestRunner
+	responseManager *shared.ResponseManager
 }
 
-func NewRequestTool(manager *PersistenceManager) *RequestTool {
-	return &RequestTool{manager: manager}
+func NewRequestTool(manager *PersistenceManager, httpTool *shared.HTTPTool, responseManager *shared.ResponseManager, varStore *shared.VariableStore) *RequestTool {
+	return &RequestTool{
+		manager:         manager,
+		runner:          NewRequestRunner(manager.GetBaseDir(), httpTool, varStore),
+		responseManager: responseManager,
+	}
 }
 
 type RequestParams struct {
-	Action  string            `json:"action"`           // "save", "load", "list"
-	Name    string            `json:"name,omitempty"`
-	Method  string            `json:"method,omitempty"`
-	URL     string            `json:"url,omitempty"`
-	Headers map[string]string `json:"headers,omitempty"`
-	Body    interface{}       `json:"body,omitempty"`
+	Action   string            `json:"action"` // "save", "load", "run", "list"
+	Name     string            `json:"name,omitempty"`
+	Method   string            `json:"method,omitempty"`
+	URL      string            `json:"url,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Query    map[string]string `json:"query,omitempty"`
+	Body     interface{}       `json:"body,omitempty"`
+	BodyType string            `json:"body_type,omitempty"` // json, form, multipart, text, xml or binary
+	BodyFile string            `json:"body_file,omitempty"` // File sent as the body
+	Tags     []string          `json:"tags,omitempty"`      // Labels for falcon test --tag
+	Session  string            `json:"session,omitempty"`   // Cookie session (default: "default")
+
+	FollowRedirects *bool                  `json:"follow_redirects,omitempty"`
+	MaxRedirects    int                    `json:"max_redirects,omitempty"`
+	Transport       *storage.Transport     `json:"transport,omitempty"`
+	Expect          map[string]interface{} `json:"expect,omitempty"` // Checks for falcon test (status_code, body_contains, ...)
+	Pre             []storage.PreStep      `json:"pre,omitempty"`    // Steps run before sending (request, token, sign, set)
+	Post            *storage.PostSteps     `json:"post,omitempty"`   // Extractions and assertions run on the response
 }
 
 func (t *RequestTool) Name() string { return "request" }
 
 func (t *RequestTool) Description() string {
-	return "Manage saved HTTP requests in .falcon/requests/. Actions: save (persist a request template), load (retrieve and substitute env vars), list (show all saved requests)"
+	return "Manage saved HTTP requests in .falcon/requests/. Actions: save (persist a request template, optionally with pre-request steps and post-response extractions/assertions), load (retrieve and substitute env vars), run (send it: pre steps, request, post steps; extracted values become {{variables}}), list (show all saved requests)"
 }
 
 func (t *RequestTool) Parameters() string {
 	return `{
-  "action": "save|load|list",
-  "name":   "string — request name (required for save/load)",
+  "action": "save|load|run|list",
+  "name":   "string — request name (required for save/load/run)",
   "method": "GET|POST|PUT|DELETE|PATCH (required for save)",
   "url":    "https://... or /path (required for save, supports {{VAR}})",
   "headers": {},
-  "body":    {}
+  "query":   {"page": "1"},
+  "body":    {},
+  "body_type": "json|form|multipart|text|xml|binary",
+  "body_file": "fixtures/avatar.png",
+  "tags":    ["smoke"],
+  "session": "admin",
+  "follow_redirects": false,
+  "transport": {"client_cert": "certs/client.pem", "client_key": "certs/client.key", "http_version": "1.1"},
+  "expect":  {"status_code": 200},
+  "pre":     [{"request": "login", "extract": {"token": "$.access_token"}}, {"sign": {"secret": "{{API_SECRET}}", "payload": "{{timestamp}}{{request.body}}", "save_as": "signature"}}],
+  "post":    {"extract": {"user_id": "$.id"}, "assert": {"status_code": 201}}
 }`
 }
 
@@ -58,10 +87,12 @@ func (t *RequestTool) Execute(args string) (string, error) {
 		return t.save(params)
 	case "load":
 		return t.load(params)
+	case "run":
+		return t.run(params)
 	case "list":
 		return t.list()
 	default:
-		return "", fmt.Errorf("unknown action '%s' (use: save, load, list)", params.Action)
+		return "", fmt.Errorf("unknown action '%s' (use: save, load, run, list)", params.Action)
 	}
 }
 
@@ -79,13 +110,30 @@ func (t *RequestTool) save(params RequestParams) (string, error) {
 	if secretErr := shared.ValidateRequestForSecrets(params.URL, params.Headers, params.Body); secretErr != "" {
 		return "", fmt.Errorf("cannot save request: %s", secretErr)
 	}
+	for key, value := range params.Query {
+		if shared.HasPlaintextSecret(value) {
+			return "", fmt.Errorf("cannot save request: query parameter '%s' contains plaintext secret. Use {{VAR}} placeholder instead", key)
+		}
+	}
 
 	req := storage.Request{
-		Name:    params.Name,
-		Method:  strings.ToUpper(params.Method),
-		URL:     params.URL,
-		Headers: params.Headers,
-		Body:    params.Body,
+		Name:     params.Name,
+		Method:   strings.ToUpper(params.Method),
+		URL:      params.URL,
+		Headers:  params.Headers,
+		Query:    params.Query,
+		Body:     params.Body,
+		BodyType: params.BodyType,
+		BodyFile: params.BodyFile,
+		Tags:     params.Tags,
+		Session:  params.Session,
+
+		FollowRedirects: params.FollowRedirects,
+		MaxRedirects:    params.MaxRedirects,
+		Transport:       params.Transport,
+		Expect:          params.Expect,
+		Pre:             params.Pre,
+		Post:            params.Post,
 	}
 
 	filename := strings.ToLower(strings.ReplaceAll(params.Name, " ", "-")) + ".yaml"
@@ -112,12 +160,7 @@ func (t *RequestTool) load(params RequestParams) (string, error) {
 		return "", fmt.Errorf("name is required for load")
 	}
 
-	filename := params.Name
-	if !strings.HasSuffix(filename, ".yaml") && !strings.HasSuffix(filename, ".yml") {
-		filename = strings.ToLower(strings.ReplaceAll(filename, " ", "-")) + ".yaml"
-	}
-
-	filePath := filepath.Join(storage.GetRequestsDir(t.manager.GetBaseDir()), filename)
+	filePath := filepath.Join(storage.GetRequestsDir(t.manager.GetBaseDir()), storage.RequestFileName(params.Name))
 	req, err := storage.LoadRequest(filePath)
 	if err != nil {
 		return "", err
@@ -125,17 +168,66 @@ func (t *RequestTool) load(params RequestParams) (string, error) {
 
 	applied := storage.ApplyEnvironment(req, t.manager.GetEnvironment())
 
-	result, _ := json.MarshalIndent(map[string]interface{}{
+	loaded := map[string]interface{}{
 		"name":    applied.Name,
 		"method":  applied.Method,
 		"url":     applied.URL,
 		"headers": applied.Headers,
 		"body":    applied.Body,
-	}, "", "  ")
+	}
+	if len(applied.Query) > 0 {
+		loaded["query"] = applied.Query
+	}
+	if applied.BodyType != "" {
+		loaded["body_type"] = applied.BodyType
+	}
+	if applied.BodyFile != "" {
+		loaded["body_file"] = applied.BodyFile
+	}
+	if applied.Session != "" {
+		loaded["session"] = applied.Session
+	}
+	if applied.FollowRedirects != nil {
+		loaded["follow_redirects"] = *applied.FollowRedirects
+	}
+	if applied.MaxRedirects > 0 {
+		loaded["max_redirects"] = applied.MaxRedirects
+	}
+	if applied.Transport != nil {
+		loaded["transport"] = applied.Transport
+	}
+	if len(applied.Pre) > 0 || applied.Post != nil {
+		// http_request would skip them; action=run executes them
+		loaded["pre"] = applied.Pre
+		loaded["post"] = applied.Post
+	}
+	result, _ := json.MarshalIndent(loaded, "", "  ")
 
 	return string(result), nil
 }
 
+func (t *RequestTool) run(params RequestParams) (string, error) {
+	if params.Name == "" {
+		return "", fmt.Errorf("name is required for run")
+	}
+
+	req, err := t.runner.Load(params.Name)
+	if err != nil {
+		return "", err
+	}
+	t.runner.SetEnvironment(t.manager.GetEnvironment())
+
+	run, err := t.runner.Send(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to run request '%s': %w", params.Name, err)
+	}
+	if t.responseManager != nil {
+		t.responseManager.SetHTTPResponse(run.Response)
+	}
+
+	return run.Format(), nil
+}
+
 func (t *RequestTool) list() (string, error) {
 	requests, err := storage.ListRequests(t.manager.GetBaseDir())
 	if err != nil {
@@ -3,6 +3,7 @@ package persistence
 import (
 	"path/filepath"
 
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
 	"github.com/blackcoderx/falcon/pkg/storage"
 )
 
@@ -12,6 +13,7 @@ type PersistenceManager struct {
 	baseDir     string
 	currentEnv  string
 	environment map[string]string
+	transport   *storage.Transport
 }
 
 // NewPersistenceManager creates a new persistence manager
@@ -30,11 +32,25 @@ func (pm *PersistenceManager) SetEnvironment(name string) error {
 	if err != nil {
 		return err
 	}
+	transport, err := storage.LoadEnvironmentTransport(envPath)
+	if err != nil {
+		return err
+	}
 	pm.currentEnv = name
 	pm.environment = env
+	pm.transport = transport
 	return nil
 }
 
+// Transport returns the TLS, proxy and HTTP version settings of the current
+// environment. HTTPTool reads it before each request.
+func (pm *PersistenceManager) Transport() shared.TransportConfig {
+	if pm.transport == nil {
+		return shared.TransportConfig{}
+	}
+	return shared.TransportConfig(*pm.transport)
+}
+
 // GetEnvironment returns the current environment variables
 func (pm *PersistenceManager) GetEnvironment() map[string]string {
 	return pm.environment
@@ -5,6 +5,7 @@ import (
 	falconagent "github.com/blackcoderx/falcon/pkg/core/tools/agent"
 	"github.com/blackcoderx/falcon/pkg/core/tools/data_driven_engine"
 	"github.com/blackcoderx/falcon/pkg/core/tools/debugging"
+	"github.com/blackcoderx/falcon/pkg/core/tools/flow_runner"
 	"github.com/blackcoderx/falcon/pkg/core/tools/functional_test_generator"
 	"github.com/blackcoderx/falcon/pkg/core/tools/idempotency_verifier"
 	"github.com/blackcoderx/falcon/pkg/core/tools/integration_orchestrator"
@@ -75,6 +76,9 @@ func (r *Registry) initServices() {
 	r.VariableStore = shared.NewVariableStore(r.FalconDir)
 	r.PersistManager = persistence.NewPersistenceManager(r.FalconDir)
 	r.HTTPTool = shared.NewHTTPTool(r.ResponseManager, r.VariableStore)
+	r.HTTPTool.SetContractChecker(shared.NewContractChecker(r.FalconDir))
+	r.HTTPTool.SetCookieSessions(shared.NewCookieSessions(r.FalconDir))
+	r.HTTPTool.SetTransportSource(r.PersistManager.Transport)
 }
 
 // registerSharedTools registers foundational tools (HTTP, Assertions, Auth, etc).
@@ -128,6 +132,7 @@ func (r *Registry) registerDebuggingTools() {
 	r.Agent.RegisterTool(debugging.NewAnalyzeFailureTool(r.LLMClient))
 	r.Agent.RegisterTool(debugging.NewProposeFixTool(r.LLMClient, r.WorkDir))
 	r.Agent.RegisterTool(debugging.NewCreateTestFileTool(r.LLMClient))
+	r.Agent.RegisterTool(debugging.NewSpecDriftTool(r.WorkDir, r.FalconDir, shared.NewReportWriter(r.FalconDir)))
 }
 
 // registerPersistenceTools registers tools for saving state and requests.
@@ -136,10 +141,13 @@ func (r *Registry) registerPersistenceTools() {
 	r.Agent.RegisterTool(persistence.NewVariableTool(r.VariableStore))
 
 	// unified request management (replaces save_request, load_request, list_requests)
-	r.Agent.RegisterTool(persistence.NewRequestTool(r.PersistManager))
+	r.Agent.RegisterTool(persistence.NewRequestTool(r.PersistManager, r.HTTPTool, r.ResponseManager, r.VariableStore))
 
 	// unified environment management (replaces set_environment, list_environments)
 	r.Agent.RegisterTool(persistence.NewEnvironmentTool(r.PersistManager))
+
+	// cookie sessions kept by http_request, saved to .falcon/sessions/
+	r.Agent.RegisterTool(persistence.NewCookiesTool(r.HTTPTool.CookieSessions()))
 }
 
 // registerAgentTools registers memory, reporting, and orchestration tools.
@@ -193,7 +201,7 @@ func (r *Registry) registerSecurityScannerTools() {
 // registerPerformanceEngineTools registers the multi-mode performance engine.
 func (r *Registry) registerPerformanceEngineTools() {
 	reportWriter := shared.NewReportWriter(r.FalconDir)
-	r.Agent.RegisterTool(performance_engine.NewPerformanceEngineTool(r.FalconDir, r.HTTPTool, reportWriter))
+	r.Agent.RegisterTool(performance_engine.NewPerformanceEngineTool(r.FalconDir, r.HTTPTool, r.VariableStore, reportWriter))
 }
 
 // registerModuleTools registers high-level capability modules.
@@ -208,5 +216,6 @@ func (r *Registry) registerModuleTools() {
 // registerWorkflowTools registers integration and regression modules.
 func (r *Registry) registerWorkflowTools() {
 	r.Agent.RegisterTool(integration_orchestrator.NewIntegrationOrchestratorTool(r.FalconDir, r.HTTPTool))
-	r.Agent.RegisterTool(regression_watchdog.NewRegressionWatchdogTool(r.FalconDir, r.HTTPTool))
+	r.Agent.RegisterTool(flow_runner.NewFlowRunnerTool(r.FalconDir, r.HTTPTool, r.VariableStore, r.PersistManager, shared.NewReportWriter(r.FalconDir)))
+	r.Agent.RegisterTool(regression_watchdog.NewRegressionWatchdogTool(r.FalconDir, r.HTTPTool, r.VariableStore))
 }
@@ -5,12 +5,32 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
 )
 
+// versionTimeFormat names versions captured without an explicit label.
+const versionTimeFormat = "20060102-150405"
+
+// validBaselineName restricts baseline names and versions to safe file names.
+var validBaselineName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// indexFile records the promoted version; no version may be named after it.
+const indexFile = "index.json"
+
 // BaselineStore manages the persistence of API snapshots.
+//
+// Each baseline name is a directory holding one file per version:
+//
+//	.falcon/baselines/<name>/<version>.json
+//	.falcon/baselines/<name>/index.json   (which version is promoted)
+//
+// Baselines written before versioning (.falcon/baselines/<name>.json) are
+// still loaded when no versioned directory exists.
 type BaselineStore struct {
 	FalconDir string
 }
@@ -18,8 +38,41 @@ type BaselineStore struct {
 // APIBaseline represents a stored snapshot of API behavior.
 type APIBaseline struct {
 	Name      string                         `json:"name"`
+	Version   string                         `json:"version,omitempty"`
 	CreatedAt time.Time                      `json:"created_at"`
+	BaseURL   string                         `json:"base_url,omitempty"`
 	Snapshots map[string]shared.HTTPResponse `json:"snapshots"`
+
+	// Requests records the request used for each snapshot, keyed like
+	// Snapshots ("METHOD /path"). URLs are stored relative to the base URL
+	// so the baseline can be replayed against another environment.
+	Requests map[string]shared.HTTPRequest `json:"requests,omitempty"`
+
+	// IgnoreRules are JSONPath globs (e.g. "$.data[*].updated_at") whose
+	// values are expected to change between runs and are never diffed.
+	IgnoreRules []string `json:"ignore_rules,omitempty"`
+	// LatencyTolerance is the allowed slowdown relative to the recorded
+	// duration (0.5 = 50% slower). Zero uses the default.
+	LatencyTolerance float64 `json:"latency_tolerance,omitempty"`
+}
+
+// BaselineVersion summarizes one stored version of a baseline.
+type BaselineVersion struct {
+	Version   string    `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Endpoints int       `json:"endpoints"`
+	Promoted  bool      `json:"promoted"`
+}
+
+// BaselineInfo summarizes a baseline name and its versions (newest first).
+type BaselineInfo struct {
+	Name     string            `json:"name"`
+	Versions []BaselineVersion `json:"versions"`
+}
+
+// baselineIndex is persisted as index.json next to a baseline's versions.
+type baselineIndex struct {
+	Promoted string `json:"promoted,omitempty"`
 }
 
 // NewBaselineStore creates a new baseline manager.
@@ -27,25 +80,90 @@ func NewBaselineStore(falconDir string) *BaselineStore {
 	return &BaselineStore{FalconDir: falconDir}
 }
 
-// Save persists a baseline to disk.
-func (s *BaselineStore) Save(b APIBaseline) error {
-	dir := filepath.Join(s.FalconDir, "baselines")
+func (s *BaselineStore) baselinesDir() string {
+	return filepath.Join(s.FalconDir, "baselines")
+}
+
+func (s *BaselineStore) legacyPath(name string) string {
+	return filepath.Join(s.baselinesDir(), name+".json")
+}
+
+func validateBaselineName(kind, value string) error {
+	if !validBaselineName.MatchString(value) || strings.Contains(value, "..") {
+		return fmt.Errorf("invalid baseline %s '%s' (use letters, digits, '.', '_' or '-')", kind, value)
+	}
+	if kind == "version" && strings.EqualFold(value+".json", indexFile) {
+		return fmt.Errorf("baseline version '%s' is reserved", value)
+	}
+	return nil
+}
+
+// Save persists a baseline version to disk. A missing Version is set to the
+// capture timestamp; saving an existing version overwrites it.
+func (s *BaselineStore) Save(b *APIBaseline) error {
+	if err := validateBaselineName("name", b.Name); err != nil {
+		return err
+	}
+	if b.CreatedAt.IsZero() {
+		b.CreatedAt = time.Now()
+	}
+	if b.Version == "" {
+		b.Version = b.CreatedAt.Format(versionTimeFormat)
+	}
+	if err := validateBaselineName("version", b.Version); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(s.baselinesDir(), b.Name)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
 
-	path := filepath.Join(dir, b.Name+".json")
 	data, err := json.MarshalIndent(b, "", "  ")
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(path, data, 0644)
+	return os.WriteFile(filepath.Join(dir, b.Version+".json"), data, 0644)
 }
 
-// Load retrieves a baseline from disk.
-func (s *BaselineStore) Load(name string) (*APIBaseline, error) {
-	path := filepath.Join(s.FalconDir, "baselines", name+".json")
+// Load retrieves a baseline from disk. An empty version resolves to the
+// promoted version, or the newest one when nothing has been promoted.
+func (s *BaselineStore) Load(name, version string) (*APIBaseline, error) {
+	if err := validateBaselineName("name", name); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(s.baselinesDir(), name)
+	if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+		if version != "" {
+			return nil, fmt.Errorf("baseline '%s' has no version '%s'", name, version)
+		}
+		return s.loadFile(s.legacyPath(name), name)
+	}
+
+	if version == "" {
+		resolved, err := s.defaultVersion(name)
+		if err != nil {
+			return nil, err
+		}
+		version = resolved
+	}
+	if err := validateBaselineName("version", version); err != nil {
+		return nil, err
+	}
+
+	b, err := s.loadFile(filepath.Join(dir, version+".json"), name)
+	if err != nil {
+		return nil, fmt.Errorf("baseline '%s' has no version '%s'", name, version)
+	}
+	if b.Version == "" {
+		b.Version = version
+	}
+	return b, nil
+}
+
+func (s *BaselineStore) loadFile(path, name string) (*APIBaseline, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -61,3 +179,164 @@ func (s *BaselineStore) Load(name string) (*APIBaseline, error) {
 
 	return &b, nil
 }
+
+// defaultVersion returns the promoted version, or the newest one.
+func (s *BaselineStore) defaultVersion(name string) (string, error) {
+	idx := s.loadIndex(name)
+	if idx.Promoted != "" {
+		return idx.Promoted, nil
+	}
+
+	versions, err := s.Versions(name)
+	if err != nil {
+		return "", err
+	}
+	if len(versions) == 0 {
+		return "", fmt.Errorf("baseline '%s' not found", name)
+	}
+	return versions[0].Version, nil
+}
+
+// Versions lists the stored versions of a baseline, newest first.
+func (s *BaselineStore) Versions(name string) ([]BaselineVersion, error) {
+	if err := validateBaselineName("name", name); err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(s.baselinesDir(), name)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			if b, err := s.loadFile(s.legacyPath(name), name); err == nil && b.Snapshots != nil {
+				return []BaselineVersion{{Version: "", CreatedAt: b.CreatedAt, Endpoints: len(b.Snapshots), Promoted: true}}, nil
+			}
+			return nil, fmt.Errorf("baseline '%s' not found", name)
+		}
+		return nil, err
+	}
+
+	promoted := s.loadIndex(name).Promoted
+	var versions []BaselineVersion
+	for _, entry := range entries {
+		if entry.IsDir() || strings.EqualFold(entry.Name(), indexFile) || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		b, err := s.loadFile(filepath.Join(dir, entry.Name()), name)
+		if err != nil {
+			continue
+		}
+		version := strings.TrimSuffix(entry.Name(), ".json")
+		versions = append(versions, BaselineVersion{
+			Version:   version,
+			CreatedAt: b.CreatedAt,
+			Endpoints: len(b.Snapshots),
+			Promoted:  version == promoted,
+		})
+	}
+
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].CreatedAt.After(versions[j].CreatedAt)
+	})
+	return versions, nil
+}
+
+// List returns every stored baseline with its versions, sorted by name.
+func (s *BaselineStore) List() ([]BaselineInfo, error) {
+	entries, err := os.ReadDir(s.baselinesDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var infos []BaselineInfo
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if !entry.IsDir() && filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		if seen[name] || validateBaselineName("name", name) != nil {
+			continue
+		}
+		// compare_responses keeps single-response baselines in the same
+		// directory; Versions skips files that carry no snapshots.
+		versions, err := s.Versions(name)
+		if err != nil || len(versions) == 0 {
+			continue
+		}
+		seen[name] = true
+		infos = append(infos, BaselineInfo{Name: name, Versions: versions})
+	}
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos, nil
+}
+
+// Delete removes one version of a baseline, or every version when version
+// is empty. Deleting the promoted version clears the promotion.
+func (s *BaselineStore) Delete(name, version string) error {
+	if err := validateBaselineName("name", name); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(s.baselinesDir(), name)
+	if version == "" {
+		_, dirErr := os.Stat(dir)
+		_, legacyErr := os.Stat(s.legacyPath(name))
+		if os.IsNotExist(dirErr) && os.IsNotExist(legacyErr) {
+			return fmt.Errorf("baseline '%s' not found", name)
+		}
+		if err := os.RemoveAll(dir); err != nil {
+			return err
+		}
+		if err := os.Remove(s.legacyPath(name)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	if err := validateBaselineName("version", version); err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(dir, version+".json")); err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("baseline '%s' has no version '%s'", name, version)
+		}
+		return err
+	}
+
+	if s.loadIndex(name).Promoted == version {
+		return s.saveIndex(name, baselineIndex{})
+	}
+	return nil
+}
+
+// Promote makes a version the default for checks against this baseline.
+func (s *BaselineStore) Promote(name, version string) error {
+	if version == "" {
+		return fmt.Errorf("version is required to promote baseline '%s'", name)
+	}
+	if _, err := s.Load(name, version); err != nil {
+		return err
+	}
+	return s.saveIndex(name, baselineIndex{Promoted: version})
+}
+
+func (s *BaselineStore) loadIndex(name string) baselineIndex {
+	var idx baselineIndex
+	data, err := os.ReadFile(filepath.Join(s.baselinesDir(), name, indexFile))
+	if err == nil {
+		_ = json.Unmarshal(data, &idx)
+	}
+	return idx
+}
+
+func (s *BaselineStore) saveIndex(name string, idx baselineIndex) error {
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(s.baselinesDir(), name, indexFile), data, 0644)
+}
@@ -0,0 +1,114 @@
+package regression_watchdog
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+)
+
+func saveVersion(t *testing.T, store *BaselineStore, version string, createdAt time.Time, endpoints ...string) {
+	t.Helper()
+	b := &APIBaseline{Name: "api", Version: version, CreatedAt: createdAt, Snapshots: map[string]shared.HTTPResponse{}}
+	for _, ep := range endpoints {
+		b.Snapshots[ep] = shared.HTTPResponse{StatusCode: 200}
+	}
+	if err := store.Save(b); err != nil {
+		t.Fatalf("Save %s: %v", version, err)
+	}
+}
+
+func TestBaselineStore_VersionsPromoteDelete(t *testing.T) {
+	dir := t.TempDir()
+	store := NewBaselineStore(dir)
+	now := time.Now()
+	saveVersion(t, store, "v1", now.Add(-time.Hour), "GET /users")
+	saveVersion(t, store, "v2", now, "GET /users", "GET /orders")
+
+	versions, err := store.Versions("api")
+	if err != nil || len(versions) != 2 || versions[0].Version != "v2" || versions[0].Endpoints != 2 || versions[0].Promoted {
+		t.Fatalf("Versions = %+v, %v", versions, err)
+	}
+	if b, err := store.Load("api", ""); err != nil || b.Version != "v2" {
+		t.Errorf("default before promote = %v, %v (want newest)", b, err)
+	}
+
+	if err := store.Promote("api", "v1"); err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "baselines", "api", "index.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var idx baselineIndex
+	if err := json.Unmarshal(data, &idx); err != nil || idx.Promoted != "v1" {
+		t.Errorf("index.json = %s", data)
+	}
+	if b, _ := store.Load("api", ""); b == nil || b.Version != "v1" {
+		t.Errorf("default after promote = %+v", b)
+	}
+	if versions, _ := store.Versions("api"); !versions[1].Promoted {
+		t.Errorf("v1 not marked promoted: %+v", versions)
+	}
+	if err := store.Promote("api", "v9"); err == nil {
+		t.Error("expected an error promoting a missing version")
+	}
+
+	// Deleting the promoted version clears the promotion
+	if err := store.Delete("api", "v1"); err != nil {
+		t.Fatalf("Delete v1: %v", err)
+	}
+	if b, _ := store.Load("api", ""); b == nil || b.Version != "v2" {
+		t.Errorf("default after deleting promoted = %+v", b)
+	}
+	if err := store.Delete("api", "v1"); err == nil {
+		t.Error("expected an error deleting a missing version")
+	}
+	if err := store.Delete("api", ""); err != nil {
+		t.Fatalf("Delete all: %v", err)
+	}
+	if _, err := store.Versions("api"); err == nil {
+		t.Error("expected the baseline to be gone")
+	}
+}
+
+func TestBaselineStore_SaveValidatesNames(t *testing.T) {
+	store := NewBaselineStore(t.TempDir())
+	for _, b := range []*APIBaseline{
+		{Name: "../escape"},
+		{Name: "api", Version: "a/b"},
+	} {
+		if err := store.Save(b); err == nil {
+			t.Errorf("Save(%q, %q): expected an error", b.Name, b.Version)
+		}
+	}
+
+	b := &APIBaseline{Name: "api", CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	if err := store.Save(b); err != nil || b.Version != "20260102-030405" {
+		t.Errorf("default version = %q, %v", b.Version, err)
+	}
+
+	// "index" would overwrite the promotion index
+	if err := store.Promote("api", b.Version); err != nil {
+		t.Fatalf("Promote: %v", err)
+	}
+	for _, version := range []string{"index", "INDEX"} {
+		if err := store.Save(&APIBaseline{Name: "api", Version: version}); err == nil || !strings.Contains(err.Error(), "reserved") {
+			t.Errorf("Save version %q: err = %v, want reserved", version, err)
+		}
+		if err := store.Delete("api", version); err == nil {
+			t.Errorf("Delete version %q: expected an error", version)
+		}
+	}
+	if got, err := store.Load("api", ""); err != nil || got.Version != b.Version {
+		t.Errorf("promoted version after rejected saves = %v, %v", got, err)
+	}
+	// Names are directories, so a baseline may still be called "index"
+	if err := store.Save(&APIBaseline{Name: "index", Version: "v1"}); err != nil {
+		t.Errorf("baseline named index: %v", err)
+	}
+}
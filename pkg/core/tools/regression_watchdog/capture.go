@@ -0,0 +1,295 @@
+package regression_watchdog
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/blackcoderx/falcon/pkg/core/tools/spec_ingester"
+)
+
+// pathParamPattern matches {id} and {{id}} path parameters.
+var pathParamPattern = regexp.MustCompile(`\{\{?\s*([A-Za-z0-9_.\-]+)\s*\}?\}`)
+
+// Capturer records live responses as a new baseline version.
+type Capturer struct {
+	httpTool  *shared.HTTPTool
+	varStore  *shared.VariableStore // fills {{VAR}} placeholders at send time
+	falconDir string
+	baseURL   string
+
+	headers       map[string]string             // sent with every request (e.g. auth)
+	requests      map[string]shared.HTTPRequest // per-endpoint headers/body overrides
+	variables     map[string]string             // values for {param} path placeholders
+	includeUnsafe bool                          // also capture POST/PUT/PATCH/DELETE from the graph
+}
+
+// CaptureResult lists what was recorded and what was skipped.
+type CaptureResult struct {
+	Baseline *APIBaseline
+	Skipped  []string
+	Failed   []string
+	Masked   []string // "METHOD /path: Header" entries stored with a masked secret
+}
+
+// Capture hits each selected endpoint and builds a baseline from the
+// responses. With no filter every safe endpoint in the knowledge graph is
+// captured; filter entries are "METHOD /path", "/path" or globs such as
+// "GET /api/users*".
+func (c *Capturer) Capture(name string, filter []string) (*CaptureResult, error) {
+	endpoints, err := c.selectEndpoints(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CaptureResult{
+		Baseline: &APIBaseline{
+			Name:      name,
+			CreatedAt: time.Now(),
+			BaseURL:   c.baseURL,
+			Snapshots: make(map[string]shared.HTTPResponse),
+			Requests:  make(map[string]shared.HTTPRequest),
+		},
+	}
+
+	for _, ep := range endpoints {
+		if !ep.explicit && !c.includeUnsafe && !isSafeMethod(ep.method) {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s (unsafe method; set include_unsafe or list it explicitly)", ep.key))
+			continue
+		}
+
+		req := c.buildRequest(ep)
+		live := expandRequest(req, c.varStore)
+		if missing := pathParamPattern.FindStringSubmatch(live.URL); missing != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s (no value for path parameter '%s')", ep.key, missing[1]))
+			continue
+		}
+
+		live.URL = joinURL(c.baseURL, live.URL)
+		resp, err := c.httpTool.Run(live)
+		if err != nil {
+			result.Failed = append(result.Failed, fmt.Sprintf("%s: %v", ep.key, err))
+			continue
+		}
+
+		// Store the template rather than what was sent, so {{VAR}} values
+		// never reach disk; literal secrets are masked.
+		stored, masked := maskRequestSecrets(req)
+		for _, name := range masked {
+			result.Masked = append(result.Masked, ep.key+": "+name)
+		}
+		result.Baseline.Snapshots[ep.key] = *resp
+		result.Baseline.Requests[ep.key] = stored
+	}
+
+	if len(result.Baseline.Snapshots) == 0 {
+		return result, fmt.Errorf("no endpoints were captured (%d skipped, %d failed)", len(result.Skipped), len(result.Failed))
+	}
+
+	return result, nil
+}
+
+// captureEndpoint is an endpoint selected for capture.
+type captureEndpoint struct {
+	key      string // "METHOD /path"
+	method   string
+	path     string
+	explicit bool // named exactly by the user rather than matched from the graph
+}
+
+// selectEndpoints resolves the filter against the knowledge graph. Exact
+// "METHOD /path" entries are used as-is when the graph is unavailable.
+func (c *Capturer) selectEndpoints(filter []string) ([]captureEndpoint, error) {
+	var graphKeys []string
+	builder := spec_ingester.NewGraphBuilder(c.falconDir)
+	if graph, err := builder.LoadGraph(); err == nil && graph != nil {
+		for key := range graph.Endpoints {
+			graphKeys = append(graphKeys, key)
+		}
+		sort.Strings(graphKeys)
+	}
+
+	selected := make(map[string]captureEndpoint)
+	add := func(key string, explicit bool) {
+		method, path := splitEndpointKey(key)
+		key = method + " " + path
+		if existing, ok := selected[key]; ok {
+			explicit = explicit || existing.explicit
+		}
+		selected[key] = captureEndpoint{key: key, method: method, path: path, explicit: explicit}
+	}
+
+	if len(filter) == 0 {
+		if len(graphKeys) == 0 {
+			return nil, fmt.Errorf("no endpoints to capture: the knowledge graph is empty (run ingest_spec first or pass endpoints)")
+		}
+		for _, key := range graphKeys {
+			add(key, false)
+		}
+	}
+
+	for _, f := range filter {
+		matched := false
+		for _, key := range graphKeys {
+			if matchEndpoint(f, key) {
+				add(key, false)
+				matched = true
+			}
+		}
+		// An exact "METHOD /path" entry is always captured, even when the
+		// graph does not know about it.
+		if strings.Contains(strings.TrimSpace(f), " ") && !strings.Contains(f, "*") {
+			add(f, true)
+			matched = true
+		}
+		if !matched {
+			return nil, fmt.Errorf("endpoint filter '%s' matched nothing in the knowledge graph", f)
+		}
+	}
+
+	endpoints := make([]captureEndpoint, 0, len(selected))
+	for _, ep := range selected {
+		endpoints = append(endpoints, ep)
+	}
+	sort.Slice(endpoints, func(i, j int) bool { return endpoints[i].key < endpoints[j].key })
+	return endpoints, nil
+}
+
+// buildRequest assembles the request for an endpoint. The URL stays relative
+// to the base URL so it can be stored and replayed elsewhere.
+func (c *Capturer) buildRequest(ep captureEndpoint) shared.HTTPRequest {
+	req := shared.HTTPRequest{
+		Method:  ep.method,
+		URL:     ep.path,
+		Headers: make(map[string]string),
+	}
+	for k, v := range c.headers {
+		req.Headers[k] = v
+	}
+	if override, ok := c.requests[ep.key]; ok {
+		for k, v := range override.Headers {
+			req.Headers[k] = v
+		}
+		if override.Body != nil {
+			req.Body = override.Body
+		}
+		if override.Timeout > 0 {
+			req.Timeout = override.Timeout
+		}
+	}
+	if len(req.Headers) == 0 {
+		req.Headers = nil
+	}
+
+	req.URL = pathParamPattern.ReplaceAllStringFunc(req.URL, func(m string) string {
+		name := pathParamPattern.FindStringSubmatch(m)[1]
+		if val, ok := c.variables[name]; ok {
+			return val
+		}
+		return m
+	})
+	return req
+}
+
+// expandRequest returns a copy of req with {{VAR}} placeholders in the URL,
+// headers and body filled from the variable store, as http_request does
+// before sending.
+func expandRequest(req shared.HTTPRequest, varStore *shared.VariableStore) shared.HTTPRequest {
+	if varStore == nil {
+		return req
+	}
+
+	req.URL = varStore.Substitute(req.URL)
+	if len(req.Headers) > 0 {
+		headers := make(map[string]string, len(req.Headers))
+		for k, v := range req.Headers {
+			headers[k] = varStore.Substitute(v)
+		}
+		req.Headers = headers
+	}
+
+	switch body := req.Body.(type) {
+	case nil:
+	case string:
+		req.Body = varStore.Substitute(body)
+	default:
+		if raw, err := json.Marshal(body); err == nil {
+			var expanded interface{}
+			if err := json.Unmarshal([]byte(varStore.Substitute(string(raw))), &expanded); err == nil {
+				req.Body = expanded
+			}
+		}
+	}
+	return req
+}
+
+// maskRequestSecrets returns a copy of req whose literal secret header values
+// are masked, along with the names of the masked headers. Values that only
+// use {{VAR}} placeholders are kept so they can be filled again on replay.
+func maskRequestSecrets(req shared.HTTPRequest) (shared.HTTPRequest, []string) {
+	if len(req.Headers) == 0 {
+		return req, nil
+	}
+
+	var masked []string
+	headers := make(map[string]string, len(req.Headers))
+	for name, value := range req.Headers {
+		if shared.IsSecret(name, value) && (!shared.ContainsVariablePlaceholder(value) || shared.HasPlaintextSecret(value)) {
+			headers[name] = shared.MaskSecret(value)
+			masked = append(masked, name)
+			continue
+		}
+		headers[name] = value
+	}
+	sort.Strings(masked)
+	req.Headers = headers
+	return req, masked
+}
+
+// splitEndpointKey splits "METHOD /path" into its parts, defaulting to GET.
+func splitEndpointKey(key string) (string, string) {
+	parts := strings.SplitN(strings.TrimSpace(key), " ", 2)
+	if len(parts) == 2 {
+		return strings.ToUpper(parts[0]), strings.TrimSpace(parts[1])
+	}
+	return "GET", parts[0]
+}
+
+// matchEndpoint reports whether an endpoint key matches a filter entry.
+// Entries without a method match any method; "*" matches any characters.
+func matchEndpoint(pattern, key string) bool {
+	pattern = strings.TrimSpace(pattern)
+	if !strings.Contains(pattern, " ") {
+		_, key = splitEndpointKey(key)
+	} else {
+		method, path := splitEndpointKey(pattern)
+		pattern = method + " " + path
+	}
+
+	if !strings.Contains(pattern, "*") {
+		return pattern == key
+	}
+	expr := "^" + strings.ReplaceAll(regexp.QuoteMeta(pattern), `\*`, ".*") + "$"
+	matched, _ := regexp.MatchString(expr, key)
+	return matched
+}
+
+func isSafeMethod(method string) bool {
+	switch strings.ToUpper(method) {
+	case "GET", "HEAD", "OPTIONS":
+		return true
+	}
+	return false
+}
+
+// joinURL combines a base URL with a relative path; absolute URLs pass through.
+func joinURL(baseURL, path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return strings.TrimSuffix(baseURL, "/") + "/" + strings.TrimPrefix(path, "/")
+}
@@ -1,16 +1,34 @@
 package regression_watchdog
 
 import (
+	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
 )
 
+const (
+	// defaultLatencyTolerance flags responses more than 50% slower than the snapshot.
+	defaultLatencyTolerance = 0.5
+	// defaultLatencyMinDelta ignores slowdowns smaller than this to avoid noise on fast endpoints.
+	defaultLatencyMinDelta = 50 * time.Millisecond
+	// maxReportedDifferences caps the per-endpoint differences listed in a description.
+	maxReportedDifferences = 5
+)
+
 // DiffEngine compares current API responses against baseline snapshots.
 type DiffEngine struct {
 	httpTool *shared.HTTPTool
+	varStore *shared.VariableStore // fills {{VAR}} placeholders at send time
 	baseURL  string
+	headers  map[string]string // overrides recorded request headers (e.g. a fresh token)
+
+	ignoreRules      []string      // JSONPath globs skipped by the structural diff
+	latencyTolerance float64       // allowed relative slowdown (0.5 = 50%)
+	latencyMinDelta  time.Duration // minimum absolute slowdown worth reporting
 }
 
 // Check identifies behavioral changes between live API and the baseline.
@@ -18,12 +36,22 @@ func (e *DiffEngine) Check(baseline *APIBaseline, filter []string) RegressionRes
 	var result RegressionResult
 	result.BaselineDate = baseline.CreatedAt.Format("2006-01-02 15:04:05")
 
-	for epKey, snapshot := range baseline.Snapshots {
+	result.Current = make(map[string]shared.HTTPResponse)
+
+	keys := make([]string, 0, len(baseline.Snapshots))
+	for epKey := range baseline.Snapshots {
+		keys = append(keys, epKey)
+	}
+	sort.Strings(keys)
+
+	for _, epKey := range keys {
+		snapshot := baseline.Snapshots[epKey]
+
 		// Filter endpoints if specified
 		if len(filter) > 0 {
 			match := false
 			for _, f := range filter {
-				if f == epKey {
+				if matchEndpoint(f, epKey) {
 					match = true
 					break
 				}
@@ -33,22 +61,25 @@ func (e *DiffEngine) Check(baseline *APIBaseline, filter []string) RegressionRes
 			}
 		}
 
-		parts := strings.SplitN(epKey, " ", 2)
-		method := "GET"
-		path := epKey
-		if len(parts) == 2 {
-			method = parts[0]
-			path = parts[1]
+		// Replay the recorded request when the baseline has one.
+		req, ok := baseline.Requests[epKey]
+		if !ok {
+			method, path := splitEndpointKey(epKey)
+			req = shared.HTTPRequest{Method: method, URL: path}
 		}
-
-		url := strings.TrimSuffix(e.baseURL, "/") + "/" + strings.TrimPrefix(path, "/")
-
-		req := shared.HTTPRequest{
-			Method: method,
-			URL:    url,
+		req.URL = joinURL(e.baseURL, req.URL)
+		if len(e.headers) > 0 {
+			headers := make(map[string]string, len(req.Headers)+len(e.headers))
+			for k, v := range req.Headers {
+				headers[k] = v
+			}
+			for k, v := range e.headers {
+				headers[k] = v
+			}
+			req.Headers = headers
 		}
 
-		resp, err := e.httpTool.Run(req)
+		resp, err := e.httpTool.Run(expandRequest(req, e.varStore))
 		if err != nil {
 			result.Regressions = append(result.Regressions, Regression{
 				Endpoint:    epKey,
@@ -68,19 +99,110 @@ func (e *DiffEngine) Check(baseline *APIBaseline, filter []string) RegressionRes
 			continue
 		}
 
-		// Compare Body structure/content (Simplified for simulation)
-		if resp.Body != snapshot.Body {
-			// In a real implementation, we'd use a structural JSON diff
-			result.Regressions = append(result.Regressions, Regression{
+		result.Current[epKey] = *resp
+		stable := true
+
+		// Compare Body structure/content
+		if reg := e.compareBody(epKey, snapshot.Body, resp.Body); reg != nil {
+			result.Regressions = append(result.Regressions, *reg)
+			stable = false
+		}
+
+		// Compare latency against the recorded duration
+		if reg := e.compareLatency(epKey, snapshot.Duration, resp.Duration); reg != nil {
+			result.Regressions = append(result.Regressions, *reg)
+			stable = false
+		}
+
+		if stable {
+			result.StableCount++
+		}
+	}
+
+	return result
+}
+
+// compareBody diffs two response bodies structurally when both are JSON,
+// falling back to an exact comparison otherwise.
+func (e *DiffEngine) compareBody(epKey, baselineBody, currentBody string) *Regression {
+	var baselineJSON, currentJSON interface{}
+	baselineErr := json.Unmarshal([]byte(baselineBody), &baselineJSON)
+	currentErr := json.Unmarshal([]byte(currentBody), &currentJSON)
+
+	if baselineErr != nil || currentErr != nil {
+		if baselineBody == currentBody {
+			return nil
+		}
+		if baselineErr == nil {
+			return &Regression{
 				Endpoint:    epKey,
 				ChangeType:  "body_diff",
-				Description: "Response body content or structure has changed",
-			})
-			continue
+				Description: "Response body is no longer valid JSON",
+			}
+		}
+		return &Regression{
+			Endpoint:    epKey,
+			ChangeType:  "body_diff",
+			Description: "Response body content has changed",
 		}
+	}
 
-		result.StableCount++
+	diffs := shared.DiffJSON(baselineJSON, currentJSON, shared.DiffOptions{IgnorePaths: e.ignoreRules})
+	if len(diffs) == 0 {
+		return nil
 	}
 
-	return result
+	changeType := "body_diff"
+	for _, d := range diffs {
+		if d.Kind != shared.DiffChanged {
+			changeType = "schema_diff"
+			break
+		}
+	}
+
+	messages := make([]string, 0, maxReportedDifferences)
+	for i, d := range diffs {
+		if i == maxReportedDifferences {
+			messages = append(messages, fmt.Sprintf("... and %d more", len(diffs)-maxReportedDifferences))
+			break
+		}
+		messages = append(messages, d.Message)
+	}
+
+	return &Regression{
+		Endpoint:    epKey,
+		ChangeType:  changeType,
+		Description: fmt.Sprintf("%d difference(s): %s", len(diffs), strings.Join(messages, "; ")),
+		Differences: diffs,
+	}
+}
+
+// compareLatency flags responses that are slower than the snapshot by more
+// than the configured tolerance. Snapshots without a duration are skipped.
+func (e *DiffEngine) compareLatency(epKey string, baseline, current time.Duration) *Regression {
+	if baseline <= 0 {
+		return nil
+	}
+
+	tolerance := e.latencyTolerance
+	if tolerance <= 0 {
+		tolerance = defaultLatencyTolerance
+	}
+	minDelta := e.latencyMinDelta
+	if minDelta <= 0 {
+		minDelta = defaultLatencyMinDelta
+	}
+
+	delta := current - baseline
+	if delta < minDelta || float64(current) <= float64(baseline)*(1+tolerance) {
+		return nil
+	}
+
+	return &Regression{
+		Endpoint:   epKey,
+		ChangeType: "response_time",
+		Description: fmt.Sprintf("Response time drifted from %dms to %dms (+%.0f%%, tolerance %.0f%%)",
+			baseline.Milliseconds(), current.Milliseconds(),
+			float64(delta)/float64(baseline)*100, tolerance*100),
+	}
 }
@@ -3,6 +3,9 @@ package regression_watchdog
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
 )
@@ -11,22 +14,38 @@ import (
 type RegressionWatchdogTool struct {
 	falconDir string
 	httpTool  *shared.HTTPTool
+	varStore  *shared.VariableStore
 }
 
 // NewRegressionWatchdogTool creates a new regression watchdog tool.
-func NewRegressionWatchdogTool(falconDir string, httpTool *shared.HTTPTool) *RegressionWatchdogTool {
+func NewRegressionWatchdogTool(falconDir string, httpTool *shared.HTTPTool, varStore *shared.VariableStore) *RegressionWatchdogTool {
 	return &RegressionWatchdogTool{
 		falconDir: falconDir,
-		httpTool: httpTool,
+		httpTool:  httpTool,
+		varStore:  varStore,
 	}
 }
 
 // RegressionParams defines parameters for checking regressions.
 type RegressionParams struct {
+	Action       string   `json:"action,omitempty"`           // check (default), capture, list, delete, promote
 	BaseURL      string   `json:"base_url"`                   // Current API URL
 	BaselineName string   `json:"baseline_name"`              // Name of the snapshot to compare against
-	Endpoints    []string `json:"endpoints,omitempty"`        // Specific endpoints to verify
-	SaveBaseline bool     `json:"save_as_baseline,omitempty"` // Whether to update the baseline after check
+	Version      string   `json:"version,omitempty"`          // Baseline version (default: promoted, else newest)
+	Endpoints    []string `json:"endpoints,omitempty"`        // Specific endpoints to verify or capture ("GET /api/users*")
+	SaveBaseline bool     `json:"save_as_baseline,omitempty"` // Save the checked responses as a new promoted version when stable
+
+	IgnorePaths       []string `json:"ignore_paths,omitempty"`         // JSONPath globs to skip in addition to the baseline's rules
+	PersistIgnore     bool     `json:"persist_ignore,omitempty"`       // Store ignore_paths on the baseline for future checks
+	LatencyTolerance  float64  `json:"latency_tolerance,omitempty"`    // Allowed slowdown vs snapshot (0.5 = 50%)
+	LatencyMinDeltaMs int      `json:"latency_min_delta_ms,omitempty"` // Ignore slowdowns smaller than this (default 50ms)
+
+	// Capture options
+	Headers       map[string]string             `json:"headers,omitempty"`        // Sent with every request (overrides recorded headers on check)
+	Requests      map[string]shared.HTTPRequest `json:"requests,omitempty"`       // Per-endpoint headers/body, keyed "METHOD /path"
+	Variables     map[string]string             `json:"variables,omitempty"`      // Values for {param} path placeholders
+	IncludeUnsafe bool                          `json:"include_unsafe,omitempty"` // Capture non-GET graph endpoints too
+	Promote       bool                          `json:"promote,omitempty"`        // Promote the captured version
 }
 
 // RegressionResult represents the outcome of the comparison.
@@ -35,13 +54,17 @@ type RegressionResult struct {
 	Regressions  []Regression `json:"regressions"`
 	StableCount  int          `json:"stable_count"`
 	Summary      string       `json:"summary"`
+
+	Current map[string]shared.HTTPResponse `json:"-"` // Responses observed during the check
 }
 
 // Regression represents a detected behavioral change.
 type Regression struct {
 	Endpoint    string `json:"endpoint"`
-	ChangeType  string `json:"change_type"` // status_code, body_diff, response_time
+	ChangeType  string `json:"change_type"` // status_code, schema_diff, body_diff, response_time, error
 	Description string `json:"description"`
+
+	Differences []shared.JSONDifference `json:"differences,omitempty"`
 }
 
 func (t *RegressionWatchdogTool) Name() string {
@@ -49,14 +72,19 @@ func (t *RegressionWatchdogTool) Name() string {
 }
 
 func (t *RegressionWatchdogTool) Description() string {
-	return "Verify current API behavior against a saved baseline snapshot (regression testing). Detects changes in status codes, JSON structure, and performance drift."
+	return "Verify current API behavior against a saved baseline snapshot (regression testing). Detects changes in status codes, JSON structure, and performance drift. Actions: check (default), capture (record a new baseline version from the knowledge graph or endpoints), list, delete, promote."
 }
 
 func (t *RegressionWatchdogTool) Parameters() string {
 	return `{
+  "action": "check",
   "base_url": "http://localhost:3000",
   "baseline_name": "stable_v1",
-  "endpoints": ["GET /api/users"]
+  "version": "last-release",
+  "endpoints": ["GET /api/users"],
+  "ignore_paths": ["$.data[*].updated_at", "$..request_id"],
+  "persist_ignore": true,
+  "latency_tolerance": 0.5
 }`
 }
 
@@ -66,28 +94,270 @@ func (t *RegressionWatchdogTool) Execute(args string) (string, error) {
 		return "", fmt.Errorf("failed to parse parameters: %w", err)
 	}
 
+	store := NewBaselineStore(t.falconDir)
+
+	switch params.Action {
+	case "", "check":
+		return t.check(store, params)
+	case "capture":
+		return t.capture(store, params)
+	case "list":
+		return t.list(store, params)
+	case "delete":
+		if params.BaselineName == "" {
+			return "", fmt.Errorf("baseline_name is required")
+		}
+		if err := store.Delete(params.BaselineName, params.Version); err != nil {
+			return "", fmt.Errorf("failed to delete baseline: %w", err)
+		}
+		if params.Version == "" {
+			return fmt.Sprintf("🗑️ Deleted baseline '%s' (all versions)", params.BaselineName), nil
+		}
+		return fmt.Sprintf("🗑️ Deleted version '%s' of baseline '%s'", params.Version, params.BaselineName), nil
+	case "promote":
+		return t.promote(store, params)
+	default:
+		return "", fmt.Errorf("unknown action '%s' (use check, capture, list, delete or promote)", params.Action)
+	}
+}
+
+// check compares live responses against a stored baseline version.
+func (t *RegressionWatchdogTool) check(store *BaselineStore, params RegressionParams) (string, error) {
 	if params.BaseURL == "" || params.BaselineName == "" {
 		return "", fmt.Errorf("base_url and baseline_name are required")
 	}
+	if err := shared.ValidateIgnorePaths(params.IgnorePaths); err != nil {
+		return "", err
+	}
 
-	store := NewBaselineStore(t.falconDir)
-	baseline, err := store.Load(params.BaselineName)
+	baseline, err := store.Load(params.BaselineName, params.Version)
 	if err != nil {
 		return "", fmt.Errorf("failed to load baseline: %w", err)
 	}
 
-	diffEngine := &DiffEngine{httpTool: t.httpTool, baseURL: params.BaseURL}
-	result := diffEngine.Check(baseline, params.Endpoints)
+	if params.PersistIgnore && len(params.IgnorePaths) > 0 {
+		baseline.IgnoreRules = mergeRules(baseline.IgnoreRules, params.IgnorePaths)
+		if params.LatencyTolerance > 0 {
+			baseline.LatencyTolerance = params.LatencyTolerance
+		}
+		if err := store.Save(baseline); err != nil {
+			return "", fmt.Errorf("failed to save ignore rules: %w", err)
+		}
+	}
 
-	if params.SaveBaseline && len(result.Regressions) == 0 {
-		// Logic to save current state as new baseline
+	latencyTolerance := baseline.LatencyTolerance
+	if params.LatencyTolerance > 0 {
+		latencyTolerance = params.LatencyTolerance
 	}
 
+	diffEngine := &DiffEngine{
+		httpTool:         t.httpTool,
+		varStore:         t.varStore,
+		baseURL:          params.BaseURL,
+		headers:          params.Headers,
+		ignoreRules:      mergeRules(baseline.IgnoreRules, params.IgnorePaths),
+		latencyTolerance: latencyTolerance,
+		latencyMinDelta:  time.Duration(params.LatencyMinDeltaMs) * time.Millisecond,
+	}
+	result := diffEngine.Check(baseline, params.Endpoints)
+
 	result.Summary = t.formatSummary(result)
+	if baseline.Version != "" {
+		result.Summary = fmt.Sprintf("Baseline: %s@%s\n", baseline.Name, baseline.Version) + result.Summary
+	}
+
+	if params.SaveBaseline {
+		if len(result.Regressions) > 0 {
+			result.Summary += "\n\nBaseline not updated: resolve or accept the regressions first."
+		} else if len(result.Current) > 0 {
+			// A filtered check only refreshes the endpoints it replayed;
+			// the others keep their snapshots from this version.
+			snapshots := make(map[string]shared.HTTPResponse, len(baseline.Snapshots))
+			for key, snap := range baseline.Snapshots {
+				snapshots[key] = snap
+			}
+			for key, snap := range result.Current {
+				snapshots[key] = snap
+			}
+			updated := &APIBaseline{
+				Name:             baseline.Name,
+				CreatedAt:        time.Now(),
+				BaseURL:          params.BaseURL,
+				Snapshots:        snapshots,
+				Requests:         baseline.Requests,
+				IgnoreRules:      baseline.IgnoreRules,
+				LatencyTolerance: baseline.LatencyTolerance,
+			}
+			if err := store.Save(updated); err != nil {
+				return "", fmt.Errorf("failed to save baseline: %w", err)
+			}
+			if err := store.Promote(updated.Name, updated.Version); err != nil {
+				return "", fmt.Errorf("failed to promote baseline: %w", err)
+			}
+			result.Summary += fmt.Sprintf("\n\n💾 Saved and promoted new version '%s'", updated.Version)
+		}
+	}
 
 	return result.Summary, nil
 }
 
+// capture records a new baseline version from live responses.
+func (t *RegressionWatchdogTool) capture(store *BaselineStore, params RegressionParams) (string, error) {
+	if params.BaseURL == "" || params.BaselineName == "" {
+		return "", fmt.Errorf("base_url and baseline_name are required")
+	}
+	if err := validateBaselineName("name", params.BaselineName); err != nil {
+		return "", err
+	}
+	if err := shared.ValidateIgnorePaths(params.IgnorePaths); err != nil {
+		return "", err
+	}
+
+	capturer := &Capturer{
+		httpTool:      t.httpTool,
+		varStore:      t.varStore,
+		falconDir:     t.falconDir,
+		baseURL:       params.BaseURL,
+		headers:       params.Headers,
+		requests:      normalizeRequestKeys(params.Requests),
+		variables:     params.Variables,
+		includeUnsafe: params.IncludeUnsafe,
+	}
+	captured, err := capturer.Capture(params.BaselineName, params.Endpoints)
+	if err != nil {
+		return "", fmt.Errorf("failed to capture baseline: %w", err)
+	}
+
+	b := captured.Baseline
+	b.Version = params.Version
+	b.IgnoreRules = params.IgnorePaths
+	b.LatencyTolerance = params.LatencyTolerance
+
+	// Carry ignore rules forward from the previous version.
+	existing, _ := store.Versions(params.BaselineName)
+	if previous, err := store.Load(params.BaselineName, ""); err == nil {
+		b.IgnoreRules = mergeRules(previous.IgnoreRules, b.IgnoreRules)
+		if b.LatencyTolerance == 0 {
+			b.LatencyTolerance = previous.LatencyTolerance
+		}
+	}
+
+	if err := store.Save(b); err != nil {
+		return "", fmt.Errorf("failed to save baseline: %w", err)
+	}
+
+	promoted := params.Promote || len(existing) == 0
+	if promoted {
+		if err := store.Promote(b.Name, b.Version); err != nil {
+			return "", fmt.Errorf("failed to promote baseline: %w", err)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("📸 Captured baseline '%s' version '%s'\n", b.Name, b.Version))
+	sb.WriteString(fmt.Sprintf("Endpoints: %d captured, %d skipped, %d failed\n", len(b.Snapshots), len(captured.Skipped), len(captured.Failed)))
+	if promoted {
+		sb.WriteString("Promoted: yes (used by default for checks)\n")
+	}
+
+	keys := make([]string, 0, len(b.Snapshots))
+	for k := range b.Snapshots {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	sb.WriteString("\nCaptured:\n")
+	for _, k := range keys {
+		snap := b.Snapshots[k]
+		sb.WriteString(fmt.Sprintf("  ✓ %s → %d (%dms)\n", k, snap.StatusCode, snap.Duration.Milliseconds()))
+	}
+	if len(captured.Skipped) > 0 {
+		sb.WriteString("\nSkipped:\n")
+		for _, s := range captured.Skipped {
+			sb.WriteString(fmt.Sprintf("  - %s\n", s))
+		}
+	}
+	if len(captured.Failed) > 0 {
+		sb.WriteString("\nFailed:\n")
+		for _, f := range captured.Failed {
+			sb.WriteString(fmt.Sprintf("  ✗ %s\n", f))
+		}
+	}
+	if len(captured.Masked) > 0 {
+		sb.WriteString("\nMasked secrets (pass them in headers on check, or use {{VAR}} placeholders):\n")
+		for _, m := range captured.Masked {
+			sb.WriteString(fmt.Sprintf("  - %s\n", m))
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// list shows stored baselines, or the versions of one baseline.
+func (t *RegressionWatchdogTool) list(store *BaselineStore, params RegressionParams) (string, error) {
+	var infos []BaselineInfo
+	if params.BaselineName != "" {
+		versions, err := store.Versions(params.BaselineName)
+		if err != nil {
+			return "", err
+		}
+		infos = []BaselineInfo{{Name: params.BaselineName, Versions: versions}}
+	} else {
+		all, err := store.List()
+		if err != nil {
+			return "", fmt.Errorf("failed to list baselines: %w", err)
+		}
+		infos = all
+	}
+
+	if len(infos) == 0 {
+		return "No baselines stored yet. Use action \"capture\" to record one.", nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("🐕 Regression Baselines\n\n")
+	for _, info := range infos {
+		sb.WriteString(fmt.Sprintf("%s (%d version(s))\n", info.Name, len(info.Versions)))
+		for _, v := range info.Versions {
+			label := v.Version
+			if label == "" {
+				label = "(unversioned)"
+			}
+			marker := " "
+			if v.Promoted {
+				marker = "★"
+			}
+			sb.WriteString(fmt.Sprintf("  %s %s  %s  %d endpoint(s)\n", marker, label, v.CreatedAt.Format("2006-01-02 15:04:05"), v.Endpoints))
+		}
+	}
+	sb.WriteString("\n★ = promoted (used by default for checks)")
+
+	return sb.String(), nil
+}
+
+// promote marks a version as the default; without a version the newest is promoted.
+func (t *RegressionWatchdogTool) promote(store *BaselineStore, params RegressionParams) (string, error) {
+	if params.BaselineName == "" {
+		return "", fmt.Errorf("baseline_name is required")
+	}
+
+	version := params.Version
+	if version == "" {
+		versions, err := store.Versions(params.BaselineName)
+		if err != nil {
+			return "", err
+		}
+		if len(versions) == 0 || versions[0].Version == "" {
+			return "", fmt.Errorf("baseline '%s' has no versions to promote (capture one first)", params.BaselineName)
+		}
+		version = versions[0].Version
+	}
+
+	if err := store.Promote(params.BaselineName, version); err != nil {
+		return "", fmt.Errorf("failed to promote baseline: %w", err)
+	}
+	return fmt.Sprintf("★ Promoted '%s' version '%s'; checks now compare against it by default", params.BaselineName, version), nil
+}
+
 func (t *RegressionWatchdogTool) formatSummary(r RegressionResult) string {
 	summary := "🐕 Regression Watchdog Results\n\n"
 	summary += fmt.Sprintf("Comparing against baseline from: %s\n", r.BaselineDate)
@@ -98,6 +368,12 @@ func (t *RegressionWatchdogTool) formatSummary(r RegressionResult) string {
 		summary += "Detected Regressions:\n"
 		for _, reg := range r.Regressions {
 			summary += fmt.Sprintf("  ❌ %s: %s (%s)\n", reg.Endpoint, reg.Description, reg.ChangeType)
+			for i, d := range reg.Differences {
+				if i == maxReportedDifferences {
+					break
+				}
+				summary += fmt.Sprintf("      - [%s] %s\n", d.Kind, d.Path)
+			}
 		}
 	} else if r.StableCount > 0 {
 		summary += "✓ No regressions detected. API behavior is consistent with the baseline."
@@ -105,3 +381,27 @@ func (t *RegressionWatchdogTool) formatSummary(r RegressionResult) string {
 
 	return summary
 }
+
+// normalizeRequestKeys upper-cases the method in "METHOD /path" keys.
+func normalizeRequestKeys(requests map[string]shared.HTTPRequest) map[string]shared.HTTPRequest {
+	normalized := make(map[string]shared.HTTPRequest, len(requests))
+	for key, req := range requests {
+		method, path := splitEndpointKey(key)
+		normalized[method+" "+path] = req
+	}
+	return normalized
+}
+
+// mergeRules appends extra ignore rules to base, skipping duplicates.
+func mergeRules(base, extra []string) []string {
+	seen := make(map[string]bool, len(base)+len(extra))
+	var merged []string
+	for _, rule := range append(append([]string{}, base...), extra...) {
+		if rule == "" || seen[rule] {
+			continue
+		}
+		seen[rule] = true
+		merged = append(merged, rule)
+	}
+	return merged
+}
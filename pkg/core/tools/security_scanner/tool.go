@@ -3,6 +3,9 @@ package security_scanner
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
@@ -37,6 +40,9 @@ type ScanParams struct {
 	AuthToken  string   `json:"auth_token,omitempty"`  // Auth token for authenticated endpoints
 	Depth      string   `json:"depth,omitempty"`       // Scan depth: quick, standard, deep (default: standard)
 	MaxPayload int      `json:"max_payload,omitempty"` // Max payload size for fuzzing (default: 10000)
+
+	JWTWordlist  string `json:"jwt_wordlist,omitempty"`   // Extra HMAC secrets to try, one per line (project or .falcon/ path)
+	JWTPublicKey string `json:"jwt_public_key,omitempty"` // PEM public key (inline or file path) for algorithm confusion; JWKS is probed when empty
 }
 
 // ScanResult represents the output of a security scan.
@@ -85,7 +91,9 @@ func (t *SecurityScannerTool) Parameters() string {
   "scan_types": ["owasp", "fuzz", "auth"],
   "auth_token": "Bearer eyJ0eXAiOiJKV1QiLCJhbGc...",
   "depth": "standard",
-  "max_payload": 10000
+  "max_payload": 10000,
+  "jwt_wordlist": "wordlists/jwt-secrets.txt",
+  "jwt_public_key": "keys/public.pem"
 }`
 }
 
@@ -142,7 +150,11 @@ func (t *SecurityScannerTool) Execute(args string) (string, error) {
 
 		case "auth":
 			if params.AuthToken != "" {
-				vulns, checks := t.authAuditor.AuditAuth(endpoints, params.BaseURL, params.AuthToken)
+				jwtOpts, err := t.jwtOptions(params)
+				if err != nil {
+					return "", err
+				}
+				vulns, checks := t.authAuditor.AuditAuth(endpoints, params.BaseURL, params.AuthToken, jwtOpts)
 				allVulnerabilities = append(allVulnerabilities, vulns...)
 				totalChecks += checks
 			}
@@ -176,6 +188,55 @@ func (t *SecurityScannerTool) Execute(args string) (string, error) {
 	return result.Summary, nil
 }
 
+// jwtOptions loads the user-supplied JWT wordlist and public key.
+func (t *SecurityScannerTool) jwtOptions(params ScanParams) (JWTOptions, error) {
+	var opts JWTOptions
+
+	if params.JWTWordlist != "" {
+		path, err := t.resolveFile(params.JWTWordlist)
+		if err != nil {
+			return opts, fmt.Errorf("jwt_wordlist: %w", err)
+		}
+		if opts.Wordlist, err = loadWordlist(path); err != nil {
+			return opts, err
+		}
+	}
+
+	if key := strings.TrimSpace(params.JWTPublicKey); key != "" {
+		if strings.Contains(key, "-----BEGIN") {
+			opts.PublicKey = []byte(params.JWTPublicKey)
+		} else {
+			path, err := t.resolveFile(key)
+			if err != nil {
+				return opts, fmt.Errorf("jwt_public_key: %w", err)
+			}
+			if opts.PublicKey, err = os.ReadFile(path); err != nil {
+				return opts, fmt.Errorf("failed to read jwt_public_key: %w", err)
+			}
+		}
+	}
+	return opts, nil
+}
+
+// resolveFile finds a file in the project or in .falcon/.
+func (t *SecurityScannerTool) resolveFile(name string) (string, error) {
+	projectRoot := filepath.Dir(t.falconDir)
+	candidates := []string{name}
+	if !filepath.IsAbs(name) {
+		candidates = append(candidates, filepath.Join(t.falconDir, name))
+	}
+	for _, candidate := range candidates {
+		absPath, err := shared.ValidatePathWithinWorkDir(candidate, projectRoot)
+		if err != nil {
+			return "", err
+		}
+		if info, err := os.Stat(absPath); err == nil && !info.IsDir() {
+			return absPath, nil
+		}
+	}
+	return "", fmt.Errorf("file not found: %s", name)
+}
+
 // getEndpoints retrieves endpoints either from the Knowledge Graph or the provided list.
 func (t *SecurityScannerTool) getEndpoints(specifiedEndpoints []string) (map[string]shared.EndpointAnalysis, error) {
 	if len(specifiedEndpoints) > 0 {
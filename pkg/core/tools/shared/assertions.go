@@ -61,7 +61,7 @@ func (t *AssertTool) Parameters() string {
   "body_contains": ["user_id", "email"],
   "body_not_contains": ["error"],
   "body_equals": {"status": "ok"},
-  "json_path": {"$.data.id": 123, "$.status": "active"},
+  "json_path": {"$.data.id": 123, "$.items[*].price": {"$gt": 0}, "$.items": {"$len": 3}, "$.email": {"$regex": "@"}},
   "response_time_max_ms": 500
 }`
 }
@@ -221,28 +221,12 @@ func (t *AssertTool) runAssertions(params AssertParams, lastResponse *HTTPRespon
 
 	// Check JSON path values
 	if len(params.JSONPath) > 0 {
-		var jsonData map[string]interface{}
-		if err := json.Unmarshal([]byte(lastResponse.Body), &jsonData); err != nil {
-			result.TotalChecks += len(params.JSONPath)
-			result.Failures = append(result.Failures,
-				fmt.Sprintf("Cannot parse response as JSON for JSONPath checks: %v", err))
+		passed, failures := checkJSONPaths(lastResponse.Body, params.JSONPath)
+		result.TotalChecks += len(params.JSONPath)
+		result.PassedChecks += passed
+		if len(failures) > 0 {
+			result.Failures = append(result.Failures, failures...)
 			result.Passed = false
-		} else {
-			for path, expectedValue := range params.JSONPath {
-				result.TotalChecks++
-				actualValue, err := getJSONPath(jsonData, path)
-				if err != nil {
-					result.Failures = append(result.Failures,
-						fmt.Sprintf("JSONPath '%s': %v", path, err))
-					result.Passed = false
-				} else if !deepEqual(actualValue, expectedValue) {
-					result.Failures = append(result.Failures,
-						fmt.Sprintf("JSONPath '%s': expected %v, got %v", path, expectedValue, actualValue))
-					result.Passed = false
-				} else {
-					result.PassedChecks++
-				}
-			}
 		}
 	}
 
@@ -287,59 +271,3 @@ func deepEqual(a, b interface{}) bool {
 	bJSON, _ := json.Marshal(b)
 	return string(aJSON) == string(bJSON)
 }
-
-// getJSONPath extracts a value from nested JSON using a simple path syntax
-// Supports: $.field, $.nested.field, $.array[0]
-func getJSONPath(data map[string]interface{}, path string) (interface{}, error) {
-	// Remove leading $. if present
-	path = strings.TrimPrefix(path, "$.")
-	if path == "" || path == "$" {
-		return data, nil
-	}
-
-	parts := strings.Split(path, ".")
-	var current interface{} = data
-
-	for _, part := range parts {
-		// Handle array indexing: field[0]
-		if strings.Contains(part, "[") {
-			fieldName := part[:strings.Index(part, "[")]
-			indexStr := part[strings.Index(part, "[")+1 : strings.Index(part, "]")]
-
-			var index int
-			if _, err := fmt.Sscanf(indexStr, "%d", &index); err != nil {
-				return nil, fmt.Errorf("invalid array index: %s", indexStr)
-			}
-
-			if fieldName != "" {
-				m, ok := current.(map[string]interface{})
-				if !ok {
-					return nil, fmt.Errorf("expected object at '%s'", fieldName)
-				}
-				current = m[fieldName]
-			}
-
-			arr, ok := current.([]interface{})
-			if !ok {
-				return nil, fmt.Errorf("expected array at '%s'", part)
-			}
-			if index < 0 || index >= len(arr) {
-				return nil, fmt.Errorf("array index %d out of bounds", index)
-			}
-			current = arr[index]
-		} else {
-			// Regular field access
-			m, ok := current.(map[string]interface{})
-			if !ok {
-				return nil, fmt.Errorf("expected object, got %T", current)
-			}
-			value, ok := m[part]
-			if !ok {
-				return nil, fmt.Errorf("field '%s' not found", part)
-			}
-			current = value
-		}
-	}
-
-	return current, nil
-}
@@ -17,6 +17,7 @@ type ConfirmationManager struct {
 	pending         bool
 	timeout         time.Duration
 	timeoutCallback TimeoutCallback
+	preset          *bool // answer for the next request, set by PresetResponse
 }
 
 // NewConfirmationManager creates a new ConfirmationManager with default timeout.
@@ -46,6 +47,11 @@ func (cm *ConfirmationManager) SetTimeout(timeout time.Duration) {
 // Returns true if approved, false if rejected or timed out.
 func (cm *ConfirmationManager) RequestConfirmation() bool {
 	cm.mu.Lock()
+	if preset := cm.preset; preset != nil {
+		cm.preset = nil
+		cm.mu.Unlock()
+		return *preset
+	}
 	cm.pending = true
 	timeout := cm.timeout
 	select {
@@ -85,6 +91,15 @@ func (cm *ConfirmationManager) SendResponse(approved bool) {
 	}
 }
 
+// PresetResponse answers the next confirmation request in advance, so it
+// returns immediately instead of waiting. Non-interactive runs use it to
+// decide a request from the event that announces it.
+func (cm *ConfirmationManager) PresetResponse(approved bool) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.preset = &approved
+}
+
 // IsPending returns whether a confirmation request is waiting for response.
 func (cm *ConfirmationManager) IsPending() bool {
 	cm.mu.Lock()
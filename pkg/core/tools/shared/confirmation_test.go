@@ -0,0 +1,50 @@
+package shared
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfirmationManager_PresetResponse(t *testing.T) {
+	cm := NewConfirmationManager()
+	cm.SetTimeout(50 * time.Millisecond)
+
+	cm.PresetResponse(true)
+	start := time.Now()
+	if !cm.RequestConfirmation() {
+		t.Error("expected the preset approval")
+	}
+	if waited := time.Since(start); waited > 20*time.Millisecond {
+		t.Errorf("preset request waited %v", waited)
+	}
+
+	// The preset answers one request only; the next one times out
+	timedOut := false
+	cm.SetTimeoutCallback(func() { timedOut = true })
+	if cm.RequestConfirmation() || !timedOut {
+		t.Errorf("expected the second request to time out as denied")
+	}
+
+	cm.PresetResponse(false)
+	if cm.RequestConfirmation() || cm.IsPending() {
+		t.Error("expected the preset denial without a pending request")
+	}
+}
+
+func TestConfirmationManager_SendResponse(t *testing.T) {
+	cm := NewConfirmationManager()
+	cm.SetTimeout(time.Second)
+
+	// Responses sent while nothing is pending are dropped
+	cm.SendResponse(true)
+
+	result := make(chan bool)
+	go func() { result <- cm.RequestConfirmation() }()
+	for !cm.IsPending() {
+		time.Sleep(time.Millisecond)
+	}
+	cm.SendResponse(true)
+	if !<-result {
+		t.Error("expected the response sent while pending")
+	}
+}
@@ -0,0 +1,523 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xeipuuv/gojsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// Contract issue kinds reported by ContractChecker.
+const (
+	ContractUndocumentedEndpoint    = "undocumented_endpoint"
+	ContractUndocumentedStatus      = "undocumented_status"
+	ContractUndocumentedContentType = "undocumented_content_type"
+	ContractInvalidBody             = "invalid_body"
+	ContractMissingField            = "missing_field"
+	ContractExtraField              = "extra_field"
+	ContractSchemaMismatch          = "schema_mismatch"
+)
+
+// ContractIssue is a single difference between a live response and the spec.
+// Warnings are reported but do not fail a contract test.
+type ContractIssue struct {
+	Kind    string `json:"kind"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+	Warning bool   `json:"warning,omitempty"`
+}
+
+func (i ContractIssue) String() string {
+	if i.Field != "" {
+		return fmt.Sprintf("%s: %s — %s", i.Kind, i.Field, i.Message)
+	}
+	return fmt.Sprintf("%s: %s", i.Kind, i.Message)
+}
+
+// ContractResult is the outcome of checking one response against the spec.
+type ContractResult struct {
+	Endpoint    string          `json:"endpoint,omitempty"` // spec key, e.g. "GET /users/{id}"
+	Status      string          `json:"status,omitempty"`   // matched status key: "200", "2XX" or "default"
+	ContentType string          `json:"content_type,omitempty"`
+	Issues      []ContractIssue `json:"issues,omitempty"`
+}
+
+// Violations returns the issues that break the contract (everything but warnings).
+func (r *ContractResult) Violations() []ContractIssue {
+	var violations []ContractIssue
+	for _, issue := range r.Issues {
+		if !issue.Warning {
+			violations = append(violations, issue)
+		}
+	}
+	return violations
+}
+
+// Passed reports whether the response honours the contract.
+func (r *ContractResult) Passed() bool {
+	return len(r.Violations()) == 0
+}
+
+// Format renders the result as a short section for tool output.
+func (r *ContractResult) Format() string {
+	var sb strings.Builder
+	target := r.Endpoint
+	if target == "" {
+		target = "undocumented endpoint"
+	}
+	if r.Status != "" {
+		target += " → " + r.Status
+	}
+	if r.ContentType != "" {
+		target += " (" + r.ContentType + ")"
+	}
+
+	switch {
+	case len(r.Issues) == 0:
+		fmt.Fprintf(&sb, "✅ Contract: %s matches the spec", target)
+	case r.Passed():
+		fmt.Fprintf(&sb, "⚠️ Contract: %s matches the spec with %d warning(s)", target, len(r.Issues))
+	default:
+		fmt.Fprintf(&sb, "❌ Contract: %s violates the spec (%d issue(s))", target, len(r.Issues))
+	}
+	for _, issue := range r.Issues {
+		icon := "✗"
+		if issue.Warning {
+			icon = "⚠"
+		}
+		fmt.Fprintf(&sb, "\n  %s %s", icon, issue)
+	}
+	return sb.String()
+}
+
+// ContractChecker validates live responses against the request and response
+// schemas stored in .falcon/spec.yaml by ingest_spec. The spec is re-read
+// whenever the file changes, so a checker can be shared by long-lived tools.
+type ContractChecker struct {
+	falconDir string
+
+	mu      sync.Mutex
+	graph   *APIKnowledgeGraph
+	modTime time.Time
+}
+
+// NewContractChecker creates a checker for the spec ingested into falconDir.
+func NewContractChecker(falconDir string) *ContractChecker {
+	return &ContractChecker{falconDir: falconDir}
+}
+
+// loadGraph returns the ingested knowledge graph, reloading it when spec.yaml changed.
+func (c *ContractChecker) loadGraph() (*APIKnowledgeGraph, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	path := filepath.Join(c.falconDir, "spec.yaml")
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no API spec ingested - run ingest_spec first")
+		}
+		return nil, fmt.Errorf("failed to read spec.yaml: %w", err)
+	}
+	if c.graph != nil && info.ModTime().Equal(c.modTime) {
+		return c.graph, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec.yaml: %w", err)
+	}
+	var graph APIKnowledgeGraph
+	if err := yaml.Unmarshal(data, &graph); err != nil {
+		return nil, fmt.Errorf("failed to parse spec.yaml: %w", err)
+	}
+	c.graph = &graph
+	c.modTime = info.ModTime()
+	return c.graph, nil
+}
+
+// Check validates a response to method rawURL against the spec. It reports
+// undocumented endpoints and status codes, content types the operation does
+// not produce, missing required fields, schema mismatches, and fields the
+// schema does not declare (as warnings). An error means the check could not
+// run at all, e.g. because no spec has been ingested.
+func (c *ContractChecker) Check(method, rawURL string, resp *HTTPResponse) (*ContractResult, error) {
+	graph, err := c.loadGraph()
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("no response to check")
+	}
+
+	result := &ContractResult{}
+	method = strings.ToUpper(method)
+	key, others := matchSpecEndpoint(graph, method, requestPath(rawURL))
+	if key == "" {
+		message := fmt.Sprintf("%s %s is not in the spec", method, requestPath(rawURL))
+		if len(others) > 0 {
+			message += fmt.Sprintf(" (documented methods: %s)", strings.Join(others, ", "))
+		}
+		result.Issues = append(result.Issues, ContractIssue{Kind: ContractUndocumentedEndpoint, Message: message})
+		return result, nil
+	}
+	result.Endpoint = key
+	endpoint := graph.Endpoints[key]
+
+	if len(endpoint.Responses) == 0 {
+		return result, nil // nothing documented to hold the response to
+	}
+	response, ok := matchSpecResponse(endpoint.Responses, resp.StatusCode)
+	if !ok {
+		result.Issues = append(result.Issues, ContractIssue{
+			Kind:    ContractUndocumentedStatus,
+			Message: fmt.Sprintf("status %d is not documented (documented: %s)", resp.StatusCode, strings.Join(responseStatuses(endpoint.Responses), ", ")),
+		})
+		return result, nil
+	}
+	result.Status = response.Status
+	if result.Status == "" {
+		result.Status = strconv.Itoa(response.StatusCode)
+	}
+
+	if len(response.Schemas) == 0 {
+		return result, nil
+	}
+	contentType := mediaType(headerValue(resp.Headers, "Content-Type"))
+	result.ContentType = contentType
+	documented, schema, ok := matchContentType(response.Schemas, contentType)
+	if !ok {
+		if contentType == "" && strings.TrimSpace(resp.Body) == "" {
+			return result, nil // an empty body without a content type is not a body
+		}
+		result.Issues = append(result.Issues, ContractIssue{
+			Kind:    ContractUndocumentedContentType,
+			Message: fmt.Sprintf("content type %q is not documented (documented: %s)", contentType, strings.Join(sortedKeys(response.Schemas), ", ")),
+		})
+		return result, nil
+	}
+	if result.ContentType == "" {
+		result.ContentType = documented
+	}
+
+	schemaMap, _ := schema.(map[string]interface{})
+	if schemaMap == nil || !strings.Contains(documented, "json") {
+		return result, nil
+	}
+	result.Issues = append(result.Issues, validateContractBody(schemaMap, resp.Body)...)
+	return result, nil
+}
+
+// validateContractBody validates a JSON body against a schema and looks for
+// fields the schema does not declare.
+func validateContractBody(schema map[string]interface{}, body string) []ContractIssue {
+	var data interface{}
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		return []ContractIssue{{Kind: ContractInvalidBody, Message: fmt.Sprintf("response body is not valid JSON: %v", err)}}
+	}
+
+	var issues []ContractIssue
+	validation, err := gojsonschema.Validate(gojsonschema.NewGoLoader(schema), gojsonschema.NewGoLoader(data))
+	if err != nil {
+		issues = append(issues, ContractIssue{
+			Kind:    ContractSchemaMismatch,
+			Message: fmt.Sprintf("the spec schema could not be used: %v", err),
+			Warning: true,
+		})
+	} else {
+		for _, e := range validation.Errors() {
+			switch e.Type() {
+			case "required":
+				issues = append(issues, ContractIssue{
+					Kind:    ContractMissingField,
+					Field:   contractField(e.Field(), e.Details()["property"]),
+					Message: "required by the spec",
+				})
+			case "additional_property_not_allowed":
+				issues = append(issues, ContractIssue{
+					Kind:    ContractExtraField,
+					Field:   contractField(e.Field(), e.Details()["property"]),
+					Message: "not allowed by the spec (additionalProperties: false)",
+				})
+			default:
+				issues = append(issues, ContractIssue{
+					Kind:    ContractSchemaMismatch,
+					Field:   contractField(e.Field(), nil),
+					Message: formatValidationError(e),
+				})
+			}
+		}
+	}
+
+	for _, field := range undeclaredFields(schema, data) {
+		issues = append(issues, ContractIssue{Kind: ContractExtraField, Field: field, Message: "not declared in the spec", Warning: true})
+	}
+	return issues
+}
+
+// contractField joins a gojsonschema context field and a property name.
+func contractField(field string, property interface{}) string {
+	if field == "(root)" {
+		field = ""
+	}
+	if property != nil {
+		if field == "" {
+			return fmt.Sprint(property)
+		}
+		return field + "." + fmt.Sprint(property)
+	}
+	if field == "" {
+		return "(root)"
+	}
+	return field
+}
+
+// undeclaredFields lists object fields in data that the schema does not
+// declare. Objects whose schema allows additional properties (or declares no
+// properties at all) are free-form and skipped. Array elements share one path
+// ("items[].id"), so a field repeated in every element is reported once.
+func undeclaredFields(schema map[string]interface{}, data interface{}) []string {
+	seen := make(map[string]bool)
+	var fields []string
+	var walk func(schema map[string]interface{}, value interface{}, path string)
+	walk = func(schema map[string]interface{}, value interface{}, path string) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			props, open := schemaProperties(schema)
+			for _, key := range sortedKeys(v) {
+				child := key
+				if path != "" {
+					child = path + "." + key
+				}
+				if sub, ok := props[key]; ok {
+					walk(sub, v[key], child)
+				} else if !open && len(props) > 0 && !seen[child] {
+					seen[child] = true
+					fields = append(fields, child)
+				}
+			}
+		case []interface{}:
+			items := schemaItems(schema)
+			if items == nil {
+				return
+			}
+			for _, elem := range v {
+				walk(items, elem, path+"[]")
+			}
+		}
+	}
+	walk(schema, data, "")
+	return fields
+}
+
+// schemaProperties collects the properties declared by a schema and its
+// allOf/oneOf/anyOf branches. open is true when extra fields are allowed or
+// are already reported by the validator (additionalProperties: false).
+func schemaProperties(schema map[string]interface{}) (props map[string]map[string]interface{}, open bool) {
+	props = make(map[string]map[string]interface{})
+	var collect func(s map[string]interface{})
+	collect = func(s map[string]interface{}) {
+		if s == nil {
+			return
+		}
+		if _, ok := s["additionalProperties"]; ok {
+			open = true
+		}
+		if _, ok := s["patternProperties"]; ok {
+			open = true
+		}
+		if declared, ok := s["properties"].(map[string]interface{}); ok {
+			for name, sub := range declared {
+				subSchema, _ := sub.(map[string]interface{})
+				if existing, ok := props[name]; !ok || existing == nil {
+					props[name] = subSchema
+				}
+			}
+		}
+		for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+			if branches, ok := s[key].([]interface{}); ok {
+				for _, branch := range branches {
+					branchSchema, _ := branch.(map[string]interface{})
+					collect(branchSchema)
+				}
+			}
+		}
+	}
+	collect(schema)
+	return props, open
+}
+
+// schemaItems returns the element schema of an array schema, looking through
+// allOf/oneOf/anyOf branches.
+func schemaItems(schema map[string]interface{}) map[string]interface{} {
+	if schema == nil {
+		return nil
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		return items
+	}
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		if branches, ok := schema[key].([]interface{}); ok {
+			for _, branch := range branches {
+				branchSchema, _ := branch.(map[string]interface{})
+				if items := schemaItems(branchSchema); items != nil {
+					return items
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// requestPath extracts the path of a request URL, ignoring the query string.
+func requestPath(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && (u.Scheme != "" || strings.HasPrefix(rawURL, "/")) {
+		if u.Path == "" {
+			return "/"
+		}
+		return u.Path
+	}
+	path, _, _ := strings.Cut(rawURL, "?")
+	return path
+}
+
+var specPathParam = regexp.MustCompile(`\{[^/{}]+\}`)
+
+// specPathPattern turns "/users/{id}" into a regexp matching "/users/42" at
+// the end of a request path, so server base paths ("/api/v1") are tolerated.
+func specPathPattern(template string) *regexp.Regexp {
+	template = "/" + strings.Trim(template, "/")
+	var sb strings.Builder
+	last := 0
+	for _, loc := range specPathParam.FindAllStringIndex(template, -1) {
+		sb.WriteString(regexp.QuoteMeta(template[last:loc[0]]))
+		sb.WriteString(`[^/]+`)
+		last = loc[1]
+	}
+	sb.WriteString(regexp.QuoteMeta(template[last:]))
+	return regexp.MustCompile(`^(.*?)` + sb.String() + `/?$`)
+}
+
+// matchSpecEndpoint finds the spec key for a request. Exact path matches win
+// over matches behind a base path, and templates with more literal text win
+// over more generic ones ("/users/me" over "/users/{id}"). When only other
+// methods match, they are returned so the caller can say so.
+func matchSpecEndpoint(graph *APIKnowledgeGraph, method, path string) (string, []string) {
+	best := ""
+	bestPrefix, bestLiteral := -1, -1
+	var otherMethods []string
+
+	for key := range graph.Endpoints {
+		specMethod, template, ok := strings.Cut(key, " ")
+		if !ok {
+			continue
+		}
+		m := specPathPattern(template).FindStringSubmatch(path)
+		if m == nil {
+			continue
+		}
+		if !strings.EqualFold(specMethod, method) {
+			otherMethods = append(otherMethods, strings.ToUpper(specMethod))
+			continue
+		}
+		prefix := len(m[1])
+		literal := len(specPathParam.ReplaceAllString(template, ""))
+		if best == "" || prefix < bestPrefix || (prefix == bestPrefix && literal > bestLiteral) ||
+			(prefix == bestPrefix && literal == bestLiteral && key < best) {
+			best, bestPrefix, bestLiteral = key, prefix, literal
+		}
+	}
+	sort.Strings(otherMethods)
+	return best, otherMethods
+}
+
+// matchSpecResponse picks the documented response for a status code: the
+// exact code first, then its range ("2XX"), then "default".
+func matchSpecResponse(responses []Response, code int) (Response, bool) {
+	exact := strconv.Itoa(code)
+	class := fmt.Sprintf("%dXX", code/100)
+	var ranged, fallback *Response
+	for i := range responses {
+		r := &responses[i]
+		status := strings.ToUpper(r.Status)
+		switch {
+		case r.StatusCode == code || status == exact:
+			return *r, true
+		case status == class:
+			ranged = r
+		case status == "DEFAULT":
+			fallback = r
+		}
+	}
+	if ranged != nil {
+		return *ranged, true
+	}
+	if fallback != nil {
+		return *fallback, true
+	}
+	return Response{}, false
+}
+
+func responseStatuses(responses []Response) []string {
+	var statuses []string
+	for _, r := range responses {
+		if r.Status != "" {
+			statuses = append(statuses, r.Status)
+		} else {
+			statuses = append(statuses, strconv.Itoa(r.StatusCode))
+		}
+	}
+	return statuses
+}
+
+// matchContentType finds the documented media type for a response content
+// type, honouring wildcards ("application/*", "*/*"). A response without a
+// content type matches when the spec documents exactly one.
+func matchContentType(schemas map[string]interface{}, contentType string) (string, interface{}, bool) {
+	if contentType == "" {
+		if len(schemas) == 1 {
+			for mt, schema := range schemas {
+				return mediaType(mt), schema, true
+			}
+		}
+		return "", nil, false
+	}
+	major, _, _ := strings.Cut(contentType, "/")
+	candidates := []string{contentType, major + "/*", "*/*"}
+	for _, want := range candidates {
+		for mt, schema := range schemas {
+			if mediaType(mt) == want {
+				return mediaType(mt), schema, true
+			}
+		}
+	}
+	return "", nil, false
+}
+
+// mediaType strips parameters from a content type: "application/json; charset=utf-8" -> "application/json".
+func mediaType(contentType string) string {
+	mt, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mt))
+}
+
+func headerValue(headers map[string]string, name string) string {
+	if v, ok := headers[name]; ok {
+		return v
+	}
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,104 @@
+package shared
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestMatchSpecEndpoint(t *testing.T) {
+	graph := &APIKnowledgeGraph{Endpoints: map[string]EndpointAnalysis{
+		"GET /users/{id}":        {},
+		"GET /users/me":          {},
+		"DELETE /users/{id}":     {},
+		"GET /files/{name}.json": {},
+	}}
+
+	cases := []struct {
+		method, path, want string
+	}{
+		{"GET", "/users/42", "GET /users/{id}"},
+		{"GET", "/users/me", "GET /users/me"},
+		{"get", "/api/v1/users/42/", "GET /users/{id}"},
+		{"GET", "/files/report.json", "GET /files/{name}.json"},
+		{"GET", "/users/42/posts", ""},
+	}
+	for _, tc := range cases {
+		if got, _ := matchSpecEndpoint(graph, strings.ToUpper(tc.method), tc.path); got != tc.want {
+			t.Errorf("%s %s matched %q, want %q", tc.method, tc.path, got, tc.want)
+		}
+	}
+
+	if got, others := matchSpecEndpoint(graph, "PUT", "/users/42"); got != "" || strings.Join(others, ",") != "DELETE,GET" {
+		t.Errorf("PUT /users/42 = %q, others %v; want no match and [DELETE GET]", got, others)
+	}
+}
+
+func TestUndeclaredFields(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(`{
+	  "type": "object",
+	  "properties": {
+	    "items": {"type": "array", "items": {"allOf": [
+	      {"properties": {"id": {"type": "integer"}}},
+	      {"properties": {"name": {"type": "string"}}}
+	    ]}},
+	    "meta": {"type": "object", "additionalProperties": true, "properties": {"total": {"type": "integer"}}}
+	  }
+	}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	var data interface{}
+	if err := json.Unmarshal([]byte(`{
+	  "items": [{"id": 1, "name": "a", "secret": "x"}, {"id": 2, "secret": "y"}],
+	  "meta": {"total": 2, "cursor": "abc"},
+	  "debug": true
+	}`), &data); err != nil {
+		t.Fatal(err)
+	}
+
+	got := undeclaredFields(schema, data)
+	if strings.Join(got, ",") != "debug,items[].secret" {
+		t.Errorf("undeclared fields = %v, want [debug items[].secret]", got)
+	}
+}
+
+func TestApplyContract(t *testing.T) {
+	falconDir := t.TempDir()
+	graph := APIKnowledgeGraph{Endpoints: map[string]EndpointAnalysis{
+		"GET /ping": {Responses: []Response{{StatusCode: 200, Status: "200", Schemas: map[string]interface{}{
+			"application/json": map[string]interface{}{
+				"type":       "object",
+				"required":   []interface{}{"ok"},
+				"properties": map[string]interface{}{"ok": map[string]interface{}{"type": "boolean"}},
+			},
+		}}}},
+	}}
+	data, err := yaml.Marshal(graph)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(falconDir, "spec.yaml"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	checker := NewContractChecker(falconDir)
+	req := HTTPRequest{Method: "GET", URL: "http://localhost/ping"}
+	headers := map[string]string{"Content-Type": "application/json"}
+
+	result := TestResult{Passed: true}
+	applyContract(&result, checker, req, &HTTPResponse{StatusCode: 200, Headers: headers, Body: `{"ok": true, "extra": 1}`})
+	if !result.Passed || result.Contract == nil || len(result.Contract.Issues) != 1 || !result.Contract.Issues[0].Warning {
+		t.Errorf("an extra field should only warn, got passed=%v contract=%+v", result.Passed, result.Contract)
+	}
+
+	result = TestResult{Passed: true}
+	applyContract(&result, checker, req, &HTTPResponse{StatusCode: 200, Headers: headers, Body: `{}`})
+	if result.Passed || !strings.Contains(result.Error, "missing_field: ok") {
+		t.Errorf("a missing required field should fail the scenario, got passed=%v error=%q", result.Passed, result.Error)
+	}
+}
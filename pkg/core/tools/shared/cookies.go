@@ -0,0 +1,406 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSession is the cookie session http_request uses when none is named.
+const DefaultSession = "default"
+
+// NoSession turns the cookie jar off for an http_request call.
+const NoSession = "none"
+
+// cookieSessionExt ends cookie session files, which share .falcon/sessions/
+// with the session_log audit records.
+const cookieSessionExt = ".cookies.json"
+
+var sessionNamePattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// StoredCookie is a cookie held by a CookieJar, in the form saved to
+// .falcon/sessions/.
+type StoredCookie struct {
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Domain   string    `json:"domain"`
+	Path     string    `json:"path"`
+	HostOnly bool      `json:"host_only,omitempty"` // sent to Domain only, not its subdomains
+	Secure   bool      `json:"secure,omitempty"`
+	HTTPOnly bool      `json:"http_only,omitempty"`
+	Expires  time.Time `json:"expires,omitzero"` // zero for session cookies
+}
+
+func (c StoredCookie) expired(now time.Time) bool {
+	return !c.Expires.IsZero() && !c.Expires.After(now)
+}
+
+// CookieJar is an http.CookieJar that can list and persist its cookies.
+// It follows the RFC 6265 domain and path rules, without a public suffix
+// list.
+type CookieJar struct {
+	mu      sync.Mutex
+	cookies []StoredCookie
+	changed bool // set by SetCookies, reset when the jar is saved
+}
+
+// NewCookieJar creates an empty cookie jar.
+func NewCookieJar() *CookieJar {
+	return &CookieJar{}
+}
+
+// SetCookies stores the cookies of a response from u (implements
+// http.CookieJar).
+func (j *CookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	host := strings.ToLower(u.Hostname())
+	now := time.Now()
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, c := range cookies {
+		stored := StoredCookie{
+			Name:     c.Name,
+			Value:    c.Value,
+			Domain:   host,
+			Path:     c.Path,
+			HostOnly: true,
+			Secure:   c.Secure,
+			HTTPOnly: c.HttpOnly,
+		}
+		if domain := strings.TrimPrefix(strings.ToLower(c.Domain), "."); domain != "" && domain != host {
+			if net.ParseIP(host) != nil || !strings.HasSuffix(host, "."+domain) {
+				continue // a server may not set cookies for another domain
+			}
+			stored.Domain, stored.HostOnly = domain, false
+		} else if domain != "" {
+			stored.HostOnly = false
+		}
+		if !strings.HasPrefix(stored.Path, "/") {
+			stored.Path = defaultCookiePath(u.Path)
+		}
+		switch {
+		case c.MaxAge < 0:
+			stored.Expires = now
+		case c.MaxAge > 0:
+			stored.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		case !c.Expires.IsZero():
+			stored.Expires = c.Expires
+		}
+
+		kept := j.cookies[:0]
+		for _, existing := range j.cookies {
+			if existing.Name != stored.Name || existing.Domain != stored.Domain || existing.Path != stored.Path {
+				kept = append(kept, existing)
+			}
+		}
+		j.cookies = kept
+		if !stored.expired(now) {
+			j.cookies = append(j.cookies, stored)
+		}
+		j.changed = true
+	}
+}
+
+// Cookies returns the cookies to send to u, longest path first (implements
+// http.CookieJar).
+func (j *CookieJar) Cookies(u *url.URL) []*http.Cookie {
+	host := strings.ToLower(u.Hostname())
+	reqPath := u.EscapedPath()
+	if reqPath == "" {
+		reqPath = "/"
+	}
+	now := time.Now()
+
+	j.mu.Lock()
+	var matched []StoredCookie
+	for _, c := range j.cookies {
+		if c.expired(now) || (c.Secure && u.Scheme != "https") {
+			continue
+		}
+		if c.HostOnly && host != c.Domain || !c.HostOnly && host != c.Domain && !strings.HasSuffix(host, "."+c.Domain) {
+			continue
+		}
+		if !cookiePathMatch(reqPath, c.Path) {
+			continue
+		}
+		matched = append(matched, c)
+	}
+	j.mu.Unlock()
+
+	sort.SliceStable(matched, func(a, b int) bool { return len(matched[a].Path) > len(matched[b].Path) })
+	cookies := make([]*http.Cookie, 0, len(matched))
+	for _, c := range matched {
+		cookies = append(cookies, &http.Cookie{Name: c.Name, Value: c.Value})
+	}
+	return cookies
+}
+
+// Lookup returns the value of the named cookie, preferring one that would be
+// sent to u.
+func (j *CookieJar) Lookup(u *url.URL, name string) (string, bool) {
+	if u != nil {
+		for _, c := range j.Cookies(u) {
+			if c.Name == name {
+				return c.Value, true
+			}
+		}
+	}
+	for _, c := range j.All() {
+		if c.Name == name {
+			return c.Value, true
+		}
+	}
+	return "", false
+}
+
+// All returns the unexpired cookies, sorted by domain, path and name.
+func (j *CookieJar) All() []StoredCookie {
+	now := time.Now()
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var cookies []StoredCookie
+	for _, c := range j.cookies {
+		if !c.expired(now) {
+			cookies = append(cookies, c)
+		}
+	}
+	sort.Slice(cookies, func(a, b int) bool {
+		if cookies[a].Domain != cookies[b].Domain {
+			return cookies[a].Domain < cookies[b].Domain
+		}
+		if cookies[a].Path != cookies[b].Path {
+			return cookies[a].Path < cookies[b].Path
+		}
+		return cookies[a].Name < cookies[b].Name
+	})
+	return cookies
+}
+
+// Clear removes every cookie.
+func (j *CookieJar) Clear() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.cookies = nil
+	j.changed = true
+}
+
+// takeChanged reports whether cookies were set since the last call.
+func (j *CookieJar) takeChanged() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	changed := j.changed
+	j.changed = false
+	return changed
+}
+
+// defaultCookiePath is the directory of the request path (RFC 6265 5.1.4).
+func defaultCookiePath(p string) string {
+	i := strings.LastIndex(p, "/")
+	if i <= 0 {
+		return "/"
+	}
+	return p[:i]
+}
+
+// cookiePathMatch reports whether a cookie path applies to a request path.
+func cookiePathMatch(reqPath, cookiePath string) bool {
+	if reqPath == cookiePath {
+		return true
+	}
+	if !strings.HasPrefix(reqPath, cookiePath) {
+		return false
+	}
+	return strings.HasSuffix(cookiePath, "/") || reqPath[len(cookiePath)] == '/'
+}
+
+// CookieSessions holds named cookie jars. A session that has been saved to
+// .falcon/sessions/<name>.cookies.json, or loaded from there, is written
+// back after every response that changes it.
+type CookieSessions struct {
+	dir        string // empty keeps sessions in memory only
+	mu         sync.Mutex
+	jars       map[string]*CookieJar
+	persistent map[string]bool
+}
+
+// sessionFile is the format of .falcon/sessions/<name>.cookies.json.
+type sessionFile struct {
+	Name    string         `json:"name"`
+	SavedAt time.Time      `json:"saved_at"`
+	Cookies []StoredCookie `json:"cookies"`
+}
+
+// NewCookieSessions creates the session store for falconDir. With an empty
+// falconDir, sessions cannot be saved.
+func NewCookieSessions(falconDir string) *CookieSessions {
+	s := &CookieSessions{jars: make(map[string]*CookieJar), persistent: make(map[string]bool)}
+	if falconDir != "" {
+		s.dir = filepath.Join(falconDir, "sessions")
+	}
+	return s
+}
+
+// Jar returns the named session's jar, loading it from .falcon/sessions/ the
+// first time if it was saved there.
+func (s *CookieSessions) Jar(name string) (*CookieJar, error) {
+	if !sessionNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid session name '%s' (use letters, digits, - and _)", name)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if jar, ok := s.jars[name]; ok {
+		return jar, nil
+	}
+	jar := NewCookieJar()
+	if cookies, err := s.read(name); err == nil {
+		jar.cookies = cookies
+		s.persistent[name] = true
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	s.jars[name] = jar
+	return jar, nil
+}
+
+// Names lists the sessions in memory and on disk.
+func (s *CookieSessions) Names() []string {
+	s.mu.Lock()
+	seen := make(map[string]bool)
+	for name := range s.jars {
+		seen[name] = true
+	}
+	s.mu.Unlock()
+	if s.dir != "" {
+		entries, _ := os.ReadDir(s.dir)
+		for _, e := range entries {
+			if name, ok := strings.CutSuffix(e.Name(), cookieSessionExt); ok && !e.IsDir() {
+				seen[name] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsPersistent reports whether the session is kept in .falcon/sessions/.
+func (s *CookieSessions) IsPersistent(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.persistent[name]
+}
+
+// Save writes the session to .falcon/sessions/<name>.cookies.json and keeps
+// the file up to date from then on. It returns the file path.
+func (s *CookieSessions) Save(name string) (string, error) {
+	if s.dir == "" {
+		return "", fmt.Errorf("sessions cannot be saved without a .falcon directory")
+	}
+	jar, err := s.Jar(name)
+	if err != nil {
+		return "", err
+	}
+	path, err := s.write(name, jar)
+	if err != nil {
+		return "", err
+	}
+	s.mu.Lock()
+	s.persistent[name] = true
+	s.mu.Unlock()
+	return path, nil
+}
+
+// Reload replaces the session's cookies with those saved on disk.
+func (s *CookieSessions) Reload(name string) (*CookieJar, error) {
+	if !sessionNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("invalid session name '%s' (use letters, digits, - and _)", name)
+	}
+	cookies, err := s.read(name)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("session '%s' has not been saved", name)
+		}
+		return nil, err
+	}
+	jar := NewCookieJar()
+	jar.cookies = cookies
+	s.mu.Lock()
+	s.jars[name] = jar
+	s.persistent[name] = true
+	s.mu.Unlock()
+	return jar, nil
+}
+
+// Delete forgets the session and removes its file.
+func (s *CookieSessions) Delete(name string) error {
+	if !sessionNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid session name '%s' (use letters, digits, - and _)", name)
+	}
+	s.mu.Lock()
+	delete(s.jars, name)
+	delete(s.persistent, name)
+	s.mu.Unlock()
+	if s.dir == "" {
+		return nil
+	}
+	if err := os.Remove(filepath.Join(s.dir, name+cookieSessionExt)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete session file: %w", err)
+	}
+	return nil
+}
+
+// sync writes a persistent session back to disk if its cookies changed.
+func (s *CookieSessions) sync(name string) error {
+	s.mu.Lock()
+	jar, persistent := s.jars[name], s.persistent[name]
+	s.mu.Unlock()
+	if jar == nil || !persistent || !jar.takeChanged() {
+		return nil
+	}
+	_, err := s.write(name, jar)
+	return err
+}
+
+func (s *CookieSessions) read(name string) ([]StoredCookie, error) {
+	if s.dir == "" {
+		return nil, os.ErrNotExist
+	}
+	data, err := os.ReadFile(filepath.Join(s.dir, name+cookieSessionExt))
+	if err != nil {
+		return nil, err
+	}
+	var file sessionFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid session file for '%s': %w", name, err)
+	}
+	return file.Cookies, nil
+}
+
+func (s *CookieSessions) write(name string, jar *CookieJar) (string, error) {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create sessions directory: %w", err)
+	}
+	jar.takeChanged()
+	data, err := json.MarshalIndent(sessionFile{Name: name, SavedAt: time.Now().UTC(), Cookies: jar.All()}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal session: %w", err)
+	}
+	path := filepath.Join(s.dir, name+cookieSessionExt)
+	// Cookies are credentials: keep the file private
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", fmt.Errorf("failed to write session file: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,120 @@
+package shared
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func newCookieServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "sid", Value: "s3cr3t-session", Path: "/"})
+		http.Redirect(w, r, "/home", http.StatusFound)
+	})
+	mux.HandleFunc("/home", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/me", http.StatusSeeOther)
+	})
+	mux.HandleFunc("/me", func(w http.ResponseWriter, r *http.Request) {
+		if c, err := r.Cookie("sid"); err != nil || c.Value != "s3cr3t-session" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(`{"user":"alice"}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPTool_CookieSessionAndRedirects(t *testing.T) {
+	server := newCookieServer(t)
+	dir := t.TempDir()
+	responses := NewResponseManager()
+	tool := NewHTTPTool(responses, nil)
+	tool.SetCookieSessions(NewCookieSessions(dir))
+
+	if _, err := tool.Execute(`{"method": "POST", "url": "` + server.URL + `/login"}`); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	resp := responses.GetHTTPResponse()
+	if resp.StatusCode != 200 || len(resp.Redirects) != 2 || resp.Redirects[0].StatusCode != 302 {
+		t.Fatalf("status %d, redirects %+v", resp.StatusCode, resp.Redirects)
+	}
+	if !strings.HasSuffix(resp.URL, "/me") {
+		t.Errorf("final URL = %q", resp.URL)
+	}
+
+	// The cookie was set on the first hop; extract_value reads it from the jar
+	extract := NewExtractTool(responses, NewVariableStore(dir))
+	if _, err := extract.Execute(`{"cookie": "sid", "save_as": "sid"}`); err != nil {
+		t.Errorf("extract cookie: %v", err)
+	}
+
+	// The default session sends the cookie with later calls; "none" does not
+	tool.Execute(`{"method": "GET", "url": "` + server.URL + `/me"}`)
+	if got := responses.GetHTTPResponse().StatusCode; got != 200 {
+		t.Errorf("with session: status %d", got)
+	}
+	tool.Execute(`{"method": "GET", "url": "` + server.URL + `/me", "session": "none"}`)
+	if got := responses.GetHTTPResponse().StatusCode; got != 401 {
+		t.Errorf("without session: status %d", got)
+	}
+
+	// Not following redirects returns the 302 itself
+	tool.Execute(`{"method": "POST", "url": "` + server.URL + `/login", "follow_redirects": false}`)
+	if got := responses.GetHTTPResponse(); got.StatusCode != 302 || got.Headers["Location"] != "/home" {
+		t.Errorf("follow_redirects false: status %d, Location %q", got.StatusCode, got.Headers["Location"])
+	}
+	if _, err := tool.Execute(`{"method": "POST", "url": "` + server.URL + `/login", "max_redirects": 1}`); err == nil {
+		t.Error("max_redirects 1: expected an error for the second redirect")
+	}
+
+	// A saved session is reloaded by a new process
+	if _, err := tool.CookieSessions().Save(DefaultSession); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	restarted := NewHTTPTool(nil, nil)
+	restarted.SetCookieSessions(NewCookieSessions(dir))
+	resp, err := restarted.Run(HTTPRequest{Method: "GET", URL: server.URL + "/me", Session: DefaultSession})
+	if err != nil || resp.StatusCode != 200 {
+		t.Errorf("after reload: status %v, err %v", resp, err)
+	}
+	if names := restarted.CookieSessions().Names(); len(names) != 1 || names[0] != DefaultSession {
+		t.Errorf("session names = %v", names)
+	}
+}
+
+func TestCookieJar_DomainAndPath(t *testing.T) {
+	jar := NewCookieJar()
+	origin, _ := url.Parse("https://api.example.com/v1/login")
+	jar.SetCookies(origin, []*http.Cookie{
+		{Name: "host", Value: "1"}, // host-only, path /v1
+		{Name: "wide", Value: "2", Domain: ".example.com", Path: "/"},
+		{Name: "other", Value: "3", Domain: "evil.com"}, // rejected
+		{Name: "secure", Value: "4", Path: "/", Secure: true},
+	})
+
+	names := func(raw string) string {
+		u, _ := url.Parse(raw)
+		var got []string
+		for _, c := range jar.Cookies(u) {
+			got = append(got, c.Name)
+		}
+		return strings.Join(got, ",")
+	}
+	if got := names("https://api.example.com/v1/users"); got != "host,wide,secure" {
+		t.Errorf("api.example.com/v1 = %q", got)
+	}
+	if got := names("http://www.example.com/v1x"); got != "wide" {
+		t.Errorf("www.example.com = %q", got)
+	}
+
+	jar.SetCookies(origin, []*http.Cookie{{Name: "wide", Domain: "example.com", Path: "/", MaxAge: -1}})
+	if got := names("https://example.com/"); got != "" {
+		t.Errorf("after delete = %q", got)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"math"
 	"os"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -219,158 +221,305 @@ func (t *CompareResponsesTool) removeFields(data interface{}, fields []string) i
 func (t *CompareResponsesTool) compareJSON(baseline, current interface{}, path string, params CompareParams) ComparisonResult {
 	result := ComparisonResult{Match: true}
 
+	for _, d := range DiffJSON(baseline, current, DiffOptions{Tolerance: params.Tolerance}) {
+		result.Match = false
+		result.Differences = append(result.Differences, d.Message)
+	}
+
+	return result
+}
+
+// Kinds of structural JSON differences reported by DiffJSON.
+const (
+	DiffAdded       = "added"
+	DiffRemoved     = "removed"
+	DiffChanged     = "changed"
+	DiffTypeChanged = "type_changed"
+	DiffArrayLength = "array_length"
+)
+
+// JSONDifference is a single structural difference between two JSON documents.
+type JSONDifference struct {
+	Path     string      `json:"path"` // JSONPath, e.g. $.data[0].id
+	Kind     string      `json:"kind"` // added, removed, changed, type_changed, array_length
+	Baseline interface{} `json:"baseline,omitempty"`
+	Current  interface{} `json:"current,omitempty"`
+	Message  string      `json:"message"`
+}
+
+// DiffOptions controls DiffJSON.
+type DiffOptions struct {
+	// IgnorePaths are JSONPath globs whose subtrees are skipped. A "*"
+	// segment (or "[*]") matches any single key or index and ".." matches
+	// any depth, e.g. "$.data[*].updated_at" or "$..request_id". Paths
+	// that ValidateIgnorePaths rejects match nothing.
+	IgnorePaths []string
+	// Tolerance is the relative numeric tolerance (0.01 = 1%).
+	Tolerance float64
+}
+
+// DiffJSON structurally compares two decoded JSON values and returns the
+// differences in a stable order (object keys sorted).
+func DiffJSON(baseline, current interface{}, opts DiffOptions) []JSONDifference {
+	ignore := make([][]string, 0, len(opts.IgnorePaths))
+	for _, p := range opts.IgnorePaths {
+		if segs, err := compileIgnorePath(p); err == nil {
+			ignore = append(ignore, segs)
+		}
+	}
+	var diffs []JSONDifference
+	diffJSON(baseline, current, []string{}, ignore, opts, &diffs)
+	return diffs
+}
+
+func diffJSON(baseline, current interface{}, segs []string, ignore [][]string, opts DiffOptions, diffs *[]JSONDifference) {
+	if isIgnoredPath(segs, ignore) {
+		return
+	}
+
+	path := joinJSONPath(segs)
+	// display keeps the legacy "a.b[0]" form used in compare_responses output.
+	display := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	add := func(kind string, b, c interface{}, msg string) {
+		*diffs = append(*diffs, JSONDifference{Path: path, Kind: kind, Baseline: b, Current: c, Message: msg})
+	}
+
 	switch baselineVal := baseline.(type) {
 	case map[string]interface{}:
 		currentMap, ok := current.(map[string]interface{})
 		if !ok {
-			result.Match = false
-			result.Differences = append(result.Differences,
-				fmt.Sprintf("Type mismatch at '%s': expected object, got %T", path, current))
-			return result
+			add(DiffTypeChanged, "object", jsonTypeName(current),
+				fmt.Sprintf("Type mismatch at '%s': expected object, got %s", display, jsonTypeName(current)))
+			return
 		}
 
-		// Check for missing fields in current
-		for key := range baselineVal {
-			keyPath := path + "." + key
-			if path == "" {
-				keyPath = key
-			}
-
+		for _, key := range sortedKeys(baselineVal) {
+			child := append(append([]string{}, segs...), key)
 			if _, exists := currentMap[key]; !exists {
-				result.Match = false
-				result.Differences = append(result.Differences,
-					fmt.Sprintf("Field removed: '%s'", keyPath))
-			} else {
-				// Recursively compare
-				subResult := t.compareJSON(baselineVal[key], currentMap[key], keyPath, params)
-				if !subResult.Match {
-					result.Match = false
-					result.Differences = append(result.Differences, subResult.Differences...)
+				if !isIgnoredPath(child, ignore) {
+					childPath := joinJSONPath(child)
+					*diffs = append(*diffs, JSONDifference{Path: childPath, Kind: DiffRemoved, Baseline: baselineVal[key],
+						Message: fmt.Sprintf("Field removed: '%s'", strings.TrimPrefix(childPath, "$."))})
 				}
+				continue
 			}
+			diffJSON(baselineVal[key], currentMap[key], child, ignore, opts, diffs)
 		}
 
-		// Check for new fields in current
-		for key := range currentMap {
-			if _, exists := baselineVal[key]; !exists {
-				keyPath := path + "." + key
-				if path == "" {
-					keyPath = key
-				}
-				result.Match = false
-				result.Differences = append(result.Differences,
-					fmt.Sprintf("Field added: '%s'", keyPath))
+		for _, key := range sortedKeys(currentMap) {
+			if _, exists := baselineVal[key]; exists {
+				continue
+			}
+			child := append(append([]string{}, segs...), key)
+			if !isIgnoredPath(child, ignore) {
+				childPath := joinJSONPath(child)
+				*diffs = append(*diffs, JSONDifference{Path: childPath, Kind: DiffAdded, Current: currentMap[key],
+					Message: fmt.Sprintf("Field added: '%s'", strings.TrimPrefix(childPath, "$."))})
 			}
 		}
 
 	case []interface{}:
 		currentArray, ok := current.([]interface{})
 		if !ok {
-			result.Match = false
-			result.Differences = append(result.Differences,
-				fmt.Sprintf("Type mismatch at '%s': expected array, got %T", path, current))
-			return result
+			add(DiffTypeChanged, "array", jsonTypeName(current),
+				fmt.Sprintf("Type mismatch at '%s': expected array, got %s", display, jsonTypeName(current)))
+			return
 		}
 
 		if len(baselineVal) != len(currentArray) {
-			result.Match = false
-			result.Differences = append(result.Differences,
+			add(DiffArrayLength, len(baselineVal), len(currentArray),
 				fmt.Sprintf("Array length mismatch at '%s': baseline has %d items, current has %d",
-					path, len(baselineVal), len(currentArray)))
+					display, len(baselineVal), len(currentArray)))
 		}
 
-		// Compare array elements
 		minLen := len(baselineVal)
 		if len(currentArray) < minLen {
 			minLen = len(currentArray)
 		}
-
 		for i := 0; i < minLen; i++ {
-			itemPath := fmt.Sprintf("%s[%d]", path, i)
-			subResult := t.compareJSON(baselineVal[i], currentArray[i], itemPath, params)
-			if !subResult.Match {
-				result.Match = false
-				result.Differences = append(result.Differences, subResult.Differences...)
-			}
+			child := append(append([]string{}, segs...), fmt.Sprintf("[%d]", i))
+			diffJSON(baselineVal[i], currentArray[i], child, ignore, opts, diffs)
 		}
 
 	case float64:
 		currentFloat, ok := current.(float64)
 		if !ok {
-			result.Match = false
-			result.Differences = append(result.Differences,
-				fmt.Sprintf("Type mismatch at '%s': expected number, got %T", path, current))
-			return result
+			add(DiffTypeChanged, "number", jsonTypeName(current),
+				fmt.Sprintf("Type mismatch at '%s': expected number, got %s", display, jsonTypeName(current)))
+			return
 		}
 
 		// Apply tolerance if specified
-		if params.Tolerance > 0 {
+		if opts.Tolerance > 0 {
 			diff := math.Abs(baselineVal - currentFloat)
-			allowedDiff := math.Abs(baselineVal * params.Tolerance)
+			allowedDiff := math.Abs(baselineVal * opts.Tolerance)
 			if diff > allowedDiff {
-				result.Match = false
-				result.Differences = append(result.Differences,
+				add(DiffChanged, baselineVal, currentFloat,
 					fmt.Sprintf("Numeric difference at '%s': baseline=%.2f, current=%.2f (diff=%.2f, tolerance=%.2f%%)",
-						path, baselineVal, currentFloat, diff, params.Tolerance*100))
+						display, baselineVal, currentFloat, diff, opts.Tolerance*100))
 			}
 		} else if baselineVal != currentFloat {
-			result.Match = false
-			result.Differences = append(result.Differences,
-				fmt.Sprintf("Value changed at '%s': baseline=%.2f, current=%.2f",
-					path, baselineVal, currentFloat))
+			add(DiffChanged, baselineVal, currentFloat,
+				fmt.Sprintf("Value changed at '%s': baseline=%.2f, current=%.2f", display, baselineVal, currentFloat))
 		}
 
 	case string:
 		currentStr, ok := current.(string)
 		if !ok {
-			result.Match = false
-			result.Differences = append(result.Differences,
-				fmt.Sprintf("Type mismatch at '%s': expected string, got %T", path, current))
-			return result
+			add(DiffTypeChanged, "string", jsonTypeName(current),
+				fmt.Sprintf("Type mismatch at '%s': expected string, got %s", display, jsonTypeName(current)))
+			return
 		}
-
 		if baselineVal != currentStr {
-			result.Match = false
-			result.Differences = append(result.Differences,
-				fmt.Sprintf("Value changed at '%s': baseline='%s', current='%s'",
-					path, baselineVal, currentStr))
+			add(DiffChanged, baselineVal, currentStr,
+				fmt.Sprintf("Value changed at '%s': baseline='%s', current='%s'", display, baselineVal, currentStr))
 		}
 
 	case bool:
 		currentBool, ok := current.(bool)
 		if !ok {
-			result.Match = false
-			result.Differences = append(result.Differences,
-				fmt.Sprintf("Type mismatch at '%s': expected boolean, got %T", path, current))
-			return result
+			add(DiffTypeChanged, "boolean", jsonTypeName(current),
+				fmt.Sprintf("Type mismatch at '%s': expected boolean, got %s", display, jsonTypeName(current)))
+			return
 		}
-
 		if baselineVal != currentBool {
-			result.Match = false
-			result.Differences = append(result.Differences,
-				fmt.Sprintf("Value changed at '%s': baseline=%t, current=%t",
-					path, baselineVal, currentBool))
+			add(DiffChanged, baselineVal, currentBool,
+				fmt.Sprintf("Value changed at '%s': baseline=%t, current=%t", display, baselineVal, currentBool))
 		}
 
 	case nil:
 		if current != nil {
-			result.Match = false
-			result.Differences = append(result.Differences,
-				fmt.Sprintf("Value changed at '%s': baseline=null, current=%v",
-					path, current))
+			add(DiffTypeChanged, "null", jsonTypeName(current),
+				fmt.Sprintf("Value changed at '%s': baseline=null, current=%v", display, current))
 		}
 
 	default:
 		// Fallback to simple equality
-		if baseline != current {
-			result.Match = false
-			result.Differences = append(result.Differences,
-				fmt.Sprintf("Value changed at '%s': baseline=%v, current=%v",
-					path, baseline, current))
+		if !deepEqual(baseline, current) {
+			add(DiffChanged, baseline, current,
+				fmt.Sprintf("Value changed at '%s': baseline=%v, current=%v", display, baseline, current))
 		}
 	}
+}
 
-	return result
+// jsonTypeName returns the JSON type name of a decoded value.
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// joinJSONPath renders path segments ("key" or "[0]") as a JSONPath string.
+func joinJSONPath(segs []string) string {
+	var sb strings.Builder
+	sb.WriteString("$")
+	for _, seg := range segs {
+		if strings.HasPrefix(seg, "[") {
+			sb.WriteString(seg)
+		} else {
+			sb.WriteString(".")
+			sb.WriteString(seg)
+		}
+	}
+	return sb.String()
+}
+
+// ValidateIgnorePaths checks that every path is a JSONPath DiffJSON can
+// match against, so broken rules are rejected instead of silently ignoring
+// nothing.
+func ValidateIgnorePaths(paths []string) error {
+	for _, p := range paths {
+		if _, err := compileIgnorePath(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compileIgnorePath parses an ignore path with ParseJSONPath and turns it
+// into match segments: keys become "key", indices "[0]", wildcards "*" and
+// recursive descent "..". Filters, slices, unions and negative indices
+// depend on the values being compared, so they are refused.
+func compileIgnorePath(pattern string) ([]string, error) {
+	jp, err := ParseJSONPath(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ignore path: %w", err)
+	}
+	if len(jp.segments) == 0 {
+		return nil, fmt.Errorf("invalid ignore path '%s': it would skip the whole body", pattern)
+	}
+
+	var segs []string
+	for _, seg := range jp.segments {
+		if seg.recursive {
+			segs = append(segs, "..")
+		}
+		switch {
+		case seg.kind == segWildcard:
+			segs = append(segs, "*")
+		case seg.kind == segChild && len(seg.names) == 1:
+			segs = append(segs, seg.names[0])
+		case seg.kind == segIndex && len(seg.indexes) == 1 && seg.indexes[0] >= 0:
+			segs = append(segs, "["+strconv.Itoa(seg.indexes[0])+"]")
+		default:
+			return nil, fmt.Errorf("invalid ignore path '%s': only keys, indices, * and .. are supported", pattern)
+		}
+	}
+	return segs, nil
+}
+
+// isIgnoredPath reports whether path (or an ancestor) matches any pattern.
+func isIgnoredPath(path []string, patterns [][]string) bool {
+	for _, pattern := range patterns {
+		if len(pattern) > 0 && matchPathPrefix(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPathPrefix reports whether pattern fully matches a prefix of path.
+func matchPathPrefix(pattern, path []string) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+	if pattern[0] == ".." {
+		for i := 0; i <= len(path); i++ {
+			if matchPathPrefix(pattern[1:], path[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(path) == 0 {
+		return false
+	}
+	if pattern[0] != "*" && pattern[0] != path[0] {
+		return false
+	}
+	return matchPathPrefix(pattern[1:], path[1:])
 }
 
 // formatComparison formats the comparison result
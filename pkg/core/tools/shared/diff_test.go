@@ -0,0 +1,74 @@
+package shared
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func decodeJSON(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("invalid JSON %q: %v", s, err)
+	}
+	return v
+}
+
+func TestDiffJSON_Kinds(t *testing.T) {
+	baseline := decodeJSON(t, `{"id":1,"name":"a","tags":["x","y"],"meta":{"v":1}}`)
+	current := decodeJSON(t, `{"id":"1","name":"b","tags":["x"],"extra":true}`)
+
+	got := map[string]string{}
+	for _, d := range DiffJSON(baseline, current, DiffOptions{}) {
+		got[d.Path] = d.Kind
+	}
+
+	want := map[string]string{
+		"$.id":    DiffTypeChanged,
+		"$.name":  DiffChanged,
+		"$.tags":  DiffArrayLength,
+		"$.meta":  DiffRemoved,
+		"$.extra": DiffAdded,
+	}
+	for path, kind := range want {
+		if got[path] != kind {
+			t.Errorf("%s: expected %s, got %q", path, kind, got[path])
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("expected %d differences, got %d: %v", len(want), len(got), got)
+	}
+}
+
+func TestDiffJSON_IgnorePaths(t *testing.T) {
+	baseline := decodeJSON(t, `{"data":[{"id":1,"updated_at":"t1"},{"id":2,"updated_at":"t2"}],"meta":{"request_id":"r1"}}`)
+	current := decodeJSON(t, `{"data":[{"id":1,"updated_at":"t3"},{"id":2,"updated_at":"t4"}],"meta":{"request_id":"r2"}}`)
+
+	diffs := DiffJSON(baseline, current, DiffOptions{IgnorePaths: []string{"$.data[*].updated_at", "$..request_id"}})
+	if len(diffs) != 0 {
+		t.Errorf("expected no differences, got %v", diffs)
+	}
+
+	diffs = DiffJSON(baseline, current, DiffOptions{IgnorePaths: []string{"$.data[0].updated_at"}})
+	if len(diffs) != 2 || diffs[0].Path != "$.data[1].updated_at" || diffs[1].Path != "$.meta.request_id" {
+		t.Errorf("unexpected differences: %v", diffs)
+	}
+
+	diffs = DiffJSON(baseline, current, DiffOptions{IgnorePaths: []string{"$['meta']['request_id']", "data.*.updated_at"}})
+	if len(diffs) != 0 {
+		t.Errorf("bracket and bare paths: unexpected differences: %v", diffs)
+	}
+}
+
+func TestValidateIgnorePaths(t *testing.T) {
+	valid := []string{"$.data[*].updated_at", "$..request_id", "$['meta']['id']", "items[0].ts", "$..*"}
+	if err := ValidateIgnorePaths(valid); err != nil {
+		t.Errorf("valid paths rejected: %v", err)
+	}
+
+	for _, p := range []string{"$.data[", "$.a..", "$.items[?(@.id > 1)]", "$.items[0:2]", "$.items[-1]", "$['a','b']", "$", ""} {
+		if err := ValidateIgnorePaths([]string{p}); err == nil {
+			t.Errorf("%q: expected an error", p)
+		}
+	}
+}
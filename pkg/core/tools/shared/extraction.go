@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
-	"strings"
 )
 
 // ExtractTool extracts values from HTTP responses for use in subsequent requests
@@ -118,12 +117,12 @@ func (t *ExtractTool) Execute(args string) (string, error) {
 
 // extractFromJSONPath extracts a value using JSON path notation
 func (t *ExtractTool) extractFromJSONPath(path string, lastResponse *HTTPResponse) (string, error) {
-	var jsonData map[string]interface{}
+	var jsonData interface{}
 	if err := json.Unmarshal([]byte(lastResponse.Body), &jsonData); err != nil {
 		return "", fmt.Errorf("response body is not valid JSON: %w", err)
 	}
 
-	value, err := getJSONPath(jsonData, path)
+	value, err := EvalJSONPath(jsonData, path)
 	if err != nil {
 		return "", err
 	}
@@ -143,26 +142,27 @@ func (t *ExtractTool) extractFromJSONPath(path string, lastResponse *HTTPRespons
 	}
 }
 
-// extractCookie extracts a cookie value from Set-Cookie headers
-func (t *ExtractTool) extractCookie(cookieName string, lastResponse *HTTPResponse) (string, error) {
-	setCookie, ok := lastResponse.Headers["Set-Cookie"]
-	if !ok {
-		return "", fmt.Errorf("no Set-Cookie header found")
+// ExtractJSONPathValue parses body as JSON and returns the value at path.
+// It is the exported entry point for tools that extract from responses they
+// hold themselves rather than from the ResponseManager.
+func ExtractJSONPathValue(body, path string) (interface{}, error) {
+	var jsonData interface{}
+	if err := json.Unmarshal([]byte(body), &jsonData); err != nil {
+		return nil, fmt.Errorf("response body is not valid JSON: %w", err)
 	}
+	return EvalJSONPath(jsonData, path)
+}
 
-	// Parse Set-Cookie header (can be comma-separated list)
-	cookies := strings.Split(setCookie, ",")
-	for _, cookie := range cookies {
-		// Cookie format: "name=value; Path=/; HttpOnly"
-		parts := strings.Split(strings.TrimSpace(cookie), ";")
-		if len(parts) > 0 {
-			nameValue := strings.SplitN(parts[0], "=", 2)
-			if len(nameValue) == 2 && strings.TrimSpace(nameValue[0]) == cookieName {
-				return strings.TrimSpace(nameValue[1]), nil
-			}
-		}
+// extractCookie reads a cookie from the response's cookie session, so
+// cookies set during redirects are found too; without a session it falls
+// back to the Set-Cookie header.
+func (t *ExtractTool) extractCookie(cookieName string, lastResponse *HTTPResponse) (string, error) {
+	if value, ok := lastResponse.Cookie(cookieName); ok {
+		return value, nil
+	}
+	if lastResponse.Session != "" {
+		return "", fmt.Errorf("cookie '%s' not found in session '%s'", cookieName, lastResponse.Session)
 	}
-
 	return "", fmt.Errorf("cookie '%s' not found in Set-Cookie header", cookieName)
 }
 
@@ -2,11 +2,14 @@ package shared
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -19,6 +22,12 @@ type HTTPTool struct {
 	responseManager *ResponseManager
 	varStore        *VariableStore
 	defaultTimeout  time.Duration
+	contract        *ContractChecker
+	sessions        *CookieSessions
+	transport       func() TransportConfig // Settings of the active environment
+
+	transportsMu sync.Mutex
+	transports   map[string]*http.Transport
 }
 
 // NewHTTPTool creates a new HTTP tool with the default 30-second timeout.
@@ -30,6 +39,7 @@ func NewHTTPTool(responseManager *ResponseManager, varStore *VariableStore) *HTT
 		responseManager: responseManager,
 		varStore:        varStore,
 		defaultTimeout:  DefaultHTTPTimeout,
+		sessions:        NewCookieSessions(""),
 	}
 }
 
@@ -39,13 +49,64 @@ func (t *HTTPTool) SetTimeout(timeout time.Duration) {
 	t.client.Timeout = timeout
 }
 
+// SetContractChecker enables the "contract" request option, which validates
+// the response against the ingested API spec.
+func (t *HTTPTool) SetContractChecker(checker *ContractChecker) {
+	t.contract = checker
+}
+
+// SetCookieSessions sets the cookie sessions requests use, e.g. ones that can
+// be saved to .falcon/sessions/.
+func (t *HTTPTool) SetCookieSessions(sessions *CookieSessions) {
+	t.sessions = sessions
+}
+
+// CookieSessions returns the cookie sessions requests use.
+func (t *HTTPTool) CookieSessions() *CookieSessions {
+	return t.sessions
+}
+
+// SetTransport sets the TLS, proxy and HTTP version settings every request
+// uses, e.g. those of the environment given on the command line.
+func (t *HTTPTool) SetTransport(config TransportConfig) {
+	t.transport = func() TransportConfig { return config }
+}
+
+// SetTransportSource reads the settings before each request, so switching
+// environments applies to the next one.
+func (t *HTTPTool) SetTransportSource(source func() TransportConfig) {
+	t.transport = source
+}
+
+// DefaultMaxRedirects is how many redirects a request follows by default.
+const DefaultMaxRedirects = 10
+
 // HTTPRequest represents an HTTP request.
 type HTTPRequest struct {
 	Method  string            `json:"method"`
 	URL     string            `json:"url"`
 	Headers map[string]string `json:"headers,omitempty"`
+	Query   map[string]string `json:"query,omitempty"` // Added to the URL's query string
 	Body    interface{}       `json:"body,omitempty"`
-	Timeout int               `json:"timeout,omitempty"`
+	// BodyType selects how Body is encoded: json, form, multipart, text, xml
+	// or binary. Without it, it follows the Content-Type header (default json).
+	BodyType string `json:"body_type,omitempty"`
+	// BodyFile is sent as the body instead of Body, e.g. for binary uploads.
+	BodyFile string `json:"body_file,omitempty"`
+	Timeout  int    `json:"timeout,omitempty"`
+	// Session names the cookie jar that stores and sends cookies. Run uses
+	// no jar without one; http_request uses the "default" session ("none"
+	// turns cookies off).
+	Session string `json:"session,omitempty"`
+	// Jar is used instead of Session, e.g. for cookies private to one flow run.
+	Jar *CookieJar `json:"-"`
+	// FollowRedirects defaults to true, up to MaxRedirects (default 10).
+	FollowRedirects *bool `json:"follow_redirects,omitempty"`
+	MaxRedirects    int   `json:"max_redirects,omitempty"`
+	// Transport overrides the environment's TLS, proxy and HTTP version settings.
+	Transport *TransportConfig `json:"transport,omitempty"`
+	// Contract validates the response against .falcon/spec.yaml (http_request only).
+	Contract bool `json:"contract,omitempty"`
 }
 
 // HTTPResponse represents an HTTP response.
@@ -55,6 +116,46 @@ type HTTPResponse struct {
 	Headers    map[string]string `json:"headers"`
 	Body       string            `json:"body"`
 	Duration   time.Duration     `json:"duration"`
+	URL        string            `json:"url,omitempty"`       // Final URL, after redirects
+	Redirects  []Redirect        `json:"redirects,omitempty"` // Redirects followed, in order
+	Session    string            `json:"session,omitempty"`   // Cookie session used
+	Proto      string            `json:"proto,omitempty"`     // e.g. HTTP/1.1, HTTP/2.0
+	TLS        *TLSInfo          `json:"tls,omitempty"`       // Nil for plain HTTP
+
+	jar *CookieJar
+}
+
+// Redirect is one hop of a followed redirect.
+type Redirect struct {
+	StatusCode int    `json:"status_code"`
+	From       string `json:"from"`
+	To         string `json:"to"`
+}
+
+// Cookie returns the named cookie from the response's cookie session, which
+// also holds cookies set during redirects. Without a session, it reads the
+// Set-Cookie header.
+func (r *HTTPResponse) Cookie(name string) (string, bool) {
+	if r.jar != nil {
+		var u *url.URL
+		if r.URL != "" {
+			u, _ = url.Parse(r.URL)
+		}
+		return r.jar.Lookup(u, name)
+	}
+	for key, value := range r.Headers {
+		if !strings.EqualFold(key, "Set-Cookie") {
+			continue
+		}
+		// Cookie format: "name=value; Path=/; HttpOnly", comma-separated
+		for _, cookie := range strings.Split(value, ",") {
+			nameValue, _, _ := strings.Cut(strings.TrimSpace(cookie), ";")
+			if n, v, ok := strings.Cut(nameValue, "="); ok && strings.TrimSpace(n) == name {
+				return strings.TrimSpace(v), true
+			}
+		}
+	}
+	return "", false
 }
 
 // Name returns the tool name.
@@ -69,7 +170,30 @@ func (t *HTTPTool) Description() string {
 
 // Parameters returns the tool parameter description.
 func (t *HTTPTool) Parameters() string {
-	return `{"method": "GET|POST|PUT|DELETE", "url": "string", "headers": {"key": "value"}, "body": {}, "timeout": 30}`
+	return `{"method": "GET|POST|PUT|DELETE", "url": "string", "headers": {"key": "value"}, "query": {"page": "2"}, "body": {}, "body_type": "json|form|multipart|text|xml|binary", "body_file": "path", "timeout": 30, "session": "default", "follow_redirects": true, "max_redirects": 10, "transport": {"ca_cert": "certs/ca.pem", "client_cert": "certs/client.pem", "client_key": "certs/client.key", "insecure_skip_verify": false, "proxy": "http://proxy:8080", "http_version": "1.1|2"}, "contract": true}`
+}
+
+// InputSchema returns the JSON Schema used for native tool calling (implements core.SchemaTool).
+func (t *HTTPTool) InputSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"method":           map[string]interface{}{"type": "string", "enum": []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}},
+			"url":              map[string]interface{}{"type": "string", "description": "Absolute URL; supports {{VAR}} placeholders"},
+			"headers":          map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}},
+			"query":            map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "string"}, "description": "Query parameters added to the URL"},
+			"body":             map[string]interface{}{"description": "Request body: any JSON value for json, an object of fields for form and multipart (\"@path\" uploads a file), a string for text, xml and binary"},
+			"body_type":        map[string]interface{}{"type": "string", "enum": []string{BodyTypeJSON, BodyTypeForm, BodyTypeMultipart, BodyTypeText, BodyTypeXML, BodyTypeBinary}, "description": "How the body is encoded (default: from the Content-Type header, else json)"},
+			"body_file":        map[string]interface{}{"type": "string", "description": "File in the project sent as the body (binary uploads, large payloads)"},
+			"timeout":          map[string]interface{}{"type": "integer", "description": "Timeout in seconds"},
+			"session":          map[string]interface{}{"type": "string", "description": "Cookie session that keeps cookies between requests (default: \"default\"; \"none\" sends and keeps no cookies)"},
+			"follow_redirects": map[string]interface{}{"type": "boolean", "description": "Follow 3xx redirects (default true); the chain is shown in the response"},
+			"max_redirects":    map[string]interface{}{"type": "integer", "description": "Redirects to follow before failing (default 10)"},
+			"transport":        transportSchema(),
+			"contract":         map[string]interface{}{"type": "boolean", "description": "Validate the response against the ingested API spec (status, content type, required and extra fields)"},
+		},
+		"required": []string{"method", "url"},
+	}
 }
 
 // Execute performs an HTTP request (implements core.Tool).
@@ -82,6 +206,12 @@ func (t *HTTPTool) Execute(args string) (string, error) {
 	if err := json.Unmarshal([]byte(args), &req); err != nil {
 		return "", fmt.Errorf("failed to parse arguments: %w", err)
 	}
+	switch req.Session {
+	case "":
+		req.Session = DefaultSession
+	case NoSession:
+		req.Session = ""
+	}
 
 	resp, err := t.Run(req)
 	if err != nil {
@@ -92,11 +222,32 @@ func (t *HTTPTool) Execute(args string) (string, error) {
 		t.responseManager.SetHTTPResponse(resp)
 	}
 
-	return resp.FormatResponse(), nil
+	output := resp.FormatResponse()
+	if req.Contract {
+		output += "\n\n" + t.checkContract(req, resp)
+	}
+	return output, nil
+}
+
+// checkContract runs the contract check for a request made with "contract": true.
+func (t *HTTPTool) checkContract(req HTTPRequest, resp *HTTPResponse) string {
+	if t.contract == nil {
+		return "⚠️ Contract check unavailable: no spec checker configured"
+	}
+	result, err := t.contract.Check(req.Method, req.URL, resp)
+	if err != nil {
+		return fmt.Sprintf("⚠️ Contract check skipped: %v", err)
+	}
+	return result.Format()
 }
 
 // Run performs an HTTP request and returns the response.
 func (t *HTTPTool) Run(req HTTPRequest) (*HTTPResponse, error) {
+	return t.RunContext(context.Background(), req)
+}
+
+// RunContext is Run with a context that aborts the request when cancelled.
+func (t *HTTPTool) RunContext(ctx context.Context, req HTTPRequest) (*HTTPResponse, error) {
 	startTime := time.Now()
 
 	timeout := t.defaultTimeout
@@ -104,36 +255,77 @@ func (t *HTTPTool) Run(req HTTPRequest) (*HTTPResponse, error) {
 		timeout = time.Duration(req.Timeout) * time.Second
 	}
 
-	client := t.client
-	if timeout != t.defaultTimeout {
-		client = &http.Client{
-			Timeout:   timeout,
-			Transport: t.client.Transport,
+	maxRedirects := DefaultMaxRedirects
+	if req.MaxRedirects > 0 {
+		maxRedirects = req.MaxRedirects
+	}
+	transport, err := t.transportFor(req.Transport)
+	if err != nil {
+		return nil, err
+	}
+	var redirects []Redirect
+	client := &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(next *http.Request, via []*http.Request) error {
+			if req.FollowRedirects != nil && !*req.FollowRedirects {
+				return http.ErrUseLastResponse
+			}
+			if len(via) > maxRedirects {
+				return fmt.Errorf("stopped after %d redirects", maxRedirects)
+			}
+			redirects = append(redirects, Redirect{
+				StatusCode: next.Response.StatusCode,
+				From:       via[len(via)-1].URL.String(),
+				To:         next.URL.String(),
+			})
+			return nil
+		},
+	}
+	jar := req.Jar
+	if jar == nil && req.Session != "" {
+		if jar, err = t.sessions.Jar(req.Session); err != nil {
+			return nil, err
 		}
 	}
+	if jar != nil {
+		client.Jar = jar
+	}
 
+	target, err := req.FullURL()
+	if err != nil {
+		return nil, err
+	}
+	body, contentType, err := req.EncodeBody()
+	if err != nil {
+		return nil, err
+	}
 	var bodyReader io.Reader
-	if req.Body != nil {
-		jsonBody, err := json.Marshal(req.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal body: %w", err)
-		}
-		bodyReader = bytes.NewBuffer(jsonBody)
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
 	}
 
-	httpReq, err := http.NewRequest(strings.ToUpper(req.Method), req.URL, bodyReader)
+	httpReq, err := http.NewRequestWithContext(ctx, strings.ToUpper(req.Method), target, bodyReader)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	if req.Body != nil {
-		httpReq.Header.Set("Content-Type", "application/json")
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
 	}
+	multipartBody := strings.HasPrefix(contentType, "multipart/")
 	for key, value := range req.Headers {
+		if multipartBody && strings.EqualFold(key, "Content-Type") {
+			continue // the generated one carries the boundary
+		}
 		httpReq.Header.Set(key, value)
 	}
 
 	httpResp, err := client.Do(httpReq)
+	if req.Jar == nil && req.Session != "" {
+		// a failed sync leaves the saved file behind the jar until the next response
+		t.sessions.sync(req.Session)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -155,6 +347,12 @@ func (t *HTTPTool) Run(req HTTPRequest) (*HTTPResponse, error) {
 		Headers:    headers,
 		Body:       string(bodyBytes),
 		Duration:   time.Since(startTime),
+		URL:        httpResp.Request.URL.String(),
+		Redirects:  redirects,
+		Session:    req.Session,
+		Proto:      httpResp.Proto,
+		TLS:        newTLSInfo(httpResp.TLS),
+		jar:        jar,
 	}, nil
 }
 
@@ -207,7 +405,20 @@ func (r *HTTPResponse) FormatResponse() string {
 	sb.WriteString(fmt.Sprintf("Size:   %s\n", sizeStr))
 	sb.WriteString(fmt.Sprintf("Meaning: %s\n\n", StatusCodeMeaning(r.StatusCode)))
 
-	importantHeaders := []string{"Content-Type", "Authorization", "X-Request-Id", "X-Error-Code"}
+	if r.TLS != nil {
+		sb.WriteString(r.TLS.Format(r.Proto))
+		sb.WriteString("\n")
+	}
+
+	if len(r.Redirects) > 0 {
+		sb.WriteString("Redirects:\n")
+		for _, hop := range r.Redirects {
+			sb.WriteString(fmt.Sprintf("  %d %s -> %s\n", hop.StatusCode, hop.From, hop.To))
+		}
+		sb.WriteString("\n")
+	}
+
+	importantHeaders := []string{"Content-Type", "Location", "Authorization", "X-Request-Id", "X-Error-Code"}
 	sb.WriteString("Headers:\n")
 	for _, key := range importantHeaders {
 		if value, ok := r.Headers[key]; ok {
@@ -0,0 +1,223 @@
+package shared
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Body types for HTTPRequest.BodyType.
+const (
+	BodyTypeJSON      = "json"      // Body marshaled as JSON; a string is sent as is
+	BodyTypeForm      = "form"      // application/x-www-form-urlencoded from an object (or an encoded string)
+	BodyTypeMultipart = "multipart" // multipart/form-data; "@path" values upload files
+	BodyTypeText      = "text"      // raw text/plain
+	BodyTypeXML       = "xml"       // raw application/xml
+	BodyTypeBinary    = "binary"    // raw bytes, usually from BodyFile
+)
+
+// FullURL returns the request URL with the Query parameters added.
+func (r HTTPRequest) FullURL() (string, error) {
+	if len(r.Query) == 0 {
+		return r.URL, nil
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL '%s': %w", r.URL, err)
+	}
+	q := u.Query()
+	for key, value := range r.Query {
+		q.Set(key, value)
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// BodyKind returns the body type, inferred from the Content-Type header when
+// BodyType is not set, so imported form and XML requests are sent as they
+// were recorded.
+func (r HTTPRequest) BodyKind() string {
+	if r.BodyType != "" {
+		return strings.ToLower(r.BodyType)
+	}
+	var contentType string
+	for key, value := range r.Headers {
+		if strings.EqualFold(key, "Content-Type") {
+			contentType = strings.ToLower(value)
+		}
+	}
+	switch {
+	case strings.Contains(contentType, "json"), contentType == "":
+		return BodyTypeJSON
+	case strings.HasPrefix(contentType, "application/x-www-form-urlencoded"):
+		return BodyTypeForm
+	case strings.HasPrefix(contentType, "multipart/form-data"):
+		return BodyTypeMultipart
+	case strings.Contains(contentType, "xml"):
+		return BodyTypeXML
+	case strings.HasPrefix(contentType, "text/"):
+		return BodyTypeText
+	case strings.HasPrefix(contentType, "application/octet-stream"):
+		return BodyTypeBinary
+	}
+	return BodyTypeJSON
+}
+
+// EncodeBody returns the body as it is sent and its default Content-Type.
+// The body is nil when the request has none.
+func (r HTTPRequest) EncodeBody() ([]byte, string, error) {
+	if r.Body == nil && r.BodyFile == "" {
+		return nil, "", nil
+	}
+
+	switch kind := r.BodyKind(); kind {
+	case BodyTypeJSON:
+		data, err := r.rawBody()
+		if err != nil || data != nil {
+			return data, "application/json", err
+		}
+		data, err = json.Marshal(r.Body)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to marshal body: %w", err)
+		}
+		return data, "application/json", nil
+
+	case BodyTypeForm:
+		data, err := r.rawBody()
+		if err != nil || data != nil {
+			return data, "application/x-www-form-urlencoded", err
+		}
+		fields, ok := r.Body.(map[string]interface{})
+		if !ok {
+			return nil, "", fmt.Errorf("form body must be an object of fields")
+		}
+		values := url.Values{}
+		for key, value := range fields {
+			values[key] = formValues(value)
+		}
+		return []byte(values.Encode()), "application/x-www-form-urlencoded", nil
+
+	case BodyTypeMultipart:
+		return r.multipartBody()
+
+	case BodyTypeText, BodyTypeXML, BodyTypeBinary:
+		data, err := r.rawBody()
+		if err != nil {
+			return nil, "", err
+		}
+		if data == nil {
+			return nil, "", fmt.Errorf("%s body must be a string or body_file", kind)
+		}
+		contentType := map[string]string{
+			BodyTypeText:   "text/plain; charset=utf-8",
+			BodyTypeXML:    "application/xml",
+			BodyTypeBinary: "application/octet-stream",
+		}[kind]
+		return data, contentType, nil
+
+	default:
+		return nil, "", fmt.Errorf("unknown body_type '%s' (use json, form, multipart, text, xml or binary)", r.BodyType)
+	}
+}
+
+// rawBody returns the contents of BodyFile, or Body when it is a string. It
+// returns nil for structured bodies.
+func (r HTTPRequest) rawBody() ([]byte, error) {
+	if r.BodyFile != "" {
+		return readBodyFile(r.BodyFile)
+	}
+	if s, ok := r.Body.(string); ok {
+		return []byte(s), nil
+	}
+	return nil, nil
+}
+
+// multipartBody encodes an object of fields as multipart/form-data. String
+// values starting with "@" name files to upload, as with curl -F.
+func (r HTTPRequest) multipartBody() ([]byte, string, error) {
+	fields, ok := r.Body.(map[string]interface{})
+	if !ok {
+		return nil, "", fmt.Errorf("multipart body must be an object of fields (\"@path\" uploads a file)")
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for _, key := range keys {
+		for _, value := range formValues(fields[key]) {
+			if path, isFile := strings.CutPrefix(value, "@"); isFile && path != "" {
+				data, err := readBodyFile(path)
+				if err != nil {
+					return nil, "", err
+				}
+				part, err := w.CreateFormFile(key, filepath.Base(path))
+				if err != nil {
+					return nil, "", fmt.Errorf("failed to add file '%s': %w", path, err)
+				}
+				part.Write(data)
+				continue
+			}
+			if err := w.WriteField(key, value); err != nil {
+				return nil, "", fmt.Errorf("failed to add field '%s': %w", key, err)
+			}
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to encode multipart body: %w", err)
+	}
+	return buf.Bytes(), w.FormDataContentType(), nil
+}
+
+// readBodyFile reads a file to send, restricted to the working directory.
+func readBodyFile(path string) ([]byte, error) {
+	absPath, err := ValidatePathWithinWorkDir(path, ".")
+	if err != nil {
+		return nil, fmt.Errorf("body file '%s': %w", path, err)
+	}
+	f, err := os.Open(absPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open body file: %w", err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read body file: %w", err)
+	}
+	return data, nil
+}
+
+// formValues renders a form field; a list becomes a repeated field.
+func formValues(value interface{}) []string {
+	switch v := value.(type) {
+	case nil:
+		return []string{""}
+	case string:
+		return []string{v}
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	case bool:
+		return []string{strconv.FormatBool(v)}
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			values = append(values, formValues(item)...)
+		}
+		return values
+	default:
+		data, _ := json.Marshal(v)
+		return []string{string(data)}
+	}
+}
@@ -0,0 +1,93 @@
+package shared
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHTTPTool_QueryAndBodyTypes(t *testing.T) {
+	var got struct {
+		query, contentType, body string
+		form                     map[string][]string
+		fileName, fileContent    string
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got.query = r.URL.RawQuery
+		got.contentType = r.Header.Get("Content-Type")
+		got.form, got.fileName, got.fileContent = nil, "", ""
+		if strings.HasPrefix(got.contentType, "multipart/") {
+			if err := r.ParseMultipartForm(1 << 20); err != nil {
+				t.Errorf("ParseMultipartForm: %v", err)
+				return
+			}
+			got.form = r.MultipartForm.Value
+			if f, header, err := r.FormFile("file"); err == nil {
+				data, _ := io.ReadAll(f)
+				got.fileName, got.fileContent = header.Filename, string(data)
+			}
+			return
+		}
+		data, _ := io.ReadAll(r.Body)
+		got.body = string(data)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	t.Chdir(dir)
+	if err := os.WriteFile(filepath.Join(dir, "avatar.png"), []byte("PNG"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tool := NewHTTPTool(nil, nil)
+	run := func(req HTTPRequest) {
+		t.Helper()
+		req.URL = server.URL + req.URL
+		if _, err := tool.Run(req); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+	}
+
+	run(HTTPRequest{Method: "GET", URL: "/users?sort=name", Query: map[string]string{"page": "2", "q": "a b"}})
+	if got.query != "page=2&q=a+b&sort=name" {
+		t.Errorf("query = %q", got.query)
+	}
+
+	run(HTTPRequest{Method: "POST", URL: "/login", BodyType: BodyTypeForm, Body: map[string]interface{}{"user": "alice", "role": []interface{}{"a", "b"}}})
+	if got.contentType != "application/x-www-form-urlencoded" || got.body != "role=a&role=b&user=alice" {
+		t.Errorf("form = %q %q", got.contentType, got.body)
+	}
+
+	// Inferred from the Content-Type header, as saved by the importers
+	run(HTTPRequest{Method: "POST", URL: "/soap", Headers: map[string]string{"Content-Type": "text/xml"}, Body: "<ping/>"})
+	if got.contentType != "text/xml" || got.body != "<ping/>" {
+		t.Errorf("xml = %q %q", got.contentType, got.body)
+	}
+
+	run(HTTPRequest{Method: "PUT", URL: "/raw", BodyType: BodyTypeBinary, BodyFile: "avatar.png"})
+	if got.contentType != "application/octet-stream" || got.body != "PNG" {
+		t.Errorf("binary = %q %q", got.contentType, got.body)
+	}
+
+	run(HTTPRequest{
+		Method:  "POST",
+		URL:     "/upload",
+		Headers: map[string]string{"Content-Type": "multipart/form-data"},
+		Body:    map[string]interface{}{"name": "me", "file": "@avatar.png"},
+	})
+	if got.form["name"][0] != "me" || got.fileName != "avatar.png" || got.fileContent != "PNG" {
+		t.Errorf("multipart = %v, file %q %q", got.form, got.fileName, got.fileContent)
+	}
+}
+
+func TestHTTPRequest_BodyFileOutsideWorkDir(t *testing.T) {
+	t.Chdir(t.TempDir())
+	_, _, err := HTTPRequest{BodyType: BodyTypeBinary, BodyFile: "../secret.key"}.EncodeBody()
+	if err == nil {
+		t.Error("expected an error for a file outside the working directory")
+	}
+}
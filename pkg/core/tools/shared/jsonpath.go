@@ -0,0 +1,1042 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// JSONPath is a compiled JSONPath expression. It is the single engine behind
+// assert_response, extract_value and the json_path checks of TestExpectation.
+//
+// Supported syntax:
+//
+//	$.data.id  $['data']['id']   child access (a leading "$." is optional)
+//	$.items[0]  $.items[-1]      array index, negative from the end
+//	$.items[0,2]  $.items[1:3]   unions and slices ([start:end:step])
+//	$.items[*].id  $.data.*      wildcards over arrays and objects
+//	$..id                        recursive descent
+//	$.items[?(@.price > 10 && @.tags)]
+//	                             filters: ==, !=, <, <=, >, >=, =~ (regex),
+//	                             existence (@.field) and negation (!@.field)
+type JSONPath struct {
+	raw      string
+	segments []pathSegment
+}
+
+type segmentKind int
+
+const (
+	segChild segmentKind = iota
+	segIndex
+	segWildcard
+	segSlice
+	segFilter
+)
+
+type pathSegment struct {
+	kind      segmentKind
+	recursive bool // preceded by ".."
+	names     []string
+	indexes   []int
+	slice     [3]*int
+	filter    *filterExpr
+}
+
+// ParseJSONPath compiles a JSONPath expression.
+func ParseJSONPath(path string) (*JSONPath, error) {
+	p := strings.TrimSpace(path)
+	if p == "" {
+		return nil, fmt.Errorf("empty JSONPath")
+	}
+	switch {
+	case p[0] == '$' || p[0] == '@':
+		p = p[1:]
+	case p[0] != '.' && p[0] != '[':
+		p = "." + p
+	}
+
+	jp := &JSONPath{raw: path}
+	for i := 0; i < len(p); {
+		recursive := false
+		if p[i] == '.' {
+			i++
+			if i < len(p) && p[i] == '.' {
+				recursive = true
+				i++
+			}
+			if i < len(p) && p[i] != '[' {
+				start := i
+				for i < len(p) && p[i] != '.' && p[i] != '[' {
+					i++
+				}
+				name := p[start:i]
+				if name == "*" {
+					jp.segments = append(jp.segments, pathSegment{kind: segWildcard, recursive: recursive})
+				} else {
+					jp.segments = append(jp.segments, pathSegment{kind: segChild, recursive: recursive, names: []string{name}})
+				}
+				continue
+			}
+			if i >= len(p) || !recursive {
+				return nil, fmt.Errorf("invalid JSONPath '%s': empty field name", path)
+			}
+		}
+		if p[i] != '[' {
+			return nil, fmt.Errorf("invalid JSONPath '%s': unexpected '%c'", path, p[i])
+		}
+		end, err := closingBracket(p, i)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSONPath '%s': %w", path, err)
+		}
+		seg, err := parseBracket(strings.TrimSpace(p[i+1 : end]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSONPath '%s': %w", path, err)
+		}
+		seg.recursive = recursive
+		jp.segments = append(jp.segments, seg)
+		i = end + 1
+	}
+	return jp, nil
+}
+
+// closingBracket returns the index of the "]" matching the "[" at open,
+// skipping brackets inside quotes and nested filter expressions.
+func closingBracket(p string, open int) (int, error) {
+	depth := 0
+	var quote byte
+	for i := open; i < len(p); i++ {
+		c := p[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unclosed '['")
+}
+
+func parseBracket(content string) (pathSegment, error) {
+	switch {
+	case content == "":
+		return pathSegment{}, fmt.Errorf("empty brackets")
+	case content == "*":
+		return pathSegment{kind: segWildcard}, nil
+	case content[0] == '?':
+		expr := strings.TrimSpace(content[1:])
+		if strings.HasPrefix(expr, "(") && strings.HasSuffix(expr, ")") {
+			expr = expr[1 : len(expr)-1]
+		}
+		filter, err := parseFilter(expr)
+		if err != nil {
+			return pathSegment{}, err
+		}
+		return pathSegment{kind: segFilter, filter: filter}, nil
+	}
+
+	parts := splitTopLevel(content, ",")
+	if content[0] == '\'' || content[0] == '"' {
+		seg := pathSegment{kind: segChild}
+		for _, part := range parts {
+			part = strings.TrimSpace(part)
+			if !isQuotedLiteral(part) {
+				return pathSegment{}, fmt.Errorf("invalid field name %s", part)
+			}
+			seg.names = append(seg.names, unquoteLiteral(part))
+		}
+		return seg, nil
+	}
+
+	if len(parts) == 1 && strings.Contains(content, ":") {
+		bounds := strings.Split(content, ":")
+		if len(bounds) > 3 {
+			return pathSegment{}, fmt.Errorf("invalid slice [%s]", content)
+		}
+		seg := pathSegment{kind: segSlice}
+		for i, b := range bounds {
+			if b = strings.TrimSpace(b); b == "" {
+				continue
+			}
+			n, err := strconv.Atoi(b)
+			if err != nil {
+				return pathSegment{}, fmt.Errorf("invalid slice [%s]", content)
+			}
+			seg.slice[i] = &n
+		}
+		if seg.slice[2] != nil && *seg.slice[2] == 0 {
+			return pathSegment{}, fmt.Errorf("slice step cannot be 0")
+		}
+		return seg, nil
+	}
+
+	seg := pathSegment{kind: segIndex}
+	for _, part := range parts {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			if len(parts) == 1 {
+				// Unquoted field name, e.g. $[data]
+				return pathSegment{kind: segChild, names: []string{strings.TrimSpace(part)}}, nil
+			}
+			return pathSegment{}, fmt.Errorf("invalid array index: %s", part)
+		}
+		seg.indexes = append(seg.indexes, n)
+	}
+	return seg, nil
+}
+
+// Definite reports whether the path selects at most one value: it has no
+// wildcards, filters, slices, unions or recursive descent.
+func (jp *JSONPath) Definite() bool {
+	for _, seg := range jp.segments {
+		if seg.recursive {
+			return false
+		}
+		switch seg.kind {
+		case segChild:
+			if len(seg.names) != 1 {
+				return false
+			}
+		case segIndex:
+			if len(seg.indexes) != 1 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the expression as written.
+func (jp *JSONPath) String() string {
+	return jp.raw
+}
+
+// Find returns every value the path selects in data, in document order.
+func (jp *JSONPath) Find(data interface{}) []interface{} {
+	return jp.find(data, data)
+}
+
+func (jp *JSONPath) find(root, data interface{}) []interface{} {
+	nodes := []interface{}{data}
+	for _, seg := range jp.segments {
+		var next []interface{}
+		for _, node := range nodes {
+			if seg.recursive {
+				for _, d := range descendants(node) {
+					next = append(next, seg.apply(root, d)...)
+				}
+			} else {
+				next = append(next, seg.apply(root, node)...)
+			}
+		}
+		nodes = next
+		if len(nodes) == 0 {
+			break
+		}
+	}
+	return nodes
+}
+
+// Get returns the single value selected by a definite path, with an error
+// that names the first segment that did not resolve. Paths that are not
+// definite return the list of matches, or an error when nothing matched.
+func (jp *JSONPath) Get(data interface{}) (interface{}, error) {
+	if !jp.Definite() {
+		matches := jp.Find(data)
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no match for '%s'", jp.raw)
+		}
+		return matches, nil
+	}
+
+	current := data
+	at := "$"
+	for _, seg := range jp.segments {
+		switch seg.kind {
+		case segChild:
+			m, ok := current.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected object at '%s', got %s", at, jsonTypeName(current))
+			}
+			value, ok := m[seg.names[0]]
+			if !ok {
+				return nil, fmt.Errorf("field '%s' not found", seg.names[0])
+			}
+			current = value
+			at += "." + seg.names[0]
+		case segIndex:
+			arr, ok := current.([]interface{})
+			if !ok {
+				return nil, fmt.Errorf("expected array at '%s', got %s", at, jsonTypeName(current))
+			}
+			idx := seg.indexes[0]
+			if idx < 0 {
+				idx += len(arr)
+			}
+			if idx < 0 || idx >= len(arr) {
+				return nil, fmt.Errorf("array index %d out of bounds (length %d)", seg.indexes[0], len(arr))
+			}
+			current = arr[idx]
+			at += fmt.Sprintf("[%d]", seg.indexes[0])
+		}
+	}
+	return current, nil
+}
+
+func (seg pathSegment) apply(root, node interface{}) []interface{} {
+	switch seg.kind {
+	case segChild:
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		var out []interface{}
+		for _, name := range seg.names {
+			if v, ok := m[name]; ok {
+				out = append(out, v)
+			}
+		}
+		return out
+	case segIndex:
+		arr, ok := node.([]interface{})
+		if !ok {
+			return nil
+		}
+		var out []interface{}
+		for _, idx := range seg.indexes {
+			if idx < 0 {
+				idx += len(arr)
+			}
+			if idx >= 0 && idx < len(arr) {
+				out = append(out, arr[idx])
+			}
+		}
+		return out
+	case segWildcard:
+		return children(node)
+	case segSlice:
+		arr, ok := node.([]interface{})
+		if !ok {
+			return nil
+		}
+		return sliceArray(arr, seg.slice)
+	case segFilter:
+		var out []interface{}
+		for _, child := range children(node) {
+			if seg.filter.eval(root, child) {
+				out = append(out, child)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// children returns the elements of an array or the values of an object,
+// the latter ordered by key so results are stable.
+func children(node interface{}) []interface{} {
+	switch v := node.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		out := make([]interface{}, 0, len(v))
+		for _, k := range sortedKeys(v) {
+			out = append(out, v[k])
+		}
+		return out
+	}
+	return nil
+}
+
+// descendants returns node and every value nested below it.
+func descendants(node interface{}) []interface{} {
+	out := []interface{}{node}
+	for _, child := range children(node) {
+		out = append(out, descendants(child)...)
+	}
+	return out
+}
+
+func sliceArray(arr []interface{}, bounds [3]*int) []interface{} {
+	n := len(arr)
+	step := 1
+	if bounds[2] != nil {
+		step = *bounds[2]
+	}
+	norm := func(p *int, def int) int {
+		if p == nil {
+			return def
+		}
+		i := *p
+		if i < 0 {
+			i += n
+		}
+		return min(max(i, -1), n)
+	}
+
+	var out []interface{}
+	if step > 0 {
+		start, end := max(norm(bounds[0], 0), 0), norm(bounds[1], n)
+		for i := start; i < end; i += step {
+			out = append(out, arr[i])
+		}
+	} else {
+		start, end := min(norm(bounds[0], n-1), n-1), norm(bounds[1], -1)
+		for i := start; i > end; i += step {
+			out = append(out, arr[i])
+		}
+	}
+	return out
+}
+
+// Filter expressions: "||" of "&&" of comparisons or existence tests.
+type filterExpr struct {
+	or [][]filterTerm
+}
+
+type filterTerm struct {
+	left   filterOperand
+	op     string // "" for an existence test
+	right  filterOperand
+	negate bool
+	regex  *regexp.Regexp
+}
+
+type filterOperand struct {
+	path     *JSONPath
+	fromRoot bool
+	literal  interface{}
+}
+
+var filterOperators = []string{"==", "!=", "<=", ">=", "=~", "<", ">"}
+
+func parseFilter(expr string) (*filterExpr, error) {
+	if strings.TrimSpace(expr) == "" {
+		return nil, fmt.Errorf("empty filter")
+	}
+	f := &filterExpr{}
+	for _, alt := range splitTopLevel(expr, "||") {
+		var terms []filterTerm
+		for _, part := range splitTopLevel(alt, "&&") {
+			term, err := parseFilterTerm(strings.TrimSpace(part))
+			if err != nil {
+				return nil, err
+			}
+			terms = append(terms, term)
+		}
+		f.or = append(f.or, terms)
+	}
+	return f, nil
+}
+
+func parseFilterTerm(s string) (filterTerm, error) {
+	if s == "" {
+		return filterTerm{}, fmt.Errorf("empty filter condition")
+	}
+	for len(s) > 1 && s[0] == '(' && s[len(s)-1] == ')' {
+		s = strings.TrimSpace(s[1 : len(s)-1])
+	}
+
+	if pos, op := findOperator(s); op != "" {
+		left, err := parseFilterOperand(strings.TrimSpace(s[:pos]))
+		if err != nil {
+			return filterTerm{}, err
+		}
+		rightSrc := strings.TrimSpace(s[pos+len(op):])
+		term := filterTerm{left: left, op: op}
+		if op == "=~" {
+			pattern := rightSrc
+			if strings.HasPrefix(pattern, "/") {
+				end := strings.LastIndex(pattern, "/")
+				if end <= 0 {
+					return filterTerm{}, fmt.Errorf("unterminated regex literal '%s' in filter", rightSrc)
+				}
+				flags := pattern[end+1:]
+				pattern = pattern[1:end]
+				if strings.Contains(flags, "i") {
+					pattern = "(?i)" + pattern
+				}
+			} else if isQuotedLiteral(pattern) {
+				pattern = unquoteLiteral(pattern)
+			}
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return filterTerm{}, fmt.Errorf("invalid regex in filter: %w", err)
+			}
+			term.regex = re
+			return term, nil
+		}
+		right, err := parseFilterOperand(rightSrc)
+		if err != nil {
+			return filterTerm{}, err
+		}
+		term.right = right
+		return term, nil
+	}
+
+	negate := strings.HasPrefix(s, "!")
+	operand, err := parseFilterOperand(strings.TrimSpace(strings.TrimPrefix(s, "!")))
+	if err != nil {
+		return filterTerm{}, err
+	}
+	if operand.path == nil {
+		return filterTerm{}, fmt.Errorf("filter condition '%s' must reference @ or $", s)
+	}
+	return filterTerm{left: operand, negate: negate}, nil
+}
+
+// findOperator returns the first comparison operator outside quotes.
+func findOperator(s string) (int, string) {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		if c == '\'' || c == '"' {
+			quote = c
+			continue
+		}
+		for _, op := range filterOperators {
+			if strings.HasPrefix(s[i:], op) {
+				return i, op
+			}
+		}
+	}
+	return -1, ""
+}
+
+func parseFilterOperand(s string) (filterOperand, error) {
+	if s == "" {
+		return filterOperand{}, fmt.Errorf("missing filter operand")
+	}
+	if s[0] == '@' || s[0] == '$' {
+		path, err := ParseJSONPath(s)
+		if err != nil {
+			return filterOperand{}, err
+		}
+		return filterOperand{path: path, fromRoot: s[0] == '$'}, nil
+	}
+	if isQuotedLiteral(s) {
+		return filterOperand{literal: unquoteLiteral(s)}, nil
+	}
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return filterOperand{}, fmt.Errorf("invalid filter value '%s'", s)
+	}
+	return filterOperand{literal: v}, nil
+}
+
+func (f *filterExpr) eval(root, current interface{}) bool {
+	for _, terms := range f.or {
+		all := true
+		for _, term := range terms {
+			if !term.eval(root, current) {
+				all = false
+				break
+			}
+		}
+		if all {
+			return true
+		}
+	}
+	return false
+}
+
+func (t filterTerm) eval(root, current interface{}) bool {
+	left, ok := t.left.value(root, current)
+	if t.op == "" {
+		return ok != t.negate
+	}
+	if !ok {
+		return false
+	}
+	if t.regex != nil {
+		return t.regex.MatchString(valueString(left))
+	}
+	right, ok := t.right.value(root, current)
+	if !ok {
+		return false
+	}
+	switch t.op {
+	case "==":
+		return LooseEqual(left, right)
+	case "!=":
+		return !LooseEqual(left, right)
+	}
+	cmp, ok := CompareValues(left, right)
+	if !ok {
+		return false
+	}
+	switch t.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return false
+}
+
+func (o filterOperand) value(root, current interface{}) (interface{}, bool) {
+	if o.path == nil {
+		return o.literal, true
+	}
+	base := current
+	if o.fromRoot {
+		base = root
+	}
+	matches := o.path.find(root, base)
+	if len(matches) == 0 {
+		return nil, false
+	}
+	if o.path.Definite() {
+		return matches[0], true
+	}
+	return matches, true
+}
+
+// splitTopLevel splits s on sep, ignoring separators inside quotes,
+// parentheses and brackets.
+func splitTopLevel(s, sep string) []string {
+	var parts []string
+	depth, start := 0, 0
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"':
+			quote = c
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		default:
+			if depth == 0 && strings.HasPrefix(s[i:], sep) {
+				parts = append(parts, s[start:i])
+				i += len(sep) - 1
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func isQuotedLiteral(s string) bool {
+	return len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]
+}
+
+func unquoteLiteral(s string) string {
+	inner := s[1 : len(s)-1]
+	return strings.NewReplacer(`\`+string(s[0]), string(s[0]), `\\`, `\`).Replace(inner)
+}
+
+// EvalJSONPath evaluates path against a decoded JSON document. Definite paths
+// return the value they point to; other paths return the list of matches.
+func EvalJSONPath(data interface{}, path string) (interface{}, error) {
+	jp, err := ParseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return jp.Get(data)
+}
+
+// JSONPath expectation operators. An expected value that is an object whose
+// keys all start with "$" is read as operators, e.g. {"$gt": 0, "$lt": 100};
+// any other value must equal the selected value.
+//
+// Paths that select several values (wildcards, filters, slices, "..") are
+// checked as the list of matches by plain values, $eq, $ne, $len and
+// $contains; the remaining operators must hold for every match, and at
+// least one value must match.
+var jsonPathOperators = map[string]bool{
+	"$exists": true, "$eq": true, "$ne": true, "$gt": true, "$gte": true, "$lt": true, "$lte": true,
+	"$regex": true, "$type": true, "$len": true, "$contains": true, "$in": true, "$nin": true,
+}
+
+// CheckJSONPath evaluates path against data and checks the result against
+// expected. It returns nil when the check passes and otherwise an error
+// describing the mismatch.
+func CheckJSONPath(data interface{}, path string, expected interface{}) error {
+	jp, err := ParseJSONPath(path)
+	if err != nil {
+		return err
+	}
+	ops, isOps := operatorMap(expected)
+
+	if !jp.Definite() {
+		matches := jp.Find(data)
+		if matches == nil {
+			matches = []interface{}{}
+		}
+		if !isOps {
+			if !deepEqual(matches, expected) {
+				return fmt.Errorf("expected %s, got %s", formatJSONValue(expected), formatJSONValue(matches))
+			}
+			return nil
+		}
+		return checkOperators(matches, true, ops)
+	}
+
+	value, getErr := jp.Get(data)
+	if isOps {
+		if want, ok := ops["$exists"]; ok {
+			if exists := getErr == nil; exists != Truthy(want) {
+				if exists {
+					return fmt.Errorf("expected no value, got %s", formatJSONValue(value))
+				}
+				return fmt.Errorf("expected a value: %v", getErr)
+			}
+			if getErr != nil {
+				return nil
+			}
+		}
+	}
+	if getErr != nil {
+		return getErr
+	}
+	if !isOps {
+		if !deepEqual(value, expected) {
+			return fmt.Errorf("expected %s, got %s", formatJSONValue(expected), formatJSONValue(value))
+		}
+		return nil
+	}
+	return checkOperators([]interface{}{value}, false, ops)
+}
+
+// checkJSONPaths decodes body once and runs every path check, sorted by
+// path. It returns the number of passing checks and one failure message per
+// failing check.
+func checkJSONPaths(body string, checks map[string]interface{}) (int, []string) {
+	if len(checks) == 0 {
+		return 0, nil
+	}
+	var data interface{}
+	if err := json.Unmarshal([]byte(body), &data); err != nil {
+		return 0, []string{fmt.Sprintf("Cannot parse response as JSON for JSONPath checks: %v", err)}
+	}
+
+	paths := make([]string, 0, len(checks))
+	for path := range checks {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	passed := 0
+	var failures []string
+	for _, path := range paths {
+		if err := CheckJSONPath(data, path, checks[path]); err != nil {
+			failures = append(failures, fmt.Sprintf("JSONPath '%s': %v", path, err))
+		} else {
+			passed++
+		}
+	}
+	return passed, failures
+}
+
+func operatorMap(expected interface{}) (map[string]interface{}, bool) {
+	m, ok := expected.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return nil, false
+	}
+	for key := range m {
+		if !strings.HasPrefix(key, "$") {
+			return nil, false
+		}
+	}
+	return m, true
+}
+
+// checkOperators applies operators to the selected values. For a definite
+// path values holds the one value; for other paths it holds every match.
+func checkOperators(values []interface{}, multi bool, ops map[string]interface{}) error {
+	names := make([]string, 0, len(ops))
+	for name := range ops {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var subject interface{} = values
+	if !multi {
+		subject = values[0]
+	}
+
+	for _, name := range names {
+		want := ops[name]
+		if !jsonPathOperators[name] {
+			return fmt.Errorf("unknown operator %s", name)
+		}
+		switch name {
+		case "$exists":
+			if multi && (len(values) > 0) != Truthy(want) {
+				if Truthy(want) {
+					return fmt.Errorf("expected at least one match, got none")
+				}
+				return fmt.Errorf("expected no match, got %s", formatJSONValue(values))
+			}
+			continue
+		case "$eq":
+			if !deepEqual(subject, want) {
+				return fmt.Errorf("expected %s, got %s", formatJSONValue(want), formatJSONValue(subject))
+			}
+			continue
+		case "$ne":
+			if deepEqual(subject, want) {
+				return fmt.Errorf("expected a value other than %s", formatJSONValue(want))
+			}
+			continue
+		case "$len":
+			n, ok := valueLength(subject)
+			if !ok {
+				return fmt.Errorf("$len: %s has no length", jsonTypeName(subject))
+			}
+			if sub, isOps := operatorMap(want); isOps {
+				if err := checkOperators([]interface{}{float64(n)}, false, sub); err != nil {
+					return fmt.Errorf("length %d: %v", n, err)
+				}
+			} else if !LooseEqual(float64(n), want) {
+				return fmt.Errorf("expected length %s, got %d", formatJSONValue(want), n)
+			}
+			continue
+		case "$contains":
+			if !ContainsValue(subject, want) {
+				return fmt.Errorf("expected %s to contain %s", formatJSONValue(subject), formatJSONValue(want))
+			}
+			continue
+		}
+
+		if multi && len(values) == 0 {
+			return fmt.Errorf("%s: no values matched", name)
+		}
+		for _, v := range values {
+			if err := checkValueOperator(name, v, want); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkValueOperator applies a comparison, pattern or type operator to one value.
+func checkValueOperator(name string, v, want interface{}) error {
+	switch name {
+	case "$gt", "$gte", "$lt", "$lte":
+		cmp, ok := CompareValues(v, want)
+		if !ok {
+			return fmt.Errorf("cannot compare %s with %s", formatJSONValue(v), formatJSONValue(want))
+		}
+		pass := map[string]bool{"$gt": cmp > 0, "$gte": cmp >= 0, "$lt": cmp < 0, "$lte": cmp <= 0}[name]
+		if !pass {
+			symbol := map[string]string{"$gt": ">", "$gte": ">=", "$lt": "<", "$lte": "<="}[name]
+			return fmt.Errorf("expected %s %s, got %s", symbol, formatJSONValue(want), formatJSONValue(v))
+		}
+	case "$regex":
+		pattern, ok := want.(string)
+		if !ok {
+			return fmt.Errorf("$regex must be a string")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("invalid $regex: %w", err)
+		}
+		if !re.MatchString(valueString(v)) {
+			return fmt.Errorf("expected to match /%s/, got %s", pattern, formatJSONValue(v))
+		}
+	case "$type":
+		wantType, ok := want.(string)
+		if !ok {
+			return fmt.Errorf("$type must be a string")
+		}
+		if !hasJSONType(v, wantType) {
+			return fmt.Errorf("expected type %s, got %s", wantType, jsonTypeName(v))
+		}
+	case "$in", "$nin":
+		list, ok := want.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s must be an array", name)
+		}
+		found := false
+		for _, candidate := range list {
+			if LooseEqual(v, candidate) {
+				found = true
+				break
+			}
+		}
+		if found != (name == "$in") {
+			if found {
+				return fmt.Errorf("expected none of %s, got %s", formatJSONValue(want), formatJSONValue(v))
+			}
+			return fmt.Errorf("expected one of %s, got %s", formatJSONValue(want), formatJSONValue(v))
+		}
+	}
+	return nil
+}
+
+func hasJSONType(v interface{}, want string) bool {
+	if want == "integer" {
+		n, ok := toNumber(v)
+		return ok && n == math.Trunc(n)
+	}
+	return jsonTypeName(v) == want
+}
+
+func valueLength(v interface{}) (int, bool) {
+	switch t := v.(type) {
+	case string:
+		return utf8.RuneCountInString(t), true
+	case []interface{}:
+		return len(t), true
+	case map[string]interface{}:
+		return len(t), true
+	}
+	return 0, false
+}
+
+// ContainsValue checks a substring, list membership or an object key.
+func ContainsValue(haystack, needle interface{}) bool {
+	switch h := haystack.(type) {
+	case string:
+		return strings.Contains(h, valueString(needle))
+	case []interface{}:
+		for _, item := range h {
+			if LooseEqual(item, needle) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		key, ok := needle.(string)
+		if ok {
+			_, ok = h[key]
+		}
+		return ok
+	}
+	return false
+}
+
+// LooseEqual compares numbers by value and everything else structurally.
+func LooseEqual(a, b interface{}) bool {
+	if x, ok := toNumber(a); ok {
+		if y, ok := toNumber(b); ok {
+			return x == y
+		}
+	}
+	return deepEqual(a, b)
+}
+
+// CompareValues orders two numbers or two strings. ok is false when the
+// values are not both numbers or both strings.
+func CompareValues(a, b interface{}) (int, bool) {
+	if x, ok := toNumber(a); ok {
+		if y, ok := toNumber(b); ok {
+			switch {
+			case x < y:
+				return -1, true
+			case x > y:
+				return 1, true
+			}
+			return 0, true
+		}
+	}
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	}
+	return 0, false
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case bool, string, nil:
+		return 0, false
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// Truthy reports whether v counts as true: false, null, "", "false" and
+// zero do not.
+func Truthy(v interface{}) bool {
+	switch t := v.(type) {
+	case bool:
+		return t
+	case nil:
+		return false
+	case string:
+		return t != "" && t != "false"
+	}
+	if n, ok := toNumber(v); ok {
+		return n != 0
+	}
+	return true
+}
+
+// valueString renders scalars as plain text and other values as JSON.
+func valueString(v interface{}) string {
+	if v == nil {
+		return "null"
+	}
+	return Stringify(v)
+}
+
+// formatJSONValue renders a value for failure messages, truncated so large
+// bodies do not flood the output.
+func formatJSONValue(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	s := string(data)
+	if len(s) > 200 {
+		s = s[:200] + "..."
+	}
+	return s
+}
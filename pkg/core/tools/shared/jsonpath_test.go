@@ -0,0 +1,155 @@
+package shared
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const jsonPathDoc = `{
+  "data": {"id": 7, "email": "ann@example.com", "user": {"id": 9}},
+  "items": [
+    {"id": 1, "price": 5, "tags": ["sale"]},
+    {"id": 2, "price": 15},
+    {"id": 3, "price": 25, "tags": []}
+  ],
+  "meta": {"total": 3, "next": null}
+}`
+
+func decodeJSONPathDoc(t *testing.T, doc string) interface{} {
+	t.Helper()
+	var data interface{}
+	if err := json.Unmarshal([]byte(doc), &data); err != nil {
+		t.Fatalf("invalid test document: %v", err)
+	}
+	return data
+}
+
+func TestEvalJSONPath(t *testing.T) {
+	data := decodeJSONPathDoc(t, jsonPathDoc)
+
+	cases := map[string]string{
+		"$.data.id":                                `7`,
+		"data.email":                               `"ann@example.com"`,
+		"$['data']['user'].id":                     `9`,
+		"$.items[-1].id":                           `3`,
+		"$.items[*].id":                            `[1,2,3]`,
+		"$.items[0,2].price":                       `[5,25]`,
+		"$.items[1:].id":                           `[2,3]`,
+		"$.items[::-1].id":                         `[3,2,1]`,
+		"$..id":                                    `[7,9,1,2,3]`,
+		"$.items[?(@.price > 10)].id":              `[2,3]`,
+		"$.items[?(@.price >= 5 && @.tags)].id":    `[1,3]`,
+		"$.items[?(!@.tags)].id":                   `[2]`,
+		"$.items[?(@.id == 1 || @.id == 3)].price": `[5,25]`,
+		"$.meta.*":                                 `[null,3]`,
+		"$.data[?(@ =~ /EXAMPLE/i)]":               `["ann@example.com"]`,
+		"$.items[?(@.price > $.meta.total)].id":    `[1,2,3]`,
+	}
+	for path, want := range cases {
+		got, err := EvalJSONPath(data, path)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", path, err)
+			continue
+		}
+		if gotJSON := formatJSONValue(got); gotJSON != want {
+			t.Errorf("%s = %s, want %s", path, gotJSON, want)
+		}
+	}
+
+	top := decodeJSONPathDoc(t, `[{"id": "a"}, {"id": "b"}]`)
+	if got, err := EvalJSONPath(top, "$[0].id"); err != nil || got != "a" {
+		t.Errorf("$[0].id on a top-level array = %v, %v", got, err)
+	}
+}
+
+func TestEvalJSONPath_Errors(t *testing.T) {
+	data := decodeJSONPathDoc(t, jsonPathDoc)
+
+	cases := map[string]string{
+		"$.data.missing":             "field 'missing' not found",
+		"$.items[5]":                 "out of bounds",
+		"$.data.id.x":                "expected object at '$.data.id', got number",
+		"$.items[?(@.x)]":            "no match",
+		"$.items[":                   "unclosed",
+		"$.items[?(== 1)]":           "missing filter operand",
+		"$.items[?(@.name =~ /abc)]": "unterminated regex literal",
+		"$.items[?(@.name =~ /)]":    "unterminated regex literal",
+	}
+	for path, want := range cases {
+		_, err := EvalJSONPath(data, path)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: error = %v, want it to contain %q", path, err, want)
+		}
+	}
+}
+
+func TestCheckJSONPath_Operators(t *testing.T) {
+	data := decodeJSONPathDoc(t, jsonPathDoc)
+
+	pass := map[string]interface{}{
+		"$.data.id":          7,
+		"$.items[*].id":      []interface{}{1, 2, 3},
+		"$.meta.total":       map[string]interface{}{"$gt": 2, "$lte": 3},
+		"$.data.email":       map[string]interface{}{"$regex": "^[^@]+@example\\.com$", "$type": "string"},
+		"$.items":            map[string]interface{}{"$len": 3, "$type": "array"},
+		"$.items[*].price":   map[string]interface{}{"$gt": 0, "$type": "integer"},
+		"$.items[?(@.tags)]": map[string]interface{}{"$len": map[string]interface{}{"$gte": 2}},
+		"$.meta.next":        map[string]interface{}{"$exists": true, "$type": "null"},
+		"$.data.deleted":     map[string]interface{}{"$exists": false},
+		"$.items[0].tags":    map[string]interface{}{"$contains": "sale"},
+		"$.data.user.id":     map[string]interface{}{"$in": []interface{}{8, 9}, "$ne": 7},
+		"$..missing":         map[string]interface{}{"$exists": false},
+	}
+	for path, expected := range pass {
+		if err := CheckJSONPath(data, path, expected); err != nil {
+			t.Errorf("%s: expected pass, got %v", path, err)
+		}
+	}
+
+	fail := map[string]interface{}{
+		"$.data.id":        8,
+		"$.meta.total":     map[string]interface{}{"$gt": 3},
+		"$.data.email":     map[string]interface{}{"$regex": "^admin"},
+		"$.items":          map[string]interface{}{"$len": 2},
+		"$.items[*].price": map[string]interface{}{"$lt": 20},
+		"$.data.id.x":      map[string]interface{}{"$type": "number"},
+		"$.data.user":      map[string]interface{}{"$type": "array"},
+		"$.meta.next":      map[string]interface{}{"$exists": false},
+		"$.data.deleted":   map[string]interface{}{"$exists": true},
+		"$.data.user.id":   map[string]interface{}{"$between": 1},
+	}
+	for path, expected := range fail {
+		if err := CheckJSONPath(data, path, expected); err == nil {
+			t.Errorf("%s: expected %v to fail", path, expected)
+		}
+	}
+}
+
+func TestValidateExpectations_JSONPath(t *testing.T) {
+	resp := &HTTPResponse{StatusCode: 200, Body: jsonPathDoc}
+
+	errs := ValidateExpectations(TestExpectation{
+		StatusCode: 200,
+		JSONPath: map[string]interface{}{
+			"$.data.id":        7,
+			"$.items[*].price": map[string]interface{}{"$lt": 20},
+			"$.meta.total":     map[string]interface{}{"$type": "string"},
+		},
+	}, resp, 0)
+
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 JSONPath failures, got %d: %v", len(errs), errs)
+	}
+	if !strings.Contains(errs[0], "$.items[*].price") || !strings.Contains(errs[0], "expected < 20, got 25") {
+		t.Errorf("unexpected first failure: %s", errs[0])
+	}
+	if !strings.Contains(errs[1], "expected type string, got number") {
+		t.Errorf("unexpected second failure: %s", errs[1])
+	}
+
+	errs = ValidateExpectations(TestExpectation{JSONPath: map[string]interface{}{"$.id": 1}}, &HTTPResponse{StatusCode: 200, Body: "<html>"}, 0)
+	if len(errs) != 1 || !strings.Contains(errs[0], "Cannot parse response as JSON") {
+		t.Errorf("expected a JSON parse failure, got %v", errs)
+	}
+}
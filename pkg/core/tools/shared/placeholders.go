@@ -0,0 +1,52 @@
+package shared
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PlaceholderPattern matches {{name}} placeholders and captures the name.
+// Names may hold letters, digits, "_", ".", "-" and ":" ({{user.id}},
+// {{env:HOME}}); spaces just inside the braces are ignored.
+var PlaceholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.\-:]+)\s*\}\}`)
+
+// PathParamPattern matches {id} and {{id}} path parameters.
+var PathParamPattern = regexp.MustCompile(`\{\{?\s*([A-Za-z0-9_.\-:]+)\s*\}?\}`)
+
+// PlaceholderName returns the variable name when s is exactly one
+// placeholder, so callers can substitute the value with its type.
+func PlaceholderName(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	m := PlaceholderPattern.FindStringSubmatch(s)
+	if m == nil || m[0] != s {
+		return "", false
+	}
+	return m[1], true
+}
+
+// Stringify renders a value for textual substitution: strings as they are,
+// numbers without exponents or trailing zeros, nil as "" and objects and
+// arrays as JSON.
+func Stringify(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(val), 'f', -1, 32)
+	case int, int64, int32, uint, uint64, uint32, bool, json.Number:
+		return fmt.Sprint(val)
+	default:
+		b, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(b)
+	}
+}
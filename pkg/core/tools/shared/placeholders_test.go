@@ -0,0 +1,54 @@
+package shared
+
+import "testing"
+
+func TestPlaceholderName(t *testing.T) {
+	cases := []struct {
+		in   string
+		name string
+		ok   bool
+	}{
+		{"{{id}}", "id", true},
+		{" {{ user.id }} ", "user.id", true},
+		{"{{env:HOME}}", "env:HOME", true},
+		{"{{order-id}}", "order-id", true},
+		{"Bearer {{token}}", "", false},
+		{"{{a}}{{b}}", "", false},
+		{"{id}", "", false},
+	}
+	for _, tc := range cases {
+		name, ok := PlaceholderName(tc.in)
+		if name != tc.name || ok != tc.ok {
+			t.Errorf("PlaceholderName(%q) = %q, %v; want %q, %v", tc.in, name, ok, tc.name, tc.ok)
+		}
+	}
+}
+
+func TestPathParamPattern(t *testing.T) {
+	got := PathParamPattern.ReplaceAllString("/users/{id}/orders/{{ order_id }}", "<$1>")
+	if want := "/users/<id>/orders/<order_id>"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStringify(t *testing.T) {
+	cases := []struct {
+		in   interface{}
+		want string
+	}{
+		{"text", "text"},
+		{float64(7), "7"},
+		{1.5, "1.5"},
+		{1e21, "1000000000000000000000"},
+		{int64(42), "42"},
+		{nil, ""},
+		{true, "true"},
+		{map[string]interface{}{"a": 1}, `{"a":1}`},
+		{[]interface{}{"x", 2.0}, `["x",2]`},
+	}
+	for _, tc := range cases {
+		if got := Stringify(tc.in); got != tc.want {
+			t.Errorf("Stringify(%#v) = %q, want %q", tc.in, got, tc.want)
+		}
+	}
+}
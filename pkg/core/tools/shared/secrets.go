@@ -53,9 +53,6 @@ var SensitiveKeyPatterns = []*regexp.Regexp{
 	regexp.MustCompile(`(?i)authorization`),
 }
 
-// VariablePlaceholderPattern matches {{VAR}} placeholders
-var VariablePlaceholderPattern = regexp.MustCompile(`\{\{[A-Za-z_][A-Za-z0-9_]*\}\}`)
-
 // IsSecret checks if a key/value pair appears to be sensitive.
 // Returns true if:
 // - The key matches a sensitive key pattern, OR
@@ -96,7 +93,7 @@ func isSecretValue(value string) bool {
 
 // hasNonPlaceholderContent checks if a string has content beyond just placeholders
 func hasNonPlaceholderContent(value string) bool {
-	stripped := VariablePlaceholderPattern.ReplaceAllString(value, "")
+	stripped := PlaceholderPattern.ReplaceAllString(value, "")
 	stripped = strings.TrimSpace(stripped)
 	// If after removing placeholders there's still significant content, it might have hardcoded secrets
 	return len(stripped) > 10
@@ -161,7 +158,7 @@ func isOnlyPlaceholder(text string) bool {
 	}
 
 	// Check if what remains is just placeholders
-	stripped := VariablePlaceholderPattern.ReplaceAllString(text, "")
+	stripped := PlaceholderPattern.ReplaceAllString(text, "")
 	stripped = strings.TrimSpace(stripped)
 	return stripped == ""
 }
@@ -169,7 +166,7 @@ func isOnlyPlaceholder(text string) bool {
 // extractNonPlaceholderParts extracts parts of text that are not {{VAR}} placeholders
 func extractNonPlaceholderParts(text string) []string {
 	// Split by placeholders and return non-empty parts
-	parts := VariablePlaceholderPattern.Split(text, -1)
+	parts := PlaceholderPattern.Split(text, -1)
 	var result []string
 	for _, part := range parts {
 		part = strings.TrimSpace(part)
@@ -185,7 +182,7 @@ func extractNonPlaceholderParts(text string) []string {
 
 // ContainsVariablePlaceholder checks if text contains {{VAR}} syntax
 func ContainsVariablePlaceholder(text string) bool {
-	return VariablePlaceholderPattern.MatchString(text)
+	return PlaceholderPattern.MatchString(text)
 }
 
 // ValidateRequestForSecrets checks a request's URL, headers, and body for plaintext secrets.
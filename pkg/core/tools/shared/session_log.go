@@ -80,7 +80,13 @@ func (t *SessionLogTool) Execute(args string) (string, error) {
 	case "end":
 		if t.sessionFile == "" {
 			// Try to find the most recent session file
-			files, err := os.ReadDir(sessionsDir)
+			entries, err := os.ReadDir(sessionsDir)
+			var files []os.DirEntry
+			for _, f := range entries {
+				if !strings.HasSuffix(f.Name(), cookieSessionExt) { // cookie jars share the directory
+					files = append(files, f)
+				}
+			}
 			if err != nil || len(files) == 0 {
 				return "No active session to end.", nil
 			}
@@ -120,7 +126,7 @@ func (t *SessionLogTool) Execute(args string) (string, error) {
 
 		var sessionFiles []string
 		for _, f := range files {
-			if !f.IsDir() && strings.HasSuffix(f.Name(), ".json") {
+			if !f.IsDir() && strings.HasSuffix(f.Name(), ".json") && !strings.HasSuffix(f.Name(), cookieSessionExt) {
 				sessionFiles = append(sessionFiles, f.Name())
 			}
 		}
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 // TestSuiteTool runs organized test suites
@@ -45,6 +47,7 @@ type TestSuiteParams struct {
 	Tests       []TestDefinition `json:"tests"`
 	OnFailure   string           `json:"on_failure,omitempty"`   // "stop" or "continue"
 	SaveResults bool             `json:"save_results,omitempty"` // Save to .falcon/test-results/
+	Tags        []string         `json:"tags,omitempty"`         // Labels for filtering suites in .falcon/suites/ (falcon test --tag)
 }
 
 // SuiteTestResult represents the result of a single test in a manual suite
@@ -68,6 +71,36 @@ type SuiteResult struct {
 	Tests      []SuiteTestResult `json:"tests"`
 }
 
+// LoadSuiteFile reads a suite saved in .falcon/suites/ (YAML or JSON with the
+// same shape as the test_suite parameters). The name defaults to the file name.
+func LoadSuiteFile(path string) (*TestSuiteParams, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read suite: %w", err)
+	}
+
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse suite YAML: %w", err)
+	}
+	jsonData, err := json.Marshal(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert suite: %w", err)
+	}
+
+	var params TestSuiteParams
+	if err := json.Unmarshal(jsonData, &params); err != nil {
+		return nil, fmt.Errorf("invalid suite format: %w", err)
+	}
+	if params.Name == "" {
+		params.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if len(params.Tests) == 0 {
+		return nil, fmt.Errorf("suite '%s' has no tests", params.Name)
+	}
+	return &params, nil
+}
+
 // Name returns the tool name
 func (t *TestSuiteTool) Name() string {
 	return "test_suite"
@@ -114,12 +147,8 @@ func (t *TestSuiteTool) Execute(args string) (string, error) {
 		return "", fmt.Errorf("'tests' array cannot be empty")
 	}
 
-	if params.OnFailure == "" {
-		params.OnFailure = "stop"
-	}
-
 	// Run the test suite
-	result := t.runSuite(params)
+	result := t.RunSuite(params)
 
 	// Save results if requested
 	if params.SaveResults {
@@ -133,8 +162,13 @@ func (t *TestSuiteTool) Execute(args string) (string, error) {
 	return t.formatResults(result), nil
 }
 
-// runSuite executes all tests in the suite
-func (t *TestSuiteTool) runSuite(params TestSuiteParams) SuiteResult {
+// RunSuite executes all tests in the suite and returns the structured result.
+// on_failure defaults to "stop".
+func (t *TestSuiteTool) RunSuite(params TestSuiteParams) SuiteResult {
+	if params.OnFailure == "" {
+		params.OnFailure = "stop"
+	}
+
 	result := SuiteResult{
 		Name:       params.Name,
 		StartTime:  time.Now(),
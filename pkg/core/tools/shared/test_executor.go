@@ -22,31 +22,24 @@ func NewTestExecutor(httpTool *HTTPTool) *TestExecutor {
 // RunScenario executes a single TestScenario against baseURL and returns a TestResult.
 // The baseURL is prepended to the scenario's URL path.
 func (e *TestExecutor) RunScenario(scenario TestScenario, baseURL string) TestResult {
-	startTime := time.Now()
-
-	// Build full URL
-	url := baseURL + scenario.URL
-	if baseURL != "" && !strings.HasPrefix(scenario.URL, "/") {
-		url = baseURL + "/" + scenario.URL
-	}
-
-	req := HTTPRequest{
-		Method:  scenario.Method,
-		URL:     url,
-		Headers: scenario.Headers,
-		Body:    scenario.Body,
-	}
-
-	resp, err := e.HTTPTool.Run(req)
-	durationMs := time.Since(startTime).Milliseconds()
-
-	return e.buildResultWithDuration(scenario, resp, err, durationMs)
+	return e.runScenario(scenario, baseURL, nil)
 }
 
 // RunScenarios executes multiple scenarios with configurable concurrency.
 // Results are returned in the same order as the input scenarios.
 // If concurrency <= 0, defaults to 5.
 func (e *TestExecutor) RunScenarios(scenarios []TestScenario, baseURL string, concurrency int) []TestResult {
+	return e.runScenarios(scenarios, baseURL, concurrency, nil)
+}
+
+// RunScenariosWithContract runs scenarios like RunScenarios and also checks
+// every response against the ingested API spec. Contract violations fail the
+// scenario; warnings (such as undeclared fields) are only logged.
+func (e *TestExecutor) RunScenariosWithContract(scenarios []TestScenario, baseURL string, concurrency int, checker *ContractChecker) []TestResult {
+	return e.runScenarios(scenarios, baseURL, concurrency, checker)
+}
+
+func (e *TestExecutor) runScenarios(scenarios []TestScenario, baseURL string, concurrency int, checker *ContractChecker) []TestResult {
 	if concurrency <= 0 {
 		concurrency = 5
 	}
@@ -60,7 +53,7 @@ func (e *TestExecutor) RunScenarios(scenarios []TestScenario, baseURL string, co
 		go func(idx int, s TestScenario) {
 			defer wg.Done()
 			semaphore <- struct{}{}
-			results[idx] = e.RunScenario(s, baseURL)
+			results[idx] = e.runScenario(s, baseURL, checker)
 			<-semaphore
 		}(i, scenario)
 	}
@@ -69,6 +62,58 @@ func (e *TestExecutor) RunScenarios(scenarios []TestScenario, baseURL string, co
 	return results
 }
 
+func (e *TestExecutor) runScenario(scenario TestScenario, baseURL string, checker *ContractChecker) TestResult {
+	startTime := time.Now()
+
+	// Build full URL
+	url := baseURL + scenario.URL
+	if baseURL != "" && !strings.HasPrefix(scenario.URL, "/") {
+		url = baseURL + "/" + scenario.URL
+	}
+
+	req := HTTPRequest{
+		Method:  scenario.Method,
+		URL:     url,
+		Headers: scenario.Headers,
+		Body:    scenario.Body,
+	}
+
+	resp, err := e.HTTPTool.Run(req)
+	durationMs := time.Since(startTime).Milliseconds()
+
+	result := e.buildResultWithDuration(scenario, resp, err, durationMs)
+	if checker != nil && err == nil {
+		applyContract(&result, checker, req, resp)
+	}
+	return result
+}
+
+// applyContract checks a response against the spec and records the outcome on the result.
+func applyContract(result *TestResult, checker *ContractChecker, req HTTPRequest, resp *HTTPResponse) {
+	contract, err := checker.Check(req.Method, req.URL, resp)
+	if err != nil {
+		result.Logs = append(result.Logs, fmt.Sprintf("Contract check skipped: %v", err))
+		return
+	}
+	result.Contract = contract
+
+	var violations []string
+	for _, issue := range contract.Issues {
+		line := "Contract " + issue.String()
+		result.Logs = append(result.Logs, line)
+		if !issue.Warning {
+			violations = append(violations, line)
+		}
+	}
+	if len(violations) > 0 {
+		result.Passed = false
+		if result.Error != "" {
+			result.Error += "; "
+		}
+		result.Error += strings.Join(violations, "; ")
+	}
+}
+
 // ValidateExpectations checks an HTTPResponse against a TestExpectation.
 // Returns a list of validation error strings. Empty list means all passed.
 // This is exported so tools with custom execution can reuse assertion logic.
@@ -116,6 +161,10 @@ func ValidateExpectations(expected TestExpectation, resp *HTTPResponse, duration
 		errors = append(errors, fmt.Sprintf("Response time %dms exceeded max %dms", durationMs, expected.MaxDurationMs))
 	}
 
+	// JSONPath values and operators ($gt, $regex, $type, $len, ...)
+	_, pathFailures := checkJSONPaths(resp.Body, expected.JSONPath)
+	errors = append(errors, pathFailures...)
+
 	return errors
 }
 
@@ -0,0 +1,280 @@
+package shared
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// HTTP versions for TransportConfig.HTTPVersion. Without one, HTTP/2 is
+// used when the server offers it over TLS.
+const (
+	HTTPVersion1 = "1.1"
+	HTTPVersion2 = "2" // Also sends HTTP/2 without TLS (h2c)
+)
+
+// NoProxy as TransportConfig.Proxy connects directly, ignoring the
+// HTTP_PROXY and HTTPS_PROXY variables.
+const NoProxy = "none"
+
+// certExpiryWarning is how close to expiry a server certificate is flagged.
+const certExpiryWarning = 30 * 24 * time.Hour
+
+// TransportConfig holds the TLS, proxy and HTTP version settings of a
+// request. The active environment sets them for every request, and a
+// request's own settings override them field by field. Its fields match
+// storage.Transport.
+type TransportConfig struct {
+	CACert             string `json:"ca_cert,omitempty"`              // PEM bundle trusted besides the system roots
+	ClientCert         string `json:"client_cert,omitempty"`          // PEM certificate for mTLS
+	ClientKey          string `json:"client_key,omitempty"`           // PEM key (default: read from client_cert)
+	InsecureSkipVerify *bool  `json:"insecure_skip_verify,omitempty"` // Skip server certificate checks (dev only)
+	Proxy              string `json:"proxy,omitempty"`                // http://, https:// or socks5:// URL, or "none"
+	HTTPVersion        string `json:"http_version,omitempty"`         // "1.1" or "2"
+}
+
+// Merge returns c with the settings of override replacing its own.
+func (c TransportConfig) Merge(override *TransportConfig) TransportConfig {
+	if override == nil {
+		return c
+	}
+	if override.CACert != "" {
+		c.CACert = override.CACert
+	}
+	if override.ClientCert != "" {
+		c.ClientCert, c.ClientKey = override.ClientCert, override.ClientKey
+	}
+	if override.InsecureSkipVerify != nil {
+		c.InsecureSkipVerify = override.InsecureSkipVerify
+	}
+	if override.Proxy != "" {
+		c.Proxy = override.Proxy
+	}
+	if override.HTTPVersion != "" {
+		c.HTTPVersion = override.HTTPVersion
+	}
+	return c
+}
+
+// Expand returns a copy with expand applied to its paths and proxy, e.g. to
+// substitute {{VAR}} placeholders. It returns nil for a nil config.
+func (c *TransportConfig) Expand(expand func(string) string) *TransportConfig {
+	if c == nil {
+		return nil
+	}
+	expanded := *c
+	for _, field := range []*string{&expanded.CACert, &expanded.ClientCert, &expanded.ClientKey, &expanded.Proxy, &expanded.HTTPVersion} {
+		*field = expand(*field)
+	}
+	return &expanded
+}
+
+// IsZero reports whether no setting is given, so the default transport applies.
+func (c TransportConfig) IsZero() bool {
+	return c.CACert == "" && c.ClientCert == "" && c.ClientKey == "" && c.InsecureSkipVerify == nil && c.Proxy == "" && c.HTTPVersion == ""
+}
+
+// Summary describes the settings in one line, e.g. for the environment tool.
+func (c TransportConfig) Summary() string {
+	var parts []string
+	if c.CACert != "" {
+		parts = append(parts, "CA bundle "+c.CACert)
+	}
+	if c.ClientCert != "" {
+		parts = append(parts, "client certificate "+c.ClientCert)
+	}
+	if c.InsecureSkipVerify != nil && *c.InsecureSkipVerify {
+		parts = append(parts, "⚠️ certificate checks off")
+	}
+	if c.Proxy != "" {
+		parts = append(parts, "proxy "+c.Proxy)
+	}
+	if c.HTTPVersion != "" {
+		parts = append(parts, "HTTP/"+c.HTTPVersion)
+	}
+	if len(parts) == 0 {
+		return "default transport"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// transportSchema is the JSON Schema of the "transport" request option.
+func transportSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "object",
+		"description": "TLS, proxy and HTTP version settings, overriding those of the active environment",
+		"properties": map[string]interface{}{
+			"ca_cert":              map[string]interface{}{"type": "string", "description": "PEM CA bundle trusted besides the system roots"},
+			"client_cert":          map[string]interface{}{"type": "string", "description": "PEM client certificate for mTLS"},
+			"client_key":           map[string]interface{}{"type": "string", "description": "PEM key for client_cert (default: read from client_cert)"},
+			"insecure_skip_verify": map[string]interface{}{"type": "boolean", "description": "Skip server certificate checks (dev only)"},
+			"proxy":                map[string]interface{}{"type": "string", "description": "Proxy URL (http://, https://, socks5://), or \"none\" to ignore HTTP_PROXY"},
+			"http_version":         map[string]interface{}{"type": "string", "enum": []string{HTTPVersion1, HTTPVersion2}, "description": "Force HTTP/1.1 or HTTP/2 (default: HTTP/2 when the server offers it)"},
+		},
+	}
+}
+
+// key identifies the config for the transport cache.
+func (c TransportConfig) key() string {
+	data, _ := json.Marshal(c)
+	return string(data)
+}
+
+// newTransport builds a transport with the config's settings on top of
+// http.DefaultTransport.
+func (c TransportConfig) newTransport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	tlsConfig := &tls.Config{}
+
+	if c.CACert != "" {
+		pem, err := os.ReadFile(c.CACert)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle '%s'", c.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if c.ClientCert != "" {
+		keyFile := c.ClientKey
+		if keyFile == "" {
+			keyFile = c.ClientCert
+		}
+		cert, err := tls.LoadX509KeyPair(c.ClientCert, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	} else if c.ClientKey != "" {
+		return nil, fmt.Errorf("client_key needs a client_cert")
+	}
+
+	if c.InsecureSkipVerify != nil {
+		tlsConfig.InsecureSkipVerify = *c.InsecureSkipVerify
+	}
+	transport.TLSClientConfig = tlsConfig
+
+	switch c.Proxy {
+	case "":
+		// keep HTTP_PROXY, HTTPS_PROXY and NO_PROXY from the environment
+	case NoProxy:
+		transport.Proxy = nil
+	default:
+		proxyURL, err := url.Parse(c.Proxy)
+		if err != nil || proxyURL.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL '%s' (expected e.g. http://proxy:8080, or \"none\")", c.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	var protocols http.Protocols
+	switch c.HTTPVersion {
+	case "":
+	case "1", HTTPVersion1:
+		protocols.SetHTTP1(true)
+		transport.Protocols = &protocols
+	case HTTPVersion2, "2.0":
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+		transport.Protocols = &protocols
+	default:
+		return nil, fmt.Errorf("unknown http_version '%s' (use: 1.1, 2)", c.HTTPVersion)
+	}
+
+	return transport, nil
+}
+
+// transportFor returns the transport for a request's settings merged over
+// the tool's. Transports are cached per config so connections are reused.
+func (t *HTTPTool) transportFor(override *TransportConfig) (http.RoundTripper, error) {
+	var config TransportConfig
+	if t.transport != nil {
+		config = t.transport()
+	}
+	config = config.Merge(override)
+	if config.IsZero() {
+		return t.client.Transport, nil
+	}
+
+	key := config.key()
+	t.transportsMu.Lock()
+	defer t.transportsMu.Unlock()
+	if transport, ok := t.transports[key]; ok {
+		return transport, nil
+	}
+	transport, err := config.newTransport()
+	if err != nil {
+		return nil, err
+	}
+	if t.transports == nil {
+		t.transports = make(map[string]*http.Transport)
+	}
+	t.transports[key] = transport
+	return transport, nil
+}
+
+// TLSInfo describes the TLS connection a response arrived on.
+type TLSInfo struct {
+	Version      string            `json:"version"`      // e.g. TLS 1.3
+	CipherSuite  string            `json:"cipher_suite"` // e.g. TLS_AES_128_GCM_SHA256
+	ServerName   string            `json:"server_name,omitempty"`
+	Certificates []CertificateInfo `json:"certificates"` // Server chain, leaf first
+}
+
+// CertificateInfo describes one certificate of the server chain.
+type CertificateInfo struct {
+	Subject  string    `json:"subject"`
+	Issuer   string    `json:"issuer"`
+	NotAfter time.Time `json:"not_after"`
+}
+
+// newTLSInfo summarizes a connection state; nil for plain HTTP.
+func newTLSInfo(state *tls.ConnectionState) *TLSInfo {
+	if state == nil {
+		return nil
+	}
+	info := &TLSInfo{
+		Version:     tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+		ServerName:  state.ServerName,
+	}
+	for _, cert := range state.PeerCertificates {
+		info.Certificates = append(info.Certificates, CertificateInfo{
+			Subject:  cert.Subject.String(),
+			Issuer:   cert.Issuer.String(),
+			NotAfter: cert.NotAfter,
+		})
+	}
+	return info
+}
+
+// Format renders the connection and certificate expiry for FormatResponse,
+// flagging certificates that expire within 30 days.
+func (i *TLSInfo) Format(proto string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("TLS: %s, %s, %s\n", i.Version, i.CipherSuite, proto))
+	for _, cert := range i.Certificates {
+		left := time.Until(cert.NotAfter)
+		status := fmt.Sprintf("in %d days", int(left.Hours()/24))
+		switch {
+		case left <= 0:
+			status = "⚠️ EXPIRED"
+		case left < certExpiryWarning:
+			status = "⚠️ " + status
+		}
+		sb.WriteString(fmt.Sprintf("  %s (issuer %s) expires %s, %s\n", cert.Subject, cert.Issuer, cert.NotAfter.Format("2006-01-02"), status))
+	}
+	return sb.String()
+}
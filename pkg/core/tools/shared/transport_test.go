@@ -0,0 +1,134 @@
+package shared
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeClientCert writes a self-signed client certificate and its key to one PEM file.
+func writeClientCert(t *testing.T, path string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "falcon-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})...)
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestHTTPTool_TLSTransport(t *testing.T) {
+	var clientCN string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientCN = ""
+		if len(r.TLS.PeerCertificates) > 0 {
+			clientCN = r.TLS.PeerCertificates[0].Subject.CommonName
+		}
+	}))
+	server.EnableHTTP2 = true
+	server.Config.ErrorLog = log.New(io.Discard, "", 0) // handshakes this test expects to fail
+	server.TLS = &tls.Config{ClientAuth: tls.RequestClientCert}
+	server.StartTLS()
+	defer server.Close()
+
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	if err := os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0644); err != nil {
+		t.Fatal(err)
+	}
+	clientFile := filepath.Join(dir, "client.pem")
+	writeClientCert(t, clientFile)
+
+	tool := NewHTTPTool(nil, nil)
+	if _, err := tool.Run(HTTPRequest{Method: "GET", URL: server.URL}); err == nil {
+		t.Fatal("expected an error for an untrusted server certificate")
+	}
+
+	// Environment settings apply to every request
+	tool.SetTransport(TransportConfig{CACert: caFile})
+	resp, err := tool.Run(HTTPRequest{Method: "GET", URL: server.URL})
+	if err != nil {
+		t.Fatalf("with CA bundle: %v", err)
+	}
+	if resp.Proto != "HTTP/2.0" || resp.TLS == nil || resp.TLS.Version != "TLS 1.3" || len(resp.TLS.Certificates) == 0 {
+		t.Fatalf("proto %q, tls %+v", resp.Proto, resp.TLS)
+	}
+	if out := resp.FormatResponse(); !strings.Contains(out, "TLS: TLS 1.3") || !strings.Contains(out, "expires") {
+		t.Errorf("FormatResponse lacks TLS details:\n%s", out)
+	}
+	if clientCN != "" {
+		t.Errorf("client certificate sent without client_cert: %q", clientCN)
+	}
+
+	// Request settings override them field by field
+	resp, err = tool.Run(HTTPRequest{Method: "GET", URL: server.URL, Transport: &TransportConfig{ClientCert: clientFile, HTTPVersion: HTTPVersion1}})
+	if err != nil {
+		t.Fatalf("with client certificate: %v", err)
+	}
+	if clientCN != "falcon-client" || resp.Proto != "HTTP/1.1" {
+		t.Errorf("client CN %q, proto %q", clientCN, resp.Proto)
+	}
+
+	insecure := true
+	tool.SetTransport(TransportConfig{InsecureSkipVerify: &insecure})
+	if _, err := tool.Run(HTTPRequest{Method: "GET", URL: server.URL}); err != nil {
+		t.Errorf("insecure_skip_verify: %v", err)
+	}
+	verify := false
+	if _, err := tool.Run(HTTPRequest{Method: "GET", URL: server.URL, Transport: &TransportConfig{InsecureSkipVerify: &verify}}); err == nil {
+		t.Error("expected the request to turn certificate checks back on")
+	}
+}
+
+func TestHTTPTool_Proxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	tool := NewHTTPTool(nil, nil)
+	resp, err := tool.Run(HTTPRequest{Method: "GET", URL: "http://api.internal.test/users", Transport: &TransportConfig{Proxy: proxy.URL}})
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if proxied != "http://api.internal.test/users" || resp.TLS != nil {
+		t.Errorf("proxy saw %q, tls %+v", proxied, resp.TLS)
+	}
+
+	if _, err := tool.Run(HTTPRequest{Method: "GET", URL: proxy.URL, Transport: &TransportConfig{HTTPVersion: "3"}}); err == nil {
+		t.Error("expected an error for an unknown http_version")
+	}
+}
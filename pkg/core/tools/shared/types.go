@@ -48,6 +48,9 @@ type TestResult struct {
 	Severity       string   `json:"severity,omitempty"`
 	OWASPRef       string   `json:"owasp_ref,omitempty"`
 	Timestamp      string   `json:"timestamp"`
+
+	// Contract holds the spec check when the scenario ran in contract mode.
+	Contract *ContractResult `json:"contract,omitempty"`
 }
 
 // EndpointAnalysis represents the structured output of analysis
@@ -57,6 +60,13 @@ type EndpointAnalysis struct {
 	AuthType   string          `json:"auth_type"`
 	Responses  []Response      `json:"responses"`
 	Security   []SecurityRisks `json:"security_risks"`
+
+	// RequestSchemas maps each accepted request content type to its JSON Schema.
+	RequestSchemas map[string]interface{} `json:"request_schemas,omitempty" yaml:",omitempty"`
+	// RequestExamples maps request content types to an example payload from the spec.
+	RequestExamples map[string]interface{} `json:"request_examples,omitempty" yaml:",omitempty"`
+	// RequestModel names the model of the request body when the spec references one.
+	RequestModel string `json:"request_model,omitempty" yaml:",omitempty"`
 }
 
 type Parameter struct {
@@ -69,6 +79,13 @@ type Parameter struct {
 type Response struct {
 	StatusCode  int    `json:"status_code"`
 	Description string `json:"description"`
+
+	// Status is the status key as written in the spec: "200", "2XX" or "default".
+	// StatusCode is 0 for ranges and the default response.
+	Status string `json:"status,omitempty" yaml:",omitempty"`
+	// Schemas maps each response content type to its JSON Schema (nil when
+	// the spec documents the content type without a schema).
+	Schemas map[string]interface{} `json:"schemas,omitempty" yaml:",omitempty"`
 }
 
 type SecurityRisks struct {
@@ -91,6 +108,9 @@ type APIKnowledgeGraph struct {
 	Models    map[string]ModelDefinition  `json:"models"`
 	Context   ProjectContext              `json:"context"`
 	Version   string                      `json:"version"`
+
+	// BaseURL is the server URL declared by the spec, if any
+	BaseURL string `json:"base_url,omitempty" yaml:",omitempty"`
 }
 
 // ModelDefinition describes a data structure used in the API
@@ -98,6 +118,9 @@ type ModelDefinition struct {
 	Name        string              `json:"name"`
 	Fields      map[string]Variable `json:"fields"`
 	Description string              `json:"description"`
+
+	// Schema is the model as a JSON Schema with every $ref inlined
+	Schema map[string]interface{} `json:"schema,omitempty" yaml:",omitempty"`
 }
 
 // Variable represents a schema field or parameter
@@ -106,4 +129,17 @@ type Variable struct {
 	Format      string `json:"format,omitempty"`
 	Required    bool   `json:"required"`
 	Description string `json:"description,omitempty"`
+
+	// Constraints from the spec, used to build valid and boundary values
+	Enum             []interface{} `json:"enum,omitempty" yaml:",omitempty"`
+	Minimum          *float64      `json:"minimum,omitempty" yaml:",omitempty"`
+	Maximum          *float64      `json:"maximum,omitempty" yaml:",omitempty"`
+	ExclusiveMinimum bool          `json:"exclusive_minimum,omitempty" yaml:",omitempty"`
+	ExclusiveMaximum bool          `json:"exclusive_maximum,omitempty" yaml:",omitempty"`
+	MinLength        *int          `json:"min_length,omitempty" yaml:",omitempty"`
+	MaxLength        *int          `json:"max_length,omitempty" yaml:",omitempty"`
+	Pattern          string        `json:"pattern,omitempty" yaml:",omitempty"`
+	Example          interface{}   `json:"example,omitempty" yaml:",omitempty"`
+	// Ref names the model this field holds (or, for arrays, the model of its items)
+	Ref string `json:"ref,omitempty" yaml:",omitempty"`
 }
@@ -9,7 +9,9 @@ import (
 )
 
 // runHealthChecks executes reachability and basic functionality checks.
-func (t *SmokeRunnerTool) runHealthChecks(baseURL string, endpoints []string) []HealthCheck {
+// With a contract checker, a reachable endpoint whose response violates the
+// ingested spec is reported as an error.
+func (t *SmokeRunnerTool) runHealthChecks(baseURL string, endpoints []string, checker *shared.ContractChecker) []HealthCheck {
 	var checks []HealthCheck
 
 	for _, ep := range endpoints {
@@ -43,6 +45,9 @@ func (t *SmokeRunnerTool) runHealthChecks(baseURL string, endpoints []string) []
 		} else if resp.StatusCode >= 200 && resp.StatusCode < 300 {
 			check.Status = "ok"
 			check.Message = fmt.Sprintf("HTTP %d", resp.StatusCode)
+			if checker != nil {
+				t.applyContract(&check, checker, req, resp)
+			}
 		} else {
 			check.Status = "error"
 			check.Message = fmt.Sprintf("HTTP %d", resp.StatusCode)
@@ -53,3 +58,18 @@ func (t *SmokeRunnerTool) runHealthChecks(baseURL string, endpoints []string) []
 
 	return checks
 }
+
+// applyContract appends the contract check outcome to a health check.
+func (t *SmokeRunnerTool) applyContract(check *HealthCheck, checker *shared.ContractChecker, req shared.HTTPRequest, resp *shared.HTTPResponse) {
+	result, err := checker.Check(req.Method, req.URL, resp)
+	if err != nil {
+		check.Message += fmt.Sprintf("; contract check skipped: %v", err)
+		return
+	}
+	if !result.Passed() {
+		check.Status = "error"
+	}
+	for _, issue := range result.Issues {
+		check.Message += "; contract " + issue.String()
+	}
+}
@@ -29,6 +29,7 @@ type SmokeParams struct {
 	Endpoints []string `json:"endpoints,omitempty"`  // Specific critical endpoints to check
 	Timeout   int      `json:"timeout_ms,omitempty"` // Timeout per request
 	Detailed  bool     `json:"detailed,omitempty"`   // Whether to provide detailed health diagnostics
+	Contract  bool     `json:"contract,omitempty"`   // Validate responses against .falcon/spec.yaml
 }
 
 // SmokeResult represents the outcome of a smoke test.
@@ -60,7 +61,8 @@ func (t *SmokeRunnerTool) Parameters() string {
 	return `{
   "base_url": "http://localhost:3000",
   "endpoints": ["GET /health", "GET /api/v1/status"],
-  "detailed": true
+  "detailed": true,
+  "contract": true
 }`
 }
 
@@ -83,7 +85,11 @@ func (t *SmokeRunnerTool) Execute(args string) (string, error) {
 	}
 
 	// 2. Run reachability and health checks
-	checks := t.runHealthChecks(params.BaseURL, endpoints)
+	var checker *shared.ContractChecker
+	if params.Contract {
+		checker = shared.NewContractChecker(t.falconDir)
+	}
+	checks := t.runHealthChecks(params.BaseURL, endpoints, checker)
 
 	// 3. Determine overall status
 	status := "pass"
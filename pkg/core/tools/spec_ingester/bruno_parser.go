@@ -0,0 +1,305 @@
+package spec_ingester
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// BrunoParser implements the SpecParser for Bruno collections: .bru request
+// files (a collection directory is read as its .bru files, one after the
+// other) and the JSON collection export.
+type BrunoParser struct{}
+
+var (
+	bruMeta       = regexp.MustCompile(`(?m)^meta\s*\{`)
+	bruBlockStart = regexp.MustCompile(`^([A-Za-z][\w:-]*)\s*\{\s*$`)
+)
+
+var bruMethods = map[string]bool{
+	"get": true, "post": true, "put": true, "delete": true, "patch": true, "options": true, "head": true,
+}
+
+type brunoExport struct {
+	Name    string      `json:"name"`
+	Version string      `json:"version"`
+	Items   []brunoItem `json:"items"`
+}
+
+type brunoItem struct {
+	Type    string      `json:"type"` // "http-request", "graphql-request", "folder"
+	Name    string      `json:"name"`
+	Items   []brunoItem `json:"items"`
+	Request struct {
+		URL     string          `json:"url"`
+		Method  string          `json:"method"`
+		Headers []brunoPair     `json:"headers"`
+		Params  []brunoPair     `json:"params"`
+		Body    brunoExportBody `json:"body"`
+		Auth    brunoExportAuth `json:"auth"`
+	} `json:"request"`
+}
+
+type brunoPair struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	Type    string `json:"type"` // params: "query" or "path"
+	Enabled bool   `json:"enabled"`
+}
+
+type brunoExportBody struct {
+	Mode           string      `json:"mode"`
+	JSON           string      `json:"json"`
+	Text           string      `json:"text"`
+	XML            string      `json:"xml"`
+	FormURLEncoded []brunoPair `json:"formUrlEncoded"`
+}
+
+type brunoExportAuth struct {
+	Mode   string `json:"mode"`
+	Bearer struct {
+		Token string `json:"token"`
+	} `json:"bearer"`
+	Basic struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	} `json:"basic"`
+}
+
+// bruBlock is one "name { ... }" section of a .bru file
+type bruBlock struct {
+	name  string
+	lines []string
+}
+
+func (p *BrunoParser) DetectFormat(content []byte) bool {
+	if bruMeta.Match(content) {
+		return true
+	}
+	var export brunoExport
+	if err := json.Unmarshal(content, &export); err != nil {
+		return false
+	}
+	return len(export.Items) > 0 && bytes.Contains(content, []byte(`"http-request"`))
+}
+
+func (p *BrunoParser) Parse(content []byte) (*ParsedSpec, error) {
+	spec := &ParsedSpec{Format: "bruno"}
+
+	if trimmed := bytes.TrimSpace(content); len(trimmed) > 0 && trimmed[0] == '{' {
+		var export brunoExport
+		if err := json.Unmarshal(content, &export); err != nil {
+			return nil, fmt.Errorf("failed to parse Bruno collection: %w", err)
+		}
+		spec.Version = export.Version
+		p.processItems(export.Items, spec)
+		return spec, nil
+	}
+
+	// Each "meta" block starts a new request file
+	var file []bruBlock
+	for _, block := range parseBruBlocks(content) {
+		if block.name == "meta" && len(file) > 0 {
+			p.addBruRequest(file, spec)
+			file = nil
+		}
+		file = append(file, block)
+	}
+	p.addBruRequest(file, spec)
+
+	if len(spec.Endpoints) == 0 {
+		return nil, fmt.Errorf("no requests found in Bruno collection")
+	}
+	return spec, nil
+}
+
+func (p *BrunoParser) processItems(items []brunoItem, spec *ParsedSpec) {
+	for _, item := range items {
+		if item.Type == "folder" {
+			p.processItems(item.Items, spec)
+			continue
+		}
+		if item.Type != "http-request" {
+			continue
+		}
+		r := item.Request
+		req := ParsedRequest{
+			Name:    item.Name,
+			Method:  r.Method,
+			URL:     r.URL,
+			Headers: make(map[string]string),
+			Tags:    []string{"bruno"},
+		}
+		for _, h := range r.Headers {
+			if h.Enabled {
+				req.Headers[h.Name] = h.Value
+			}
+		}
+		for _, q := range r.Params {
+			if q.Enabled && q.Type != "path" {
+				if req.Query == nil {
+					req.Query = make(map[string]string)
+				}
+				req.Query[q.Name] = q.Value
+			}
+		}
+		var fields [][2]string
+		for _, f := range r.Body.FormURLEncoded {
+			if f.Enabled {
+				fields = append(fields, [2]string{f.Name, f.Value})
+			}
+		}
+		setBruBody(&req, r.Body.Mode, map[string]string{
+			"json":           r.Body.JSON,
+			"text":           r.Body.Text,
+			"xml":            r.Body.XML,
+			"formUrlEncoded": encodeForm(fields),
+		})
+		if header := authHeader(r.Auth.Mode, r.Auth.Bearer.Token, r.Auth.Basic.Username, r.Auth.Basic.Password); header != "" {
+			req.Headers["Authorization"] = header
+		}
+		addRequest(spec, req)
+	}
+}
+
+// addBruRequest turns the blocks of one .bru file into a request. Files
+// without a method block (collection.bru, environments) are skipped.
+func (p *BrunoParser) addBruRequest(blocks []bruBlock, spec *ParsedSpec) {
+	req := ParsedRequest{Headers: make(map[string]string), Tags: []string{"bruno"}}
+	var bodyMode, authMode string
+	bodies := make(map[string]string)
+	auth := make(map[string]map[string]string)
+
+	for _, block := range blocks {
+		switch {
+		case block.name == "meta":
+			req.Name = bruDict(block)["name"]
+		case bruMethods[block.name]:
+			req.Method = strings.ToUpper(block.name)
+			fields := bruDict(block)
+			req.URL = fields["url"]
+			bodyMode = fields["body"]
+			authMode = fields["auth"]
+		case block.name == "headers":
+			for k, v := range bruDict(block) {
+				req.Headers[k] = v
+			}
+		case block.name == "params:query" || block.name == "query":
+			if req.Query == nil {
+				req.Query = make(map[string]string)
+			}
+			for k, v := range bruDict(block) {
+				req.Query[k] = v
+			}
+		case strings.HasPrefix(block.name, "auth:"):
+			auth[strings.TrimPrefix(block.name, "auth:")] = bruDict(block)
+		case block.name == "body:form-urlencoded":
+			var fields [][2]string
+			for _, line := range block.lines {
+				if k, v, ok := bruPair(line); ok {
+					fields = append(fields, [2]string{k, v})
+				}
+			}
+			bodies["formUrlEncoded"] = encodeForm(fields)
+		case strings.HasPrefix(block.name, "body:"):
+			bodies[strings.TrimPrefix(block.name, "body:")] = bruText(block)
+		}
+	}
+	if req.Method == "" {
+		return
+	}
+
+	setBruBody(&req, bodyMode, bodies)
+	if fields, ok := auth[authMode]; ok {
+		if header := authHeader(authMode, fields["token"], fields["username"], fields["password"]); header != "" {
+			req.Headers["Authorization"] = header
+		}
+	}
+	addRequest(spec, req)
+}
+
+// parseBruBlocks splits .bru content into its top-level blocks. Blocks end
+// at a closing brace in the first column; their lines keep the two-space
+// indent, which bruText and bruDict strip.
+func parseBruBlocks(content []byte) []bruBlock {
+	var blocks []bruBlock
+	var current *bruBlock
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if current == nil {
+			if m := bruBlockStart.FindStringSubmatch(line); m != nil {
+				current = &bruBlock{name: m[1]}
+			}
+			continue
+		}
+		if line == "}" {
+			blocks = append(blocks, *current)
+			current = nil
+			continue
+		}
+		current.lines = append(current.lines, line)
+	}
+	return blocks
+}
+
+// bruDict reads a key: value block. Lines starting with "~" are disabled.
+func bruDict(block bruBlock) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range block.lines {
+		if k, v, ok := bruPair(line); ok {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+func bruPair(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "~") {
+		return "", "", false
+	}
+	key, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", "", false
+	}
+	return strings.TrimSpace(key), strings.TrimSpace(value), true
+}
+
+// bruText returns a raw block (body:json, body:text, ...) without its indent.
+func bruText(block bruBlock) string {
+	lines := make([]string, len(block.lines))
+	for i, line := range block.lines {
+		lines[i] = strings.TrimPrefix(line, "  ")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// setBruBody sets the body selected by a request's body mode, with the
+// matching Content-Type unless one was given.
+func setBruBody(req *ParsedRequest, mode string, bodies map[string]string) {
+	switch mode {
+	case "json":
+		req.Body = decodeBody(bodies["json"], "application/json")
+		setContentType(req.Headers, "application/json")
+	case "text":
+		req.Body = decodeBody(bodies["text"], "text/plain")
+		setContentType(req.Headers, "text/plain")
+	case "xml":
+		req.Body = decodeBody(bodies["xml"], "application/xml")
+		setContentType(req.Headers, "application/xml")
+	case "formUrlEncoded", "form-urlencoded":
+		req.Body = decodeBody(bodies["formUrlEncoded"], "application/x-www-form-urlencoded")
+		setContentType(req.Headers, "application/x-www-form-urlencoded")
+	}
+}
+
+func setContentType(headers map[string]string, contentType string) {
+	if headerValue(headers, "Content-Type") == "" {
+		headers["Content-Type"] = contentType
+	}
+}
@@ -0,0 +1,312 @@
+package spec_ingester
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// CurlParser implements the SpecParser for curl commands, e.g. a runbook or
+// a file of "Copy as cURL" snippets. Every line starting with "curl" (or
+// "$ curl") is a command; trailing "\", "^" and "`" continue it. Commands
+// that cannot be split into words are skipped.
+type CurlParser struct{}
+
+var (
+	curlCommandStart = regexp.MustCompile(`^\s*(?:\$\s+)?curl\s`)
+	shellVar         = regexp.MustCompile(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)\}|([A-Za-z_][A-Za-z0-9_]*))`)
+)
+
+// curlValueFlags take an argument. Flags not listed here are switches.
+var curlValueFlags = map[string]bool{
+	"-X": true, "--request": true, "-H": true, "--header": true,
+	"-d": true, "--data": true, "--data-raw": true, "--data-binary": true, "--data-ascii": true,
+	"--data-urlencode": true, "--json": true, "-F": true, "--form": true, "--form-string": true,
+	"-u": true, "--user": true, "--url": true, "-b": true, "--cookie": true, "-c": true, "--cookie-jar": true,
+	"-A": true, "--user-agent": true, "-e": true, "--referer": true, "-o": true, "--output": true,
+	"-m": true, "--max-time": true, "--connect-timeout": true, "-w": true, "--write-out": true,
+	"--retry": true, "-x": true, "--proxy": true, "--cacert": true, "-E": true, "--cert": true,
+	"--key": true, "--resolve": true, "-T": true, "--upload-file": true, "--oauth2-bearer": true,
+}
+
+func (p *CurlParser) DetectFormat(content []byte) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		if curlCommandStart.MatchString(scanner.Text()) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *CurlParser) Parse(content []byte) (*ParsedSpec, error) {
+	spec := &ParsedSpec{Format: "curl"}
+	for _, command := range splitCurlCommands(content) {
+		req, err := parseCurlCommand(command)
+		if err != nil || req.URL == "" {
+			continue
+		}
+		addRequest(spec, req)
+	}
+	if len(spec.Endpoints) == 0 {
+		return nil, fmt.Errorf("no curl commands with a URL found")
+	}
+	return spec, nil
+}
+
+// splitCurlCommands extracts each curl command, joining continuation lines.
+func splitCurlCommands(content []byte) []string {
+	var commands []string
+	var current strings.Builder
+	inCommand, inQuote := false, false
+
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if inQuote && curlCommandStart.MatchString(line) {
+			// the previous command never closed its quote
+			commands = append(commands, current.String())
+			current.Reset()
+			inCommand, inQuote = false, false
+		}
+		if !inCommand {
+			if !curlCommandStart.MatchString(line) {
+				continue
+			}
+			line = strings.TrimPrefix(strings.TrimSpace(line), "$")
+			inCommand = true
+		}
+		trimmed := strings.TrimRight(line, "\\^`")
+		current.WriteString(trimmed)
+		if len(trimmed) < len(line) {
+			current.WriteString(" ")
+			continue
+		}
+		// a quoted body may span several lines
+		if _, err := shellSplit(current.String()); err != nil {
+			current.WriteString("\n")
+			inQuote = true
+			continue
+		}
+		commands = append(commands, current.String())
+		current.Reset()
+		inCommand, inQuote = false, false
+	}
+	if inCommand {
+		commands = append(commands, current.String())
+	}
+	return commands
+}
+
+// parseCurlCommand reads the method, URL, headers, body and basic auth of
+// one curl command.
+func parseCurlCommand(command string) (ParsedRequest, error) {
+	args, err := shellSplit(command)
+	if err != nil {
+		return ParsedRequest{}, fmt.Errorf("failed to parse curl command: %w", err)
+	}
+	req := ParsedRequest{Headers: make(map[string]string), Tags: []string{"curl"}}
+	var data []string
+	var form [][2]string
+	var user string
+	useGet := false
+
+	for i := 1; i < len(args); i++ {
+		// runbook variables ($API_KEY, ${HOST}) become {{VAR}} placeholders
+		arg := shellVar.ReplaceAllString(args[i], "{{$1$2}}")
+		if arg == "|" || arg == "&&" || arg == ";" || arg == ">" || arg == "||" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			if req.URL == "" {
+				req.URL = arg
+			}
+			continue
+		}
+
+		flag, value, hasValue := arg, "", false
+		if strings.HasPrefix(arg, "--") {
+			if name, v, ok := strings.Cut(arg, "="); ok && curlValueFlags[name] {
+				flag, value, hasValue = name, v, true
+			}
+		} else if len(arg) > 2 && curlValueFlags[arg[:2]] {
+			// -XPOST, -HAccept:...
+			flag, value, hasValue = arg[:2], arg[2:], true
+		} else if len(arg) > 2 {
+			// a cluster of switches such as -sSL; -G and -I still count
+			for _, c := range arg[1:] {
+				switch c {
+				case 'G':
+					useGet = true
+				case 'I':
+					req.Method = "HEAD"
+				}
+			}
+			continue
+		}
+		if curlValueFlags[flag] && !hasValue {
+			if i+1 >= len(args) {
+				break
+			}
+			i++
+			value = shellVar.ReplaceAllString(args[i], "{{$1$2}}")
+		}
+
+		switch flag {
+		case "-X", "--request":
+			req.Method = strings.ToUpper(value)
+		case "-H", "--header":
+			if name, v, ok := strings.Cut(value, ":"); ok {
+				req.Headers[strings.TrimSpace(name)] = strings.TrimSpace(v)
+			}
+		case "-d", "--data", "--data-raw", "--data-binary", "--data-ascii":
+			data = append(data, value)
+		case "--data-urlencode":
+			if name, v, ok := strings.Cut(value, "="); ok {
+				data = append(data, name+"="+queryEscape(v))
+			} else {
+				data = append(data, queryEscape(value))
+			}
+		case "--json":
+			data = append(data, value)
+			setContentType(req.Headers, "application/json")
+			if headerValue(req.Headers, "Accept") == "" {
+				req.Headers["Accept"] = "application/json"
+			}
+		case "-F", "--form", "--form-string":
+			if name, v, ok := strings.Cut(value, "="); ok {
+				form = append(form, [2]string{name, v})
+			}
+		case "-u", "--user":
+			user = value
+		case "--oauth2-bearer":
+			req.Headers["Authorization"] = "Bearer " + value
+		case "--url":
+			req.URL = value
+		case "-G", "--get":
+			useGet = true
+		case "-I", "--head":
+			req.Method = "HEAD"
+		}
+	}
+
+	if user != "" {
+		username, password, _ := strings.Cut(user, ":")
+		if header := authHeader("basic", "", username, password); header != "" {
+			req.Headers["Authorization"] = header
+		}
+	}
+
+	switch {
+	case len(data) > 0 && useGet:
+		sep := "?"
+		if strings.Contains(req.URL, "?") {
+			sep = "&"
+		}
+		req.URL += sep + strings.Join(data, "&")
+	case len(data) > 0:
+		setContentType(req.Headers, "application/x-www-form-urlencoded")
+		req.Body = decodeBody(strings.Join(data, "&"), mediaTypeOf(req.Headers))
+	case len(form) > 0:
+		setContentType(req.Headers, "multipart/form-data")
+		fields := make(map[string]interface{}, len(form))
+		for _, f := range form {
+			fields[f[0]] = f[1]
+		}
+		req.Body = fields
+	}
+	if req.Method == "" {
+		req.Method = "GET"
+		if req.Body != nil {
+			req.Method = "POST"
+		}
+	}
+	return req, nil
+}
+
+// shellSplit splits a command line into words the way a POSIX shell would
+// for the quoting curl snippets use: '...', "...", $'...' and backslashes.
+func shellSplit(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	runes := []rune(s)
+
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '\'':
+			inWord = true
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			word.WriteString(string(runes[i+1 : end]))
+			i = end
+		case c == '$' && i+1 < len(runes) && runes[i+1] == '\'':
+			inWord = true
+			i += 2
+			for ; i < len(runes) && runes[i] != '\''; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+					switch runes[i] {
+					case 'n':
+						word.WriteRune('\n')
+					case 't':
+						word.WriteRune('\t')
+					case 'r':
+						word.WriteRune('\r')
+					default:
+						word.WriteRune(runes[i])
+					}
+					continue
+				}
+				word.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated $'' quote")
+			}
+		case c == '"':
+			inWord = true
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`", runes[i+1]) {
+					i++
+				}
+				word.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+		case c == '\\' && i+1 < len(runes):
+			inWord = true
+			i++
+			word.WriteRune(runes[i])
+		default:
+			inWord = true
+			word.WriteRune(c)
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+func indexRune(runes []rune, from int, r rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
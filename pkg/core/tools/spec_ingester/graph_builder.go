@@ -3,6 +3,9 @@ package spec_ingester
 import (
 	"fmt"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
 	"gopkg.in/yaml.v3"
@@ -25,15 +28,41 @@ func (b *GraphBuilder) BuildGraph(spec *ParsedSpec, context shared.ProjectContex
 		Models:    make(map[string]shared.ModelDefinition),
 		Context:   context,
 		Version:   spec.Version,
+		BaseURL:   spec.BaseURL,
+	}
+
+	raw := make(map[string]map[string]interface{}, len(spec.Models))
+	for _, m := range spec.Models {
+		raw[m.Name] = m.Raw
+	}
+	resolve := func(ref string) map[string]interface{} {
+		return raw[refName(ref)]
+	}
+	for _, m := range spec.Models {
+		graph.Models[m.Name] = shared.ModelDefinition{
+			Name:        m.Name,
+			Fields:      modelFields(m.Raw, resolve),
+			Description: m.Description,
+			Schema:      m.Schema,
+		}
 	}
 
 	for _, endpoint := range spec.Endpoints {
 		uniqueID := fmt.Sprintf("%s %s", endpoint.Method, endpoint.Path)
-		graph.Endpoints[uniqueID] = shared.EndpointAnalysis{
-			Summary:    endpoint.Summary,
-			Parameters: b.mapParameters(endpoint.Parameters),
-			Responses:  b.mapResponses(endpoint.Responses),
+		analysis := shared.EndpointAnalysis{
+			Summary:         endpoint.Summary,
+			Parameters:      b.mapParameters(endpoint.Parameters),
+			Responses:       b.mapResponses(endpoint.Responses),
+			RequestSchemas:  endpoint.RequestSchemas,
+			RequestExamples: endpoint.RequestExamples,
+			RequestModel:    endpoint.RequestModel,
+			AuthType:        endpoint.AuthType,
+		}
+		analysis.Parameters = append(analysis.Parameters, b.mapBodyFields(endpoint.RequestSchemas)...)
+		if len(endpoint.ResponseBodies) > 0 {
+			analysis.Responses = b.mapResponseBodies(endpoint.ResponseBodies)
 		}
+		graph.Endpoints[uniqueID] = analysis
 	}
 
 	return graph, nil
@@ -52,6 +81,45 @@ func (b *GraphBuilder) mapParameters(params []ParsedParameter) []shared.Paramete
 	return result
 }
 
+// mapBodyFields lists the top-level fields of a JSON request body as
+// "in: body" parameters, the form the test strategies look for.
+func (b *GraphBuilder) mapBodyFields(schemas map[string]interface{}) []shared.Parameter {
+	for _, contentType := range sortedSchemaKeys(schemas) {
+		schema, ok := schemas[contentType].(map[string]interface{})
+		if !ok || !strings.Contains(contentType, "json") {
+			continue
+		}
+		fields := modelFields(schema, nil)
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var result []shared.Parameter
+		for _, name := range names {
+			field := fields[name]
+			result = append(result, shared.Parameter{
+				Name:        name,
+				Type:        field.Type,
+				Required:    field.Required,
+				Description: "in: body",
+			})
+		}
+		return result
+	}
+	return nil
+}
+
+func sortedSchemaKeys(schemas map[string]interface{}) []string {
+	keys := make([]string, 0, len(schemas))
+	for k := range schemas {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func (b *GraphBuilder) mapResponses(codes []int) []shared.Response {
 	var result []shared.Response
 	for _, c := range codes {
@@ -63,6 +131,24 @@ func (b *GraphBuilder) mapResponses(codes []int) []shared.Response {
 	return result
 }
 
+func (b *GraphBuilder) mapResponseBodies(responses []ParsedResponse) []shared.Response {
+	var result []shared.Response
+	for _, r := range responses {
+		code, _ := strconv.Atoi(r.Status) // 0 for "2XX" and "default"
+		description := r.Description
+		if description == "" {
+			description = "Derived from spec"
+		}
+		result = append(result, shared.Response{
+			StatusCode:  code,
+			Status:      r.Status,
+			Description: description,
+			Schemas:     r.Schemas,
+		})
+	}
+	return result
+}
+
 // SaveGraph persists the graph to .falcon/spec.yaml (human-readable YAML).
 func (b *GraphBuilder) SaveGraph(graph *shared.APIKnowledgeGraph) error {
 	data, err := yaml.Marshal(graph)
@@ -0,0 +1,799 @@
+package spec_ingester
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// GraphQLParser implements the SpecParser for GraphQL schemas, given as SDL
+// or as an introspection query result. Every query and mutation field
+// becomes an endpoint "POST /graphql#<field>" whose example body is a
+// ready-to-send {"query", "variables"} document; the fragment keeps the
+// endpoints apart in the graph and is dropped when the request is sent.
+type GraphQLParser struct {
+	// Path is the GraphQL endpoint path; "/graphql" when empty
+	Path string
+}
+
+var graphqlSDL = regexp.MustCompile(`(?m)^\s*(?:extend\s+)?type\s+(?:Query|Mutation)\b|^\s*schema\s*(?:@\w+\s*)*\{`)
+
+// gqlSchema is the part of a GraphQL schema the parser needs, built from
+// either SDL or introspection. Type references are kept in SDL notation,
+// e.g. "[User!]!".
+type gqlSchema struct {
+	queryType    string
+	mutationType string
+	types        map[string]*gqlType
+	order        []string
+}
+
+type gqlType struct {
+	kind        string // OBJECT, INPUT_OBJECT, INTERFACE, UNION, ENUM, SCALAR
+	name        string
+	description string
+	fields      []gqlField
+	enumValues  []string
+}
+
+type gqlField struct {
+	name        string
+	description string
+	typ         string
+	args        []gqlField
+	hasDefault  bool
+}
+
+func (p *GraphQLParser) DetectFormat(content []byte) bool {
+	return strings.Contains(string(content), `"__schema"`) || graphqlSDL.Match(content)
+}
+
+func (p *GraphQLParser) Parse(content []byte) (*ParsedSpec, error) {
+	var schema *gqlSchema
+	var err error
+	if trimmed := strings.TrimSpace(string(content)); strings.HasPrefix(trimmed, "{") && strings.Contains(trimmed, `"__schema"`) {
+		schema, err = parseIntrospection(content)
+	} else {
+		schema, err = parseSDL(string(content))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	path := p.Path
+	if path == "" {
+		path = "/graphql"
+	}
+	spec := &ParsedSpec{Format: "graphql"}
+
+	for _, op := range []struct{ kind, typeName string }{
+		{"query", schema.queryType},
+		{"mutation", schema.mutationType},
+	} {
+		root := schema.types[op.typeName]
+		if root == nil {
+			continue
+		}
+		for _, field := range root.fields {
+			p.addOperation(spec, schema, path, op.kind, field)
+		}
+	}
+	if len(spec.Endpoints) == 0 {
+		return nil, fmt.Errorf("GraphQL schema has no query or mutation fields")
+	}
+
+	for _, name := range schema.order {
+		t := schema.types[name]
+		if (t.kind != "OBJECT" && t.kind != "INPUT_OBJECT") || name == schema.queryType || name == schema.mutationType || strings.HasPrefix(name, "__") {
+			continue
+		}
+		spec.Models = append(spec.Models, gqlModel(schema, t))
+	}
+	resolve := func(ref string) map[string]interface{} {
+		for _, m := range spec.Models {
+			if m.Name == refName(ref) {
+				return m.Raw
+			}
+		}
+		return nil
+	}
+	for i := range spec.Models {
+		spec.Models[i].Schema = inlineRefs(spec.Models[i].Raw, resolve)
+	}
+
+	return spec, nil
+}
+
+// addOperation records one query or mutation field as an endpoint and a
+// saved request.
+func (p *GraphQLParser) addOperation(spec *ParsedSpec, schema *gqlSchema, path, kind string, field gqlField) {
+	query := gqlOperationText(schema, kind, field)
+	variables := make(map[string]interface{})
+	varProps := make(map[string]interface{})
+	var required []interface{}
+
+	endpoint := ParsedEndpoint{
+		Method:      "POST",
+		Path:        path + "#" + field.name,
+		Summary:     kind + " " + field.name,
+		Description: field.description,
+		HasBody:     true,
+		Responses:   []int{200},
+	}
+	for _, arg := range field.args {
+		isRequired := strings.HasSuffix(arg.typ, "!") && !arg.hasDefault
+		endpoint.Parameters = append(endpoint.Parameters, ParsedParameter{
+			Name:     arg.name,
+			In:       "variable",
+			Required: isRequired,
+			Type:     gqlJSONType(schema, arg.typ),
+		})
+		varProps[arg.name] = gqlJSONSchema(schema, arg.typ)
+		if isRequired {
+			required = append(required, arg.name)
+			variables[arg.name] = gqlSample(schema, arg.typ, 0)
+		}
+	}
+
+	varSchema := map[string]interface{}{"type": "object", "properties": varProps}
+	if len(required) > 0 {
+		varSchema["required"] = required
+	}
+	body := map[string]interface{}{"query": query, "variables": variables}
+	endpoint.RequestSchemas = map[string]interface{}{"application/json": map[string]interface{}{
+		"type":     "object",
+		"required": []interface{}{"query"},
+		"properties": map[string]interface{}{
+			"query":     map[string]interface{}{"type": "string"},
+			"variables": varSchema,
+		},
+	}}
+	endpoint.RequestExamples = map[string]interface{}{"application/json": body}
+	spec.Endpoints = append(spec.Endpoints, endpoint)
+
+	spec.Requests = append(spec.Requests, ParsedRequest{
+		Name:    kind + "-" + kebabCase(field.name),
+		Method:  "POST",
+		URL:     "{{BASE_URL}}" + path,
+		Headers: map[string]string{"Content-Type": "application/json"},
+		Body:    body,
+		Tags:    []string{"graphql"},
+	})
+}
+
+// gqlOperationText writes a one-line operation document for a root field,
+// declaring every argument as a variable and selecting the scalar fields of
+// the result (and of its object fields, one level down).
+func gqlOperationText(schema *gqlSchema, kind string, field gqlField) string {
+	var sb strings.Builder
+	name := field.name
+	sb.WriteString(kind + " " + strings.ToUpper(name[:1]) + name[1:])
+	if len(field.args) > 0 {
+		decls := make([]string, len(field.args))
+		uses := make([]string, len(field.args))
+		for i, arg := range field.args {
+			decls[i] = "$" + arg.name + ": " + arg.typ
+			uses[i] = arg.name + ": $" + arg.name
+		}
+		sb.WriteString("(" + strings.Join(decls, ", ") + ")")
+		sb.WriteString(" { " + field.name + "(" + strings.Join(uses, ", ") + ")")
+	} else {
+		sb.WriteString(" { " + field.name)
+	}
+	if selection := gqlSelection(schema, field.typ, 2); selection != "" {
+		sb.WriteString(" " + selection)
+	}
+	sb.WriteString(" }")
+	return sb.String()
+}
+
+// gqlSelection returns the selection set for a type, or "" for scalars and
+// enums. Fields that need arguments are left out.
+func gqlSelection(schema *gqlSchema, typ string, depth int) string {
+	t := schema.types[gqlNamedType(typ)]
+	if t == nil || t.kind == "SCALAR" || t.kind == "ENUM" {
+		return ""
+	}
+	if t.kind == "UNION" {
+		return "{ __typename }"
+	}
+	var parts []string
+	for _, f := range t.fields {
+		if gqlNeedsArgs(f) {
+			continue
+		}
+		ft := schema.types[gqlNamedType(f.typ)]
+		if ft == nil || ft.kind == "SCALAR" || ft.kind == "ENUM" {
+			parts = append(parts, f.name)
+			continue
+		}
+		if depth > 1 {
+			if sub := gqlSelection(schema, f.typ, depth-1); sub != "" && sub != "{ __typename }" {
+				parts = append(parts, f.name+" "+sub)
+			}
+		}
+	}
+	if len(parts) == 0 {
+		return "{ __typename }"
+	}
+	return "{ " + strings.Join(parts, " ") + " }"
+}
+
+func gqlNeedsArgs(f gqlField) bool {
+	for _, arg := range f.args {
+		if strings.HasSuffix(arg.typ, "!") && !arg.hasDefault {
+			return true
+		}
+	}
+	return false
+}
+
+// gqlNamedType strips list and non-null wrappers: "[User!]!" -> "User".
+func gqlNamedType(typ string) string {
+	return strings.Trim(typ, "[]!")
+}
+
+// gqlSample builds a placeholder value for a variable of the given type.
+func gqlSample(schema *gqlSchema, typ string, depth int) interface{} {
+	inner := strings.TrimSuffix(typ, "!")
+	if strings.HasPrefix(inner, "[") {
+		return []interface{}{gqlSample(schema, strings.TrimSuffix(strings.TrimPrefix(inner, "["), "]"), depth)}
+	}
+	switch inner {
+	case "Int":
+		return 1
+	case "Float":
+		return 1.5
+	case "Boolean":
+		return true
+	case "ID":
+		return "1"
+	case "String":
+		return "string"
+	}
+	t := schema.types[inner]
+	if t == nil {
+		return "string"
+	}
+	switch t.kind {
+	case "ENUM":
+		if len(t.enumValues) > 0 {
+			return t.enumValues[0]
+		}
+	case "INPUT_OBJECT":
+		obj := make(map[string]interface{})
+		if depth < 3 {
+			for _, f := range t.fields {
+				if strings.HasSuffix(f.typ, "!") && !f.hasDefault {
+					obj[f.name] = gqlSample(schema, f.typ, depth+1)
+				}
+			}
+		}
+		return obj
+	}
+	return "string"
+}
+
+// gqlJSONType maps a GraphQL type to the JSON type of its values.
+func gqlJSONType(schema *gqlSchema, typ string) string {
+	if strings.HasPrefix(strings.TrimSuffix(typ, "!"), "[") {
+		return "array"
+	}
+	switch gqlNamedType(typ) {
+	case "Int":
+		return "integer"
+	case "Float":
+		return "number"
+	case "Boolean":
+		return "boolean"
+	case "String", "ID":
+		return "string"
+	}
+	if t := schema.types[gqlNamedType(typ)]; t != nil && (t.kind == "OBJECT" || t.kind == "INPUT_OBJECT" || t.kind == "INTERFACE") {
+		return "object"
+	}
+	return "string"
+}
+
+// gqlJSONSchema describes a GraphQL type as JSON Schema. Object types are
+// $refs to their models; nullable scalars allow null.
+func gqlJSONSchema(schema *gqlSchema, typ string) map[string]interface{} {
+	nonNull := strings.HasSuffix(typ, "!")
+	inner := strings.TrimSuffix(typ, "!")
+	var result map[string]interface{}
+	if strings.HasPrefix(inner, "[") {
+		result = map[string]interface{}{
+			"type":  "array",
+			"items": gqlJSONSchema(schema, strings.TrimSuffix(strings.TrimPrefix(inner, "["), "]")),
+		}
+	} else if t := schema.types[inner]; t != nil && (t.kind == "OBJECT" || t.kind == "INPUT_OBJECT") {
+		return map[string]interface{}{"$ref": "#/definitions/" + inner}
+	} else {
+		result = map[string]interface{}{"type": gqlJSONType(schema, inner)}
+		if t != nil && t.kind == "ENUM" && len(t.enumValues) > 0 {
+			enum := make([]interface{}, len(t.enumValues))
+			for i, v := range t.enumValues {
+				enum[i] = v
+			}
+			result["enum"] = enum
+		}
+		if inner == "ID" {
+			result["type"] = []interface{}{"string", "integer"}
+		}
+	}
+	if !nonNull {
+		switch tv := result["type"].(type) {
+		case string:
+			result["type"] = []interface{}{tv, "null"}
+		case []interface{}:
+			result["type"] = append(tv, "null")
+		}
+		if enum, ok := result["enum"].([]interface{}); ok {
+			result["enum"] = append(enum, nil)
+		}
+	}
+	return result
+}
+
+// gqlModel describes an object or input type as a model. Non-null fields
+// are required.
+func gqlModel(schema *gqlSchema, t *gqlType) ParsedModel {
+	props := make(map[string]interface{}, len(t.fields))
+	var required []interface{}
+	for _, f := range t.fields {
+		prop := gqlJSONSchema(schema, f.typ)
+		if f.description != "" {
+			prop["description"] = f.description
+		}
+		props[f.name] = prop
+		if strings.HasSuffix(f.typ, "!") {
+			required = append(required, f.name)
+		}
+	}
+	raw := map[string]interface{}{"type": "object", "properties": props}
+	if len(required) > 0 {
+		raw["required"] = required
+	}
+	return ParsedModel{Name: t.name, Description: t.description, Raw: raw}
+}
+
+// kebabCase turns "createUser" into "create-user".
+func kebabCase(s string) string {
+	var sb strings.Builder
+	for i, r := range s {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				sb.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		} else if r == '_' {
+			r = '-'
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// --- introspection ---
+
+type gqlIntrospectionTypeRef struct {
+	Kind   string                   `json:"kind"`
+	Name   string                   `json:"name"`
+	OfType *gqlIntrospectionTypeRef `json:"ofType"`
+}
+
+type gqlIntrospectionField struct {
+	Name         string                  `json:"name"`
+	Description  string                  `json:"description"`
+	Type         gqlIntrospectionTypeRef `json:"type"`
+	Args         []gqlIntrospectionField `json:"args"`
+	DefaultValue *string                 `json:"defaultValue"`
+}
+
+type gqlIntrospectionSchema struct {
+	QueryType    *struct{ Name string } `json:"queryType"`
+	MutationType *struct{ Name string } `json:"mutationType"`
+	Types        []struct {
+		Kind        string                  `json:"kind"`
+		Name        string                  `json:"name"`
+		Description string                  `json:"description"`
+		Fields      []gqlIntrospectionField `json:"fields"`
+		InputFields []gqlIntrospectionField `json:"inputFields"`
+		EnumValues  []struct {
+			Name string `json:"name"`
+		} `json:"enumValues"`
+	} `json:"types"`
+}
+
+func parseIntrospection(content []byte) (*gqlSchema, error) {
+	var doc struct {
+		Data struct {
+			Schema *gqlIntrospectionSchema `json:"__schema"`
+		} `json:"data"`
+		Schema *gqlIntrospectionSchema `json:"__schema"`
+	}
+	if err := json.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse GraphQL introspection result: %w", err)
+	}
+	in := doc.Schema
+	if in == nil {
+		in = doc.Data.Schema
+	}
+	if in == nil {
+		return nil, fmt.Errorf("introspection result has no __schema")
+	}
+
+	schema := &gqlSchema{queryType: "Query", types: make(map[string]*gqlType)}
+	if in.QueryType != nil {
+		schema.queryType = in.QueryType.Name
+	}
+	if in.MutationType != nil {
+		schema.mutationType = in.MutationType.Name
+	}
+	convert := func(fields []gqlIntrospectionField) []gqlField {
+		out := make([]gqlField, 0, len(fields))
+		for _, f := range fields {
+			field := gqlField{name: f.Name, description: f.Description, typ: f.Type.sdl(), hasDefault: f.DefaultValue != nil}
+			for _, arg := range f.Args {
+				field.args = append(field.args, gqlField{name: arg.Name, description: arg.Description, typ: arg.Type.sdl(), hasDefault: arg.DefaultValue != nil})
+			}
+			out = append(out, field)
+		}
+		return out
+	}
+	for _, t := range in.Types {
+		gt := &gqlType{kind: t.Kind, name: t.Name, description: t.Description}
+		gt.fields = append(convert(t.Fields), convert(t.InputFields)...)
+		for _, v := range t.EnumValues {
+			gt.enumValues = append(gt.enumValues, v.Name)
+		}
+		schema.types[t.Name] = gt
+		schema.order = append(schema.order, t.Name)
+	}
+	return schema, nil
+}
+
+func (ref gqlIntrospectionTypeRef) sdl() string {
+	switch ref.Kind {
+	case "NON_NULL":
+		if ref.OfType != nil {
+			return ref.OfType.sdl() + "!"
+		}
+	case "LIST":
+		if ref.OfType != nil {
+			return "[" + ref.OfType.sdl() + "]"
+		}
+	}
+	return ref.Name
+}
+
+// --- SDL ---
+
+type sdlToken struct {
+	kind  byte // 'n' name, 's' string, 'v' number, or the punctuator itself
+	value string
+}
+
+type sdlParser struct {
+	tokens []sdlToken
+	pos    int
+}
+
+func parseSDL(source string) (*gqlSchema, error) {
+	tokens, err := lexSDL(source)
+	if err != nil {
+		return nil, err
+	}
+	p := &sdlParser{tokens: tokens}
+	schema := &gqlSchema{types: make(map[string]*gqlType)}
+
+	for !p.done() {
+		description := ""
+		if p.peek().kind == 's' {
+			description = p.next().value
+		}
+		keyword := p.next()
+		if keyword.kind != 'n' {
+			return nil, fmt.Errorf("unexpected %q in GraphQL schema", keyword.value)
+		}
+		extend := keyword.value == "extend"
+		if extend {
+			keyword = p.next()
+		}
+
+		switch keyword.value {
+		case "schema":
+			p.skipDirectives()
+			if p.accept('{') {
+				for !p.done() && !p.accept('}') {
+					op := p.next().value
+					p.accept(':')
+					name := p.next().value
+					switch op {
+					case "query":
+						schema.queryType = name
+					case "mutation":
+						schema.mutationType = name
+					}
+				}
+			}
+		case "type", "interface", "input":
+			kind := map[string]string{"type": "OBJECT", "interface": "INTERFACE", "input": "INPUT_OBJECT"}[keyword.value]
+			t := schema.define(p.next().value, kind, description)
+			if p.peek().value == "implements" {
+				p.next()
+				p.accept('&')
+				for p.peek().kind == 'n' {
+					p.next()
+					if !p.accept('&') {
+						break
+					}
+				}
+			}
+			p.skipDirectives()
+			if p.accept('{') {
+				for !p.done() && !p.accept('}') {
+					field, err := p.field()
+					if err != nil {
+						return nil, err
+					}
+					t.fields = append(t.fields, field)
+				}
+			}
+		case "enum":
+			t := schema.define(p.next().value, "ENUM", description)
+			p.skipDirectives()
+			if p.accept('{') {
+				for !p.done() && !p.accept('}') {
+					if p.peek().kind == 's' {
+						p.next()
+					}
+					t.enumValues = append(t.enumValues, p.next().value)
+					p.skipDirectives()
+				}
+			}
+		case "scalar":
+			schema.define(p.next().value, "SCALAR", description)
+			p.skipDirectives()
+		case "union":
+			schema.define(p.next().value, "UNION", description)
+			p.skipDirectives()
+			if p.accept('=') {
+				p.accept('|')
+				for p.peek().kind == 'n' {
+					p.next()
+					if !p.accept('|') {
+						break
+					}
+				}
+			}
+		case "directive":
+			p.accept('@')
+			p.next()
+			if p.peek().kind == '(' {
+				p.skipBalanced('(', ')')
+			}
+			if p.peek().value == "repeatable" {
+				p.next()
+			}
+			if p.peek().value == "on" {
+				p.next()
+				p.accept('|')
+				for p.peek().kind == 'n' {
+					p.next()
+					if !p.accept('|') {
+						break
+					}
+				}
+			}
+		case "query", "mutation", "subscription", "fragment":
+			return nil, fmt.Errorf("document contains GraphQL operations, not a schema")
+		default:
+			return nil, fmt.Errorf("unexpected %q in GraphQL schema", keyword.value)
+		}
+	}
+
+	if schema.queryType == "" {
+		schema.queryType = "Query"
+	}
+	if schema.mutationType == "" {
+		schema.mutationType = "Mutation"
+	}
+	return schema, nil
+}
+
+// define returns the named type, creating it on first use so "extend type"
+// adds to the original definition.
+func (s *gqlSchema) define(name, kind, description string) *gqlType {
+	if t, ok := s.types[name]; ok {
+		if t.description == "" {
+			t.description = description
+		}
+		return t
+	}
+	t := &gqlType{kind: kind, name: name, description: description}
+	s.types[name] = t
+	s.order = append(s.order, name)
+	return t
+}
+
+// field parses a field or input value: description? name (args)? : Type (= default)? directives
+func (p *sdlParser) field() (gqlField, error) {
+	var f gqlField
+	if p.peek().kind == 's' {
+		f.description = p.next().value
+	}
+	name := p.next()
+	if name.kind != 'n' {
+		return f, fmt.Errorf("expected a field name, got %q", name.value)
+	}
+	f.name = name.value
+	if p.accept('(') {
+		for !p.done() && !p.accept(')') {
+			arg, err := p.field()
+			if err != nil {
+				return f, err
+			}
+			f.args = append(f.args, arg)
+		}
+	}
+	if !p.accept(':') {
+		return f, fmt.Errorf("expected ':' after field %q", f.name)
+	}
+	f.typ = p.typeRef()
+	if p.accept('=') {
+		f.hasDefault = true
+		p.skipValue()
+	}
+	p.skipDirectives()
+	return f, nil
+}
+
+func (p *sdlParser) typeRef() string {
+	var typ string
+	if p.accept('[') {
+		typ = "[" + p.typeRef() + "]"
+		p.accept(']')
+	} else {
+		typ = p.next().value
+	}
+	if p.accept('!') {
+		typ += "!"
+	}
+	return typ
+}
+
+func (p *sdlParser) skipDirectives() {
+	for p.peek().kind == '@' {
+		p.next()
+		p.next()
+		if p.peek().kind == '(' {
+			p.skipBalanced('(', ')')
+		}
+	}
+}
+
+func (p *sdlParser) skipValue() {
+	switch p.peek().kind {
+	case '[':
+		p.skipBalanced('[', ']')
+	case '{':
+		p.skipBalanced('{', '}')
+	case '$':
+		p.next()
+		p.next()
+	default:
+		p.next()
+	}
+}
+
+func (p *sdlParser) skipBalanced(open, close byte) {
+	depth := 0
+	for !p.done() {
+		t := p.next()
+		if t.kind == open {
+			depth++
+		} else if t.kind == close {
+			depth--
+			if depth == 0 {
+				return
+			}
+		}
+	}
+}
+
+func (p *sdlParser) done() bool { return p.pos >= len(p.tokens) }
+
+func (p *sdlParser) peek() sdlToken {
+	if p.done() {
+		return sdlToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *sdlParser) next() sdlToken {
+	t := p.peek()
+	if !p.done() {
+		p.pos++
+	}
+	return t
+}
+
+func (p *sdlParser) accept(kind byte) bool {
+	if p.peek().kind == kind {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// lexSDL splits GraphQL SDL into names, strings, numbers and punctuators,
+// dropping whitespace, commas and comments.
+func lexSDL(src string) ([]sdlToken, error) {
+	var tokens []sdlToken
+	src = strings.TrimPrefix(src, "\ufeff")
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(src) && src[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(src[i:], `"""`):
+			end := strings.Index(src[i+3:], `"""`)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated block string in GraphQL schema")
+			}
+			tokens = append(tokens, sdlToken{kind: 's', value: strings.TrimSpace(src[i+3 : i+3+end])})
+			i += end + 6
+		case c == '"':
+			j := i + 1
+			for j < len(src) && src[j] != '"' && src[j] != '\n' {
+				if src[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(src) || src[j] != '"' {
+				return nil, fmt.Errorf("unterminated string in GraphQL schema")
+			}
+			tokens = append(tokens, sdlToken{kind: 's', value: src[i+1 : j]})
+			i = j + 1
+		case isNameStart(c):
+			j := i
+			for j < len(src) && (isNameStart(src[j]) || isDigit(src[j])) {
+				j++
+			}
+			tokens = append(tokens, sdlToken{kind: 'n', value: src[i:j]})
+			i = j
+		case c == '-' || isDigit(c):
+			j := i + 1
+			for j < len(src) && strings.ContainsRune("0123456789.eE+-", rune(src[j])) {
+				j++
+			}
+			tokens = append(tokens, sdlToken{kind: 'v', value: src[i:j]})
+			i = j
+		case strings.HasPrefix(src[i:], "..."):
+			tokens = append(tokens, sdlToken{kind: '.', value: "..."})
+			i += 3
+		case strings.ContainsRune("!$()[]{}:=@|&", rune(c)):
+			tokens = append(tokens, sdlToken{kind: c, value: string(c)})
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q in GraphQL schema", c)
+		}
+	}
+	return tokens, nil
+}
+
+func isNameStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
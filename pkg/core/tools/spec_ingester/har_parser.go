@@ -0,0 +1,133 @@
+package spec_ingester
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// HARParser implements the SpecParser for HTTP Archive (HAR) captures
+// exported from browser dev tools or proxies.
+type HARParser struct{}
+
+type harFile struct {
+	Log struct {
+		Version string     `json:"version"`
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	Request struct {
+		Method      string         `json:"method"`
+		URL         string         `json:"url"`
+		Headers     []harNameValue `json:"headers"`
+		QueryString []harNameValue `json:"queryString"`
+		PostData    *struct {
+			MimeType string         `json:"mimeType"`
+			Text     string         `json:"text"`
+			Params   []harNameValue `json:"params"`
+		} `json:"postData"`
+	} `json:"request"`
+	Response struct {
+		Status  int `json:"status"`
+		Content struct {
+			MimeType string `json:"mimeType"`
+		} `json:"content"`
+	} `json:"response"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// staticExtensions are page assets rather than API calls
+var staticExtensions = map[string]bool{
+	".js": true, ".mjs": true, ".css": true, ".map": true, ".html": true, ".htm": true,
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".ico": true, ".webp": true,
+	".woff": true, ".woff2": true, ".ttf": true, ".otf": true, ".eot": true,
+}
+
+func (p *HARParser) DetectFormat(content []byte) bool {
+	var har harFile
+	if err := json.Unmarshal(content, &har); err != nil {
+		return false
+	}
+	return har.Log.Version != "" || len(har.Log.Entries) > 0
+}
+
+func (p *HARParser) Parse(content []byte) (*ParsedSpec, error) {
+	var har harFile
+	if err := json.Unmarshal(content, &har); err != nil {
+		return nil, fmt.Errorf("failed to parse HAR file: %w", err)
+	}
+
+	spec := &ParsedSpec{
+		Format:  "har",
+		Version: har.Log.Version,
+	}
+	for _, entry := range har.Log.Entries {
+		if !isAPIEntry(entry) {
+			continue
+		}
+		req := ParsedRequest{
+			Method:  entry.Request.Method,
+			URL:     entry.Request.URL,
+			Headers: make(map[string]string),
+			Status:  entry.Response.Status,
+			Tags:    []string{"har"},
+		}
+		for _, h := range entry.Request.Headers {
+			if !isNoiseHeader(h.Name) {
+				req.Headers[h.Name] = h.Value
+			}
+		}
+		if len(entry.Request.QueryString) > 0 {
+			req.Query = make(map[string]string)
+			for _, q := range entry.Request.QueryString {
+				req.Query[q.Name] = q.Value
+			}
+		}
+		if data := entry.Request.PostData; data != nil {
+			if data.Text == "" && len(data.Params) > 0 {
+				fields := make([][2]string, 0, len(data.Params))
+				for _, param := range data.Params {
+					fields = append(fields, [2]string{param.Name, param.Value})
+				}
+				req.Body = encodeForm(fields)
+			} else {
+				req.Body = decodeBody(data.Text, data.MimeType)
+			}
+			if data.MimeType != "" && headerValue(req.Headers, "Content-Type") == "" {
+				req.Headers["Content-Type"] = data.MimeType
+			}
+		}
+		addRequest(spec, req)
+	}
+
+	return spec, nil
+}
+
+// isAPIEntry drops page loads, static assets and CORS preflights from a
+// browser capture.
+func isAPIEntry(entry harEntry) bool {
+	if strings.EqualFold(entry.Request.Method, "OPTIONS") {
+		return false
+	}
+	if !strings.HasPrefix(entry.Request.URL, "http://") && !strings.HasPrefix(entry.Request.URL, "https://") {
+		return false
+	}
+	_, urlPath, _ := splitRequestURL(entry.Request.URL)
+	if staticExtensions[strings.ToLower(path.Ext(urlPath))] {
+		return false
+	}
+	mime := strings.ToLower(entry.Response.Content.MimeType)
+	for _, prefix := range []string{"text/html", "text/css", "image/", "font/", "application/javascript", "text/javascript"} {
+		if strings.HasPrefix(mime, prefix) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,159 @@
+package spec_ingester
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// InsomniaParser implements the SpecParser for Insomnia exports: the v4 JSON
+// export ("__export_format": 4) and the v5 YAML collection format.
+type InsomniaParser struct{}
+
+// insomniaExport covers both layouts; YAML decoding also reads the JSON export
+type insomniaExport struct {
+	Type         string `yaml:"type"`
+	ExportFormat int    `yaml:"__export_format"`
+
+	// v4: a flat list of resources linked by parentId
+	Resources []insomniaItem `yaml:"resources"`
+
+	// v5: nested folders and requests
+	Collection []insomniaItem `yaml:"collection"`
+}
+
+type insomniaItem struct {
+	ResourceType   string         `yaml:"_type"` // v4: "request", "request_group", "environment", ...
+	Name           string         `yaml:"name"`
+	Method         string         `yaml:"method"`
+	URL            string         `yaml:"url"`
+	Headers        []insomniaPair `yaml:"headers"`
+	Parameters     []insomniaPair `yaml:"parameters"`
+	Body           insomniaBody   `yaml:"body"`
+	Authentication insomniaAuth   `yaml:"authentication"`
+	Children       []insomniaItem `yaml:"children"` // v5 folders
+}
+
+type insomniaPair struct {
+	Name     string `yaml:"name"`
+	Value    string `yaml:"value"`
+	Disabled bool   `yaml:"disabled"`
+}
+
+type insomniaBody struct {
+	MimeType string         `yaml:"mimeType"`
+	Text     string         `yaml:"text"`
+	Params   []insomniaPair `yaml:"params"`
+}
+
+type insomniaAuth struct {
+	Type     string `yaml:"type"`
+	Disabled bool   `yaml:"disabled"`
+	Token    string `yaml:"token"`
+	Prefix   string `yaml:"prefix"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	Key      string `yaml:"key"`
+	Value    string `yaml:"value"`
+	AddTo    string `yaml:"addTo"`
+}
+
+func (p *InsomniaParser) DetectFormat(content []byte) bool {
+	return bytes.Contains(content, []byte(`"__export_format"`)) ||
+		bytes.Contains(content, []byte("collection.insomnia.rest/"))
+}
+
+func (p *InsomniaParser) Parse(content []byte) (*ParsedSpec, error) {
+	var export insomniaExport
+	if err := yaml.Unmarshal(content, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse Insomnia export: %w", err)
+	}
+
+	spec := &ParsedSpec{Format: "insomnia"}
+	if export.ExportFormat > 0 {
+		spec.Version = fmt.Sprintf("%d", export.ExportFormat)
+		for _, item := range export.Resources {
+			if item.ResourceType == "request" {
+				addRequest(spec, item.toRequest())
+			}
+		}
+	} else {
+		spec.Version = strings.TrimPrefix(export.Type, "collection.insomnia.rest/")
+		p.processItems(export.Collection, spec)
+	}
+
+	return spec, nil
+}
+
+func (p *InsomniaParser) processItems(items []insomniaItem, spec *ParsedSpec) {
+	for _, item := range items {
+		if len(item.Children) > 0 {
+			p.processItems(item.Children, spec)
+			continue
+		}
+		if item.URL != "" {
+			addRequest(spec, item.toRequest())
+		}
+	}
+}
+
+func (item insomniaItem) toRequest() ParsedRequest {
+	req := ParsedRequest{
+		Name:    item.Name,
+		Method:  item.Method,
+		URL:     normalizeTemplate(item.URL),
+		Headers: make(map[string]string),
+		Tags:    []string{"insomnia"},
+	}
+	for _, h := range item.Headers {
+		if !h.Disabled && h.Name != "" {
+			req.Headers[h.Name] = normalizeTemplate(h.Value)
+		}
+	}
+	for _, q := range item.Parameters {
+		if !q.Disabled && q.Name != "" {
+			if req.Query == nil {
+				req.Query = make(map[string]string)
+			}
+			req.Query[q.Name] = normalizeTemplate(q.Value)
+		}
+	}
+
+	switch {
+	case item.Body.Text != "":
+		req.Body = decodeBody(normalizeTemplate(item.Body.Text), item.Body.MimeType)
+	case len(item.Body.Params) > 0:
+		var fields [][2]string
+		for _, param := range item.Body.Params {
+			if !param.Disabled {
+				fields = append(fields, [2]string{param.Name, normalizeTemplate(param.Value)})
+			}
+		}
+		req.Body = encodeForm(fields)
+	}
+	if item.Body.MimeType != "" && headerValue(req.Headers, "Content-Type") == "" {
+		req.Headers["Content-Type"] = item.Body.MimeType
+	}
+
+	auth := item.Authentication
+	if !auth.Disabled {
+		if auth.Type == "apikey" && auth.Key != "" {
+			if auth.AddTo == "queryParams" {
+				if req.Query == nil {
+					req.Query = make(map[string]string)
+				}
+				req.Query[auth.Key] = normalizeTemplate(auth.Value)
+			} else {
+				req.Headers[auth.Key] = normalizeTemplate(auth.Value)
+			}
+		} else if header := authHeader(auth.Type, normalizeTemplate(auth.Token), auth.Username, auth.Password); header != "" {
+			if auth.Prefix != "" && strings.HasPrefix(header, "Bearer ") {
+				header = auth.Prefix + " " + strings.TrimPrefix(header, "Bearer ")
+			}
+			req.Headers["Authorization"] = header
+		}
+	}
+	return req
+}
@@ -2,11 +2,15 @@ package spec_ingester
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel"
 	validator "github.com/pb33f/libopenapi/datamodel/high/base"
 	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+	"gopkg.in/yaml.v3"
 )
 
 // OpenAPIParser implements the SpecParser for OpenAPI 3.x and Swagger 2.0
@@ -25,10 +29,13 @@ func (p *OpenAPIParser) Parse(content []byte) (*ParsedSpec, error) {
 		return nil, fmt.Errorf("failed to parse spec document: %w", err)
 	}
 
-	// We primarily target V3 for now
+	// Swagger 2.0 documents have their own model
+	if info := document.GetSpecInfo(); info != nil && info.SpecFormat == datamodel.OAS2 {
+		return p.parseSwagger2(document, operationSecurity(content))
+	}
+
 	model, err := document.BuildV3Model()
 	if err != nil {
-		// If V3 fails, could try V2, but for now let's just return the error
 		return nil, fmt.Errorf("failed to build OpenAPI v3 model: %w", err)
 	}
 
@@ -36,6 +43,25 @@ func (p *OpenAPIParser) Parse(content []byte) (*ParsedSpec, error) {
 		Format:  "openapi3",
 		Version: model.Model.Info.Version,
 	}
+	explicit := operationSecurity(content)
+	if len(model.Model.Servers) > 0 && model.Model.Servers[0] != nil {
+		spec.BaseURL = strings.TrimSuffix(model.Model.Servers[0].URL, "/")
+	}
+
+	// Component schemas, kept as written so $refs can be resolved (and
+	// cycles detected) when inlining operation schemas
+	components := make(map[string]map[string]interface{})
+	if model.Model.Components != nil && model.Model.Components.Schemas != nil {
+		for pair := model.Model.Components.Schemas.First(); pair != nil; pair = pair.Next() {
+			components[pair.Key()] = rawJSONSchema(pair.Value())
+		}
+	}
+	resolve := func(ref string) map[string]interface{} {
+		if !strings.HasPrefix(ref, "#/components/schemas/") {
+			return nil
+		}
+		return components[refName(ref)]
+	}
 
 	// Iterate Paths using ordered map iterator
 	for pair := model.Model.Paths.PathItems.First(); pair != nil; pair = pair.Next() {
@@ -73,21 +99,64 @@ func (p *OpenAPIParser) Parse(content []byte) (*ParsedSpec, error) {
 				})
 			}
 
-			// Extract Response Codes
-			for pair := op.Responses.Codes.First(); pair != nil; pair = pair.Next() {
-				status := pair.Key()
-				// val := pair.Value()
+			// Extract the request body contract
+			if op.RequestBody != nil {
+				endpoint.RequestSchemas = p.extractSchemas(op.RequestBody.Content, resolve)
+				endpoint.RequestExamples = p.extractExamples(op.RequestBody.Content)
+				endpoint.RequestModel = p.extractModelName(op.RequestBody.Content)
+			}
+
+			// Extract Response Codes and their body contracts
+			if op.Responses != nil {
+				for pair := op.Responses.Codes.First(); pair != nil; pair = pair.Next() {
+					status := pair.Key()
+					val := pair.Value()
 
-				var code int
-				if n, err := fmt.Sscanf(status, "%d", &code); err == nil && n == 1 {
-					endpoint.Responses = append(endpoint.Responses, code)
+					if code, err := strconv.Atoi(status); err == nil {
+						endpoint.Responses = append(endpoint.Responses, code)
+					}
+					endpoint.ResponseBodies = append(endpoint.ResponseBodies, p.extractResponse(strings.ToUpper(status), val, resolve))
+				}
+				if op.Responses.Default != nil {
+					endpoint.ResponseBodies = append(endpoint.ResponseBodies, p.extractResponse("default", op.Responses.Default, resolve))
 				}
 			}
 
+			// "security: []" on the operation opts out of the global requirement
+			security := op.Security
+			if security == nil && !explicit[method+" "+path] {
+				security = model.Model.Security
+			}
+			endpoint.AuthType = authTypeFor(security, func(name string) string {
+				if model.Model.Components == nil || model.Model.Components.SecuritySchemes == nil {
+					return name
+				}
+				scheme, ok := model.Model.Components.SecuritySchemes.Get(name)
+				if !ok || scheme == nil {
+					return name
+				}
+				return describeSecurityScheme(scheme.Type, scheme.Scheme, scheme.In, scheme.Name)
+			})
+
 			spec.Endpoints = append(spec.Endpoints, endpoint)
 		}
 	}
 
+	// Extract component models
+	if model.Model.Components != nil && model.Model.Components.Schemas != nil {
+		for pair := model.Model.Components.Schemas.First(); pair != nil; pair = pair.Next() {
+			parsed := ParsedModel{
+				Name:   pair.Key(),
+				Schema: inlineRefs(components[pair.Key()], resolve),
+				Raw:    components[pair.Key()],
+			}
+			if s := pair.Value().Schema(); s != nil {
+				parsed.Description = s.Description
+			}
+			spec.Models = append(spec.Models, parsed)
+		}
+	}
+
 	return spec, nil
 }
 
@@ -98,6 +167,85 @@ func (p *OpenAPIParser) resolveParameters(params []*v3.Parameter) []*v3.Paramete
 	return params
 }
 
+func (p *OpenAPIParser) extractResponse(status string, resp *v3.Response, resolve schemaResolver) ParsedResponse {
+	parsed := ParsedResponse{Status: status}
+	if resp != nil {
+		parsed.Description = resp.Description
+		parsed.Schemas = p.extractSchemas(resp.Content, resolve)
+	}
+	return parsed
+}
+
+// extractSchemas converts each media type's schema into a plain JSON Schema
+// document. Media types without a schema are kept with a nil schema so the
+// content type itself is still part of the contract.
+func (p *OpenAPIParser) extractSchemas(content *orderedmap.Map[string, *v3.MediaType], resolve schemaResolver) map[string]interface{} {
+	if content == nil || content.Len() == 0 {
+		return nil
+	}
+	schemas := make(map[string]interface{})
+	for pair := content.First(); pair != nil; pair = pair.Next() {
+		var schema map[string]interface{}
+		if media := pair.Value(); media != nil {
+			schema = schemaToJSONSchema(media.Schema, resolve)
+		}
+		schemas[pair.Key()] = schema
+	}
+	return schemas
+}
+
+// extractExamples picks one example payload per media type: the media type's
+// own example, else its first named example.
+func (p *OpenAPIParser) extractExamples(content *orderedmap.Map[string, *v3.MediaType]) map[string]interface{} {
+	if content == nil {
+		return nil
+	}
+	examples := make(map[string]interface{})
+	for pair := content.First(); pair != nil; pair = pair.Next() {
+		media := pair.Value()
+		if media == nil {
+			continue
+		}
+		node := media.Example
+		if node == nil && media.Examples != nil {
+			for ex := media.Examples.First(); ex != nil; ex = ex.Next() {
+				if ex.Value() != nil && ex.Value().Value != nil {
+					node = ex.Value().Value
+					break
+				}
+			}
+		}
+		if node == nil {
+			continue
+		}
+		var example interface{}
+		if err := node.Decode(&example); err == nil && example != nil {
+			examples[pair.Key()] = example
+		}
+	}
+	if len(examples) == 0 {
+		return nil
+	}
+	return examples
+}
+
+// extractModelName returns the component name a JSON body schema refers to.
+func (p *OpenAPIParser) extractModelName(content *orderedmap.Map[string, *v3.MediaType]) string {
+	if content == nil {
+		return ""
+	}
+	for pair := content.First(); pair != nil; pair = pair.Next() {
+		media := pair.Value()
+		if media == nil || media.Schema == nil || !strings.Contains(pair.Key(), "json") {
+			continue
+		}
+		if media.Schema.IsReference() {
+			return refName(media.Schema.GetReference())
+		}
+	}
+	return ""
+}
+
 func (p *OpenAPIParser) extractType(schema *validator.SchemaProxy) string {
 	if schema == nil || schema.Schema() == nil {
 		return "unknown"
@@ -108,3 +256,26 @@ func (p *OpenAPIParser) extractType(schema *validator.SchemaProxy) string {
 	}
 	return "object"
 }
+
+// operationSecurity reports which operations ("GET /pets") declare their own
+// security list. libopenapi leaves Security nil for both a missing and an
+// empty list, but "security: []" means the operation is public.
+func operationSecurity(content []byte) map[string]bool {
+	var doc struct {
+		Paths map[string]map[string]interface{} `yaml:"paths"`
+	}
+	explicit := make(map[string]bool)
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return explicit
+	}
+	for path, item := range doc.Paths {
+		for method, op := range item {
+			if fields, ok := op.(map[string]interface{}); ok {
+				if _, declared := fields["security"]; declared {
+					explicit[strings.ToUpper(method)+" "+path] = true
+				}
+			}
+		}
+	}
+	return explicit
+}
@@ -13,9 +13,12 @@ type SpecParser interface {
 
 // ParsedSpec is an intermediate representation of a parsed API spec
 type ParsedSpec struct {
-	Format    string // "openapi3", "swagger2", "postman2.1"
+	Format    string // "openapi3", "swagger2", "postman2.1", "har", "insomnia", "bruno", "curl", "graphql"
 	Version   string
+	BaseURL   string // first server URL (OpenAPI 3) or scheme://host/basePath (Swagger 2.0)
 	Endpoints []ParsedEndpoint
+	Models    []ParsedModel
+	Requests  []ParsedRequest // concrete requests to seed into .falcon/requests/
 }
 
 // ParsedEndpoint represents a single API operation found in the spec
@@ -28,6 +31,31 @@ type ParsedEndpoint struct {
 	// Simplified representation of request/response for initial indexing
 	HasBody   bool
 	Responses []int
+
+	// Full request and response contracts, as JSON Schema documents
+	RequestSchemas  map[string]interface{} // content type -> schema
+	RequestExamples map[string]interface{} // content type -> example payload
+	RequestModel    string                 // component model of the request body, if it is a $ref
+	ResponseBodies  []ParsedResponse
+
+	// AuthType summarises the security requirements, e.g. "bearer" or
+	// "apiKey (header: X-API-Key)"; empty when the operation is public
+	AuthType string
+}
+
+// ParsedModel is a named schema from the spec's components
+type ParsedModel struct {
+	Name        string
+	Description string
+	Schema      map[string]interface{} // fully inlined JSON Schema
+	Raw         map[string]interface{} // as written, with $refs to other models kept
+}
+
+// ParsedResponse is one documented response of an operation
+type ParsedResponse struct {
+	Status      string // "200", "2XX" or "default"
+	Description string
+	Schemas     map[string]interface{} // content type -> schema
 }
 
 type ParsedParameter struct {
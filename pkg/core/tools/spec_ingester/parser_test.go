@@ -1,8 +1,12 @@
 package spec_ingester
 
 import (
+	"fmt"
 	"os"
+	"strings"
 	"testing"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
 )
 
 // Minimal test to verify compilation and basic function references
@@ -29,3 +33,408 @@ func TestParserDetection(t *testing.T) {
 		t.Error("Postman parser failed to detect postman content")
 	}
 }
+
+const contractSpec = `openapi: 3.0.3
+info:
+  title: Users
+  version: "1.0"
+paths:
+  /users/{id}:
+    get:
+      parameters:
+        - name: id
+          in: path
+          required: true
+          schema:
+            type: integer
+      responses:
+        "200":
+          description: A user
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/User'
+        4XX:
+          description: Client error
+          content:
+            application/problem+json:
+              schema:
+                type: object
+                properties:
+                  title:
+                    type: string
+        default:
+          description: Unexpected error
+  /users:
+    post:
+      requestBody:
+        required: true
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/User'
+      responses:
+        "201":
+          description: Created
+components:
+  schemas:
+    User:
+      type: object
+      required: [id, email]
+      properties:
+        id:
+          type: integer
+        email:
+          type: string
+        nickname:
+          type: string
+          nullable: true
+`
+
+func TestOpenAPIParser_KeepsSchemas(t *testing.T) {
+	spec, err := (&OpenAPIParser{}).Parse([]byte(contractSpec))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var get, post *ParsedEndpoint
+	for i := range spec.Endpoints {
+		switch spec.Endpoints[i].Method {
+		case "GET":
+			get = &spec.Endpoints[i]
+		case "POST":
+			post = &spec.Endpoints[i]
+		}
+	}
+	if get == nil || post == nil {
+		t.Fatalf("expected GET and POST endpoints, got %+v", spec.Endpoints)
+	}
+
+	if len(get.Responses) != 1 || get.Responses[0] != 200 {
+		t.Errorf("numeric responses = %v, want [200]", get.Responses)
+	}
+	var statuses []string
+	for _, r := range get.ResponseBodies {
+		statuses = append(statuses, r.Status)
+	}
+	if strings.Join(statuses, ",") != "200,4XX,default" {
+		t.Errorf("response statuses = %v, want [200 4XX default]", statuses)
+	}
+
+	user, _ := get.ResponseBodies[0].Schemas["application/json"].(map[string]interface{})
+	props, _ := user["properties"].(map[string]interface{})
+	if props == nil || user["$ref"] != nil {
+		t.Fatalf("expected the User $ref to be inlined, got %v", user)
+	}
+	nickname, _ := props["nickname"].(map[string]interface{})
+	if types, _ := nickname["type"].([]interface{}); len(types) != 2 || types[1] != "null" {
+		t.Errorf("nullable nickname type = %v, want [string null]", nickname["type"])
+	}
+
+	if _, ok := post.RequestSchemas["application/json"].(map[string]interface{}); !ok {
+		t.Errorf("expected a JSON request schema, got %v", post.RequestSchemas)
+	}
+}
+
+func TestContractCheck_IngestedSpec(t *testing.T) {
+	spec, err := (&OpenAPIParser{}).Parse([]byte(contractSpec))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	builder := NewGraphBuilder(t.TempDir())
+	graph, err := builder.BuildGraph(spec, shared.ProjectContext{})
+	if err != nil {
+		t.Fatalf("BuildGraph failed: %v", err)
+	}
+	if err := builder.SaveGraph(graph); err != nil {
+		t.Fatalf("SaveGraph failed: %v", err)
+	}
+
+	checker := shared.NewContractChecker(builder.FalconDir)
+	jsonHeaders := map[string]string{"Content-Type": "application/json; charset=utf-8"}
+
+	cases := []struct {
+		name   string
+		url    string
+		resp   *shared.HTTPResponse
+		issues []string
+	}{
+		{"valid", "http://api.test/users/7", &shared.HTTPResponse{StatusCode: 200, Headers: jsonHeaders, Body: `{"id": 7, "email": "a@b.c", "nickname": null}`}, nil},
+		{"base path", "http://api.test/v1/users/7?x=1", &shared.HTTPResponse{StatusCode: 200, Headers: jsonHeaders, Body: `{"id": 7, "email": "a@b.c"}`}, nil},
+		{"missing and extra", "http://api.test/users/7", &shared.HTTPResponse{StatusCode: 200, Headers: jsonHeaders, Body: `{"id": "7", "role": "admin"}`}, []string{"missing_field:email", "schema_mismatch:id", "extra_field:role"}},
+		{"status range", "http://api.test/users/7", &shared.HTTPResponse{StatusCode: 404, Headers: map[string]string{"Content-Type": "application/problem+json"}, Body: `{"title": "not found"}`}, nil},
+		{"content type", "http://api.test/users/7", &shared.HTTPResponse{StatusCode: 200, Headers: map[string]string{"Content-Type": "text/html"}, Body: `<html>`}, []string{"undocumented_content_type:"}},
+		{"undocumented status", "http://api.test/users", &shared.HTTPResponse{StatusCode: 500}, []string{"undocumented_status:"}},
+		{"undocumented endpoint", "http://api.test/orders", &shared.HTTPResponse{StatusCode: 200}, []string{"undocumented_endpoint:"}},
+	}
+	for _, tc := range cases {
+		method := "GET"
+		if strings.HasSuffix(tc.url, "/users") {
+			method = "POST"
+		}
+		result, err := checker.Check(method, tc.url, tc.resp)
+		if err != nil {
+			t.Fatalf("%s: Check failed: %v", tc.name, err)
+		}
+		var got []string
+		for _, issue := range result.Issues {
+			got = append(got, issue.Kind+":"+issue.Field)
+		}
+		if strings.Join(got, ",") != strings.Join(tc.issues, ",") {
+			t.Errorf("%s: issues = %v, want %v\n%s", tc.name, got, tc.issues, result.Format())
+		}
+	}
+}
+
+const modelSpec = `openapi: 3.1.0
+info: {title: Pets, version: "1"}
+paths:
+  /pets:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/NewPet'}
+            examples:
+              rex: {value: {name: Rex, kind: dog}}
+      responses:
+        "201": {description: Created}
+components:
+  schemas:
+    Base:
+      type: object
+      required: [name]
+      properties:
+        name: {type: string, minLength: 2, maxLength: 20}
+    NewPet:
+      description: A pet to create
+      allOf:
+        - $ref: '#/components/schemas/Base'
+        - type: object
+          required: [kind]
+          properties:
+            kind: {type: string, enum: [dog, cat]}
+            age: {type: integer, minimum: 0, exclusiveMaximum: 30}
+            owner: {$ref: '#/components/schemas/Owner'}
+    Owner:
+      type: object
+      properties:
+        email: {type: string, format: email}
+        pets: {type: array, items: {$ref: '#/components/schemas/NewPet'}}
+`
+
+func TestBuildGraph_ModelsAndRequestBodies(t *testing.T) {
+	spec, err := (&OpenAPIParser{}).Parse([]byte(modelSpec))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	graph, err := NewGraphBuilder(t.TempDir()).BuildGraph(spec, shared.ProjectContext{})
+	if err != nil {
+		t.Fatalf("BuildGraph failed: %v", err)
+	}
+
+	pet, ok := graph.Models["NewPet"]
+	if !ok || pet.Description != "A pet to create" {
+		t.Fatalf("expected the NewPet model, got %+v", graph.Models)
+	}
+	if name := pet.Fields["name"]; !name.Required || name.MinLength == nil || *name.MinLength != 2 || name.MaxLength == nil || *name.MaxLength != 20 {
+		t.Errorf("name (from allOf Base) = %+v, want required with length 2..20", name)
+	}
+	if kind := pet.Fields["kind"]; !kind.Required || len(kind.Enum) != 2 {
+		t.Errorf("kind = %+v, want a required enum", kind)
+	}
+	if age := pet.Fields["age"]; age.Maximum == nil || *age.Maximum != 30 || !age.ExclusiveMaximum || age.Minimum == nil || *age.Minimum != 0 {
+		t.Errorf("age = %+v, want 0 <= age < 30", age)
+	}
+	if owner := pet.Fields["owner"]; owner.Ref != "Owner" || owner.Type != "object" {
+		t.Errorf("owner = %+v, want a reference to Owner", owner)
+	}
+	if pets := graph.Models["Owner"].Fields["pets"]; pets.Type != "array" || pets.Ref != "NewPet" {
+		t.Errorf("Owner.pets = %+v, want an array of NewPet", pets)
+	}
+	if email := graph.Models["Owner"].Fields["email"]; email.Format != "email" {
+		t.Errorf("Owner.email format = %q, want email", email.Format)
+	}
+
+	post := graph.Endpoints["POST /pets"]
+	if post.RequestModel != "NewPet" {
+		t.Errorf("request model = %q, want NewPet", post.RequestModel)
+	}
+	if example, _ := post.RequestExamples["application/json"].(map[string]interface{}); example["name"] != "Rex" {
+		t.Errorf("request example = %v, want the rex example", post.RequestExamples)
+	}
+	var bodyParams []string
+	for _, p := range post.Parameters {
+		if p.Description == "in: body" {
+			bodyParams = append(bodyParams, p.Name)
+		}
+	}
+	if strings.Join(bodyParams, ",") != "age,kind,name,owner" {
+		t.Errorf("body parameters = %v, want [age kind name owner]", bodyParams)
+	}
+
+	// The circular NewPet -> Owner -> pets -> NewPet reference is cut off, not looped
+	schema, _ := post.RequestSchemas["application/json"].(map[string]interface{})
+	if strings.Contains(fmt.Sprint(schema), "$ref") || !strings.Contains(fmt.Sprint(schema), "circular reference to") {
+		t.Errorf("expected an inlined schema with the cycle cut off, got %v", schema)
+	}
+}
+
+const swaggerSpec = `swagger: "2.0"
+info:
+  title: Legacy Store
+  version: 2.4.0
+host: store.example.com
+basePath: /api/v1/
+schemes: [http, https]
+consumes: [application/json]
+produces: [application/json]
+securityDefinitions:
+  apiKey:
+    type: apiKey
+    in: header
+    name: X-API-Key
+  basicAuth:
+    type: basic
+security:
+  - apiKey: []
+paths:
+  /orders/{id}:
+    parameters:
+      - name: id
+        in: path
+        required: true
+        type: integer
+    get:
+      parameters:
+        - name: expand
+          in: query
+          type: string
+          enum: [items, customer]
+      responses:
+        "200":
+          description: The order
+          schema:
+            $ref: "#/definitions/Order"
+        default:
+          description: Error
+          schema:
+            $ref: "#/definitions/Error"
+    put:
+      security:
+        - basicAuth: []
+      parameters:
+        - name: body
+          in: body
+          required: true
+          schema:
+            $ref: "#/definitions/Order"
+      responses:
+        "204":
+          description: Updated
+  /uploads:
+    post:
+      consumes: [multipart/form-data]
+      security: []
+      parameters:
+        - name: file
+          in: formData
+          type: file
+          required: true
+        - name: label
+          in: formData
+          type: string
+          maxLength: 40
+      responses:
+        "201":
+          description: Stored
+definitions:
+  Base:
+    type: object
+    required: [id]
+    properties:
+      id:
+        type: integer
+  Order:
+    description: A customer order
+    allOf:
+      - $ref: "#/definitions/Base"
+      - type: object
+        required: [total]
+        properties:
+          total:
+            type: number
+            minimum: 0
+          lines:
+            type: array
+            items:
+              $ref: "#/definitions/Line"
+  Line:
+    type: object
+    properties:
+      sku:
+        type: string
+  Error:
+    type: object
+    properties:
+      message:
+        type: string
+`
+
+func TestOpenAPIParser_Swagger2(t *testing.T) {
+	spec, err := (&OpenAPIParser{}).Parse([]byte(swaggerSpec))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if spec.Format != "swagger2" || spec.Version != "2.4.0" || spec.BaseURL != "https://store.example.com/api/v1" {
+		t.Errorf("format/version/base = %q/%q/%q", spec.Format, spec.Version, spec.BaseURL)
+	}
+
+	graph, err := NewGraphBuilder(t.TempDir()).BuildGraph(spec, shared.ProjectContext{})
+	if err != nil {
+		t.Fatalf("BuildGraph failed: %v", err)
+	}
+	if graph.BaseURL != "https://store.example.com/api/v1" {
+		t.Errorf("graph base URL = %q", graph.BaseURL)
+	}
+
+	order := graph.Models["Order"]
+	if order.Description != "A customer order" || !order.Fields["id"].Required || !order.Fields["total"].Required || order.Fields["lines"].Ref != "Line" {
+		t.Errorf("Order model = %+v", order)
+	}
+
+	get := graph.Endpoints["GET /orders/{id}"]
+	var params []string
+	for _, p := range get.Parameters {
+		params = append(params, p.Name+" "+p.Description)
+	}
+	if strings.Join(params, ",") != "id in: path,expand in: query" {
+		t.Errorf("GET parameters = %v, want the path-level id and the query expand", params)
+	}
+	if get.AuthType != "apiKey (header: X-API-Key)" {
+		t.Errorf("GET auth = %q, want the global api key", get.AuthType)
+	}
+	if len(get.Responses) != 2 || get.Responses[1].Status != "default" {
+		t.Fatalf("GET responses = %+v, want 200 and default", get.Responses)
+	}
+	schema, _ := get.Responses[0].Schemas["application/json"].(map[string]interface{})
+	if strings.Contains(fmt.Sprint(schema), "$ref") || !strings.Contains(fmt.Sprint(schema), "sku") {
+		t.Errorf("200 schema should be inlined from definitions, got %v", schema)
+	}
+
+	put := graph.Endpoints["PUT /orders/{id}"]
+	if put.RequestModel != "Order" || put.RequestSchemas["application/json"] == nil || put.AuthType != "basic" {
+		t.Errorf("PUT model/auth = %q/%q, schemas %v", put.RequestModel, put.AuthType, put.RequestSchemas)
+	}
+
+	upload := graph.Endpoints["POST /uploads"]
+	form, _ := upload.RequestSchemas["multipart/form-data"].(map[string]interface{})
+	props, _ := form["properties"].(map[string]interface{})
+	file, _ := props["file"].(map[string]interface{})
+	if file["format"] != "binary" || fmt.Sprint(form["required"]) != "[file]" {
+		t.Errorf("upload form schema = %v, want a required binary file", form)
+	}
+	if upload.AuthType != "" {
+		t.Errorf("upload auth = %q, want none (security: [])", upload.AuthType)
+	}
+}
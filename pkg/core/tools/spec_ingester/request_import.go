@@ -0,0 +1,375 @@
+package spec_ingester
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+)
+
+// ParsedRequest is a concrete request found in an import (a HAR entry, an
+// Insomnia or Bruno request, a curl command). Requests are seeded into
+// .falcon/requests/ alongside the endpoints they describe.
+type ParsedRequest struct {
+	Name    string
+	Method  string
+	URL     string // full URL, may start with a {{VAR}} placeholder
+	Headers map[string]string
+	Query   map[string]string
+	Body    interface{} // decoded JSON, or the raw text
+	Status  int         // captured response status, if known
+	Tags    []string
+}
+
+var (
+	uuidSegment    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	numericSegment = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// noiseHeaders are added by browsers and HTTP clients rather than chosen by
+// the API, so they are neither documented nor saved.
+var noiseHeaders = map[string]bool{
+	"accept-encoding": true, "accept-language": true, "cache-control": true, "connection": true,
+	"content-length": true, "cookie": true, "dnt": true, "host": true, "origin": true,
+	"pragma": true, "referer": true, "te": true, "upgrade-insecure-requests": true, "user-agent": true,
+}
+
+func isNoiseHeader(name string) bool {
+	lower := strings.ToLower(name)
+	return noiseHeaders[lower] || strings.HasPrefix(lower, ":") || strings.HasPrefix(lower, "sec-")
+}
+
+// normalizeTemplate rewrites Insomnia ({{ _.name }}) and spaced ({{ name }})
+// variables to Falcon's {{name}} form.
+func normalizeTemplate(s string) string {
+	return shared.PlaceholderPattern.ReplaceAllStringFunc(s, func(m string) string {
+		name := shared.PlaceholderPattern.FindStringSubmatch(m)[1]
+		return "{{" + strings.TrimPrefix(name, "_.") + "}}"
+	})
+}
+
+// splitRequestURL separates a request URL into its origin (scheme://host, or
+// a leading {{VAR}}), its path and its query parameters.
+func splitRequestURL(raw string) (origin, path string, query map[string]string) {
+	raw = strings.TrimSpace(raw)
+	if i := strings.Index(raw, "#"); i >= 0 {
+		raw = raw[:i]
+	}
+	rest := raw
+	if strings.HasPrefix(raw, "{{") {
+		if end := strings.Index(raw, "}}"); end >= 0 {
+			origin, rest = raw[:end+2], raw[end+2:]
+		}
+	} else if i := strings.Index(raw, "://"); i >= 0 {
+		hostStart := i + 3
+		slash := strings.IndexAny(raw[hostStart:], "/?")
+		if slash < 0 {
+			origin, rest = raw, ""
+		} else {
+			origin, rest = raw[:hostStart+slash], raw[hostStart+slash:]
+		}
+	}
+
+	path = rest
+	if i := strings.Index(rest, "?"); i >= 0 {
+		path = rest[:i]
+		query = make(map[string]string)
+		for _, pair := range strings.Split(rest[i+1:], "&") {
+			if pair == "" {
+				continue
+			}
+			key, value, _ := strings.Cut(pair, "=")
+			if k, err := url.QueryUnescape(key); err == nil {
+				key = k
+			}
+			if v, err := url.QueryUnescape(value); err == nil {
+				value = v
+			}
+			query[key] = value
+		}
+	}
+	if path == "" {
+		path = "/"
+	}
+	return origin, path, query
+}
+
+// templatePath turns a concrete request path into an endpoint path: ":id"
+// and "{{id}}" segments become "{id}", and numeric or UUID segments become
+// "{id}", "{id2}", ... It returns the path parameter names in order.
+func templatePath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, seg := range segments {
+		name := ""
+		switch {
+		case strings.HasPrefix(seg, ":") && len(seg) > 1:
+			name = seg[1:]
+		case strings.HasPrefix(seg, "{{") && strings.HasSuffix(seg, "}}"):
+			name = strings.TrimSpace(strings.TrimPrefix(seg[2:len(seg)-2], "_."))
+		case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") && len(seg) > 2:
+			name = seg[1 : len(seg)-1]
+		case numericSegment.MatchString(seg) || uuidSegment.MatchString(seg):
+			name = "id"
+			for n := 2; containsName(params, name); n++ {
+				name = fmt.Sprintf("id%d", n)
+			}
+		default:
+			continue
+		}
+		segments[i] = "{" + name + "}"
+		params = append(params, name)
+	}
+	return strings.Join(segments, "/"), params
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// addRequest records a concrete request and the endpoint it exercises.
+// Requests to an endpoint that is already known add their query and header
+// names to it instead of creating a second endpoint.
+func addRequest(spec *ParsedSpec, req ParsedRequest) {
+	req.Method = strings.ToUpper(strings.TrimSpace(req.Method))
+	if req.Method == "" {
+		req.Method = "GET"
+	}
+	origin, rawPath, urlQuery := splitRequestURL(req.URL)
+	if len(urlQuery) > 0 {
+		if req.Query == nil {
+			req.Query = make(map[string]string)
+		}
+		for k, v := range urlQuery {
+			if _, exists := req.Query[k]; !exists {
+				req.Query[k] = v
+			}
+		}
+		req.URL = strings.SplitN(req.URL, "?", 2)[0]
+	}
+	if spec.BaseURL == "" && strings.Contains(origin, "://") {
+		spec.BaseURL = origin
+	}
+	path, pathParams := templatePath(rawPath)
+	if req.Name == "" {
+		req.Name = requestName(req.Method, path)
+	}
+
+	endpoint := ParsedEndpoint{
+		Method:  req.Method,
+		Path:    path,
+		Summary: req.Name,
+		HasBody: req.Body != nil,
+	}
+	for _, name := range pathParams {
+		endpoint.Parameters = append(endpoint.Parameters, ParsedParameter{Name: name, In: "path", Required: true, Type: "string"})
+	}
+	for _, name := range sortedKeys(req.Query) {
+		endpoint.Parameters = append(endpoint.Parameters, ParsedParameter{Name: name, In: "query", Type: valueType(req.Query[name])})
+	}
+	for _, name := range sortedKeys(req.Headers) {
+		if !isNoiseHeader(name) && !strings.EqualFold(name, "content-type") {
+			endpoint.Parameters = append(endpoint.Parameters, ParsedParameter{Name: name, In: "header", Type: "string"})
+		}
+	}
+	if req.Body != nil {
+		contentType := mediaTypeOf(req.Headers)
+		endpoint.RequestExamples = map[string]interface{}{contentType: req.Body}
+		if _, isText := req.Body.(string); !isText {
+			endpoint.RequestSchemas = map[string]interface{}{contentType: inferSchema(req.Body)}
+		}
+	}
+	if req.Status > 0 {
+		endpoint.Responses = []int{req.Status}
+	}
+
+	mergeEndpoint(spec, endpoint)
+	spec.Requests = append(spec.Requests, req)
+}
+
+// mergeEndpoint adds endpoint to the spec, or folds its parameters and
+// response codes into an existing endpoint with the same method and path.
+func mergeEndpoint(spec *ParsedSpec, endpoint ParsedEndpoint) {
+	for i := range spec.Endpoints {
+		existing := &spec.Endpoints[i]
+		if existing.Method != endpoint.Method || existing.Path != endpoint.Path {
+			continue
+		}
+		for _, param := range endpoint.Parameters {
+			known := false
+			for _, p := range existing.Parameters {
+				if p.Name == param.Name && p.In == param.In {
+					known = true
+					break
+				}
+			}
+			if !known {
+				existing.Parameters = append(existing.Parameters, param)
+			}
+		}
+		for _, code := range endpoint.Responses {
+			if !containsCode(existing.Responses, code) {
+				existing.Responses = append(existing.Responses, code)
+			}
+		}
+		if !existing.HasBody && endpoint.HasBody {
+			existing.HasBody = true
+			existing.RequestSchemas = endpoint.RequestSchemas
+			existing.RequestExamples = endpoint.RequestExamples
+		}
+		return
+	}
+	spec.Endpoints = append(spec.Endpoints, endpoint)
+}
+
+func containsCode(codes []int, code int) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// requestName derives a request name from its method and endpoint path:
+// GET /users/{id}/posts -> "get-users-id-posts".
+func requestName(method, path string) string {
+	name := strings.ToLower(method)
+	for _, seg := range strings.Split(path, "/") {
+		seg = strings.Trim(seg, "{}")
+		if seg != "" {
+			name += "-" + strings.ToLower(seg)
+		}
+	}
+	return name
+}
+
+// decodeBody parses a JSON body so it is saved as structured YAML; any other
+// body is kept as text.
+func decodeBody(text, contentType string) interface{} {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	if contentType == "" || strings.Contains(contentType, "json") {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(text), &decoded); err == nil {
+			return decoded
+		}
+	}
+	return text
+}
+
+// encodeForm renders form fields as an application/x-www-form-urlencoded
+// body, leaving {{VAR}} placeholders readable.
+func encodeForm(fields [][2]string) string {
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		parts = append(parts, queryEscape(f[0])+"="+queryEscape(f[1]))
+	}
+	return strings.Join(parts, "&")
+}
+
+func queryEscape(s string) string {
+	escaped := url.QueryEscape(s)
+	return strings.NewReplacer("%7B%7B", "{{", "%7D%7D", "}}").Replace(escaped)
+}
+
+// authHeader builds an Authorization header from an importer's auth block.
+// Basic credentials that are still variables cannot be encoded, so they are
+// left to the auth tools.
+func authHeader(kind, token, username, password string) string {
+	switch strings.ToLower(kind) {
+	case "bearer", "oauth2", "jwt":
+		if token != "" {
+			return "Bearer " + token
+		}
+	case "basic":
+		if username != "" && !strings.Contains(username+password, "{{") {
+			return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+		}
+	}
+	return ""
+}
+
+// headerValue looks a header up case-insensitively.
+func headerValue(headers map[string]string, name string) string {
+	for k, v := range headers {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+func mediaTypeOf(headers map[string]string) string {
+	contentType := headerValue(headers, "Content-Type")
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	contentType = strings.TrimSpace(strings.ToLower(contentType))
+	if contentType == "" {
+		return "application/json"
+	}
+	return contentType
+}
+
+// inferSchema describes an example payload as a JSON Schema, so imported
+// bodies feed the same generators as spec-defined ones.
+func inferSchema(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		props := make(map[string]interface{}, len(v))
+		for k, child := range v {
+			props[k] = inferSchema(child)
+		}
+		return map[string]interface{}{"type": "object", "properties": props}
+	case []interface{}:
+		schema := map[string]interface{}{"type": "array"}
+		if len(v) > 0 {
+			schema["items"] = inferSchema(v[0])
+		}
+		return schema
+	case string:
+		return map[string]interface{}{"type": "string"}
+	case bool:
+		return map[string]interface{}{"type": "boolean"}
+	case float64:
+		if v == float64(int64(v)) {
+			return map[string]interface{}{"type": "integer"}
+		}
+		return map[string]interface{}{"type": "number"}
+	case int, int64:
+		return map[string]interface{}{"type": "integer"}
+	}
+	return map[string]interface{}{}
+}
+
+// valueType guesses a parameter type from a sample value.
+func valueType(value string) string {
+	if numericSegment.MatchString(value) {
+		return "integer"
+	}
+	if value == "true" || value == "false" {
+		return "boolean"
+	}
+	return "string"
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
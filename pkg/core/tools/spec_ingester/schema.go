@@ -0,0 +1,347 @@
+package spec_ingester
+
+import (
+	"strings"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"gopkg.in/yaml.v3"
+)
+
+// schemaToJSONSchema renders a libopenapi schema as a plain JSON Schema map
+// with every $ref resolved in place, so it can be stored in spec.yaml and
+// validated without the original document.
+func schemaToJSONSchema(proxy *base.SchemaProxy, resolve schemaResolver) map[string]interface{} {
+	return inlineRefs(rawJSONSchema(proxy), resolve)
+}
+
+// inlineRefs returns a copy of schema with each $ref replaced by the schema it
+// points to. A reference back to a schema that is already being expanded
+// (User.friends -> User) is cut off with a "$comment" placeholder, which
+// validators accept as "any value".
+func inlineRefs(schema map[string]interface{}, resolve schemaResolver) map[string]interface{} {
+	if schema == nil {
+		return nil
+	}
+	active := make(map[string]bool)
+	var inline func(node interface{}) interface{}
+	inline = func(node interface{}) interface{} {
+		switch v := node.(type) {
+		case map[string]interface{}:
+			if ref, ok := v["$ref"].(string); ok && resolve != nil {
+				if active[ref] {
+					return map[string]interface{}{"$comment": "circular reference to " + refName(ref)}
+				}
+				if target := resolve(ref); target != nil {
+					active[ref] = true
+					resolved, _ := inline(target).(map[string]interface{})
+					delete(active, ref)
+					// keep siblings such as a description next to the $ref
+					for k, sibling := range v {
+						if _, exists := resolved[k]; !exists && k != "$ref" {
+							resolved[k] = inline(sibling)
+						}
+					}
+					return resolved
+				}
+			}
+			out := make(map[string]interface{}, len(v))
+			for k, child := range v {
+				out[k] = inline(child)
+			}
+			return out
+		case []interface{}:
+			out := make([]interface{}, len(v))
+			for i, child := range v {
+				out[i] = inline(child)
+			}
+			return out
+		}
+		return node
+	}
+	result, _ := inline(schema).(map[string]interface{})
+	return result
+}
+
+// rawJSONSchema renders a schema as written in the spec, keeping $refs to
+// other components so model relationships can be recovered.
+func rawJSONSchema(proxy *base.SchemaProxy) map[string]interface{} {
+	if proxy == nil || proxy.Schema() == nil {
+		return nil
+	}
+	rendered, err := proxy.Schema().Render()
+	if err != nil {
+		return nil
+	}
+	var result map[string]interface{}
+	if err := yaml.Unmarshal(rendered, &result); err != nil {
+		return nil
+	}
+	normalizeSchema(result)
+	return result
+}
+
+// refName returns the component name of a local reference:
+// "#/components/schemas/User" and "#/definitions/User" both give "User".
+func refName(ref string) string {
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		return ref[i+1:]
+	}
+	return ref
+}
+
+// schemaResolver looks up a component schema by its $ref.
+type schemaResolver func(ref string) map[string]interface{}
+
+// objectShape merges the properties and required lists of a schema with its
+// allOf branches, following $refs through resolve. oneOf/anyOf branches add
+// their properties as optional, since only one of them applies at a time.
+func objectShape(schema map[string]interface{}, resolve schemaResolver) (map[string]map[string]interface{}, map[string]bool) {
+	props := make(map[string]map[string]interface{})
+	required := make(map[string]bool)
+	visited := make(map[string]bool)
+
+	var merge func(s map[string]interface{}, optional bool)
+	merge = func(s map[string]interface{}, optional bool) {
+		if s == nil {
+			return
+		}
+		if ref, ok := s["$ref"].(string); ok {
+			if visited[ref] || resolve == nil {
+				return
+			}
+			visited[ref] = true
+			merge(resolve(ref), optional)
+			return
+		}
+		if declared, ok := s["properties"].(map[string]interface{}); ok {
+			for name, sub := range declared {
+				if subSchema, ok := sub.(map[string]interface{}); ok {
+					if _, exists := props[name]; !exists {
+						props[name] = subSchema
+					}
+				}
+			}
+		}
+		if !optional {
+			if list, ok := s["required"].([]interface{}); ok {
+				for _, name := range list {
+					if n, ok := name.(string); ok {
+						required[n] = true
+					}
+				}
+			}
+		}
+		for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+			branches, _ := s[key].([]interface{})
+			for _, branch := range branches {
+				branchSchema, _ := branch.(map[string]interface{})
+				merge(branchSchema, optional || key != "allOf")
+			}
+		}
+	}
+	merge(schema, false)
+	return props, required
+}
+
+// modelFields describes each property of a model as a shared.Variable.
+func modelFields(schema map[string]interface{}, resolve schemaResolver) map[string]shared.Variable {
+	props, required := objectShape(schema, resolve)
+	if len(props) == 0 {
+		return nil
+	}
+	fields := make(map[string]shared.Variable, len(props))
+	for name, prop := range props {
+		field := schemaVariable(prop, resolve)
+		field.Required = required[name]
+		fields[name] = field
+	}
+	return fields
+}
+
+// schemaVariable summarises one property schema: its type, format, enum,
+// numeric and length limits, pattern, example, and the model it refers to.
+func schemaVariable(prop map[string]interface{}, resolve schemaResolver) shared.Variable {
+	v := shared.Variable{}
+	if desc, ok := prop["description"].(string); ok {
+		v.Description = desc
+	}
+
+	target := prop
+	if ref, ok := prop["$ref"].(string); ok {
+		v.Ref = refName(ref)
+		target = nil
+		if resolve != nil {
+			target = resolve(ref)
+		}
+	} else if allOf, ok := prop["allOf"].([]interface{}); ok && len(allOf) == 1 {
+		// allOf with a single $ref is the usual way to add a description or
+		// nullable to a reference
+		if branch, ok := allOf[0].(map[string]interface{}); ok {
+			if ref, ok := branch["$ref"].(string); ok {
+				v.Ref = refName(ref)
+				if resolve != nil {
+					target = resolve(ref)
+				}
+			}
+		}
+	}
+	if target == nil {
+		target = map[string]interface{}{}
+	}
+	if v.Description == "" {
+		v.Description, _ = target["description"].(string)
+	}
+
+	v.Type = schemaType(target)
+	if v.Type == "" {
+		v.Type = schemaType(prop)
+	}
+	if v.Type == "" && v.Ref != "" {
+		v.Type = "object"
+	}
+	if v.Type == "array" {
+		if items, ok := target["items"].(map[string]interface{}); ok {
+			if ref, ok := items["$ref"].(string); ok {
+				v.Ref = refName(ref)
+			}
+		}
+	}
+
+	v.Format, _ = target["format"].(string)
+	v.Pattern, _ = target["pattern"].(string)
+	if enum, ok := target["enum"].([]interface{}); ok {
+		v.Enum = enum
+	}
+	if n, ok := toFloat(target["minimum"]); ok {
+		v.Minimum = &n
+	}
+	if n, ok := toFloat(target["maximum"]); ok {
+		v.Maximum = &n
+	}
+	// exclusiveMinimum is a flag in OpenAPI 3.0 and a bound of its own in 3.1
+	switch ex := target["exclusiveMinimum"].(type) {
+	case bool:
+		v.ExclusiveMinimum = ex
+	default:
+		if n, ok := toFloat(ex); ok {
+			v.Minimum, v.ExclusiveMinimum = &n, true
+		}
+	}
+	switch ex := target["exclusiveMaximum"].(type) {
+	case bool:
+		v.ExclusiveMaximum = ex
+	default:
+		if n, ok := toFloat(ex); ok {
+			v.Maximum, v.ExclusiveMaximum = &n, true
+		}
+	}
+	if n, ok := toFloat(target["minLength"]); ok {
+		i := int(n)
+		v.MinLength = &i
+	}
+	if n, ok := toFloat(target["maxLength"]); ok {
+		i := int(n)
+		v.MaxLength = &i
+	}
+	if example, ok := prop["example"]; ok {
+		v.Example = example
+	} else if example, ok := target["example"]; ok {
+		v.Example = example
+	} else if examples, ok := target["examples"].([]interface{}); ok && len(examples) > 0 {
+		v.Example = examples[0]
+	}
+	return v
+}
+
+// schemaType returns the first non-null type of a schema, inferring "object"
+// and "array" from properties and items when the type is omitted.
+func schemaType(schema map[string]interface{}) string {
+	switch t := schema["type"].(type) {
+	case string:
+		return t
+	case []interface{}:
+		for _, item := range t {
+			if s, ok := item.(string); ok && s != "null" {
+				return s
+			}
+		}
+	}
+	if _, ok := schema["properties"]; ok {
+		return "object"
+	}
+	if _, ok := schema["items"]; ok {
+		return "array"
+	}
+	return ""
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
+
+// normalizeSchema rewrites OpenAPI 3.0 keywords that JSON Schema validators
+// do not understand. "nullable: true" becomes a "null" member of the type
+// (and of the enum, when there is one).
+func normalizeSchema(schema map[string]interface{}) {
+	if schema == nil {
+		return
+	}
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		switch t := schema["type"].(type) {
+		case string:
+			schema["type"] = []interface{}{t, "null"}
+		case []interface{}:
+			if !containsString(t, "null") {
+				schema["type"] = append(t, "null")
+			}
+		}
+		if enum, ok := schema["enum"].([]interface{}); ok {
+			schema["enum"] = append(enum, nil)
+		}
+	}
+	delete(schema, "nullable")
+
+	// Only descend into keywords that hold schemas, so a property that happens
+	// to be called "nullable" is left alone.
+	for _, key := range []string{"items", "additionalProperties", "not"} {
+		if sub, ok := schema[key].(map[string]interface{}); ok {
+			normalizeSchema(sub)
+		}
+	}
+	for _, key := range []string{"properties", "patternProperties"} {
+		if props, ok := schema[key].(map[string]interface{}); ok {
+			for _, sub := range props {
+				if subSchema, ok := sub.(map[string]interface{}); ok {
+					normalizeSchema(subSchema)
+				}
+			}
+		}
+	}
+	for _, key := range []string{"allOf", "oneOf", "anyOf"} {
+		if list, ok := schema[key].([]interface{}); ok {
+			for _, sub := range list {
+				if subSchema, ok := sub.(map[string]interface{}); ok {
+					normalizeSchema(subSchema)
+				}
+			}
+		}
+	}
+}
+
+func containsString(values []interface{}, want string) bool {
+	for _, v := range values {
+		if s, ok := v.(string); ok && s == want {
+			return true
+		}
+	}
+	return false
+}
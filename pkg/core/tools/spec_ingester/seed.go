@@ -0,0 +1,151 @@
+package spec_ingester
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
+	"github.com/blackcoderx/falcon/pkg/storage"
+)
+
+// SeedResult reports what seedRequests wrote to .falcon/requests/.
+type SeedResult struct {
+	Saved    []string
+	Existing []string          // already saved; never overwritten
+	Skipped  map[string]string // name -> reason
+}
+
+var nonVarChars = regexp.MustCompile(`[^A-Za-z0-9]+`)
+
+// seedRequests saves imported requests as request files. Concrete origins
+// become {{BASE_URL}} and secrets become {{VAR}} placeholders, so the files
+// pass the same checks as requests saved through the request tool.
+func seedRequests(falconDir string, requests []ParsedRequest) (*SeedResult, error) {
+	result := &SeedResult{Skipped: make(map[string]string)}
+	if len(requests) == 0 {
+		return result, nil
+	}
+	dir := storage.GetRequestsDir(falconDir)
+	used := make(map[string]bool)
+
+	for _, parsed := range requests {
+		req := storage.Request{
+			Name:    uniqueRequestName(parsed.Name, used),
+			Method:  parsed.Method,
+			URL:     seedURL(parsed.URL, parsed.Query),
+			Headers: redactHeaders(parsed.Headers),
+			Body:    redactBody(parsed.Body),
+			Tags:    parsed.Tags,
+		}
+		if parsed.Status > 0 && parsed.Status < 400 {
+			req.Expect = map[string]interface{}{"status_code": parsed.Status}
+		}
+
+		if reason := shared.ValidateRequestForSecrets(req.URL, req.Headers, req.Body); reason != "" {
+			result.Skipped[req.Name] = strings.SplitN(reason, "\n", 2)[0]
+			continue
+		}
+		filePath := filepath.Join(dir, storage.RequestFileName(req.Name))
+		if _, err := os.Stat(filePath); err == nil {
+			result.Existing = append(result.Existing, req.Name)
+			continue
+		}
+		if err := storage.SaveRequest(req, filePath); err != nil {
+			return result, fmt.Errorf("failed to save request '%s': %w", req.Name, err)
+		}
+		result.Saved = append(result.Saved, req.Name)
+	}
+
+	if len(result.Saved) > 0 {
+		shared.UpdateManifestCounts(falconDir)
+	}
+	return result, nil
+}
+
+// uniqueRequestName slugs a request name and numbers repeats: "list-users",
+// "list-users-2", ...
+func uniqueRequestName(name string, used map[string]bool) string {
+	base := strings.Trim(nonVarChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if base == "" {
+		base = "request"
+	}
+	candidate := base
+	for n := 2; used[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d", base, n)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// seedURL replaces a concrete scheme://host origin with {{BASE_URL}} and
+// appends the query parameters, redacting secret values.
+func seedURL(raw string, query map[string]string) string {
+	origin, path, _ := splitRequestURL(raw)
+	u := raw
+	if strings.Contains(origin, "://") {
+		u = "{{BASE_URL}}" + path
+	}
+	if len(query) == 0 {
+		return u
+	}
+	var params []string
+	for _, key := range sortedKeys(query) {
+		value := query[key]
+		if shared.IsSecret(key, value) && !shared.ContainsVariablePlaceholder(value) {
+			value = "{{" + envVarName(key) + "}}"
+		}
+		params = append(params, queryEscape(key)+"="+queryEscape(value))
+	}
+	return u + "?" + strings.Join(params, "&")
+}
+
+// redactHeaders replaces secret header values with placeholders, keeping an
+// auth scheme prefix: "Bearer eyJ..." becomes "Bearer {{AUTH_TOKEN}}".
+func redactHeaders(headers map[string]string) map[string]string {
+	if len(headers) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(headers))
+	for name, value := range headers {
+		if !shared.IsSecret(name, value) || (shared.ContainsVariablePlaceholder(value) && !shared.HasPlaintextSecret(value)) {
+			out[name] = value
+			continue
+		}
+		scheme, _, hasScheme := strings.Cut(value, " ")
+		switch {
+		case hasScheme && strings.EqualFold(scheme, "bearer"):
+			out[name] = "Bearer {{AUTH_TOKEN}}"
+		case hasScheme && strings.EqualFold(scheme, "basic"):
+			out[name] = "Basic {{BASIC_AUTH}}"
+		default:
+			out[name] = "{{" + envVarName(name) + "}}"
+		}
+	}
+	return out
+}
+
+// redactBody replaces secret values in the top-level fields of a JSON body.
+func redactBody(body interface{}) interface{} {
+	fields, ok := body.(map[string]interface{})
+	if !ok {
+		return body
+	}
+	out := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		if s, isString := value.(string); isString && shared.IsSecret(key, s) && s != "" && !shared.ContainsVariablePlaceholder(s) {
+			out[key] = "{{" + envVarName(key) + "}}"
+			continue
+		}
+		out[key] = value
+	}
+	return out
+}
+
+// envVarName turns a header or field name into an environment variable
+// name: "X-API-Key" -> "X_API_KEY".
+func envVarName(name string) string {
+	return strings.Trim(nonVarChars.ReplaceAllString(strings.ToUpper(name), "_"), "_")
+}
@@ -0,0 +1,371 @@
+package spec_ingester
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pb33f/libopenapi"
+	"github.com/pb33f/libopenapi/datamodel/high/base"
+	v2 "github.com/pb33f/libopenapi/datamodel/high/v2"
+)
+
+// parseSwagger2 maps a Swagger 2.0 document into the same ParsedSpec as
+// OpenAPI 3: definitions become models, body and formData parameters become
+// request schemas per "consumes" type, response schemas are listed per
+// "produces" type, and host/basePath become the base URL.
+func (p *OpenAPIParser) parseSwagger2(document libopenapi.Document, explicit map[string]bool) (*ParsedSpec, error) {
+	model, err := document.BuildV2Model()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build Swagger 2.0 model: %w", err)
+	}
+	swagger := &model.Model
+
+	spec := &ParsedSpec{
+		Format:  "swagger2",
+		BaseURL: swaggerBaseURL(swagger),
+	}
+	if swagger.Info != nil {
+		spec.Version = swagger.Info.Version
+	}
+
+	// Definitions, kept as written so $refs can be resolved when inlining
+	definitions := make(map[string]map[string]interface{})
+	if swagger.Definitions != nil && swagger.Definitions.Definitions != nil {
+		for pair := swagger.Definitions.Definitions.First(); pair != nil; pair = pair.Next() {
+			definitions[pair.Key()] = rawJSONSchema(pair.Value())
+		}
+	}
+	resolve := func(ref string) map[string]interface{} {
+		if !strings.HasPrefix(ref, "#/definitions/") {
+			return nil
+		}
+		return definitions[refName(ref)]
+	}
+
+	if swagger.Paths != nil && swagger.Paths.PathItems != nil {
+		for pair := swagger.Paths.PathItems.First(); pair != nil; pair = pair.Next() {
+			path := pair.Key()
+			pathItem := pair.Value()
+
+			ops := []struct {
+				method string
+				op     *v2.Operation
+			}{
+				{"GET", pathItem.Get},
+				{"POST", pathItem.Post},
+				{"PUT", pathItem.Put},
+				{"DELETE", pathItem.Delete},
+				{"PATCH", pathItem.Patch},
+			}
+			for _, entry := range ops {
+				if entry.op == nil {
+					continue
+				}
+				spec.Endpoints = append(spec.Endpoints, p.parseSwagger2Operation(swagger, entry.method, path, pathItem, entry.op, resolve, explicit))
+			}
+		}
+	}
+
+	if swagger.Definitions != nil && swagger.Definitions.Definitions != nil {
+		for pair := swagger.Definitions.Definitions.First(); pair != nil; pair = pair.Next() {
+			parsed := ParsedModel{
+				Name:   pair.Key(),
+				Schema: inlineRefs(definitions[pair.Key()], resolve),
+				Raw:    definitions[pair.Key()],
+			}
+			if s := pair.Value().Schema(); s != nil {
+				parsed.Description = s.Description
+			}
+			spec.Models = append(spec.Models, parsed)
+		}
+	}
+
+	return spec, nil
+}
+
+func (p *OpenAPIParser) parseSwagger2Operation(swagger *v2.Swagger, method, path string, pathItem *v2.PathItem, op *v2.Operation, resolve schemaResolver, explicit map[string]bool) ParsedEndpoint {
+	endpoint := ParsedEndpoint{
+		Method:      method,
+		Path:        path,
+		Summary:     op.Summary,
+		Description: op.Description,
+	}
+
+	consumes := op.Consumes
+	if len(consumes) == 0 {
+		consumes = swagger.Consumes
+	}
+	produces := op.Produces
+	if len(produces) == 0 {
+		produces = swagger.Produces
+	}
+	if len(produces) == 0 {
+		produces = []string{"application/json"}
+	}
+
+	var bodySchema map[string]interface{}
+	var bodyModel string
+	form := map[string]interface{}{"type": "object"}
+	formProps := make(map[string]interface{})
+	var formRequired []interface{}
+	hasFile := false
+
+	// Path-level parameters apply to every operation unless overridden
+	for _, param := range mergeSwagger2Parameters(pathItem.Parameters, op.Parameters) {
+		required := param.Required != nil && *param.Required
+		switch param.In {
+		case "body":
+			bodySchema = schemaToJSONSchema(param.Schema, resolve)
+			if param.Schema != nil && param.Schema.IsReference() {
+				bodyModel = refName(param.Schema.GetReference())
+			}
+		case "formData":
+			formProps[param.Name] = swagger2ParameterSchema(param)
+			if required {
+				formRequired = append(formRequired, param.Name)
+			}
+			if param.Type == "file" {
+				hasFile = true
+			}
+			endpoint.Parameters = append(endpoint.Parameters, ParsedParameter{Name: param.Name, In: param.In, Required: required, Type: param.Type})
+		default:
+			endpoint.Parameters = append(endpoint.Parameters, ParsedParameter{Name: param.Name, In: param.In, Required: required, Type: param.Type})
+		}
+	}
+
+	if bodySchema != nil {
+		types := consumes
+		if len(types) == 0 {
+			types = []string{"application/json"}
+		}
+		endpoint.RequestSchemas = make(map[string]interface{})
+		for _, contentType := range types {
+			endpoint.RequestSchemas[contentType] = bodySchema
+		}
+		if example, ok := bodySchema["example"]; ok {
+			endpoint.RequestExamples = map[string]interface{}{}
+			for _, contentType := range types {
+				endpoint.RequestExamples[contentType] = example
+			}
+		}
+		endpoint.RequestModel = bodyModel
+	} else if len(formProps) > 0 {
+		form["properties"] = formProps
+		if len(formRequired) > 0 {
+			form["required"] = formRequired
+		}
+		contentType := "application/x-www-form-urlencoded"
+		if hasFile || containsMediaType(consumes, "multipart/form-data") {
+			contentType = "multipart/form-data"
+		}
+		endpoint.RequestSchemas = map[string]interface{}{contentType: form}
+	}
+	endpoint.HasBody = endpoint.RequestSchemas != nil
+
+	if op.Responses != nil {
+		if op.Responses.Codes != nil {
+			for pair := op.Responses.Codes.First(); pair != nil; pair = pair.Next() {
+				status := pair.Key()
+				if code, err := strconv.Atoi(status); err == nil {
+					endpoint.Responses = append(endpoint.Responses, code)
+				}
+				endpoint.ResponseBodies = append(endpoint.ResponseBodies, swagger2Response(strings.ToUpper(status), pair.Value(), produces, resolve))
+			}
+		}
+		if op.Responses.Default != nil {
+			endpoint.ResponseBodies = append(endpoint.ResponseBodies, swagger2Response("default", op.Responses.Default, produces, resolve))
+		}
+	}
+
+	// "security: []" on the operation opts out of the global requirement
+	security := op.Security
+	if security == nil && !explicit[method+" "+path] {
+		security = swagger.Security
+	}
+	endpoint.AuthType = authTypeFor(security, func(name string) string {
+		if swagger.SecurityDefinitions == nil || swagger.SecurityDefinitions.Definitions == nil {
+			return name
+		}
+		scheme, ok := swagger.SecurityDefinitions.Definitions.Get(name)
+		if !ok || scheme == nil {
+			return name
+		}
+		return describeSecurityScheme(scheme.Type, "", scheme.In, scheme.Name)
+	})
+
+	return endpoint
+}
+
+// mergeSwagger2Parameters combines path-level and operation parameters; an
+// operation parameter with the same name and location wins.
+func mergeSwagger2Parameters(pathParams, opParams []*v2.Parameter) []*v2.Parameter {
+	var merged []*v2.Parameter
+	overridden := make(map[string]bool)
+	for _, param := range opParams {
+		if param != nil {
+			overridden[param.In+":"+param.Name] = true
+		}
+	}
+	for _, param := range pathParams {
+		if param != nil && !overridden[param.In+":"+param.Name] {
+			merged = append(merged, param)
+		}
+	}
+	for _, param := range opParams {
+		if param != nil {
+			merged = append(merged, param)
+		}
+	}
+	return merged
+}
+
+// swagger2ParameterSchema turns a non-body parameter's inline type and
+// constraints into a JSON Schema.
+func swagger2ParameterSchema(param *v2.Parameter) map[string]interface{} {
+	schema := map[string]interface{}{}
+	switch param.Type {
+	case "":
+	case "file":
+		schema["type"] = "string"
+		schema["format"] = "binary"
+	default:
+		schema["type"] = param.Type
+	}
+	if param.Format != "" {
+		schema["format"] = param.Format
+	}
+	if param.Description != "" {
+		schema["description"] = param.Description
+	}
+	if param.Minimum != nil {
+		schema["minimum"] = *param.Minimum
+		if param.ExclusiveMinimum != nil && *param.ExclusiveMinimum {
+			schema["exclusiveMinimum"] = true
+		}
+	}
+	if param.Maximum != nil {
+		schema["maximum"] = *param.Maximum
+		if param.ExclusiveMaximum != nil && *param.ExclusiveMaximum {
+			schema["exclusiveMaximum"] = true
+		}
+	}
+	if param.MinLength != nil {
+		schema["minLength"] = *param.MinLength
+	}
+	if param.MaxLength != nil {
+		schema["maxLength"] = *param.MaxLength
+	}
+	if param.Pattern != "" {
+		schema["pattern"] = param.Pattern
+	}
+	if len(param.Enum) > 0 {
+		schema["enum"] = decodeNodes(param.Enum)
+	}
+	if param.Default != nil {
+		var def interface{}
+		if err := param.Default.Decode(&def); err == nil {
+			schema["default"] = def
+		}
+	}
+	if param.Type == "array" && param.Items != nil && param.Items.Type != "" {
+		schema["items"] = map[string]interface{}{"type": param.Items.Type}
+	}
+	return schema
+}
+
+// swagger2Response lists a response schema under each "produces" type.
+func swagger2Response(status string, resp *v2.Response, produces []string, resolve schemaResolver) ParsedResponse {
+	parsed := ParsedResponse{Status: status}
+	if resp == nil {
+		return parsed
+	}
+	parsed.Description = resp.Description
+	if resp.Schema == nil {
+		return parsed
+	}
+	schema := schemaToJSONSchema(resp.Schema, resolve)
+	parsed.Schemas = make(map[string]interface{}, len(produces))
+	for _, contentType := range produces {
+		parsed.Schemas[contentType] = schema
+	}
+	return parsed
+}
+
+// swaggerBaseURL builds the API base URL from schemes, host and basePath.
+// Without a host only the base path is known.
+func swaggerBaseURL(swagger *v2.Swagger) string {
+	basePath := strings.TrimSuffix(swagger.BasePath, "/")
+	if swagger.Host == "" {
+		return basePath
+	}
+	scheme := "https"
+	if len(swagger.Schemes) > 0 && !containsMediaType(swagger.Schemes, "https") {
+		scheme = swagger.Schemes[0]
+	}
+	return fmt.Sprintf("%s://%s%s", scheme, swagger.Host, basePath)
+}
+
+// authTypeFor describes the security requirements of an operation, e.g.
+// "bearer" or "apiKey (header: X-API-Key) or oauth2". Alternatives are
+// joined with "or"; schemes required together with "+".
+func authTypeFor(requirements []*base.SecurityRequirement, describe func(name string) string) string {
+	var alternatives []string
+	seen := make(map[string]bool)
+	for _, req := range requirements {
+		if req == nil || req.Requirements == nil {
+			continue
+		}
+		var together []string
+		for pair := req.Requirements.First(); pair != nil; pair = pair.Next() {
+			together = append(together, describe(pair.Key()))
+		}
+		if len(together) == 0 {
+			continue
+		}
+		alt := strings.Join(together, " + ")
+		if !seen[alt] {
+			seen[alt] = true
+			alternatives = append(alternatives, alt)
+		}
+	}
+	return strings.Join(alternatives, " or ")
+}
+
+// describeSecurityScheme names a security scheme the way the auth tool
+// talks about it: "bearer", "basic", "apiKey (header: X-API-Key)", "oauth2".
+func describeSecurityScheme(schemeType, httpScheme, in, name string) string {
+	switch schemeType {
+	case "http":
+		if httpScheme != "" {
+			return strings.ToLower(httpScheme)
+		}
+		return "http"
+	case "apiKey":
+		return fmt.Sprintf("apiKey (%s: %s)", in, name)
+	default:
+		return schemeType
+	}
+}
+
+func containsMediaType(values []string, want string) bool {
+	for _, v := range values {
+		if strings.EqualFold(strings.TrimSpace(v), want) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeNodes decodes enum values from their YAML nodes, skipping any that
+// fail to decode.
+func decodeNodes[N interface{ Decode(v any) error }](nodes []N) []interface{} {
+	values := make([]interface{}, 0, len(nodes))
+	for _, node := range nodes {
+		var v interface{}
+		if node.Decode(&v) == nil {
+			values = append(values, v)
+		}
+	}
+	return values
+}
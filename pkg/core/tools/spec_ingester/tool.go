@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
@@ -28,8 +30,56 @@ func NewIngestSpecTool(llmClient llm.LLMClient, falconDir string) *IngestSpecToo
 
 // IngestParams checks inputs for file path or URL
 type IngestParams struct {
-	Action string `json:"action"` // "index", "update", "status"
-	Source string `json:"source"` // file path or URL
+	Action string `json:"action"`           // "index", "update", "status"
+	Source string `json:"source"`           // file path, directory (Bruno) or URL
+	Format string `json:"format,omitempty"` // force a parser instead of detecting one
+}
+
+// specParsers lists the parsers in detection order: formats with an
+// unambiguous structure first, then OpenAPI and Postman, and the loosely
+// detected GraphQL SDL and curl text last.
+func specParsers() []struct {
+	name   string
+	parser SpecParser
+} {
+	return []struct {
+		name   string
+		parser SpecParser
+	}{
+		{"har", &HARParser{}},
+		{"insomnia", &InsomniaParser{}},
+		{"bruno", &BrunoParser{}},
+		{"openapi", &OpenAPIParser{}},
+		{"postman", &PostmanParser{}},
+		{"graphql", &GraphQLParser{}},
+		{"curl", &CurlParser{}},
+	}
+}
+
+// detectParser returns the parser named by format, or the first parser
+// that recognises content.
+func detectParser(content []byte, format string) (SpecParser, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "swagger" {
+		format = "openapi"
+	}
+	var names []string
+	for _, entry := range specParsers() {
+		if format != "" {
+			if entry.name == format {
+				return entry.parser, nil
+			}
+			names = append(names, entry.name)
+			continue
+		}
+		if entry.parser.DetectFormat(content) {
+			return entry.parser, nil
+		}
+	}
+	if format != "" {
+		return nil, fmt.Errorf("unknown format '%s' (use: %s)", format, strings.Join(names, ", "))
+	}
+	return nil, fmt.Errorf("unsupported spec format")
 }
 
 func (t *IngestSpecTool) Name() string {
@@ -37,13 +87,14 @@ func (t *IngestSpecTool) Name() string {
 }
 
 func (t *IngestSpecTool) Description() string {
-	return "Ingest API specifications (OpenAPI/Swagger/Postman) to build a Knowledge Graph for autonomous testing. Use 'index' to start a fresh scan."
+	return "Ingest API specifications (OpenAPI/Swagger, Postman, HAR, Insomnia, Bruno, curl commands, GraphQL SDL/introspection) to build a Knowledge Graph for autonomous testing. Concrete requests from HAR, Insomnia, Bruno, curl and GraphQL imports are also saved to .falcon/requests/. Use 'index' to start a fresh scan."
 }
 
 func (t *IngestSpecTool) Parameters() string {
 	return `{
   "action": "index",
-  "source": "./docs/openapi.yaml"
+  "source": "./docs/openapi.yaml",
+  "format": "openapi|postman|har|insomnia|bruno|curl|graphql (optional, detected from the content)"
 }`
 }
 
@@ -77,16 +128,9 @@ func (t *IngestSpecTool) Execute(args string) (string, error) {
 	}
 
 	// 2. Detect & Parse
-	var parser SpecParser
-	openapi := &OpenAPIParser{}
-	postman := &PostmanParser{}
-
-	if openapi.DetectFormat(content) {
-		parser = openapi
-	} else if postman.DetectFormat(content) {
-		parser = postman
-	} else {
-		return "", fmt.Errorf("unsupported spec format")
+	parser, err := detectParser(content, params.Format)
+	if err != nil {
+		return "", err
 	}
 
 	parsedSpec, err := parser.Parse(content)
@@ -112,7 +156,26 @@ func (t *IngestSpecTool) Execute(args string) (string, error) {
 		return "", fmt.Errorf("failed to save graph: %w", err)
 	}
 
-	return fmt.Sprintf("Successfully indexed API from %s. Found %d endpoints.", params.Source, len(graph.Endpoints)), nil
+	summary := fmt.Sprintf("Successfully indexed API from %s. Found %d endpoints.", params.Source, len(graph.Endpoints))
+	if graph.BaseURL != "" {
+		summary += fmt.Sprintf(" Base URL: %s", graph.BaseURL)
+	}
+
+	// 5. Seed saved requests from imports that carry concrete requests
+	seeded, err := seedRequests(t.falconDir, parsedSpec.Requests)
+	if err != nil {
+		return "", err
+	}
+	if len(seeded.Saved) > 0 {
+		summary += fmt.Sprintf("\nSaved %d requests to .falcon/requests/: %s", len(seeded.Saved), strings.Join(seeded.Saved, ", "))
+	}
+	if len(seeded.Existing) > 0 {
+		summary += fmt.Sprintf("\nKept %d existing requests unchanged: %s", len(seeded.Existing), strings.Join(seeded.Existing, ", "))
+	}
+	for _, name := range sortedKeys(seeded.Skipped) {
+		summary += fmt.Sprintf("\nSkipped request '%s': %s", name, seeded.Skipped[name])
+	}
+	return summary, nil
 }
 
 func (t *IngestSpecTool) fetchContent(source string) ([]byte, error) {
@@ -124,5 +187,43 @@ func (t *IngestSpecTool) fetchContent(source string) ([]byte, error) {
 		defer resp.Body.Close()
 		return io.ReadAll(resp.Body)
 	}
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		return readBrunoCollection(source)
+	}
 	return os.ReadFile(source)
 }
+
+// readBrunoCollection reads a Bruno collection directory as its request
+// files one after the other. collection.bru, folder.bru and environments
+// hold settings rather than requests and are left out.
+func readBrunoCollection(dir string) ([]byte, error) {
+	var content []byte
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == "environments" || d.Name() == "node_modules" || (strings.HasPrefix(d.Name(), ".") && path != dir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) != ".bru" || d.Name() == "collection.bru" || d.Name() == "folder.bru" {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		content = append(content, data...)
+		content = append(content, '\n')
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(content) == 0 {
+		return nil, fmt.Errorf("no .bru request files found in %s", dir)
+	}
+	return content, nil
+}
@@ -2,6 +2,8 @@
 // implementation for the Falcon API debugging assistant.
 package core
 
+import "context"
+
 // Tool represents an agent capability that can be executed.
 // Each tool has a name, description, parameters schema, and execution logic.
 // Tools are registered with the Agent and can be invoked during the ReAct loop.
@@ -16,6 +18,16 @@ type Tool interface {
 	Execute(args string) (string, error)
 }
 
+// SchemaTool is a tool that describes its arguments as a JSON Schema object.
+// The schema is sent to models that support native tool calling. Tools that
+// do not implement it are exposed with a permissive object schema whose
+// description is taken from Parameters().
+type SchemaTool interface {
+	Tool
+	// InputSchema returns a JSON Schema of type "object" for the tool arguments.
+	InputSchema() map[string]interface{}
+}
+
 // AgentEvent represents a state change during agent processing.
 // Events are emitted via callbacks to enable real-time UI updates.
 type AgentEvent struct {
@@ -54,3 +66,11 @@ type ConfirmableTool interface {
 	SetEventCallback(callback EventCallback)
 }
 
+// ContextTool is a long-running tool that can stop early when its context is
+// cancelled, e.g. when the user presses Esc in the TUI.
+type ContextTool interface {
+	Tool
+	// ExecuteContext runs the tool like Execute, aborting when ctx is done.
+	ExecuteContext(ctx context.Context, args string) (string, error)
+}
+
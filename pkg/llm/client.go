@@ -3,10 +3,54 @@
 // enabling easy switching between different LLM backends like Ollama and Gemini.
 package llm
 
+import "errors"
+
+// ErrToolsUnsupported is returned by ChatWithTools when the selected model
+// does not support native function calling. Callers should fall back to the
+// text-based ACTION protocol.
+var ErrToolsUnsupported = errors.New("model does not support native tool calling")
+
 // Message represents a chat message
 type Message struct {
-	Role    string `json:"role"` // "system", "user", or "assistant"
+	Role    string `json:"role"` // "system", "user", "assistant", or "tool"
 	Content string `json:"content"`
+
+	// ToolCalls holds the native tool calls requested by an assistant message.
+	ToolCalls []ToolCall `json:"-"`
+	// ToolCallID links a "tool" message to the ToolCall it answers.
+	ToolCallID string `json:"-"`
+	// ToolName is the name of the tool that produced a "tool" message.
+	ToolName string `json:"-"`
+}
+
+// ToolDefinition describes a tool the model may call natively.
+type ToolDefinition struct {
+	Name        string
+	Description string
+	// Parameters is a JSON Schema object describing the tool arguments.
+	Parameters map[string]interface{}
+}
+
+// ToolCall is a structured tool invocation returned by the model.
+type ToolCall struct {
+	// ID is the provider-assigned call identifier (may be empty for providers
+	// that do not assign one).
+	ID string
+	// Name is the tool to execute.
+	Name string
+	// Arguments is the JSON-encoded argument object.
+	Arguments string
+	// Signature is an opaque provider token that must be replayed with the
+	// call in subsequent turns (e.g. Gemini thought signatures).
+	Signature []byte
+}
+
+// ToolChatResponse is the result of a tool-aware chat request.
+type ToolChatResponse struct {
+	// Content is any free text the model produced alongside (or instead of) tool calls.
+	Content string
+	// ToolCalls are the structured tool calls the model wants executed, in order.
+	ToolCalls []ToolCall
 }
 
 // StreamCallback is called for each chunk of streaming response
@@ -22,9 +66,40 @@ type LLMClient interface {
 	// Returns the complete response when streaming finishes.
 	ChatStream(messages []Message, callback StreamCallback) (string, error)
 
+	// ChatWithTools sends a chat request with native tool definitions and returns
+	// the model's text and structured tool calls. If callback is non-nil, text
+	// content is streamed through it as it arrives.
+	// Returns ErrToolsUnsupported if the model cannot do function calling.
+	ChatWithTools(messages []Message, tools []ToolDefinition, callback StreamCallback) (*ToolChatResponse, error)
+
 	// CheckConnection verifies that the LLM service is accessible.
 	CheckConnection() error
 
 	// GetModel returns the name of the model being used.
 	GetModel() string
 }
+
+// FlattenToolMessages rewrites native tool-call turns as plain text so that
+// history recorded with ChatWithTools can still be sent through Chat/ChatStream.
+// Assistant tool calls become ACTION lines and tool results become observations.
+func FlattenToolMessages(messages []Message) []Message {
+	out := make([]Message, 0, len(messages))
+	for _, msg := range messages {
+		switch {
+		case msg.Role == "tool":
+			out = append(out, Message{Role: "user", Content: "Observation: " + msg.Content})
+		case len(msg.ToolCalls) > 0:
+			content := msg.Content
+			for _, tc := range msg.ToolCalls {
+				if content != "" {
+					content += "\n"
+				}
+				content += "ACTION: " + tc.Name + "(" + tc.Arguments + ")"
+			}
+			out = append(out, Message{Role: msg.Role, Content: content})
+		default:
+			out = append(out, Message{Role: msg.Role, Content: msg.Content})
+		}
+	}
+	return out
+}
@@ -2,7 +2,9 @@ package gemini
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/blackcoderx/falcon/pkg/llm"
@@ -47,15 +49,44 @@ func (c *GeminiClient) convertMessages(messages []llm.Message) []*genai.Content
 	var contents []*genai.Content
 
 	for _, msg := range messages {
+		// Tool results are sent back as functionResponse parts in a user turn.
+		// Consecutive results (parallel calls) are grouped into one turn.
+		if msg.Role == "tool" {
+			part := &genai.Part{FunctionResponse: &genai.FunctionResponse{
+				ID:       msg.ToolCallID,
+				Name:     msg.ToolName,
+				Response: map[string]any{"output": msg.Content},
+			}}
+			if n := len(contents); n > 0 && contents[n-1].Role == "user" && contents[n-1].Parts[0].FunctionResponse != nil {
+				contents[n-1].Parts = append(contents[n-1].Parts, part)
+			} else {
+				contents = append(contents, &genai.Content{Role: "user", Parts: []*genai.Part{part}})
+			}
+			continue
+		}
+
 		role := msg.Role
 		// Gemini uses "model" instead of "assistant"
 		if role == "assistant" {
 			role = "model"
 		}
 
+		var parts []*genai.Part
+		if msg.Content != "" || len(msg.ToolCalls) == 0 {
+			parts = append(parts, genai.NewPartFromText(msg.Content))
+		}
+		for _, tc := range msg.ToolCalls {
+			var args map[string]any
+			_ = json.Unmarshal([]byte(tc.Arguments), &args)
+			parts = append(parts, &genai.Part{
+				FunctionCall:     &genai.FunctionCall{ID: tc.ID, Name: tc.Name, Args: args},
+				ThoughtSignature: tc.Signature,
+			})
+		}
+
 		contents = append(contents, &genai.Content{
 			Role:  role,
-			Parts: []*genai.Part{genai.NewPartFromText(msg.Content)},
+			Parts: parts,
 		})
 	}
 
@@ -89,7 +120,7 @@ func (c *GeminiClient) Chat(messages []llm.Message) (string, error) {
 	defer cancel()
 
 	// Extract system instruction from messages
-	systemInstruction, conversationMessages := c.extractSystemInstruction(messages)
+	systemInstruction, conversationMessages := c.extractSystemInstruction(llm.FlattenToolMessages(messages))
 
 	// Convert messages to Gemini format
 	contents := c.convertMessages(conversationMessages)
@@ -121,7 +152,7 @@ func (c *GeminiClient) ChatStream(messages []llm.Message, callback llm.StreamCal
 	ctx := context.Background() // No timeout for streaming
 
 	// Extract system instruction from messages
-	systemInstruction, conversationMessages := c.extractSystemInstruction(messages)
+	systemInstruction, conversationMessages := c.extractSystemInstruction(llm.FlattenToolMessages(messages))
 
 	// Convert messages to Gemini format
 	contents := c.convertMessages(conversationMessages)
@@ -160,6 +191,95 @@ func (c *GeminiClient) ChatStream(messages []llm.Message, callback llm.StreamCal
 	return fullContent, nil
 }
 
+// ChatWithTools sends a chat request with native function declarations.
+// When callback is non-nil the response is streamed and text chunks are
+// forwarded as they arrive; function calls are collected from every chunk.
+func (c *GeminiClient) ChatWithTools(messages []llm.Message, tools []llm.ToolDefinition, callback llm.StreamCallback) (*llm.ToolChatResponse, error) {
+	systemInstruction, conversationMessages := c.extractSystemInstruction(messages)
+	contents := c.convertMessages(conversationMessages)
+
+	declarations := make([]*genai.FunctionDeclaration, 0, len(tools))
+	for _, t := range tools {
+		declarations = append(declarations, &genai.FunctionDeclaration{
+			Name:                 t.Name,
+			Description:          t.Description,
+			ParametersJsonSchema: t.Parameters,
+		})
+	}
+
+	config := &genai.GenerateContentConfig{
+		Tools: []*genai.Tool{{FunctionDeclarations: declarations}},
+	}
+	if systemInstruction != "" {
+		config.SystemInstruction = &genai.Content{
+			Parts: []*genai.Part{genai.NewPartFromText(systemInstruction)},
+		}
+	}
+
+	result := &llm.ToolChatResponse{}
+	var content strings.Builder
+
+	if callback == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 120*time.Second)
+		defer cancel()
+
+		response, err := c.client.Models.GenerateContent(ctx, c.model, contents, config)
+		if err != nil {
+			return nil, c.wrapToolError(err)
+		}
+		collectToolParts(response, &content, result, nil)
+		result.Content = content.String()
+		return result, nil
+	}
+
+	for response, err := range c.client.Models.GenerateContentStream(context.Background(), c.model, contents, config) {
+		if err != nil {
+			return nil, c.wrapToolError(err)
+		}
+		collectToolParts(response, &content, result, callback)
+	}
+
+	result.Content = content.String()
+	return result, nil
+}
+
+// collectToolParts appends the text and function-call parts of a response
+// (or streamed chunk) to the accumulated result. Thought parts are skipped.
+func collectToolParts(response *genai.GenerateContentResponse, content *strings.Builder, result *llm.ToolChatResponse, callback llm.StreamCallback) {
+	if response == nil || len(response.Candidates) == 0 || response.Candidates[0].Content == nil {
+		return
+	}
+	for _, part := range response.Candidates[0].Content.Parts {
+		switch {
+		case part.FunctionCall != nil:
+			args, err := json.Marshal(part.FunctionCall.Args)
+			if err != nil || part.FunctionCall.Args == nil {
+				args = []byte("{}")
+			}
+			result.ToolCalls = append(result.ToolCalls, llm.ToolCall{
+				ID:        part.FunctionCall.ID,
+				Name:      part.FunctionCall.Name,
+				Arguments: string(args),
+				Signature: part.ThoughtSignature,
+			})
+		case part.Text != "" && !part.Thought:
+			content.WriteString(part.Text)
+			if callback != nil {
+				callback(part.Text)
+			}
+		}
+	}
+}
+
+// wrapToolError maps Gemini's "function calling not enabled" rejection to
+// llm.ErrToolsUnsupported so the agent can fall back to the text protocol.
+func (c *GeminiClient) wrapToolError(err error) error {
+	if strings.Contains(strings.ToLower(err.Error()), "function calling is not enabled") {
+		return fmt.Errorf("%w: gemini (model: %s)", llm.ErrToolsUnsupported, c.model)
+	}
+	return fmt.Errorf("gemini (model: %s) request failed: %w", c.model, err)
+}
+
 // CheckConnection verifies that the Gemini API is accessible.
 func (c *GeminiClient) CheckConnection() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/blackcoderx/falcon/pkg/llm"
@@ -28,6 +29,50 @@ type ChatResponse struct {
 	Done      bool        `json:"done"`
 }
 
+// ToolChatRequest is an Ollama chat request carrying native tool definitions.
+type ToolChatRequest struct {
+	Model    string        `json:"model"`
+	Messages []ToolMessage `json:"messages"`
+	Tools    []ToolSpec    `json:"tools"`
+	Stream   bool          `json:"stream"`
+}
+
+// ToolMessage is the Ollama wire format for a message that may carry tool calls.
+type ToolMessage struct {
+	Role      string         `json:"role"`
+	Content   string         `json:"content"`
+	ToolCalls []ToolCallWire `json:"tool_calls,omitempty"`
+	ToolName  string         `json:"tool_name,omitempty"`
+}
+
+// ToolSpec declares a function the model may call.
+type ToolSpec struct {
+	Type     string       `json:"type"`
+	Function FunctionSpec `json:"function"`
+}
+
+// FunctionSpec is the function declaration inside ToolSpec.
+type FunctionSpec struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+// ToolCallWire is a tool call as returned by Ollama. Unlike OpenAI, Ollama
+// returns arguments as a JSON object rather than an encoded string.
+type ToolCallWire struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+// ToolChatResponse is a (possibly streamed) Ollama response to a ToolChatRequest.
+type ToolChatResponse struct {
+	Message ToolMessage `json:"message"`
+	Done    bool        `json:"done"`
+}
+
 // OllamaClient handles communication with Ollama API
 type OllamaClient struct {
 	BaseURL         string
@@ -59,7 +104,7 @@ func NewOllamaClient(baseURL, model, apiKey string) *OllamaClient {
 func (c *OllamaClient) Chat(messages []llm.Message) (string, error) {
 	req := ChatRequest{
 		Model:    c.Model,
-		Messages: messages,
+		Messages: llm.FlattenToolMessages(messages),
 		Stream:   false,
 	}
 
@@ -104,7 +149,7 @@ func (c *OllamaClient) Chat(messages []llm.Message) (string, error) {
 func (c *OllamaClient) ChatStream(messages []llm.Message, callback llm.StreamCallback) (string, error) {
 	req := ChatRequest{
 		Model:    c.Model,
-		Messages: messages,
+		Messages: llm.FlattenToolMessages(messages),
 		Stream:   true,
 	}
 
@@ -200,6 +245,146 @@ func (c *OllamaClient) chatWithFallback(messages []llm.Message, callback llm.Str
 	return content, nil
 }
 
+// ChatWithTools sends a chat request with native tool definitions.
+// Models without tool support make Ollama answer 400 "does not support tools",
+// which is reported as llm.ErrToolsUnsupported.
+func (c *OllamaClient) ChatWithTools(messages []llm.Message, tools []llm.ToolDefinition, callback llm.StreamCallback) (*llm.ToolChatResponse, error) {
+	req := ToolChatRequest{
+		Model:    c.Model,
+		Messages: toToolMessages(messages),
+		Tools:    toToolSpecs(tools),
+		Stream:   callback != nil,
+	}
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/api/chat", c.BaseURL)
+	httpReq, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	if c.APIKey != "" {
+		httpReq.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
+	}
+
+	client := c.HTTPClient
+	if req.Stream {
+		client = c.StreamingClient
+	}
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusBadRequest && strings.Contains(string(body), "does not support tools") {
+			return nil, fmt.Errorf("%w: ollama (model: %s)", llm.ErrToolsUnsupported, c.Model)
+		}
+		return nil, fmt.Errorf("ollama (url: %s, model: %s) returned status %d: %s", url, c.Model, resp.StatusCode, string(body))
+	}
+
+	result := &llm.ToolChatResponse{}
+	if !req.Stream {
+		var chatResp ToolChatResponse
+		if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		result.Content = chatResp.Message.Content
+		result.ToolCalls = fromToolCallWire(chatResp.Message.ToolCalls)
+		return result, nil
+	}
+
+	var content strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		var chatResp ToolChatResponse
+		if err := json.Unmarshal([]byte(line), &chatResp); err != nil {
+			continue
+		}
+
+		if chunk := chatResp.Message.Content; chunk != "" {
+			content.WriteString(chunk)
+			callback(chunk)
+		}
+		result.ToolCalls = append(result.ToolCalls, fromToolCallWire(chatResp.Message.ToolCalls)...)
+
+		if chatResp.Done {
+			break
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading stream: %w", err)
+	}
+
+	result.Content = content.String()
+	return result, nil
+}
+
+// toToolMessages converts llm messages, including tool turns, to the Ollama wire format.
+func toToolMessages(messages []llm.Message) []ToolMessage {
+	out := make([]ToolMessage, 0, len(messages))
+	for _, msg := range messages {
+		wire := ToolMessage{Role: msg.Role, Content: msg.Content, ToolName: msg.ToolName}
+		for _, tc := range msg.ToolCalls {
+			var call ToolCallWire
+			call.Function.Name = tc.Name
+			call.Function.Arguments = json.RawMessage(tc.Arguments)
+			if !json.Valid(call.Function.Arguments) {
+				call.Function.Arguments = json.RawMessage("{}")
+			}
+			wire.ToolCalls = append(wire.ToolCalls, call)
+		}
+		out = append(out, wire)
+	}
+	return out
+}
+
+// toToolSpecs converts tool definitions to Ollama function declarations.
+func toToolSpecs(tools []llm.ToolDefinition) []ToolSpec {
+	out := make([]ToolSpec, 0, len(tools))
+	for _, t := range tools {
+		out = append(out, ToolSpec{
+			Type: "function",
+			Function: FunctionSpec{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.Parameters,
+			},
+		})
+	}
+	return out
+}
+
+// fromToolCallWire converts Ollama tool calls to llm.ToolCall values.
+func fromToolCallWire(calls []ToolCallWire) []llm.ToolCall {
+	var out []llm.ToolCall
+	for _, tc := range calls {
+		if tc.Function.Name == "" {
+			continue
+		}
+		args := string(tc.Function.Arguments)
+		if strings.TrimSpace(args) == "" || args == "null" {
+			args = "{}"
+		}
+		out = append(out, llm.ToolCall{Name: tc.Function.Name, Arguments: args})
+	}
+	return out
+}
+
 // CheckConnection verifies that Ollama is running and accessible
 func (c *OllamaClient) CheckConnection() error {
 	url := fmt.Sprintf("%s/api/tags", c.BaseURL)
@@ -22,8 +22,8 @@ func (p *OllamaProvider) SetupFields() []llm.SetupField {
 			Title: "Ollama mode",
 			Description: "Local runs on your machine; Cloud uses Ollama's hosted service.",
 			Options: []llm.FieldOption{
-				{"Local (run on your machine)", "local"},
-				{"Cloud (Ollama Cloud)", "cloud"},
+				{Label: "Local (run on your machine)", Value: "local"},
+				{Label: "Cloud (Ollama Cloud)", Value: "cloud"},
 			},
 		},
 		{
@@ -22,10 +22,55 @@ type openRouterRequest struct {
 	Stream   bool          `json:"stream"`
 }
 
+// openRouterToolRequest is the request body used when native tools are supplied.
+type openRouterToolRequest struct {
+	Model    string              `json:"model"`
+	Messages []openRouterMessage `json:"messages"`
+	Tools    []openRouterTool    `json:"tools"`
+	Stream   bool                `json:"stream"`
+}
+
+// openRouterMessage is the OpenAI-compatible wire format for a chat message,
+// including tool calls and tool results.
+type openRouterMessage struct {
+	Role       string               `json:"role"`
+	Content    string               `json:"content"`
+	ToolCalls  []openRouterToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string               `json:"tool_call_id,omitempty"`
+}
+
+// openRouterTool declares a function the model may call.
+type openRouterTool struct {
+	Type     string             `json:"type"`
+	Function openRouterFunction `json:"function"`
+}
+
+// openRouterFunction is the function declaration inside openRouterTool.
+type openRouterFunction struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description,omitempty"`
+	Parameters  map[string]interface{} `json:"parameters"`
+}
+
+// openRouterToolCall is a tool call in a response message or streaming delta.
+// During streaming, Index identifies which call a partial delta belongs to.
+type openRouterToolCall struct {
+	Index    int                    `json:"index"`
+	ID       string                 `json:"id,omitempty"`
+	Type     string                 `json:"type,omitempty"`
+	Function openRouterFunctionCall `json:"function"`
+}
+
+// openRouterFunctionCall holds the function name and JSON-encoded arguments.
+type openRouterFunctionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"`
+}
+
 // openRouterChoice represents a single choice in a non-streaming response.
 type openRouterChoice struct {
-	Message      llm.Message `json:"message"`
-	FinishReason string      `json:"finish_reason"`
+	Message      openRouterMessage `json:"message"`
+	FinishReason string            `json:"finish_reason"`
 }
 
 // openRouterResponse is the non-streaming response body from OpenRouter.
@@ -44,7 +89,8 @@ type openRouterError struct {
 
 // openRouterStreamDelta holds the partial content from a streaming chunk.
 type openRouterStreamDelta struct {
-	Content string `json:"content"`
+	Content   string               `json:"content"`
+	ToolCalls []openRouterToolCall `json:"tool_calls,omitempty"`
 }
 
 // openRouterStreamChoice is one choice entry in a streaming chunk.
@@ -110,7 +156,7 @@ func (c *OpenRouterClient) newRequest(body []byte, stream bool) (*http.Request,
 func (c *OpenRouterClient) Chat(messages []llm.Message) (string, error) {
 	payload := openRouterRequest{
 		Model:    c.model,
-		Messages: messages,
+		Messages: llm.FlattenToolMessages(messages),
 		Stream:   false,
 	}
 
@@ -160,7 +206,7 @@ func (c *OpenRouterClient) Chat(messages []llm.Message) (string, error) {
 func (c *OpenRouterClient) ChatStream(messages []llm.Message, callback llm.StreamCallback) (string, error) {
 	payload := openRouterRequest{
 		Model:    c.model,
-		Messages: messages,
+		Messages: llm.FlattenToolMessages(messages),
 		Stream:   true,
 	}
 
@@ -234,6 +280,163 @@ func (c *OpenRouterClient) ChatStream(messages []llm.Message, callback llm.Strea
 	return fullContent, nil
 }
 
+// ChatWithTools sends a chat request with native function declarations.
+// When callback is non-nil the response is streamed over SSE and partial
+// tool-call deltas are reassembled by index.
+func (c *OpenRouterClient) ChatWithTools(messages []llm.Message, tools []llm.ToolDefinition, callback llm.StreamCallback) (*llm.ToolChatResponse, error) {
+	payload := openRouterToolRequest{
+		Model:    c.model,
+		Messages: toWireMessages(messages),
+		Tools:    toWireTools(tools),
+		Stream:   callback != nil,
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	req, err := c.newRequest(body, payload.Stream)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	client := c.httpClient
+	if payload.Stream {
+		client = c.streamingClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("openrouter request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		rawBody, _ := io.ReadAll(resp.Body)
+		// OpenRouter answers 404 "No endpoints found that support tool use"
+		// when the chosen model has no function-calling capable provider.
+		if resp.StatusCode == http.StatusNotFound && strings.Contains(strings.ToLower(string(rawBody)), "tool") {
+			return nil, fmt.Errorf("%w: openrouter (model: %s)", llm.ErrToolsUnsupported, c.model)
+		}
+		return nil, fmt.Errorf("openrouter (model: %s) returned status %d: %s", c.model, resp.StatusCode, string(rawBody))
+	}
+
+	if !payload.Stream {
+		var result openRouterResponse
+		if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		if result.Error != nil {
+			return nil, fmt.Errorf("openrouter error (code %d): %s", result.Error.Code, result.Error.Message)
+		}
+		if len(result.Choices) == 0 {
+			return nil, fmt.Errorf("openrouter returned no choices")
+		}
+		msg := result.Choices[0].Message
+		return &llm.ToolChatResponse{Content: msg.Content, ToolCalls: fromWireToolCalls(msg.ToolCalls)}, nil
+	}
+
+	var content strings.Builder
+	var partial []openRouterToolCall
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		data := strings.TrimPrefix(line, "data: ")
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk openRouterStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			continue
+		}
+		if chunk.Error != nil {
+			return nil, fmt.Errorf("openrouter stream error (code %d): %s", chunk.Error.Code, chunk.Error.Message)
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		delta := chunk.Choices[0].Delta
+		if delta.Content != "" {
+			content.WriteString(delta.Content)
+			callback(delta.Content)
+		}
+		for _, tc := range delta.ToolCalls {
+			for len(partial) <= tc.Index {
+				partial = append(partial, openRouterToolCall{})
+			}
+			p := &partial[tc.Index]
+			if tc.ID != "" {
+				p.ID = tc.ID
+			}
+			if tc.Function.Name != "" {
+				p.Function.Name = tc.Function.Name
+			}
+			p.Function.Arguments += tc.Function.Arguments
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading openrouter stream: %w", err)
+	}
+
+	return &llm.ToolChatResponse{Content: content.String(), ToolCalls: fromWireToolCalls(partial)}, nil
+}
+
+// toWireMessages converts llm messages, including tool turns, to the OpenAI wire format.
+func toWireMessages(messages []llm.Message) []openRouterMessage {
+	out := make([]openRouterMessage, 0, len(messages))
+	for _, msg := range messages {
+		wire := openRouterMessage{Role: msg.Role, Content: msg.Content, ToolCallID: msg.ToolCallID}
+		for _, tc := range msg.ToolCalls {
+			wire.ToolCalls = append(wire.ToolCalls, openRouterToolCall{
+				ID:       tc.ID,
+				Type:     "function",
+				Function: openRouterFunctionCall{Name: tc.Name, Arguments: tc.Arguments},
+			})
+		}
+		out = append(out, wire)
+	}
+	return out
+}
+
+// toWireTools converts tool definitions to OpenAI function declarations.
+func toWireTools(tools []llm.ToolDefinition) []openRouterTool {
+	out := make([]openRouterTool, 0, len(tools))
+	for _, t := range tools {
+		out = append(out, openRouterTool{
+			Type: "function",
+			Function: openRouterFunction{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.Parameters,
+			},
+		})
+	}
+	return out
+}
+
+// fromWireToolCalls converts response tool calls, dropping entries without a name.
+func fromWireToolCalls(calls []openRouterToolCall) []llm.ToolCall {
+	var out []llm.ToolCall
+	for _, tc := range calls {
+		if tc.Function.Name == "" {
+			continue
+		}
+		args := tc.Function.Arguments
+		if strings.TrimSpace(args) == "" {
+			args = "{}"
+		}
+		out = append(out, llm.ToolCall{ID: tc.ID, Name: tc.Function.Name, Arguments: args})
+	}
+	return out
+}
+
 // CheckConnection verifies that the OpenRouter API is reachable and the key is valid
 // by fetching the models list (a cheap, read-only endpoint).
 func (c *OpenRouterClient) CheckConnection() error {
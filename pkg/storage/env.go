@@ -4,47 +4,144 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 
+	"github.com/blackcoderx/falcon/pkg/core/tools/shared"
 	"gopkg.in/yaml.v3"
 )
 
-// varPattern matches {{VAR_NAME}} or {{env:VAR_NAME}}
-var varPattern = regexp.MustCompile(`\{\{([^}]+)\}\}`)
+// transportKey is the environment file key that holds transport settings
+// instead of a variable.
+const transportKey = "transport"
 
 // LoadEnvironment loads environment variables from a YAML file
 func LoadEnvironment(filePath string) (map[string]string, error) {
+	env, _, err := loadEnvironmentFile(filePath)
+	return env, err
+}
+
+// LoadEnvironmentTransport loads the "transport" settings of an environment
+// file, with {{VAR}} and {{env:VAR}} references resolved. It returns nil
+// when the file has none.
+func LoadEnvironmentTransport(filePath string) (*Transport, error) {
+	env, transport, err := loadEnvironmentFile(filePath)
+	if err != nil || transport == nil {
+		return nil, err
+	}
+	for _, field := range []*string{&transport.CACert, &transport.ClientCert, &transport.ClientKey, &transport.Proxy, &transport.HTTPVersion} {
+		*field = SubstituteVariables(*field, env)
+	}
+	return transport, nil
+}
+
+func loadEnvironmentFile(filePath string) (map[string]string, *Transport, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read environment file: %w", err)
+		return nil, nil, fmt.Errorf("failed to read environment file: %w", err)
 	}
 
-	var env map[string]string
-	if err := yaml.Unmarshal(data, &env); err != nil {
-		return nil, fmt.Errorf("failed to parse environment YAML: %w", err)
+	var nodes map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &nodes); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse environment YAML: %w", err)
 	}
 
-	// Resolve any {{env:VAR}} references to actual environment variables
-	for key, value := range env {
+	env := make(map[string]string, len(nodes))
+	var transport *Transport
+	for key, node := range nodes {
+		if key == transportKey {
+			transport = &Transport{}
+			if err := node.Decode(transport); err != nil {
+				return nil, nil, fmt.Errorf("failed to parse environment transport: %w", err)
+			}
+			continue
+		}
+		var value string
+		if err := node.Decode(&value); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse environment variable '%s': %w", key, err)
+		}
+		// Resolve any {{env:VAR}} references to actual environment variables
 		env[key] = resolveEnvRefs(value)
 	}
 
-	return env, nil
+	return env, transport, nil
 }
 
-// SaveEnvironment saves environment variables to a YAML file
+// SaveEnvironment saves environment variables to a YAML file, keeping the
+// transport settings already in it.
 func SaveEnvironment(env map[string]string, filePath string) error {
-	dir := filepath.Dir(filePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
+	filePath = environmentPath(filePath)
+	nodes, err := readEnvironmentNodes(filePath)
+	if err != nil {
+		return err
+	}
+	for key := range nodes {
+		if key != transportKey {
+			delete(nodes, key)
+		}
+	}
+	for key, value := range env {
+		var node yaml.Node
+		if err := node.Encode(value); err != nil {
+			return fmt.Errorf("failed to marshal environment: %w", err)
+		}
+		nodes[key] = node
+	}
+	return writeEnvironmentNodes(nodes, filePath)
+}
+
+// SaveEnvironmentTransport sets the transport settings of an environment
+// file, creating it if needed. A nil transport removes them.
+func SaveEnvironmentTransport(transport *Transport, filePath string) error {
+	filePath = environmentPath(filePath)
+	nodes, err := readEnvironmentNodes(filePath)
+	if err != nil {
+		return err
 	}
+	delete(nodes, transportKey)
+	if transport != nil {
+		var node yaml.Node
+		if err := node.Encode(transport); err != nil {
+			return fmt.Errorf("failed to marshal transport: %w", err)
+		}
+		nodes[transportKey] = node
+	}
+	return writeEnvironmentNodes(nodes, filePath)
+}
 
+func environmentPath(filePath string) string {
 	if !strings.HasSuffix(filePath, ".yaml") && !strings.HasSuffix(filePath, ".yml") {
-		filePath = filePath + ".yaml"
+		return filePath + ".yaml"
+	}
+	return filePath
+}
+
+// readEnvironmentNodes reads an environment file as raw YAML, so values are
+// written back unresolved. A missing file reads as empty.
+func readEnvironmentNodes(filePath string) (map[string]yaml.Node, error) {
+	nodes := make(map[string]yaml.Node)
+	data, err := os.ReadFile(filePath)
+	if os.IsNotExist(err) {
+		return nodes, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environment file: %w", err)
+	}
+	if err := yaml.Unmarshal(data, &nodes); err != nil {
+		return nil, fmt.Errorf("failed to parse environment YAML: %w", err)
+	}
+	if nodes == nil {
+		nodes = make(map[string]yaml.Node)
+	}
+	return nodes, nil
+}
+
+func writeEnvironmentNodes(nodes map[string]yaml.Node, filePath string) error {
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
-	data, err := yaml.Marshal(env)
+	data, err := yaml.Marshal(nodes)
 	if err != nil {
 		return fmt.Errorf("failed to marshal environment: %w", err)
 	}
@@ -78,10 +175,8 @@ func ListEnvironments(baseDir string) ([]string, error) {
 
 // SubstituteVariables replaces {{VAR}} placeholders with values from the environment
 func SubstituteVariables(text string, env map[string]string) string {
-	return varPattern.ReplaceAllStringFunc(text, func(match string) string {
-		// Extract variable name (remove {{ and }})
-		varName := strings.TrimPrefix(strings.TrimSuffix(match, "}}"), "{{")
-		varName = strings.TrimSpace(varName)
+	return shared.PlaceholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		varName := shared.PlaceholderPattern.FindStringSubmatch(match)[1]
 
 		// Check for env: prefix (reference to system environment)
 		if strings.HasPrefix(varName, "env:") {
@@ -104,12 +199,22 @@ func SubstituteVariables(text string, env map[string]string) string {
 // ApplyEnvironment applies environment variables to a request
 func ApplyEnvironment(req *Request, env map[string]string) *Request {
 	applied := &Request{
-		Name:    req.Name,
-		Method:  req.Method,
-		URL:     SubstituteVariables(req.URL, env),
-		Headers: make(map[string]string),
-		Query:   make(map[string]string),
-		Body:    req.Body,
+		Name:            req.Name,
+		Method:          req.Method,
+		URL:             SubstituteVariables(req.URL, env),
+		Headers:         make(map[string]string),
+		Query:           make(map[string]string),
+		Body:            req.Body,
+		BodyType:        req.BodyType,
+		BodyFile:        SubstituteVariables(req.BodyFile, env),
+		Tags:            req.Tags,
+		Session:         req.Session,
+		FollowRedirects: req.FollowRedirects,
+		MaxRedirects:    req.MaxRedirects,
+		Transport:       req.Transport,
+		Expect:          req.Expect,
+		Pre:             req.Pre,
+		Post:            req.Post,
 	}
 
 	// Apply to headers
@@ -132,9 +237,8 @@ func ApplyEnvironment(req *Request, env map[string]string) *Request {
 
 // resolveEnvRefs resolves {{env:VAR}} references in a string
 func resolveEnvRefs(text string) string {
-	return varPattern.ReplaceAllStringFunc(text, func(match string) string {
-		varName := strings.TrimPrefix(strings.TrimSuffix(match, "}}"), "{{")
-		varName = strings.TrimSpace(varName)
+	return shared.PlaceholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		varName := shared.PlaceholderPattern.FindStringSubmatch(match)[1]
 
 		if strings.HasPrefix(varName, "env:") {
 			sysVar := strings.TrimPrefix(varName, "env:")
@@ -8,12 +8,92 @@ type Request struct {
 	Headers map[string]string `yaml:"headers,omitempty"` // HTTP headers
 	Query   map[string]string `yaml:"query,omitempty"`   // Query parameters
 	Body    interface{}       `yaml:"body,omitempty"`    // Request body (JSON or string)
+	// BodyType is json, form, multipart, text, xml or binary; without it the
+	// Content-Type header decides. BodyFile is sent instead of Body.
+	BodyType string   `yaml:"body_type,omitempty"`
+	BodyFile string   `yaml:"body_file,omitempty"`
+	Tags     []string `yaml:"tags,omitempty"` // Labels for filtering (falcon test --tag)
+	// Session names the cookie session (default: "default", or the session
+	// of the request that runs this one as a pre step; "none" sends no
+	// cookies). FollowRedirects and MaxRedirects are as in http_request.
+	Session         string `yaml:"session,omitempty"`
+	FollowRedirects *bool  `yaml:"follow_redirects,omitempty"`
+	MaxRedirects    int    `yaml:"max_redirects,omitempty"`
+	// Transport overrides the environment's TLS, proxy and HTTP version
+	// settings for this request.
+	Transport *Transport `yaml:"transport,omitempty"`
+	// Expect holds checks applied by falcon test, in run_tests expectation
+	// form (status_code, body_contains, ...). Without it, status < 400 passes.
+	Expect map[string]interface{} `yaml:"expect,omitempty"`
+	// Pre steps run in order before the request is sent, Post after the
+	// response arrives. Values they produce are stored as {{variables}}.
+	Pre  []PreStep  `yaml:"pre,omitempty"`
+	Post *PostSteps `yaml:"post,omitempty"`
+}
+
+// PreStep prepares a saved request. Exactly one of Request, Token, Sign or
+// Set is given.
+type PreStep struct {
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	// Request runs another saved request first (with its own hooks) and
+	// keeps the values named in Extract: var -> $.path, header:Name,
+	// cookie:Name, status or body.
+	Request string            `yaml:"request,omitempty" json:"request,omitempty"`
+	Extract map[string]string `yaml:"extract,omitempty" json:"extract,omitempty"`
+	Token   *TokenStep        `yaml:"token,omitempty" json:"token,omitempty"`
+	Sign    *SignStep         `yaml:"sign,omitempty" json:"sign,omitempty"`
+	Set     map[string]string `yaml:"set,omitempty" json:"set,omitempty"` // var -> value, supports {{VAR}}
+}
+
+// TokenStep fetches an OAuth2 access token and stores it as {{save_as}},
+// with "Bearer <token>" in {{save_as_header}}.
+type TokenStep struct {
+	Flow         string   `yaml:"flow,omitempty" json:"flow,omitempty"` // client_credentials (default) or password
+	TokenURL     string   `yaml:"token_url" json:"token_url"`
+	ClientID     string   `yaml:"client_id" json:"client_id"`
+	ClientSecret string   `yaml:"client_secret" json:"client_secret"`
+	Username     string   `yaml:"username,omitempty" json:"username,omitempty"`
+	Password     string   `yaml:"password,omitempty" json:"password,omitempty"`
+	Scopes       []string `yaml:"scopes,omitempty" json:"scopes,omitempty"`
+	SaveAs       string   `yaml:"save_as,omitempty" json:"save_as,omitempty"` // default: access_token
+}
+
+// SignStep computes an HMAC over Payload and stores it as {{save_as}}.
+// Payload supports {{VAR}} and the request being signed: {{request.method}},
+// {{request.url}}, {{request.path}}, {{request.body}} (as encoded for
+// sending; empty for multipart). The Unix time of
+// signing is stored in {{timestamp}} first, so payload and headers can use it.
+type SignStep struct {
+	Algorithm string `yaml:"algorithm,omitempty" json:"algorithm,omitempty"` // hmac-sha256 (default), hmac-sha1, hmac-sha512
+	Secret    string `yaml:"secret" json:"secret"`
+	Payload   string `yaml:"payload" json:"payload"`
+	Encoding  string `yaml:"encoding,omitempty" json:"encoding,omitempty"` // hex (default) or base64
+	SaveAs    string `yaml:"save_as" json:"save_as"`
+}
+
+// PostSteps run after the response arrives, every time the request is sent.
+type PostSteps struct {
+	Extract map[string]string      `yaml:"extract,omitempty" json:"extract,omitempty"` // var -> $.path, header:Name, cookie:Name, status or body
+	Assert  map[string]interface{} `yaml:"assert,omitempty" json:"assert,omitempty"`   // run_tests expectation form
 }
 
 // Environment represents a set of environment variables.
 type Environment struct {
 	Name      string            `yaml:"name"`    // Environment name (e.g., "dev", "prod")
 	Variables map[string]string `yaml:",inline"` // Key-value pairs for variables
+	Transport *Transport        `yaml:"transport,omitempty"`
+}
+
+// Transport holds TLS, proxy and HTTP version settings, under the
+// "transport" key of an environment file or a saved request. Its fields
+// match shared.TransportConfig, so one converts to the other.
+type Transport struct {
+	CACert             string `yaml:"ca_cert,omitempty" json:"ca_cert,omitempty"`                           // PEM bundle trusted besides the system roots
+	ClientCert         string `yaml:"client_cert,omitempty" json:"client_cert,omitempty"`                   // PEM certificate for mTLS
+	ClientKey          string `yaml:"client_key,omitempty" json:"client_key,omitempty"`                     // PEM key (default: read from client_cert)
+	InsecureSkipVerify *bool  `yaml:"insecure_skip_verify,omitempty" json:"insecure_skip_verify,omitempty"` // Skip server certificate checks
+	Proxy              string `yaml:"proxy,omitempty" json:"proxy,omitempty"`                               // Proxy URL, or "none"
+	HTTPVersion        string `yaml:"http_version,omitempty" json:"http_version,omitempty"`                 // "1.1" or "2"
 }
 
 // Collection represents a folder of related requests.
@@ -90,3 +90,23 @@ func GetEnvironmentsDir(baseDir string) string {
 func GetFlowsDir(baseDir string) string {
 	return filepath.Join(baseDir, "flows")
 }
+
+// GetSuitesDir returns the test suites directory path
+func GetSuitesDir(baseDir string) string {
+	return filepath.Join(baseDir, "suites")
+}
+
+// GetTranscriptsDir returns the directory headless agent transcripts are written to
+func GetTranscriptsDir(baseDir string) string {
+	return filepath.Join(baseDir, "transcripts")
+}
+
+// RequestFileName returns the file name a saved request is stored under:
+// names are lowercased with spaces as dashes, and ".yaml" is added unless
+// the name already has a YAML extension.
+func RequestFileName(name string) string {
+	if strings.HasSuffix(name, ".yaml") || strings.HasSuffix(name, ".yml") {
+		return name
+	}
+	return strings.ToLower(strings.ReplaceAll(name, " ", "-")) + ".yaml"
+}
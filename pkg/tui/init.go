@@ -30,10 +30,11 @@ func registerTools(agent *core.Agent, falconDir, workDir string, confirmManager
 	return registry.PersistManager
 }
 
-// newLLMClient creates and configures the LLM client from Viper config.
+// NewLLMClient creates and configures the LLM client from Viper config.
 // Provider selection and instantiation are fully driven by the llm.Provider
-// registry — adding a new provider requires no changes here.
-func newLLMClient() llm.LLMClient {
+// registry — adding a new provider requires no changes here. The headless
+// `falcon ask` command uses it to build the same client as the TUI.
+func NewLLMClient() llm.LLMClient {
 	providerID := viper.GetString("provider")
 	model := viper.GetString("default_model")
 
@@ -166,7 +167,7 @@ func InitialModel() Model {
 		modelName = "llama3"
 	}
 
-	client := newLLMClient()
+	client := NewLLMClient()
 	agent := core.NewAgent(client)
 
 	// Set framework from config for context-aware assistance
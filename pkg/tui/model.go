@@ -118,6 +118,13 @@ type agentCancelMsg struct {
 	cancel context.CancelFunc
 }
 
+// flowDoneMsg carries the result of a flow run started from the slash panel
+type flowDoneMsg struct {
+	name    string
+	summary string
+	err     error
+}
+
 // confirmationTimeoutMsg signals that a file confirmation has timed out
 type confirmationTimeoutMsg struct{}
 
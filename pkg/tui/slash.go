@@ -53,7 +53,7 @@ func listFlowFiles(falconDir string) []SlashCommand {
 		if ext == ".yaml" || ext == ".yml" {
 			cmds = append(cmds, SlashCommand{
 				Name:        name,
-				Description: "Flow",
+				Description: "Run flow",
 				Kind:        "flow",
 			})
 		}
@@ -154,15 +154,14 @@ func (m Model) acceptSlashCommand() (Model, tea.Cmd) {
 			m.textinput.SetValue("")
 		}
 
-	case "flow", "request":
-		// Determine file path
-		var subDir string
-		if selected.Kind == "flow" {
-			subDir = "flows"
-		} else {
-			subDir = "requests"
-		}
-		filePath := filepath.Join(core.FalconFolderName, subDir, selected.Name)
+	case "flow":
+		// Flows run deterministically; the LLM is not involved.
+		m.slashState = SlashState{}
+		m.textinput.SetValue("")
+		return m.startFlowRun(selected.Name)
+
+	case "request":
+		filePath := filepath.Join(core.FalconFolderName, "requests", selected.Name)
 
 		content, err := os.ReadFile(filePath)
 		if err == nil {
@@ -189,6 +188,25 @@ func (m Model) acceptSlashCommand() (Model, tea.Cmd) {
 	return m, nil
 }
 
+// startFlowRun logs the selected flow and runs it in the background.
+func (m Model) startFlowRun(name string) (Model, tea.Cmd) {
+	if m.thinking {
+		return m, nil
+	}
+
+	if len(m.logs) > 0 {
+		m.logs = append(m.logs, logEntry{Type: "separator", Content: ""})
+	}
+	m.logs = append(m.logs, logEntry{Type: "user", Content: "▶ /" + name})
+
+	m.thinking = true
+	m.status = "flow"
+	m.currentTool = "run_flow"
+	m.updateViewportContent()
+
+	return m, tea.Batch(m.spinner.Tick, runFlowAsync(m.agent, strings.TrimSuffix(name, filepath.Ext(name))))
+}
+
 // handleSlashKeys intercepts key presses when the slash panel is active.
 // Returns (handled bool, updated model, command).
 // When a key is consumed, a non-nil cmd is always returned so that Update()
@@ -2,10 +2,12 @@ package tui
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 	"time"
 
 	"github.com/blackcoderx/falcon/pkg/core"
+	"github.com/blackcoderx/falcon/pkg/llm"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
@@ -44,6 +46,22 @@ func runAgentAsync(agent *core.Agent, input string) tea.Cmd {
 	}
 }
 
+// runFlowAsync runs a saved flow through the agent's run_flow tool, so it
+// shares the session's variables and active environment. The flow is
+// executed step by step by the flow runner, not interpreted by the LLM.
+// Esc cancels the run through the same cancel function as an agent turn.
+func runFlowAsync(agent *core.Agent, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		globalProgram.Send(agentCancelMsg{cancel: cancel})
+
+		args, _ := json.Marshal(map[string]string{"flow": name})
+		summary, err := agent.ExecuteToolContext(ctx, "run_flow", string(args))
+		return flowDoneMsg{name: name, summary: summary, err: err}
+	}
+}
+
 // Update handles all messages and updates the model state.
 // This is the main event loop handler for the Bubble Tea application.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -74,6 +92,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case agentDoneMsg:
 		m = m.handleAgentDone(msg)
 
+	case flowDoneMsg:
+		m = m.handleFlowDone(msg)
+
 	case spinner.TickMsg:
 		if m.thinking {
 			var cmd tea.Cmd
@@ -296,3 +317,27 @@ func (m Model) handleAgentDone(msg agentDoneMsg) Model {
 	}
 	return m
 }
+
+// handleFlowDone shows a finished flow run and records it in the agent's
+// history so follow-up questions ("why did step 3 fail?") have context.
+func (m Model) handleFlowDone(msg flowDoneMsg) Model {
+	m.thinking = false
+	m.status = "idle"
+	m.currentTool = ""
+	m.cancelAgent = nil
+	m.resetAnimState()
+
+	if msg.err != nil {
+		m.logs = append(m.logs, logEntry{Type: "error", Content: msg.err.Error()})
+		m.updateViewportContent()
+		return m
+	}
+
+	m.logs = append(m.logs, logEntry{Type: "response", Content: "```\n" + msg.summary + "\n```"})
+	m.agent.AppendHistoryPair(
+		llm.Message{Role: "user", Content: "Run flow " + msg.name},
+		llm.Message{Role: "assistant", Content: msg.summary},
+	)
+	m.updateViewportContent()
+	return m
+}
@@ -231,6 +231,8 @@ func (m Model) renderStatusText() string {
 		return StatusLabelStyle.Render("streaming")
 	case "tool":
 		return m.spinner.View() + " " + StatusLabelStyle.Render("tool calling")
+	case "flow":
+		return m.spinner.View() + " " + StatusLabelStyle.Render("running flow")
 	default:
 		return StatusIdleStyle.Render("ready")
 	}